@@ -0,0 +1,189 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package api // import "miniflux.app/api"
+
+import (
+	json_parser "encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"miniflux.app/http/request"
+	"miniflux.app/http/response"
+	"miniflux.app/http/response/json"
+	"miniflux.app/model"
+	"miniflux.app/storage"
+	"miniflux.app/validator"
+)
+
+func (h *handler) getEntryAnnotations(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	entryID := request.RouteInt64Param(r, "entryID")
+
+	if !h.store.EntryExists(userID, entryID) {
+		json.NotFound(w, r)
+		return
+	}
+
+	annotations, err := h.store.EntryAnnotations(userID, entryID)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.OK(w, r, annotations)
+}
+
+func (h *handler) createAnnotation(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	entryID := request.RouteInt64Param(r, "entryID")
+
+	if !h.store.EntryExists(userID, entryID) {
+		json.NotFound(w, r)
+		return
+	}
+
+	var annotationRequest model.AnnotationRequest
+	if err := json_parser.NewDecoder(r.Body).Decode(&annotationRequest); err != nil {
+		json.BadRequest(w, r, err)
+		return
+	}
+
+	annotationRequest.Note = strings.TrimSpace(annotationRequest.Note)
+	if validationErr := validator.ValidateAnnotationCreation(&annotationRequest); validationErr != nil {
+		json.BadRequest(w, r, validationErr.Error())
+		return
+	}
+
+	annotation, err := h.store.CreateAnnotation(userID, entryID, &annotationRequest)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.Created(w, r, annotation)
+}
+
+func (h *handler) updateAnnotation(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	annotationID := request.RouteInt64Param(r, "annotationID")
+
+	annotation, err := h.store.Annotation(userID, annotationID)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	if annotation == nil {
+		json.NotFound(w, r)
+		return
+	}
+
+	var modificationRequest model.AnnotationModificationRequest
+	if err := json_parser.NewDecoder(r.Body).Decode(&modificationRequest); err != nil {
+		json.BadRequest(w, r, err)
+		return
+	}
+
+	if modificationRequest.Note != nil {
+		*modificationRequest.Note = strings.TrimSpace(*modificationRequest.Note)
+	}
+
+	if validationErr := validator.ValidateAnnotationModification(annotation, &modificationRequest); validationErr != nil {
+		json.BadRequest(w, r, validationErr.Error())
+		return
+	}
+
+	modificationRequest.Patch(annotation)
+	if err := h.store.UpdateAnnotation(annotation); err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.Created(w, r, annotation)
+}
+
+func (h *handler) removeAnnotation(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	annotationID := request.RouteInt64Param(r, "annotationID")
+
+	annotation, err := h.store.Annotation(userID, annotationID)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	if annotation == nil {
+		json.NotFound(w, r)
+		return
+	}
+
+	if err := h.store.RemoveAnnotation(userID, annotation.ID); err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.NoContent(w, r)
+}
+
+func (h *handler) getAnnotations(w http.ResponseWriter, r *http.Request) {
+	annotations, err := h.store.Annotations(request.UserID(r), buildAnnotationQuery(r))
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.OK(w, r, annotations)
+}
+
+func (h *handler) exportAnnotations(w http.ResponseWriter, r *http.Request) {
+	annotations, err := h.store.Annotations(request.UserID(r), buildAnnotationQuery(r))
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	builder := response.New(w, r)
+	builder.WithHeader("Content-Type", "text/markdown; charset=utf-8")
+	builder.WithBody(annotationsToMarkdown(annotations))
+	builder.Write()
+}
+
+func buildAnnotationQuery(r *http.Request) *storage.AnnotationQuery {
+	return &storage.AnnotationQuery{
+		EntryID:     request.QueryInt64Param(r, "entry_id", 0),
+		SearchQuery: request.QueryStringParam(r, "search", ""),
+		Language:    request.UserLanguage(r),
+	}
+}
+
+// annotationsToMarkdown groups the annotations by entry, each entry is a level-one section.
+func annotationsToMarkdown(annotations model.Annotations) string {
+	var builder strings.Builder
+	var group model.Annotations
+
+	flush := func() {
+		if len(group) == 0 {
+			return
+		}
+
+		if builder.Len() > 0 {
+			builder.WriteString("\n")
+		}
+
+		fmt.Fprintf(&builder, "# %s\n\n<%s>\n\n%s", group[0].EntryTitle, group[0].EntryURL, group.Markdown())
+		group = nil
+	}
+
+	for _, annotation := range annotations {
+		if len(group) > 0 && group[0].EntryID != annotation.EntryID {
+			flush()
+		}
+		group = append(group, annotation)
+	}
+	flush()
+
+	return builder.String()
+}
@@ -35,8 +35,22 @@ func Serve(router *mux.Router, store *storage.Storage, pool *worker.Pool) {
 	sr.HandleFunc("/users/{userID:[0-9]+}", handler.updateUser).Methods(http.MethodPut)
 	sr.HandleFunc("/users/{userID:[0-9]+}", handler.removeUser).Methods(http.MethodDelete)
 	sr.HandleFunc("/users/{userID:[0-9]+}/mark-all-as-read", handler.markUserAsRead).Methods(http.MethodPut)
+	sr.HandleFunc("/users/{userID:[0-9]+}/api-keys", handler.getAPIKeys).Methods(http.MethodGet)
+	sr.HandleFunc("/users/{userID:[0-9]+}/api-keys", handler.createAPIKey).Methods(http.MethodPost)
+	sr.HandleFunc("/users/{userID:[0-9]+}/api-keys/{keyID}", handler.removeAPIKey).Methods(http.MethodDelete)
+	sr.HandleFunc("/users/{userID:[0-9]+}/sessions", handler.getSessions).Methods(http.MethodGet)
+	sr.HandleFunc("/users/{userID:[0-9]+}/sessions", handler.removeSessions).Methods(http.MethodDelete)
+	sr.HandleFunc("/users/{userID:[0-9]+}/sessions/{sessionID}", handler.removeSession).Methods(http.MethodDelete)
 	sr.HandleFunc("/users/{username}", handler.userByUsername).Methods(http.MethodGet)
 	sr.HandleFunc("/me", handler.currentUser).Methods(http.MethodGet)
+	sr.HandleFunc("/api-keys", handler.getAPIKeys).Methods(http.MethodGet)
+	sr.HandleFunc("/api-keys", handler.createAPIKey).Methods(http.MethodPost)
+	sr.HandleFunc("/api-keys/{keyID}", handler.removeAPIKey).Methods(http.MethodDelete)
+	sr.HandleFunc("/sessions", handler.getSessions).Methods(http.MethodGet)
+	sr.HandleFunc("/sessions", handler.removeSessions).Methods(http.MethodDelete)
+	sr.HandleFunc("/sessions/{sessionID}", handler.removeSession).Methods(http.MethodDelete)
+	sr.HandleFunc("/integrations", handler.getIntegrations).Methods(http.MethodGet)
+	sr.HandleFunc("/integrations", handler.updateIntegrations).Methods(http.MethodPut)
 	sr.HandleFunc("/categories", handler.createCategory).Methods(http.MethodPost)
 	sr.HandleFunc("/categories", handler.getCategories).Methods(http.MethodGet)
 	sr.HandleFunc("/categories/{categoryID}", handler.updateCategory).Methods(http.MethodPut)
@@ -46,6 +60,11 @@ func Serve(router *mux.Router, store *storage.Storage, pool *worker.Pool) {
 	sr.HandleFunc("/categories/{categoryID}/refresh", handler.refreshCategory).Methods(http.MethodPut)
 	sr.HandleFunc("/categories/{categoryID}/entries", handler.getCategoryEntries).Methods(http.MethodGet)
 	sr.HandleFunc("/categories/{categoryID}/entries/{entryID}", handler.getCategoryEntry).Methods(http.MethodGet)
+	sr.HandleFunc("/labels", handler.createLabel).Methods(http.MethodPost)
+	sr.HandleFunc("/labels", handler.getLabels).Methods(http.MethodGet)
+	sr.HandleFunc("/labels/{labelID}", handler.updateLabel).Methods(http.MethodPut)
+	sr.HandleFunc("/labels/{labelID}", handler.removeLabel).Methods(http.MethodDelete)
+	sr.HandleFunc("/labels/{labelID}/entries", handler.getLabelEntries).Methods(http.MethodGet)
 	sr.HandleFunc("/discover", handler.discoverSubscriptions).Methods(http.MethodPost)
 	sr.HandleFunc("/feeds", handler.createFeed).Methods(http.MethodPost)
 	sr.HandleFunc("/feeds", handler.getFeeds).Methods(http.MethodGet)
@@ -56,6 +75,7 @@ func Serve(router *mux.Router, store *storage.Storage, pool *worker.Pool) {
 	sr.HandleFunc("/feeds/{feedID}", handler.updateFeed).Methods(http.MethodPut)
 	sr.HandleFunc("/feeds/{feedID}", handler.removeFeed).Methods(http.MethodDelete)
 	sr.HandleFunc("/feeds/{feedID}/icon", handler.feedIcon).Methods(http.MethodGet)
+	sr.HandleFunc("/icons/{iconID}", handler.iconByID).Methods(http.MethodGet)
 	sr.HandleFunc("/feeds/{feedID}/mark-all-as-read", handler.markFeedAsRead).Methods(http.MethodPut)
 	sr.HandleFunc("/export", handler.exportFeeds).Methods(http.MethodGet)
 	sr.HandleFunc("/import", handler.importFeeds).Methods(http.MethodPost)
@@ -65,5 +85,16 @@ func Serve(router *mux.Router, store *storage.Storage, pool *worker.Pool) {
 	sr.HandleFunc("/entries", handler.setEntryStatus).Methods(http.MethodPut)
 	sr.HandleFunc("/entries/{entryID}", handler.getEntry).Methods(http.MethodGet)
 	sr.HandleFunc("/entries/{entryID}/bookmark", handler.toggleBookmark).Methods(http.MethodPut)
+	sr.HandleFunc("/entries/{entryID}/labels", handler.updateEntryLabels).Methods(http.MethodPut)
+	sr.HandleFunc("/entries/{entryID}/annotations", handler.getEntryAnnotations).Methods(http.MethodGet)
+	sr.HandleFunc("/entries/{entryID}/annotations", handler.createAnnotation).Methods(http.MethodPost)
+	sr.HandleFunc("/entries/{entryID}/revisions", handler.getEntryRevisions).Methods(http.MethodGet)
+	sr.HandleFunc("/entries/{entryID}/revisions/{revisionID}/diff", handler.getEntryRevisionDiff).Methods(http.MethodGet)
+	sr.HandleFunc("/annotations", handler.getAnnotations).Methods(http.MethodGet)
+	sr.HandleFunc("/annotations/export", handler.exportAnnotations).Methods(http.MethodGet)
+	sr.HandleFunc("/annotations/{annotationID}", handler.updateAnnotation).Methods(http.MethodPut)
+	sr.HandleFunc("/annotations/{annotationID}", handler.removeAnnotation).Methods(http.MethodDelete)
 	sr.HandleFunc("/entries/{entryID}/fetch-content", handler.fetchContent).Methods(http.MethodGet)
+	sr.HandleFunc("/events", handler.streamEvents).Methods(http.MethodGet)
+	sr.HandleFunc("/openapi.json", handler.openAPISpec).Methods(http.MethodGet)
 }
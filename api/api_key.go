@@ -0,0 +1,88 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package api // import "miniflux.app/api"
+
+import (
+	json_parser "encoding/json"
+	"net/http"
+
+	"miniflux.app/http/request"
+	"miniflux.app/http/response/json"
+	"miniflux.app/model"
+	"miniflux.app/validator"
+)
+
+func (h *handler) getAPIKeys(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.targetUserID(w, r)
+	if !ok {
+		return
+	}
+
+	apiKeys, err := h.store.APIKeys(userID)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	// Tokens are only returned once, when the key is created.
+	for _, apiKey := range apiKeys {
+		apiKey.Token = ""
+	}
+
+	json.OK(w, r, apiKeys)
+}
+
+func (h *handler) createAPIKey(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.targetUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var apiKeyCreationRequest model.APIKeyCreationRequest
+	if err := json_parser.NewDecoder(r.Body).Decode(&apiKeyCreationRequest); err != nil {
+		json.BadRequest(w, r, err)
+		return
+	}
+
+	if validationErr := validator.ValidateAPIKeyCreation(h.store, userID, &apiKeyCreationRequest); validationErr != nil {
+		json.BadRequest(w, r, validationErr.Error())
+		return
+	}
+
+	apiKey := model.NewAPIKey(userID, apiKeyCreationRequest.Description)
+	if apiKeyCreationRequest.Scopes != nil {
+		apiKey.Scopes = apiKeyCreationRequest.Scopes
+	}
+	if apiKeyCreationRequest.CategoryIDs != nil {
+		apiKey.CategoryIDs = apiKeyCreationRequest.CategoryIDs
+	}
+
+	if err := h.store.CreateAPIKey(apiKey); err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.Created(w, r, apiKey)
+}
+
+func (h *handler) removeAPIKey(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.targetUserID(w, r)
+	if !ok {
+		return
+	}
+
+	keyID := request.RouteInt64Param(r, "keyID")
+	if !h.store.APIKeyIDExists(userID, keyID) {
+		json.NotFound(w, r)
+		return
+	}
+
+	if err := h.store.RemoveAPIKey(userID, keyID); err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.NoContent(w, r)
+}
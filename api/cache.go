@@ -0,0 +1,43 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package api // import "miniflux.app/api"
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"miniflux.app/crypto"
+	"miniflux.app/http/request"
+	"miniflux.app/http/response/json"
+	"miniflux.app/version"
+)
+
+// okWithValidators sends a listing of the user that can be revalidated with If-None-Match.
+// The last change of the user is loaded before the data, a concurrent modification is then detected by the next request.
+func (h *handler) okWithValidators(w http.ResponseWriter, r *http.Request, body func() (interface{}, error)) {
+	changedAt, err := h.store.UserChangedAt(request.UserID(r))
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.OKWithValidators(w, r, listingETag(r, changedAt), changedAt, body)
+}
+
+// listingETag returns a weak ETag, the response depends on the query string, the host used to rewrite
+// the media URLs, and the categories allowed by the API key.
+func listingETag(r *http.Request, changedAt time.Time) string {
+	key := fmt.Sprintf(
+		"%s:%d:%d:%s%s:%v",
+		version.Version,
+		request.UserID(r),
+		changedAt.UnixNano(),
+		r.Host,
+		r.URL.RequestURI(),
+		request.APIKeyCategoryIDs(r),
+	)
+	return `W/"` + crypto.Hash(key) + `"`
+}
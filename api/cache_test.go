@@ -0,0 +1,119 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package api // import "miniflux.app/api"
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"miniflux.app/model"
+	"miniflux.app/storage/storagetest"
+
+	"github.com/gorilla/mux"
+)
+
+func TestConditionalListings(t *testing.T) {
+	store := storagetest.NewStorage(t)
+	user, feed, entries := createTestFeed(t, store)
+
+	apiKey := model.NewAPIKey(user.ID, "Test")
+	if err := store.CreateAPIKey(apiKey); err != nil {
+		t.Fatal(err)
+	}
+
+	restrictedKey := model.NewAPIKey(user.ID, "Restricted")
+	restrictedKey.CategoryIDs = []int64{feed.Category.ID}
+	if err := store.CreateAPIKey(restrictedKey); err != nil {
+		t.Fatal(err)
+	}
+
+	router := mux.NewRouter()
+	Serve(router, store, nil)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	get := func(token, path string, headers map[string]string) *http.Response {
+		request, err := http.NewRequest(http.MethodGet, server.URL+"/v1"+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		request.Header.Set("X-Auth-Token", token)
+		for key, value := range headers {
+			request.Header.Set(key, value)
+		}
+
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		response.Body.Close()
+		return response
+	}
+
+	paths := []string{
+		"/feeds",
+		"/feeds/counters",
+		"/categories",
+		"/entries?status=unread",
+		fmt.Sprintf("/feeds/%d/entries", feed.ID),
+		fmt.Sprintf("/categories/%d/feeds", feed.Category.ID),
+		fmt.Sprintf("/categories/%d/entries", feed.Category.ID),
+	}
+
+	for _, path := range paths {
+		response := get(apiKey.Token, path, nil)
+		if response.StatusCode != http.StatusOK {
+			t.Fatalf(`Unexpected status code for %s: got %d`, path, response.StatusCode)
+		}
+
+		etag := response.Header.Get("ETag")
+		lastModified := response.Header.Get("Last-Modified")
+		if etag == "" || lastModified == "" {
+			t.Fatalf(`Missing validators for %s: ETag=%q Last-Modified=%q`, path, etag, lastModified)
+		}
+
+		if response := get(apiKey.Token, path, map[string]string{"If-None-Match": etag}); response.StatusCode != http.StatusNotModified {
+			t.Errorf(`Unexpected status code for %s with If-None-Match: got %d`, path, response.StatusCode)
+		}
+
+		// The ETag detects the changes made in the same second, the Last-Modified date alone is not trusted.
+		if response := get(apiKey.Token, path, map[string]string{"If-Modified-Since": lastModified}); response.StatusCode != http.StatusOK {
+			t.Errorf(`Unexpected status code for %s with If-Modified-Since: got %d`, path, response.StatusCode)
+		}
+
+		if response := get(restrictedKey.Token, path, map[string]string{"If-None-Match": etag}); response.StatusCode != http.StatusOK {
+			t.Errorf(`The ETag of %s should depend on the categories of the API key`, path)
+		}
+	}
+
+	etag := get(apiKey.Token, "/entries?status=unread", nil).Header.Get("ETag")
+	if response := get(apiKey.Token, "/entries?status=read", map[string]string{"If-None-Match": etag}); response.StatusCode != http.StatusOK {
+		t.Errorf(`The ETag should depend on the query string`)
+	}
+
+	if err := store.SetEntriesStatus(user.ID, []int64{entries[0].ID}, model.EntryStatusRead); err != nil {
+		t.Fatal(err)
+	}
+
+	response := get(apiKey.Token, "/entries?status=unread", map[string]string{"If-None-Match": etag})
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf(`The entries should be sent again after a change: got %d`, response.StatusCode)
+	}
+
+	if response.Header.Get("ETag") == etag {
+		t.Errorf(`The ETag should change after a modification`)
+	}
+
+	etag = get(apiKey.Token, "/categories", nil).Header.Get("ETag")
+	if _, err := store.CreateCategory(user.ID, &model.CategoryRequest{Title: "Other"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if response := get(apiKey.Token, "/categories", map[string]string{"If-None-Match": etag}); response.StatusCode != http.StatusOK {
+		t.Errorf(`The categories should be sent again after a change: got %d`, response.StatusCode)
+	}
+}
@@ -98,13 +98,20 @@ func (h *handler) markCategoryAsRead(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *handler) getCategories(w http.ResponseWriter, r *http.Request) {
-	categories, err := h.store.Categories(request.UserID(r))
-	if err != nil {
-		json.ServerError(w, r, err)
-		return
-	}
-
-	json.OK(w, r, categories)
+	h.okWithValidators(w, r, func() (interface{}, error) {
+		categories, err := h.store.Categories(request.UserID(r))
+		if err != nil || len(request.APIKeyCategoryIDs(r)) == 0 {
+			return categories, err
+		}
+
+		allowedCategories := make(model.Categories, 0, len(categories))
+		for _, category := range categories {
+			if isCategoryAllowed(r, category.ID) {
+				allowedCategories = append(allowedCategories, category)
+			}
+		}
+		return allowedCategories, nil
+	})
 }
 
 func (h *handler) removeCategory(w http.ResponseWriter, r *http.Request) {
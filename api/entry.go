@@ -24,7 +24,7 @@ import (
 )
 
 func (h *handler) getEntryFromBuilder(w http.ResponseWriter, r *http.Request, b *storage.EntryQueryBuilder) {
-	entry, err := b.GetEntry()
+	entry, err := b.WithAnnotations().GetEntry()
 	if err != nil {
 		json.ServerError(w, r, err)
 		return
@@ -84,19 +84,19 @@ func (h *handler) getEntry(w http.ResponseWriter, r *http.Request) {
 
 func (h *handler) getFeedEntries(w http.ResponseWriter, r *http.Request) {
 	feedID := request.RouteInt64Param(r, "feedID")
-	h.findEntries(w, r, feedID, 0)
+	h.findEntries(w, r, feedID, 0, 0)
 }
 
 func (h *handler) getCategoryEntries(w http.ResponseWriter, r *http.Request) {
 	categoryID := request.RouteInt64Param(r, "categoryID")
-	h.findEntries(w, r, 0, categoryID)
+	h.findEntries(w, r, 0, categoryID, 0)
 }
 
 func (h *handler) getEntries(w http.ResponseWriter, r *http.Request) {
-	h.findEntries(w, r, 0, 0)
+	h.findEntries(w, r, 0, 0, 0)
 }
 
-func (h *handler) findEntries(w http.ResponseWriter, r *http.Request, feedID int64, categoryID int64) {
+func (h *handler) findEntries(w http.ResponseWriter, r *http.Request, feedID, categoryID, labelID int64) {
 	statuses := request.QueryStringParamList(r, "status")
 	for _, status := range statuses {
 		if err := validator.ValidateEntryStatus(status); err != nil {
@@ -124,6 +124,23 @@ func (h *handler) findEntries(w http.ResponseWriter, r *http.Request, feedID int
 		return
 	}
 
+	// Cursors are only available with the default sorting order because they are built on the publication date.
+	keyset := order == model.DefaultSortingOrder && request.QueryStringParam(r, "search", "") == ""
+
+	var cursor *model.EntryCursor
+	if value := request.QueryStringParam(r, "cursor", ""); value != "" {
+		if !keyset || offset > 0 {
+			json.BadRequest(w, r, errors.New("The cursor cannot be combined with an offset, a search query or a sorting order other than published_at"))
+			return
+		}
+
+		var err error
+		if cursor, err = model.ParseEntryCursor(value); err != nil {
+			json.BadRequest(w, r, err)
+			return
+		}
+	}
+
 	userID := request.UserID(r)
 	categoryID = request.QueryInt64Param(r, "category_id", categoryID)
 	if categoryID > 0 && !h.store.CategoryIDExists(userID, categoryID) {
@@ -137,11 +154,19 @@ func (h *handler) findEntries(w http.ResponseWriter, r *http.Request, feedID int
 		return
 	}
 
+	labelID = request.QueryInt64Param(r, "label_id", labelID)
+	if labelID > 0 && !h.store.LabelIDExists(userID, labelID) {
+		json.BadRequest(w, r, errors.New("Invalid label ID"))
+		return
+	}
+
 	tags := request.QueryStringParamList(r, "tags")
 
 	builder := h.store.NewEntryQueryBuilder(userID)
 	builder.WithFeedID(feedID)
 	builder.WithCategoryID(categoryID)
+	builder.WithCategoryIDs(request.APIKeyCategoryIDs(r))
+	builder.WithLabelID(labelID)
 	builder.WithStatuses(statuses)
 	builder.WithOrder(order)
 	builder.WithDirection(direction)
@@ -150,23 +175,32 @@ func (h *handler) findEntries(w http.ResponseWriter, r *http.Request, feedID int
 	builder.WithTags(tags)
 	configureFilters(builder, r)
 
-	entries, err := builder.GetEntries()
-	if err != nil {
-		json.ServerError(w, r, err)
-		return
+	if keyset {
+		builder.WithCursor(cursor)
 	}
 
-	count, err := builder.CountEntries()
-	if err != nil {
-		json.ServerError(w, r, err)
-		return
-	}
+	h.okWithValidators(w, r, func() (interface{}, error) {
+		entries, err := builder.GetEntries()
+		if err != nil {
+			return nil, err
+		}
 
-	for i := range entries {
-		entries[i].Content = proxy.AbsoluteProxyRewriter(h.router, r.Host, entries[i].Content)
-	}
+		count, err := builder.CountEntries()
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range entries {
+			entries[i].Content = proxy.AbsoluteProxyRewriter(h.router, r.Host, entries[i].Content)
+		}
 
-	json.OK(w, r, &entriesResponse{Total: count, Entries: entries})
+		response := &entriesResponse{Total: count, Entries: entries}
+		if keyset && limit > 0 && len(entries) == limit {
+			response.NextCursor = model.NewEntryCursor(entries[len(entries)-1]).String()
+		}
+
+		return response, nil
+	})
 }
 
 func (h *handler) setEntryStatus(w http.ResponseWriter, r *http.Request) {
@@ -181,7 +215,17 @@ func (h *handler) setEntryStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.store.SetEntriesStatus(request.UserID(r), entriesStatusUpdateRequest.EntryIDs, entriesStatusUpdateRequest.Status); err != nil {
+	userID := request.UserID(r)
+	if len(request.APIKeyCategoryIDs(r)) > 0 {
+		for _, entryID := range entriesStatusUpdateRequest.EntryIDs {
+			if !isCategoryAllowed(r, h.store.EntryCategoryID(userID, entryID)) {
+				json.Forbidden(w, r)
+				return
+			}
+		}
+	}
+
+	if err := h.store.SetEntriesStatus(userID, entriesStatusUpdateRequest.EntryIDs, entriesStatusUpdateRequest.Status); err != nil {
 		json.ServerError(w, r, err)
 		return
 	}
@@ -247,7 +291,7 @@ func (h *handler) fetchContent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	json.OK(w, r, map[string]string{"content": entry.Content})
+	json.OK(w, r, &entryContentResponse{Content: entry.Content})
 }
 
 func configureFilters(builder *storage.EntryQueryBuilder, r *http.Request) {
@@ -285,6 +329,6 @@ func configureFilters(builder *storage.EntryQueryBuilder, r *http.Request) {
 
 	searchQuery := request.QueryStringParam(r, "search", "")
 	if searchQuery != "" {
-		builder.WithSearchQuery(searchQuery)
+		builder.WithSearchQuery(searchQuery, request.UserLanguage(r))
 	}
 }
@@ -0,0 +1,66 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package api // import "miniflux.app/api"
+
+import (
+	"net/http"
+
+	"miniflux.app/http/request"
+	"miniflux.app/http/response/json"
+	"miniflux.app/model"
+)
+
+func (h *handler) getEntryRevisions(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	entryID := request.RouteInt64Param(r, "entryID")
+
+	if !h.store.EntryExists(userID, entryID) {
+		json.NotFound(w, r)
+		return
+	}
+
+	revisions, err := h.store.EntryRevisions(userID, entryID)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.OK(w, r, revisions)
+}
+
+func (h *handler) getEntryRevisionDiff(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	entryID := request.RouteInt64Param(r, "entryID")
+	revisionID := request.RouteInt64Param(r, "revisionID")
+
+	builder := h.store.NewEntryQueryBuilder(userID)
+	builder.WithEntryID(entryID)
+	builder.WithoutStatus(model.EntryStatusRemoved)
+
+	entry, err := builder.GetEntry()
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	if entry == nil {
+		json.NotFound(w, r)
+		return
+	}
+
+	revisions, err := h.store.EntryRevisions(userID, entryID)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	revisionDiff := revisions.RevisionDiff(entry, revisionID)
+	if revisionDiff == nil {
+		json.NotFound(w, r)
+		return
+	}
+
+	json.OK(w, r, revisionDiff)
+}
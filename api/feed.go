@@ -151,33 +151,60 @@ func (h *handler) getCategoryFeeds(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	feeds, err := h.store.FeedsByCategoryWithCounters(userID, categoryID)
-	if err != nil {
-		json.ServerError(w, r, err)
-		return
-	}
-
-	json.OK(w, r, feeds)
+	h.okWithValidators(w, r, func() (interface{}, error) {
+		return h.store.FeedsByCategoryWithCounters(userID, categoryID)
+	})
 }
 
 func (h *handler) getFeeds(w http.ResponseWriter, r *http.Request) {
-	feeds, err := h.store.Feeds(request.UserID(r))
-	if err != nil {
-		json.ServerError(w, r, err)
-		return
-	}
-
-	json.OK(w, r, feeds)
+	h.okWithValidators(w, r, func() (interface{}, error) {
+		return h.allowedFeeds(r)
+	})
 }
 
 func (h *handler) fetchCounters(w http.ResponseWriter, r *http.Request) {
+	h.okWithValidators(w, r, func() (interface{}, error) {
+		return h.allowedCounters(r)
+	})
+}
+
+func (h *handler) allowedCounters(r *http.Request) (model.FeedCounters, error) {
 	counters, err := h.store.FetchCounters(request.UserID(r))
+	if err != nil || len(request.APIKeyCategoryIDs(r)) == 0 {
+		return counters, err
+	}
+
+	feeds, err := h.allowedFeeds(r)
 	if err != nil {
-		json.ServerError(w, r, err)
-		return
+		return counters, err
+	}
+
+	allowedCounters := model.FeedCounters{ReadCounters: make(map[int64]int), UnreadCounters: make(map[int64]int)}
+	for _, feed := range feeds {
+		if count, found := counters.ReadCounters[feed.ID]; found {
+			allowedCounters.ReadCounters[feed.ID] = count
+		}
+		if count, found := counters.UnreadCounters[feed.ID]; found {
+			allowedCounters.UnreadCounters[feed.ID] = count
+		}
+	}
+	return allowedCounters, nil
+}
+
+// allowedFeeds returns the feeds of the user that belong to the categories allowed by the API key.
+func (h *handler) allowedFeeds(r *http.Request) (model.Feeds, error) {
+	feeds, err := h.store.Feeds(request.UserID(r))
+	if err != nil || len(request.APIKeyCategoryIDs(r)) == 0 {
+		return feeds, err
 	}
 
-	json.OK(w, r, counters)
+	allowedFeeds := make(model.Feeds, 0, len(feeds))
+	for _, feed := range feeds {
+		if isCategoryAllowed(r, feed.Category.ID) {
+			allowedFeeds = append(allowedFeeds, feed)
+		}
+	}
+	return allowedFeeds, nil
 }
 
 func (h *handler) getFeed(w http.ResponseWriter, r *http.Request) {
@@ -36,3 +36,24 @@ func (h *handler) feedIcon(w http.ResponseWriter, r *http.Request) {
 		Data:     icon.DataURL(),
 	})
 }
+
+func (h *handler) iconByID(w http.ResponseWriter, r *http.Request) {
+	iconID := request.RouteInt64Param(r, "iconID")
+
+	icon, err := h.store.IconByID(iconID)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	if icon == nil {
+		json.NotFound(w, r)
+		return
+	}
+
+	json.OK(w, r, &feedIconResponse{
+		ID:       icon.ID,
+		MimeType: icon.MimeType,
+		Data:     icon.DataURL(),
+	})
+}
@@ -0,0 +1,62 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package api // import "miniflux.app/api"
+
+import (
+	json_parser "encoding/json"
+	"errors"
+	"net/http"
+
+	"miniflux.app/http/request"
+	"miniflux.app/http/response/json"
+	"miniflux.app/model"
+	"miniflux.app/validator"
+)
+
+func (h *handler) getIntegrations(w http.ResponseWriter, r *http.Request) {
+	integration, err := h.store.Integration(request.UserID(r))
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.OK(w, r, integration)
+}
+
+func (h *handler) updateIntegrations(w http.ResponseWriter, r *http.Request) {
+	var integrationModificationRequest model.IntegrationModificationRequest
+	if err := json_parser.NewDecoder(r.Body).Decode(&integrationModificationRequest); err != nil {
+		json.BadRequest(w, r, err)
+		return
+	}
+
+	integration, err := h.store.Integration(request.UserID(r))
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	// The Fever token is derived from the username and the password, it cannot be recomputed without the password.
+	feverUsernameChanged := integrationModificationRequest.FeverUsername != nil && *integrationModificationRequest.FeverUsername != integration.FeverUsername
+
+	integrationModificationRequest.Patch(integration)
+
+	if integration.FeverEnabled && feverUsernameChanged && integrationModificationRequest.FeverPassword == nil {
+		json.BadRequest(w, r, errors.New("The Fever password is required to change the Fever username"))
+		return
+	}
+
+	if validationErr := validator.ValidateIntegration(h.store, integration); validationErr != nil {
+		json.BadRequest(w, r, validationErr.Error())
+		return
+	}
+
+	if err := h.store.UpdateIntegration(integration); err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.Created(w, r, integration)
+}
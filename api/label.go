@@ -0,0 +1,153 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package api // import "miniflux.app/api"
+
+import (
+	json_parser "encoding/json"
+	"net/http"
+	"strings"
+
+	"miniflux.app/http/request"
+	"miniflux.app/http/response/json"
+	"miniflux.app/model"
+	"miniflux.app/validator"
+)
+
+func (h *handler) createLabel(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+
+	var labelRequest model.LabelRequest
+	if err := json_parser.NewDecoder(r.Body).Decode(&labelRequest); err != nil {
+		json.BadRequest(w, r, err)
+		return
+	}
+
+	labelRequest.Title = strings.TrimSpace(labelRequest.Title)
+	if validationErr := validator.ValidateLabelCreation(h.store, userID, &labelRequest); validationErr != nil {
+		json.BadRequest(w, r, validationErr.Error())
+		return
+	}
+
+	label, err := h.store.CreateLabel(userID, &labelRequest)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.Created(w, r, label)
+}
+
+func (h *handler) updateLabel(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	labelID := request.RouteInt64Param(r, "labelID")
+
+	label, err := h.store.Label(userID, labelID)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	if label == nil {
+		json.NotFound(w, r)
+		return
+	}
+
+	var labelRequest model.LabelRequest
+	if err := json_parser.NewDecoder(r.Body).Decode(&labelRequest); err != nil {
+		json.BadRequest(w, r, err)
+		return
+	}
+
+	labelRequest.Title = strings.TrimSpace(labelRequest.Title)
+	if validationErr := validator.ValidateLabelModification(h.store, userID, label.ID, &labelRequest); validationErr != nil {
+		json.BadRequest(w, r, validationErr.Error())
+		return
+	}
+
+	labelRequest.Patch(label)
+	if err := h.store.UpdateLabel(label); err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.Created(w, r, label)
+}
+
+func (h *handler) getLabels(w http.ResponseWriter, r *http.Request) {
+	labels, err := h.store.Labels(request.UserID(r))
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.OK(w, r, labels)
+}
+
+func (h *handler) removeLabel(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	labelID := request.RouteInt64Param(r, "labelID")
+
+	if !h.store.LabelIDExists(userID, labelID) {
+		json.NotFound(w, r)
+		return
+	}
+
+	if err := h.store.RemoveLabel(userID, labelID); err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.NoContent(w, r)
+}
+
+func (h *handler) getLabelEntries(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	labelID := request.RouteInt64Param(r, "labelID")
+
+	if !h.store.LabelIDExists(userID, labelID) {
+		json.NotFound(w, r)
+		return
+	}
+
+	h.findEntries(w, r, 0, 0, labelID)
+}
+
+func (h *handler) updateEntryLabels(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	entryID := request.RouteInt64Param(r, "entryID")
+
+	var labelsRequest model.EntryLabelsRequest
+	if err := json_parser.NewDecoder(r.Body).Decode(&labelsRequest); err != nil {
+		json.BadRequest(w, r, err)
+		return
+	}
+
+	builder := h.store.NewEntryQueryBuilder(userID)
+	builder.WithEntryID(entryID)
+	builder.WithoutStatus(model.EntryStatusRemoved)
+
+	entry, err := builder.GetEntry()
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	if entry == nil {
+		json.NotFound(w, r)
+		return
+	}
+
+	if validationErr := validator.ValidateEntryLabels(h.store, userID, labelsRequest.Labels); validationErr != nil {
+		json.BadRequest(w, r, validationErr.Error())
+		return
+	}
+
+	if err := h.store.SetEntryLabels(userID, entry.ID, labelsRequest.Labels); err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.NoContent(w, r)
+}
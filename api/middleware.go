@@ -59,6 +59,24 @@ func (m *middleware) apiKeyAuth(next http.Handler) http.Handler {
 			return
 		}
 
+		apiKey, err := m.store.APIKeyByToken(token)
+		if err != nil {
+			logger.Error("[API][TokenAuth] %v", err)
+			json.ServerError(w, r, err)
+			return
+		}
+
+		if apiKey == nil {
+			json.Unauthorized(w, r)
+			return
+		}
+
+		if !m.isAllowedByAPIKey(r, apiKey) {
+			logger.Error("[API][TokenAuth] [ClientIP=%s] The API key %q does not allow %s %s", clientIP, apiKey.Description, r.Method, r.URL.Path)
+			json.Forbidden(w, r)
+			return
+		}
+
 		logger.Info("[API][TokenAuth] [ClientIP=%s] User authenticated: %s", clientIP, user.Username)
 		m.store.SetLastLogin(user.ID)
 		m.store.SetAPIKeyUsedTimestamp(user.ID, token)
@@ -66,8 +84,10 @@ func (m *middleware) apiKeyAuth(next http.Handler) http.Handler {
 		ctx := r.Context()
 		ctx = context.WithValue(ctx, request.UserIDContextKey, user.ID)
 		ctx = context.WithValue(ctx, request.UserTimezoneContextKey, user.Timezone)
+		ctx = context.WithValue(ctx, request.UserLanguageContextKey, user.Language)
 		ctx = context.WithValue(ctx, request.IsAdminUserContextKey, user.IsAdmin)
 		ctx = context.WithValue(ctx, request.IsAuthenticatedContextKey, true)
+		ctx = context.WithValue(ctx, request.APIKeyCategoryIDsContextKey, apiKey.CategoryIDs)
 
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
@@ -121,6 +141,7 @@ func (m *middleware) basicAuth(next http.Handler) http.Handler {
 		ctx := r.Context()
 		ctx = context.WithValue(ctx, request.UserIDContextKey, user.ID)
 		ctx = context.WithValue(ctx, request.UserTimezoneContextKey, user.Timezone)
+		ctx = context.WithValue(ctx, request.UserLanguageContextKey, user.Language)
 		ctx = context.WithValue(ctx, request.IsAdminUserContextKey, user.IsAdmin)
 		ctx = context.WithValue(ctx, request.IsAuthenticatedContextKey, true)
 
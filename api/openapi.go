@@ -0,0 +1,458 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package api // import "miniflux.app/api"
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"miniflux.app/http/response/json"
+	"miniflux.app/model"
+	"miniflux.app/reader/subscription"
+	"miniflux.app/version"
+)
+
+const (
+	openAPIVersion = "3.0.3"
+
+	contentTypeJSON        = "application/json"
+	contentTypeXML         = "text/xml"
+	contentTypeMarkdown    = "text/markdown"
+	contentTypeEventStream = "text/event-stream"
+)
+
+// openAPIRoute documents a route declared in Serve.
+type openAPIRoute struct {
+	Method      string
+	Path        string
+	OperationID string
+	Summary     string
+	Tag         string
+	Parameters  []*openAPIParameter
+
+	// Request is a value of the request body type, nil when the route does not accept a body.
+	Request            interface{}
+	RequestContentType string
+
+	// Response is a value of the response body type, nil when the route does not return a body.
+	Status              int
+	Response            interface{}
+	ResponseContentType string
+
+	// Conditional is true when the response can be revalidated with If-None-Match.
+	Conditional bool
+}
+
+var (
+	entryStatusSchema = &openAPISchema{Type: "string", Enum: []string{model.EntryStatusUnread, model.EntryStatusRead, model.EntryStatusRemoved}}
+	entryOrderSchema  = &openAPISchema{Type: "string", Enum: []string{"id", "status", "changed_at", "published_at", "created_at", "category_title", "category_id", "title", "author"}}
+	directionSchema   = &openAPISchema{Type: "string", Enum: []string{"asc", "desc"}}
+	stringSchema      = &openAPISchema{Type: "string"}
+	integerSchema     = &openAPISchema{Type: "integer"}
+	int64Schema       = &openAPISchema{Type: "integer", Format: "int64"}
+	booleanSchema     = &openAPISchema{Type: "boolean"}
+)
+
+var entryQueryParameters = []*openAPIParameter{
+	newQueryParameter("status", "Filter by entry status, can be repeated.", &openAPISchema{Type: "array", Items: entryStatusSchema}),
+	newQueryParameter("order", "Sorting order.", entryOrderSchema),
+	newQueryParameter("direction", "Sorting direction.", directionSchema),
+	newQueryParameter("limit", "Maximum number of entries returned, 100 by default.", integerSchema),
+	newQueryParameter("offset", "Number of entries to skip.", integerSchema),
+	newQueryParameter("cursor", "Cursor returned by the previous page.", stringSchema),
+	newQueryParameter("category_id", "Filter by category ID.", int64Schema),
+	newQueryParameter("feed_id", "Filter by feed ID.", int64Schema),
+	newQueryParameter("label_id", "Filter by label ID.", int64Schema),
+	newQueryParameter("tags", "Filter by tag, can be repeated.", &openAPISchema{Type: "array", Items: stringSchema}),
+	newQueryParameter("before_entry_id", "Entries with an ID lower than this value.", int64Schema),
+	newQueryParameter("after_entry_id", "Entries with an ID greater than this value.", int64Schema),
+	newQueryParameter("before", "Entries published before this Unix timestamp.", int64Schema),
+	newQueryParameter("after", "Entries published after this Unix timestamp.", int64Schema),
+	newQueryParameter("search", "Full-text search query.", stringSchema),
+	newQueryParameter("starred", "Filter by bookmark state.", booleanSchema),
+}
+
+// The routes /users/{userID} and /users/{username} are equivalent for OpenAPI, they are documented as a single operation.
+var userIdentifierParameter = &openAPIParameter{Name: "userID", In: "path", Description: "User ID or username.", Required: true, Schema: stringSchema}
+
+var conditionalParameters = []*openAPIParameter{
+	{Name: "If-None-Match", In: "header", Description: "ETag of the version known by the client.", Schema: stringSchema},
+}
+
+var annotationQueryParameters = []*openAPIParameter{
+	newQueryParameter("entry_id", "Filter by entry ID.", int64Schema),
+	newQueryParameter("search", "Search in quotes and notes.", stringSchema),
+}
+
+var openAPIRoutes = []*openAPIRoute{
+	{Method: http.MethodPost, Path: "/users", OperationID: "createUser", Summary: "Create a user", Tag: "Users", Request: model.UserCreationRequest{}, Status: http.StatusCreated, Response: model.User{}},
+	{Method: http.MethodGet, Path: "/users", OperationID: "getUsers", Summary: "Get all users", Tag: "Users", Status: http.StatusOK, Response: model.Users{}},
+	{Method: http.MethodGet, Path: "/users/{userID}", OperationID: "getUser", Summary: "Get a user by ID or username", Tag: "Users", Parameters: []*openAPIParameter{userIdentifierParameter}, Status: http.StatusOK, Response: model.User{}},
+	{Method: http.MethodPut, Path: "/users/{userID}", OperationID: "updateUser", Summary: "Update a user", Tag: "Users", Request: model.UserModificationRequest{}, Status: http.StatusCreated, Response: model.User{}},
+	{Method: http.MethodDelete, Path: "/users/{userID}", OperationID: "removeUser", Summary: "Remove a user", Tag: "Users", Status: http.StatusNoContent},
+	{Method: http.MethodPut, Path: "/users/{userID}/mark-all-as-read", OperationID: "markUserAsRead", Summary: "Mark all entries of a user as read", Tag: "Users", Status: http.StatusNoContent},
+	{Method: http.MethodGet, Path: "/users/{userID}/api-keys", OperationID: "getUserAPIKeys", Summary: "Get the API keys of a user", Tag: "API Keys", Status: http.StatusOK, Response: model.APIKeys{}},
+	{Method: http.MethodPost, Path: "/users/{userID}/api-keys", OperationID: "createUserAPIKey", Summary: "Create an API key for a user", Tag: "API Keys", Request: model.APIKeyCreationRequest{}, Status: http.StatusCreated, Response: model.APIKey{}},
+	{Method: http.MethodDelete, Path: "/users/{userID}/api-keys/{keyID}", OperationID: "removeUserAPIKey", Summary: "Remove an API key of a user", Tag: "API Keys", Status: http.StatusNoContent},
+	{Method: http.MethodGet, Path: "/users/{userID}/sessions", OperationID: "getUserSessions", Summary: "Get the sessions of a user", Tag: "Sessions", Status: http.StatusOK, Response: model.UserSessions{}},
+	{Method: http.MethodDelete, Path: "/users/{userID}/sessions", OperationID: "removeUserSessions", Summary: "Remove all sessions of a user", Tag: "Sessions", Status: http.StatusNoContent},
+	{Method: http.MethodDelete, Path: "/users/{userID}/sessions/{sessionID}", OperationID: "removeUserSession", Summary: "Remove a session of a user", Tag: "Sessions", Status: http.StatusNoContent},
+	{Method: http.MethodGet, Path: "/me", OperationID: "getCurrentUser", Summary: "Get the authenticated user", Tag: "Users", Status: http.StatusOK, Response: model.User{}},
+	{Method: http.MethodGet, Path: "/api-keys", OperationID: "getAPIKeys", Summary: "Get the API keys of the authenticated user", Tag: "API Keys", Status: http.StatusOK, Response: model.APIKeys{}},
+	{Method: http.MethodPost, Path: "/api-keys", OperationID: "createAPIKey", Summary: "Create an API key", Tag: "API Keys", Request: model.APIKeyCreationRequest{}, Status: http.StatusCreated, Response: model.APIKey{}},
+	{Method: http.MethodDelete, Path: "/api-keys/{keyID}", OperationID: "removeAPIKey", Summary: "Remove an API key", Tag: "API Keys", Status: http.StatusNoContent},
+	{Method: http.MethodGet, Path: "/sessions", OperationID: "getSessions", Summary: "Get the sessions of the authenticated user", Tag: "Sessions", Status: http.StatusOK, Response: model.UserSessions{}},
+	{Method: http.MethodDelete, Path: "/sessions", OperationID: "removeSessions", Summary: "Remove all sessions", Tag: "Sessions", Status: http.StatusNoContent},
+	{Method: http.MethodDelete, Path: "/sessions/{sessionID}", OperationID: "removeSession", Summary: "Remove a session", Tag: "Sessions", Status: http.StatusNoContent},
+	{Method: http.MethodGet, Path: "/integrations", OperationID: "getIntegrations", Summary: "Get the integration settings", Tag: "Integrations", Status: http.StatusOK, Response: model.Integration{}},
+	{Method: http.MethodPut, Path: "/integrations", OperationID: "updateIntegrations", Summary: "Update the integration settings", Tag: "Integrations", Request: model.IntegrationModificationRequest{}, Status: http.StatusCreated, Response: model.Integration{}},
+	{Method: http.MethodPost, Path: "/categories", OperationID: "createCategory", Summary: "Create a category", Tag: "Categories", Request: model.CategoryRequest{}, Status: http.StatusCreated, Response: model.Category{}},
+	{Method: http.MethodGet, Path: "/categories", OperationID: "getCategories", Summary: "Get all categories", Tag: "Categories", Status: http.StatusOK, Response: model.Categories{}, Conditional: true},
+	{Method: http.MethodPut, Path: "/categories/{categoryID}", OperationID: "updateCategory", Summary: "Update a category", Tag: "Categories", Request: model.CategoryRequest{}, Status: http.StatusCreated, Response: model.Category{}},
+	{Method: http.MethodDelete, Path: "/categories/{categoryID}", OperationID: "removeCategory", Summary: "Remove a category", Tag: "Categories", Status: http.StatusNoContent},
+	{Method: http.MethodPut, Path: "/categories/{categoryID}/mark-all-as-read", OperationID: "markCategoryAsRead", Summary: "Mark all entries of a category as read", Tag: "Categories", Status: http.StatusNoContent},
+	{Method: http.MethodGet, Path: "/categories/{categoryID}/feeds", OperationID: "getCategoryFeeds", Summary: "Get the feeds of a category", Tag: "Categories", Status: http.StatusOK, Response: model.Feeds{}, Conditional: true},
+	{Method: http.MethodPut, Path: "/categories/{categoryID}/refresh", OperationID: "refreshCategory", Summary: "Refresh the feeds of a category", Tag: "Categories", Status: http.StatusNoContent},
+	{Method: http.MethodGet, Path: "/categories/{categoryID}/entries", OperationID: "getCategoryEntries", Summary: "Get the entries of a category", Tag: "Entries", Parameters: entryQueryParameters, Status: http.StatusOK, Response: entriesResponse{}, Conditional: true},
+	{Method: http.MethodGet, Path: "/categories/{categoryID}/entries/{entryID}", OperationID: "getCategoryEntry", Summary: "Get an entry of a category", Tag: "Entries", Status: http.StatusOK, Response: model.Entry{}},
+	{Method: http.MethodPost, Path: "/labels", OperationID: "createLabel", Summary: "Create a label", Tag: "Labels", Request: model.LabelRequest{}, Status: http.StatusCreated, Response: model.Label{}},
+	{Method: http.MethodGet, Path: "/labels", OperationID: "getLabels", Summary: "Get all labels", Tag: "Labels", Status: http.StatusOK, Response: model.Labels{}},
+	{Method: http.MethodPut, Path: "/labels/{labelID}", OperationID: "updateLabel", Summary: "Update a label", Tag: "Labels", Request: model.LabelRequest{}, Status: http.StatusCreated, Response: model.Label{}},
+	{Method: http.MethodDelete, Path: "/labels/{labelID}", OperationID: "removeLabel", Summary: "Remove a label", Tag: "Labels", Status: http.StatusNoContent},
+	{Method: http.MethodGet, Path: "/labels/{labelID}/entries", OperationID: "getLabelEntries", Summary: "Get the entries of a label", Tag: "Entries", Parameters: entryQueryParameters, Status: http.StatusOK, Response: entriesResponse{}, Conditional: true},
+	{Method: http.MethodPost, Path: "/discover", OperationID: "discoverSubscriptions", Summary: "Discover the feeds of a website", Tag: "Feeds", Request: model.SubscriptionDiscoveryRequest{}, Status: http.StatusOK, Response: subscription.Subscriptions{}},
+	{Method: http.MethodPost, Path: "/feeds", OperationID: "createFeed", Summary: "Subscribe to a feed", Tag: "Feeds", Request: model.FeedCreationRequest{}, Status: http.StatusCreated, Response: feedCreationResponse{}},
+	{Method: http.MethodGet, Path: "/feeds", OperationID: "getFeeds", Summary: "Get all feeds", Tag: "Feeds", Status: http.StatusOK, Response: model.Feeds{}, Conditional: true},
+	{Method: http.MethodGet, Path: "/feeds/counters", OperationID: "getFeedCounters", Summary: "Get the read and unread counters of each feed", Tag: "Feeds", Status: http.StatusOK, Response: model.FeedCounters{}, Conditional: true},
+	{Method: http.MethodPut, Path: "/feeds/refresh", OperationID: "refreshAllFeeds", Summary: "Refresh all feeds", Tag: "Feeds", Status: http.StatusNoContent},
+	{Method: http.MethodPut, Path: "/feeds/{feedID}/refresh", OperationID: "refreshFeed", Summary: "Refresh a feed", Tag: "Feeds", Status: http.StatusNoContent},
+	{Method: http.MethodGet, Path: "/feeds/{feedID}", OperationID: "getFeed", Summary: "Get a feed", Tag: "Feeds", Status: http.StatusOK, Response: model.Feed{}},
+	{Method: http.MethodPut, Path: "/feeds/{feedID}", OperationID: "updateFeed", Summary: "Update a feed", Tag: "Feeds", Request: model.FeedModificationRequest{}, Status: http.StatusCreated, Response: model.Feed{}},
+	{Method: http.MethodDelete, Path: "/feeds/{feedID}", OperationID: "removeFeed", Summary: "Unsubscribe from a feed", Tag: "Feeds", Status: http.StatusNoContent},
+	{Method: http.MethodGet, Path: "/feeds/{feedID}/icon", OperationID: "getFeedIcon", Summary: "Get the icon of a feed", Tag: "Feeds", Status: http.StatusOK, Response: feedIconResponse{}},
+	{Method: http.MethodGet, Path: "/icons/{iconID}", OperationID: "getIcon", Summary: "Get an icon", Tag: "Feeds", Status: http.StatusOK, Response: feedIconResponse{}},
+	{Method: http.MethodPut, Path: "/feeds/{feedID}/mark-all-as-read", OperationID: "markFeedAsRead", Summary: "Mark all entries of a feed as read", Tag: "Feeds", Status: http.StatusNoContent},
+	{Method: http.MethodGet, Path: "/export", OperationID: "exportFeeds", Summary: "Export the subscriptions as OPML", Tag: "OPML", Status: http.StatusOK, Response: "", ResponseContentType: contentTypeXML},
+	{Method: http.MethodPost, Path: "/import", OperationID: "importFeeds", Summary: "Import an OPML file", Tag: "OPML", Request: "", RequestContentType: contentTypeXML, Status: http.StatusCreated, Response: importResponse{}},
+	{Method: http.MethodGet, Path: "/feeds/{feedID}/entries", OperationID: "getFeedEntries", Summary: "Get the entries of a feed", Tag: "Entries", Parameters: entryQueryParameters, Status: http.StatusOK, Response: entriesResponse{}, Conditional: true},
+	{Method: http.MethodGet, Path: "/feeds/{feedID}/entries/{entryID}", OperationID: "getFeedEntry", Summary: "Get an entry of a feed", Tag: "Entries", Status: http.StatusOK, Response: model.Entry{}},
+	{Method: http.MethodGet, Path: "/entries", OperationID: "getEntries", Summary: "Get entries", Tag: "Entries", Parameters: entryQueryParameters, Status: http.StatusOK, Response: entriesResponse{}, Conditional: true},
+	{Method: http.MethodPut, Path: "/entries", OperationID: "updateEntriesStatus", Summary: "Change the status of entries", Tag: "Entries", Request: model.EntriesStatusUpdateRequest{}, Status: http.StatusNoContent},
+	{Method: http.MethodGet, Path: "/entries/{entryID}", OperationID: "getEntry", Summary: "Get an entry", Tag: "Entries", Status: http.StatusOK, Response: model.Entry{}},
+	{Method: http.MethodPut, Path: "/entries/{entryID}/bookmark", OperationID: "toggleBookmark", Summary: "Toggle the bookmark state of an entry", Tag: "Entries", Status: http.StatusNoContent},
+	{Method: http.MethodPut, Path: "/entries/{entryID}/labels", OperationID: "updateEntryLabels", Summary: "Replace the labels of an entry", Tag: "Labels", Request: model.EntryLabelsRequest{}, Status: http.StatusNoContent},
+	{Method: http.MethodGet, Path: "/entries/{entryID}/annotations", OperationID: "getEntryAnnotations", Summary: "Get the annotations of an entry", Tag: "Annotations", Status: http.StatusOK, Response: model.Annotations{}},
+	{Method: http.MethodPost, Path: "/entries/{entryID}/annotations", OperationID: "createAnnotation", Summary: "Annotate an entry", Tag: "Annotations", Request: model.AnnotationRequest{}, Status: http.StatusCreated, Response: model.Annotation{}},
+	{Method: http.MethodGet, Path: "/entries/{entryID}/revisions", OperationID: "getEntryRevisions", Summary: "Get the revisions of an entry", Tag: "Revisions", Status: http.StatusOK, Response: model.EntryRevisions{}},
+	{Method: http.MethodGet, Path: "/entries/{entryID}/revisions/{revisionID}/diff", OperationID: "getEntryRevisionDiff", Summary: "Compare a revision with the version that replaced it", Tag: "Revisions", Status: http.StatusOK, Response: model.EntryRevisionDiff{}},
+	{Method: http.MethodGet, Path: "/annotations", OperationID: "getAnnotations", Summary: "Get all annotations", Tag: "Annotations", Parameters: annotationQueryParameters, Status: http.StatusOK, Response: model.Annotations{}},
+	{Method: http.MethodGet, Path: "/annotations/export", OperationID: "exportAnnotations", Summary: "Export the annotations as Markdown", Tag: "Annotations", Parameters: annotationQueryParameters, Status: http.StatusOK, Response: "", ResponseContentType: contentTypeMarkdown},
+	{Method: http.MethodPut, Path: "/annotations/{annotationID}", OperationID: "updateAnnotation", Summary: "Update the note of an annotation", Tag: "Annotations", Request: model.AnnotationModificationRequest{}, Status: http.StatusCreated, Response: model.Annotation{}},
+	{Method: http.MethodDelete, Path: "/annotations/{annotationID}", OperationID: "removeAnnotation", Summary: "Remove an annotation", Tag: "Annotations", Status: http.StatusNoContent},
+	{Method: http.MethodGet, Path: "/entries/{entryID}/fetch-content", OperationID: "fetchEntryContent", Summary: "Fetch the original content of an entry", Tag: "Entries", Status: http.StatusOK, Response: entryContentResponse{}},
+	{Method: http.MethodGet, Path: "/events", OperationID: "streamEvents", Summary: "Stream counters and entry changes as Server-Sent Events", Tag: "Events", Status: http.StatusOK, Response: "", ResponseContentType: contentTypeEventStream},
+	{Method: http.MethodGet, Path: "/openapi.json", OperationID: "getOpenAPISpecification", Summary: "Get the OpenAPI specification of this API", Tag: "Specification", Status: http.StatusOK, Response: map[string]interface{}{}},
+}
+
+type openAPIDocument struct {
+	OpenAPI    string                                      `json:"openapi"`
+	Info       *openAPIInfo                                `json:"info"`
+	Servers    []*openAPIServer                            `json:"servers"`
+	Security   []map[string][]string                       `json:"security"`
+	Paths      map[string]map[string]*openAPIPathOperation `json:"paths"`
+	Components *openAPIComponents                          `json:"components"`
+}
+
+type openAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type openAPIServer struct {
+	URL string `json:"url"`
+}
+
+type openAPIPathOperation struct {
+	OperationID string                      `json:"operationId"`
+	Summary     string                      `json:"summary"`
+	Tags        []string                    `json:"tags"`
+	Parameters  []*openAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *openAPIRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*openAPIResponse `json:"responses"`
+}
+
+type openAPIParameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required,omitempty"`
+	Schema      *openAPISchema `json:"schema"`
+}
+
+type openAPIRequestBody struct {
+	Required bool                         `json:"required"`
+	Content  map[string]*openAPIMediaType `json:"content"`
+}
+
+type openAPIResponse struct {
+	Description string                       `json:"description"`
+	Content     map[string]*openAPIMediaType `json:"content,omitempty"`
+}
+
+type openAPIMediaType struct {
+	Schema *openAPISchema `json:"schema"`
+}
+
+type openAPIComponents struct {
+	Schemas         map[string]*openAPISchema         `json:"schemas"`
+	SecuritySchemes map[string]*openAPISecurityScheme `json:"securitySchemes"`
+}
+
+type openAPISecurityScheme struct {
+	Type   string `json:"type"`
+	Scheme string `json:"scheme,omitempty"`
+	In     string `json:"in,omitempty"`
+	Name   string `json:"name,omitempty"`
+}
+
+type openAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	AllOf                []*openAPISchema          `json:"allOf,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Enum                 []string                  `json:"enum,omitempty"`
+	Nullable             bool                      `json:"nullable,omitempty"`
+	Items                *openAPISchema            `json:"items,omitempty"`
+	Properties           map[string]*openAPISchema `json:"properties,omitempty"`
+	Required             []string                  `json:"required,omitempty"`
+	AdditionalProperties *openAPISchema            `json:"additionalProperties,omitempty"`
+}
+
+var routeVariablePattern = regexp.MustCompile(`{([^}:]+)(:[^}]+)?}`)
+
+func newQueryParameter(name, description string, schema *openAPISchema) *openAPIParameter {
+	return &openAPIParameter{Name: name, In: "query", Description: description, Schema: schema}
+}
+
+// newPathParameter returns the parameter of a route variable, route variables are IDs unless declared otherwise.
+func newPathParameter(name string) *openAPIParameter {
+	return &openAPIParameter{Name: name, In: "path", Required: true, Schema: int64Schema}
+}
+
+func (r *openAPIRoute) declaresParameter(name, location string) bool {
+	for _, parameter := range r.Parameters {
+		if parameter.Name == name && parameter.In == location {
+			return true
+		}
+	}
+	return false
+}
+
+// openAPIPath converts a route template to an OpenAPI path, regular expressions are removed from variables.
+func openAPIPath(template string) string {
+	return routeVariablePattern.ReplaceAllString(template, "{$1}")
+}
+
+func (h *handler) openAPISpec(w http.ResponseWriter, r *http.Request) {
+	serverURL := strings.TrimSuffix(r.URL.Path, "/openapi.json")
+	json.OK(w, r, newOpenAPIDocument(serverURL))
+}
+
+// newOpenAPIDocument builds the specification of the REST API, schemas are generated from the Go types.
+func newOpenAPIDocument(serverURL string) *openAPIDocument {
+	generator := newOpenAPISchemaGenerator()
+	errorSchema := generator.schema(reflect.TypeOf(errorResponse{}), true)
+
+	document := &openAPIDocument{
+		OpenAPI: openAPIVersion,
+		Info:    &openAPIInfo{Title: "Miniflux API", Version: version.Version},
+		Servers: []*openAPIServer{{URL: serverURL}},
+		Security: []map[string][]string{
+			{"apiKeyAuth": {}},
+			{"basicAuth": {}},
+		},
+		Paths: make(map[string]map[string]*openAPIPathOperation),
+		Components: &openAPIComponents{
+			Schemas: generator.schemas,
+			SecuritySchemes: map[string]*openAPISecurityScheme{
+				"apiKeyAuth": {Type: "apiKey", In: "header", Name: "X-Auth-Token"},
+				"basicAuth":  {Type: "http", Scheme: "basic"},
+			},
+		},
+	}
+
+	for _, route := range openAPIRoutes {
+		operation := &openAPIPathOperation{
+			OperationID: route.OperationID,
+			Summary:     route.Summary,
+			Tags:        []string{route.Tag},
+			Responses: map[string]*openAPIResponse{
+				"default": {
+					Description: "Error",
+					Content:     map[string]*openAPIMediaType{contentTypeJSON: {Schema: errorSchema}},
+				},
+			},
+		}
+
+		for _, match := range routeVariablePattern.FindAllStringSubmatch(route.Path, -1) {
+			if !route.declaresParameter(match[1], "path") {
+				operation.Parameters = append(operation.Parameters, newPathParameter(match[1]))
+			}
+		}
+		operation.Parameters = append(operation.Parameters, route.Parameters...)
+		if route.Conditional {
+			operation.Parameters = append(operation.Parameters, conditionalParameters...)
+			operation.Responses[strconv.Itoa(http.StatusNotModified)] = &openAPIResponse{Description: http.StatusText(http.StatusNotModified)}
+		}
+
+		if route.Request != nil {
+			contentType := route.RequestContentType
+			if contentType == "" {
+				contentType = contentTypeJSON
+			}
+
+			operation.RequestBody = &openAPIRequestBody{
+				Required: true,
+				Content: map[string]*openAPIMediaType{
+					contentType: {Schema: generator.schema(reflect.TypeOf(route.Request), false)},
+				},
+			}
+		}
+
+		response := &openAPIResponse{Description: http.StatusText(route.Status)}
+		if route.Response != nil {
+			contentType := route.ResponseContentType
+			if contentType == "" {
+				contentType = contentTypeJSON
+			}
+
+			response.Content = map[string]*openAPIMediaType{
+				contentType: {Schema: generator.schema(reflect.TypeOf(route.Response), true)},
+			}
+		}
+		operation.Responses[strconv.Itoa(route.Status)] = response
+
+		path := openAPIPath(route.Path)
+		if _, found := document.Paths[path]; !found {
+			document.Paths[path] = make(map[string]*openAPIPathOperation)
+		}
+		document.Paths[path][strings.ToLower(route.Method)] = operation
+	}
+
+	return document
+}
+
+// openAPISchemaGenerator converts Go types to OpenAPI schemas by following the encoding/json rules.
+// Structs are declared as components and referenced by name.
+type openAPISchemaGenerator struct {
+	schemas map[string]*openAPISchema
+	names   map[reflect.Type]string
+}
+
+func newOpenAPISchemaGenerator() *openAPISchemaGenerator {
+	return &openAPISchemaGenerator{
+		schemas: make(map[string]*openAPISchema),
+		names:   make(map[reflect.Type]string),
+	}
+}
+
+// schema returns the schema of the given type.
+// Fields without the omitempty option are required when the type is used in responses.
+func (g *openAPISchemaGenerator) schema(t reflect.Type, response bool) *openAPISchema {
+	if t == reflect.TypeOf(time.Time{}) {
+		return &openAPISchema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		schema := g.schema(t.Elem(), response)
+		if schema.Ref != "" {
+			return &openAPISchema{AllOf: []*openAPISchema{schema}, Nullable: true}
+		}
+		schema.Nullable = true
+		return schema
+	case reflect.Bool:
+		return &openAPISchema{Type: "boolean"}
+	case reflect.Int64, reflect.Uint64:
+		return &openAPISchema{Type: "integer", Format: "int64"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &openAPISchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &openAPISchema{Type: "number"}
+	case reflect.String:
+		return &openAPISchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &openAPISchema{Type: "string", Format: "byte"}
+		}
+		// Nil slices are encoded as null.
+		return &openAPISchema{Type: "array", Items: g.schema(t.Elem(), response), Nullable: t.Kind() == reflect.Slice}
+	case reflect.Map:
+		return &openAPISchema{Type: "object", AdditionalProperties: g.schema(t.Elem(), response), Nullable: true}
+	case reflect.Struct:
+		return &openAPISchema{Ref: "#/components/schemas/" + g.component(t, response)}
+	default:
+		return &openAPISchema{}
+	}
+}
+
+func (g *openAPISchemaGenerator) component(t reflect.Type, response bool) string {
+	if name, found := g.names[t]; found {
+		return name
+	}
+
+	name := strings.ToUpper(t.Name()[:1]) + t.Name()[1:]
+	if _, found := g.schemas[name]; found {
+		pkg := t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:]
+		name = strings.ToUpper(pkg[:1]) + pkg[1:] + name
+	}
+
+	// The name is reserved before visiting the fields to support recursive types.
+	schema := &openAPISchema{Type: "object", Properties: make(map[string]*openAPISchema)}
+	g.names[t] = name
+	g.schemas[name] = schema
+	g.addProperties(schema, t, response)
+
+	return name
+}
+
+func (g *openAPISchemaGenerator) addProperties(schema *openAPISchema, t reflect.Type, response bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			fieldType := field.Type
+			if fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+			}
+			if fieldType.Kind() == reflect.Struct {
+				g.addProperties(schema, fieldType, response)
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		schema.Properties[name] = g.schema(field.Type, response)
+		if response && !strings.Contains(options, "omitempty") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
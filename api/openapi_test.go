@@ -0,0 +1,592 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package api // import "miniflux.app/api"
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"miniflux.app/model"
+	"miniflux.app/storage"
+	"miniflux.app/storage/storagetest"
+
+	"github.com/gorilla/mux"
+)
+
+// These routes fetch remote resources or never end, their responses are not validated.
+var openAPIUnvalidatedOperations = map[string]bool{
+	"createFeed":            true,
+	"discoverSubscriptions": true,
+	"refreshAllFeeds":       true,
+	"refreshFeed":           true,
+	"refreshCategory":       true,
+	"fetchEntryContent":     true,
+	"streamEvents":          true,
+}
+
+func TestOpenAPIPath(t *testing.T) {
+	scenarios := map[string]string{
+		"/users":                       "/users",
+		"/users/{userID:[0-9]+}":       "/users/{userID}",
+		"/users/{userID:[0-9]+}/{key}": "/users/{userID}/{key}",
+	}
+
+	for input, expected := range scenarios {
+		if result := openAPIPath(input); result != expected {
+			t.Errorf(`Unexpected path for %q: got %q instead of %q`, input, result, expected)
+		}
+	}
+}
+
+func TestOpenAPIDocumentsEveryRoute(t *testing.T) {
+	router := mux.NewRouter()
+	Serve(router, nil, nil)
+
+	document := newOpenAPIDocument("/v1")
+	documented := make(map[string]bool)
+	for path, operations := range document.Paths {
+		for method := range operations {
+			documented[routeSignature(method, path)] = true
+		}
+	}
+
+	declared := make(map[string]bool)
+	err := router.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		template, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+
+		methods, err := route.GetMethods()
+		if err != nil {
+			return nil
+		}
+
+		for _, method := range methods {
+			if method == http.MethodOptions {
+				continue
+			}
+
+			signature := routeSignature(method, openAPIPath(strings.TrimPrefix(template, "/v1")))
+			declared[signature] = true
+			if !documented[signature] {
+				t.Errorf(`The route %s %s is not documented`, method, template)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for signature := range documented {
+		if !declared[signature] {
+			t.Errorf(`The documented route %s is not declared`, signature)
+		}
+	}
+}
+
+func TestOpenAPIOperationIDsAreUnique(t *testing.T) {
+	operationIDs := make(map[string]bool)
+	for _, route := range openAPIRoutes {
+		if operationIDs[route.OperationID] {
+			t.Errorf(`Duplicate operation ID: %s`, route.OperationID)
+		}
+		operationIDs[route.OperationID] = true
+	}
+}
+
+func TestOpenAPISchemaGenerator(t *testing.T) {
+	generator := newOpenAPISchemaGenerator()
+	schema := generator.schema(reflect.TypeOf(model.Entries{}), true)
+
+	if schema.Type != "array" || schema.Items == nil || schema.Items.AllOf[0].Ref != "#/components/schemas/Entry" {
+		t.Fatalf(`Unexpected schema: %+v`, schema)
+	}
+
+	entry := generator.schemas["Entry"]
+	if entry == nil {
+		t.Fatal(`The Entry schema is missing`)
+	}
+
+	if _, found := entry.Properties["published_at"]; !found {
+		t.Error(`The property published_at is missing`)
+	}
+
+	if entry.Properties["published_at"].Format != "date-time" {
+		t.Errorf(`Unexpected published_at schema: %+v`, entry.Properties["published_at"])
+	}
+
+	if entry.Properties["feed"].AllOf[0].Ref != "#/components/schemas/Feed" || !entry.Properties["feed"].Nullable {
+		t.Errorf(`Unexpected feed schema: %+v`, entry.Properties["feed"])
+	}
+
+	for _, name := range entry.Required {
+		if name == "feed" || name == "snippet" {
+			t.Errorf(`The property %s is omitted when empty and should not be required`, name)
+		}
+	}
+
+	if _, found := generator.schemas["Feed"].Properties["unread_count"]; found {
+		t.Error(`Properties ignored by encoding/json should not be documented`)
+	}
+
+	request := generator.schema(reflect.TypeOf(model.UserModificationRequest{}), false)
+	if len(generator.schemas["UserModificationRequest"].Required) != 0 || request.Ref == "" {
+		t.Errorf(`Request schemas should not have required properties: %+v`, generator.schemas["UserModificationRequest"])
+	}
+}
+
+func TestOpenAPIResponsesMatchSchemas(t *testing.T) {
+	store := storagetest.NewStorage(t)
+	admin, feed, entries := createTestFeed(t, store)
+
+	router := mux.NewRouter()
+	Serve(router, store, nil)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	contract := newOpenAPIContract(t, store, admin.ID, server.URL)
+
+	var user model.User
+	contract.decode(contract.call(http.MethodGet, "/me", ""), &user)
+	if user.ID != admin.ID {
+		t.Fatalf(`Unexpected user: %+v`, user)
+	}
+
+	var otherUser model.User
+	contract.decode(contract.call(http.MethodPost, "/users", `{"username": "bob", "password": "test456"}`), &otherUser)
+	contract.call(http.MethodGet, "/users", "")
+	contract.call(http.MethodGet, fmt.Sprintf("/users/%d", otherUser.ID), "")
+	contract.call(http.MethodGet, "/users/bob", "")
+	contract.call(http.MethodPut, fmt.Sprintf("/users/%d", otherUser.ID), `{"theme": "dark_serif"}`)
+
+	var apiKey model.APIKey
+	contract.decode(contract.call(http.MethodPost, fmt.Sprintf("/users/%d/api-keys", otherUser.ID), `{"description": "Reader"}`), &apiKey)
+	contract.call(http.MethodGet, fmt.Sprintf("/users/%d/api-keys", otherUser.ID), "")
+	contract.call(http.MethodDelete, fmt.Sprintf("/users/%d/api-keys/%d", otherUser.ID, apiKey.ID), "")
+
+	contract.decode(contract.call(http.MethodPost, "/api-keys", `{"description": "Script"}`), &apiKey)
+	contract.call(http.MethodGet, "/api-keys", "")
+	contract.call(http.MethodDelete, fmt.Sprintf("/api-keys/%d", apiKey.ID), "")
+
+	for _, username := range []string{"admin", "bob"} {
+		if _, _, err := store.CreateUserSessionFromUsername(username, "Test", "127.0.0.1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var sessions model.UserSessions
+	contract.decode(contract.call(http.MethodGet, fmt.Sprintf("/users/%d/sessions", otherUser.ID), ""), &sessions)
+	contract.call(http.MethodDelete, fmt.Sprintf("/users/%d/sessions/%d", otherUser.ID, sessions[0].ID), "")
+	contract.call(http.MethodDelete, fmt.Sprintf("/users/%d/sessions", otherUser.ID), "")
+
+	contract.decode(contract.call(http.MethodGet, "/sessions", ""), &sessions)
+	contract.call(http.MethodDelete, fmt.Sprintf("/sessions/%d", sessions[0].ID), "")
+	contract.call(http.MethodDelete, "/sessions", "")
+
+	contract.call(http.MethodGet, "/integrations", "")
+	contract.call(http.MethodPut, "/integrations", `{"pinboard_tags": "miniflux"}`)
+
+	var category model.Category
+	contract.decode(contract.call(http.MethodPost, "/categories", `{"title": "News"}`), &category)
+	contract.call(http.MethodGet, "/categories", "")
+	contract.call(http.MethodPut, fmt.Sprintf("/categories/%d", category.ID), `{"title": "World News"}`)
+	contract.call(http.MethodPut, fmt.Sprintf("/categories/%d/mark-all-as-read", category.ID), "")
+	contract.call(http.MethodGet, fmt.Sprintf("/categories/%d/feeds", feed.Category.ID), "")
+	contract.call(http.MethodGet, fmt.Sprintf("/categories/%d/entries?status=unread&direction=asc", feed.Category.ID), "")
+	contract.call(http.MethodGet, fmt.Sprintf("/categories/%d/entries/%d", feed.Category.ID, entries[0].ID), "")
+
+	var label model.Label
+	contract.decode(contract.call(http.MethodPost, "/labels", `{"title": "Later"}`), &label)
+	contract.call(http.MethodGet, "/labels", "")
+	contract.call(http.MethodPut, fmt.Sprintf("/labels/%d", label.ID), `{"title": "Read later"}`)
+	contract.call(http.MethodPut, fmt.Sprintf("/entries/%d/labels", entries[0].ID), `{"labels": ["Read later"]}`)
+	contract.call(http.MethodGet, fmt.Sprintf("/labels/%d/entries", label.ID), "")
+
+	contract.call(http.MethodGet, "/feeds", "")
+	contract.call(http.MethodGet, "/feeds/counters", "")
+	contract.call(http.MethodGet, fmt.Sprintf("/feeds/%d", feed.ID), "")
+	contract.call(http.MethodPut, fmt.Sprintf("/feeds/%d", feed.ID), `{"title": "Renamed"}`)
+	var icon feedIconResponse
+	contract.decode(contract.call(http.MethodGet, fmt.Sprintf("/feeds/%d/icon", feed.ID), ""), &icon)
+	contract.call(http.MethodGet, fmt.Sprintf("/icons/%d", icon.ID), "")
+	contract.call(http.MethodGet, fmt.Sprintf("/feeds/%d/entries?limit=1", feed.ID), "")
+	contract.call(http.MethodGet, fmt.Sprintf("/feeds/%d/entries/%d", feed.ID, entries[0].ID), "")
+
+	contract.call(http.MethodGet, "/entries?starred=false&order=id", "")
+	contract.call(http.MethodGet, fmt.Sprintf("/entries/%d", entries[0].ID), "")
+	contract.call(http.MethodPut, fmt.Sprintf("/entries/%d/bookmark", entries[0].ID), "")
+	contract.call(http.MethodPut, "/entries", fmt.Sprintf(`{"entry_ids": [%d], "status": "read"}`, entries[1].ID))
+
+	var annotation model.Annotation
+	contract.decode(contract.call(http.MethodPost, fmt.Sprintf("/entries/%d/annotations", entries[0].ID), `{"quote": "Plant", "start_offset": 0, "end_offset": 5, "note": "Soon"}`), &annotation)
+	contract.call(http.MethodGet, fmt.Sprintf("/entries/%d/annotations", entries[0].ID), "")
+	contract.call(http.MethodGet, "/annotations?search=plant", "")
+	contract.call(http.MethodGet, "/annotations/export", "")
+	contract.call(http.MethodPut, fmt.Sprintf("/annotations/%d", annotation.ID), `{"note": "In April"}`)
+	contract.call(http.MethodDelete, fmt.Sprintf("/annotations/%d", annotation.ID), "")
+
+	var revisions model.EntryRevisions
+	contract.decode(contract.call(http.MethodGet, fmt.Sprintf("/entries/%d/revisions", entries[0].ID), ""), &revisions)
+	if len(revisions) == 0 {
+		t.Fatal(`The entry should have a revision`)
+	}
+	contract.call(http.MethodGet, fmt.Sprintf("/entries/%d/revisions/%d/diff", entries[0].ID, revisions[0].ID), "")
+
+	contract.call(http.MethodGet, "/export", "")
+	contract.call(http.MethodPost, "/import", `<?xml version="1.0" encoding="UTF-8"?>
+<opml version="2.0">
+	<body>
+		<outline text="Blogs">
+			<outline title="Blog" text="Blog" xmlUrl="https://example.org/blog.xml" htmlUrl="https://example.org/blog"></outline>
+		</outline>
+	</body>
+</opml>`)
+
+	contract.call(http.MethodPut, fmt.Sprintf("/feeds/%d/mark-all-as-read", feed.ID), "")
+	contract.call(http.MethodDelete, fmt.Sprintf("/labels/%d", label.ID), "")
+	contract.call(http.MethodDelete, fmt.Sprintf("/feeds/%d", feed.ID), "")
+	contract.call(http.MethodDelete, fmt.Sprintf("/categories/%d", category.ID), "")
+	contract.call(http.MethodDelete, fmt.Sprintf("/users/%d", otherUser.ID), "")
+	contract.call(http.MethodPut, fmt.Sprintf("/users/%d/mark-all-as-read", admin.ID), "")
+
+	for _, route := range openAPIRoutes {
+		if !contract.exercised[route.OperationID] && !openAPIUnvalidatedOperations[route.OperationID] {
+			t.Errorf(`The response of the operation %s has not been validated`, route.OperationID)
+		}
+	}
+}
+
+func TestOpenAPIErrorResponsesMatchSchema(t *testing.T) {
+	store := storagetest.NewStorage(t)
+	admin, _, _ := createTestFeed(t, store)
+
+	router := mux.NewRouter()
+	Serve(router, store, nil)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	contract := newOpenAPIContract(t, store, admin.ID, server.URL)
+	contract.callError(http.MethodGet, "/feeds/9999", "", http.StatusNotFound)
+	contract.callError(http.MethodPost, "/categories", `{"title": ""}`, http.StatusBadRequest)
+	contract.callError(http.MethodGet, "/entries?direction=sideways", "", http.StatusBadRequest)
+}
+
+// openAPIContract sends requests to the API and validates the responses against the served specification.
+type openAPIContract struct {
+	t         *testing.T
+	baseURL   string
+	token     string
+	document  *openAPIDocument
+	exercised map[string]bool
+
+	lastContentType string
+}
+
+func newOpenAPIContract(t *testing.T, store *storage.Storage, userID int64, serverURL string) *openAPIContract {
+	t.Helper()
+
+	// API keys are used because checking passwords for every request is slow.
+	apiKey := model.NewAPIKey(userID, "OpenAPI")
+	if err := store.CreateAPIKey(apiKey); err != nil {
+		t.Fatal(err)
+	}
+
+	contract := &openAPIContract{t: t, baseURL: serverURL, token: apiKey.Token, exercised: make(map[string]bool)}
+
+	// The specification is validated against itself once it has been downloaded.
+	body := contract.send(http.MethodGet, "/v1/openapi.json", "", http.StatusOK)
+	contract.document = new(openAPIDocument)
+	contract.decode(body, contract.document)
+
+	if contract.document.OpenAPI != openAPIVersion || len(contract.document.Servers) != 1 || contract.document.Servers[0].URL != "/v1" {
+		t.Fatalf(`Unexpected document: %+v`, contract.document)
+	}
+
+	contract.validateResponse(http.MethodGet, "/openapi.json", http.StatusOK, contentTypeJSON, body)
+	contract.exercised["getOpenAPISpecification"] = true
+	return contract
+}
+
+func (c *openAPIContract) call(method, path, body string) []byte {
+	c.t.Helper()
+
+	template, operation := c.operation(method, path)
+	if operation == nil {
+		c.t.Fatalf(`No operation documented for %s %s`, method, path)
+	}
+
+	// Conditional requests are not sent, the response is never a 304.
+	expectedStatus := 0
+	for status := range operation.Responses {
+		if status != "default" && status != "304" {
+			fmt.Sscanf(status, "%d", &expectedStatus)
+		}
+	}
+
+	responseBody := c.send(method, c.document.Servers[0].URL+path, body, expectedStatus)
+	c.exercised[operation.OperationID] = true
+	c.validateResponse(method, template, expectedStatus, c.lastContentType, responseBody)
+	return responseBody
+}
+
+func (c *openAPIContract) callError(method, path, body string, expectedStatus int) {
+	c.t.Helper()
+
+	template, _ := c.operation(method, path)
+	responseBody := c.send(method, c.document.Servers[0].URL+path, body, expectedStatus)
+	c.validateResponse(method, template, expectedStatus, c.lastContentType, responseBody)
+}
+
+func (c *openAPIContract) send(method, path, body string, expectedStatus int) []byte {
+	c.t.Helper()
+
+	request, err := http.NewRequest(method, c.baseURL+path, bytes.NewBufferString(body))
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	request.Header.Set("X-Auth-Token", c.token)
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	defer response.Body.Close()
+
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+
+	if response.StatusCode != expectedStatus {
+		c.t.Fatalf(`Unexpected status code for %s %s: got %d instead of %d: %s`, method, path, response.StatusCode, expectedStatus, responseBody)
+	}
+
+	c.lastContentType, _, _ = mime.ParseMediaType(response.Header.Get("Content-Type"))
+	return responseBody
+}
+
+func (c *openAPIContract) decode(body []byte, v interface{}) {
+	c.t.Helper()
+
+	if err := json.Unmarshal(body, v); err != nil {
+		c.t.Fatalf(`Unable to decode response: %v: %s`, err, body)
+	}
+}
+
+// operation returns the documented operation matching the request, literal path segments take precedence over variables.
+func (c *openAPIContract) operation(method, path string) (string, *openAPIPathOperation) {
+	path, _, _ = strings.Cut(path, "?")
+
+	var template string
+	var operation *openAPIPathOperation
+	for candidate, operations := range c.document.Paths {
+		literals := strings.Split(routeVariablePattern.ReplaceAllString(candidate, "{}"), "{}")
+		for i := range literals {
+			literals[i] = regexp.QuoteMeta(literals[i])
+		}
+
+		pattern := regexp.MustCompile("^" + strings.Join(literals, "[^/]+") + "$")
+		if !pattern.MatchString(path) || operations[strings.ToLower(method)] == nil {
+			continue
+		}
+
+		if operation == nil || strings.Count(candidate, "{") < strings.Count(template, "{") {
+			template, operation = candidate, operations[strings.ToLower(method)]
+		}
+	}
+
+	return template, operation
+}
+
+func (c *openAPIContract) validateResponse(method, template string, status int, contentType string, body []byte) {
+	c.t.Helper()
+
+	operation := c.document.Paths[template][strings.ToLower(method)]
+	response := operation.Responses[fmt.Sprint(status)]
+	if response == nil {
+		response = operation.Responses["default"]
+	}
+
+	if len(response.Content) == 0 {
+		if len(body) > 0 {
+			c.t.Errorf(`%s %s: unexpected body for status %d: %s`, method, template, status, body)
+		}
+		return
+	}
+
+	mediaType := response.Content[contentType]
+	if mediaType == nil {
+		c.t.Errorf(`%s %s: undocumented content type %q`, method, template, contentType)
+		return
+	}
+
+	if contentType != contentTypeJSON {
+		return
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		c.t.Errorf(`%s %s: invalid JSON response: %v`, method, template, err)
+		return
+	}
+
+	for _, err := range c.validate("$", mediaType.Schema, value) {
+		c.t.Errorf(`%s %s: %v`, method, template, err)
+	}
+}
+
+// validate checks a decoded JSON value against a schema.
+// Unlike the OpenAPI default, properties that are not documented are reported as errors.
+func (c *openAPIContract) validate(location string, schema *openAPISchema, value interface{}) []error {
+	if schema.Ref != "" {
+		name := strings.TrimPrefix(schema.Ref, "#/components/schemas/")
+		component, found := c.document.Components.Schemas[name]
+		if !found {
+			return []error{fmt.Errorf(`%s: unknown schema reference %q`, location, schema.Ref)}
+		}
+		return c.validate(location, component, value)
+	}
+
+	if value == nil {
+		if schema.Nullable || (schema.Type == "" && len(schema.AllOf) == 0) {
+			return nil
+		}
+		return []error{fmt.Errorf(`%s: null is not allowed`, location)}
+	}
+
+	var errs []error
+	for _, subschema := range schema.AllOf {
+		errs = append(errs, c.validate(location, subschema, value)...)
+	}
+
+	switch schema.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return append(errs, fmt.Errorf(`%s: expected an object, got %T`, location, value))
+		}
+
+		if schema.AdditionalProperties != nil {
+			for key, item := range object {
+				errs = append(errs, c.validate(location+"."+key, schema.AdditionalProperties, item)...)
+			}
+			return errs
+		}
+
+		for _, name := range schema.Required {
+			if _, found := object[name]; !found {
+				errs = append(errs, fmt.Errorf(`%s: missing required property %q`, location, name))
+			}
+		}
+
+		for key, item := range object {
+			property, found := schema.Properties[key]
+			if !found {
+				errs = append(errs, fmt.Errorf(`%s: undocumented property %q`, location, key))
+				continue
+			}
+			errs = append(errs, c.validate(location+"."+key, property, item)...)
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return append(errs, fmt.Errorf(`%s: expected an array, got %T`, location, value))
+		}
+
+		for i, item := range items {
+			errs = append(errs, c.validate(fmt.Sprintf("%s[%d]", location, i), schema.Items, item)...)
+		}
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			return append(errs, fmt.Errorf(`%s: expected a string, got %T`, location, value))
+		}
+
+		if schema.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339Nano, text); err != nil {
+				errs = append(errs, fmt.Errorf(`%s: invalid date-time %q`, location, text))
+			}
+		}
+
+		if len(schema.Enum) > 0 && !containsString(schema.Enum, text) {
+			errs = append(errs, fmt.Errorf(`%s: %q is not one of %v`, location, text, schema.Enum))
+		}
+	case "integer":
+		number, ok := value.(json.Number)
+		if !ok {
+			return append(errs, fmt.Errorf(`%s: expected an integer, got %T`, location, value))
+		}
+
+		if _, err := number.Int64(); err != nil {
+			errs = append(errs, fmt.Errorf(`%s: %s is not an integer`, location, number))
+		}
+	case "number":
+		if _, ok := value.(json.Number); !ok {
+			errs = append(errs, fmt.Errorf(`%s: expected a number, got %T`, location, value))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			errs = append(errs, fmt.Errorf(`%s: expected a boolean, got %T`, location, value))
+		}
+	}
+
+	return errs
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func routeSignature(method, path string) string {
+	return strings.ToUpper(method) + " " + routeVariablePattern.ReplaceAllString(path, "{}")
+}
+
+// createTestFeed creates an administrator with a feed, an icon and two entries, the first entry has a revision.
+func createTestFeed(t *testing.T, store *storage.Storage) (*model.User, *model.Feed, model.Entries) {
+	t.Helper()
+
+	user := storagetest.CreateUser(t, store, true)
+	feed := storagetest.CreateFeed(t, store, user)
+
+	icon := &model.Icon{Hash: "icon", MimeType: "image/png", Content: []byte("png")}
+	if err := store.CreateFeedIcon(feed.ID, icon); err != nil {
+		t.Fatal(err)
+	}
+
+	newEntries := func(content string) model.Entries {
+		return model.Entries{
+			{Hash: "1", Title: "Gardening in spring", URL: "https://example.org/1", Date: time.Now().Add(-time.Hour), Content: content, Tags: []string{"garden"}},
+			{Hash: "2", Title: "Cooking pasta", URL: "https://example.org/2", Date: time.Now(), Content: "<p>Boil the water.</p>"},
+		}
+	}
+
+	storagetest.RefreshEntries(t, store, feed, newEntries("<p>Plant tomatoes.</p>"), false)
+	entries := storagetest.RefreshEntries(t, store, feed, newEntries("<p>Plant tomatoes after the last frost.</p>"), true)
+	if len(entries) != 2 {
+		t.Fatalf(`Unexpected entries: %+v`, entries)
+	}
+
+	return user, feed, entries
+}
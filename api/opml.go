@@ -33,5 +33,5 @@ func (h *handler) importFeeds(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	json.Created(w, r, map[string]string{"message": "Feeds imported successfully"})
+	json.Created(w, r, &importResponse{Message: "Feeds imported successfully"})
 }
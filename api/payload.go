@@ -15,10 +15,23 @@ type feedIconResponse struct {
 }
 
 type entriesResponse struct {
-	Total   int           `json:"total"`
-	Entries model.Entries `json:"entries"`
+	Total      int           `json:"total"`
+	Entries    model.Entries `json:"entries"`
+	NextCursor string        `json:"next_cursor,omitempty"`
 }
 
 type feedCreationResponse struct {
 	FeedID int64 `json:"feed_id"`
 }
+
+type entryContentResponse struct {
+	Content string `json:"content"`
+}
+
+type importResponse struct {
+	Message string `json:"message"`
+}
+
+type errorResponse struct {
+	ErrorMessage string `json:"error_message"`
+}
@@ -0,0 +1,138 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package api // import "miniflux.app/api"
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"miniflux.app/http/request"
+	"miniflux.app/model"
+
+	"github.com/gorilla/mux"
+)
+
+// Permissions required by the routes when the request is authenticated with a restricted API key.
+const (
+	// permissionAccount is only granted to keys without restrictions.
+	permissionAccount = iota
+	permissionRead
+	permissionEntriesWrite
+	permissionFeedsAdmin
+)
+
+// These routes are not bound to a category, their results are filtered by the handlers.
+var categoryFilteredRoutes = map[string]bool{
+	"GET /me":             true,
+	"GET /openapi.json":   true,
+	"GET /categories":     true,
+	"GET /feeds":          true,
+	"GET /feeds/counters": true,
+	"GET /entries":        true,
+	"PUT /entries":        true,
+}
+
+// routePermission returns the permission required by the route, the template is relative to the API prefix.
+func routePermission(method, template string) int {
+	switch {
+	case template == "/me" || template == "/openapi.json":
+		return permissionRead
+	case strings.HasSuffix(template, "/mark-all-as-read"):
+		return permissionEntriesWrite
+	case hasPathPrefix(template, "/users"), hasPathPrefix(template, "/api-keys"), hasPathPrefix(template, "/sessions"), hasPathPrefix(template, "/integrations"):
+		return permissionAccount
+	case strings.HasSuffix(template, "/fetch-content"):
+		// The original content replaces the content of the entry.
+		return permissionEntriesWrite
+	case method == http.MethodGet:
+		return permissionRead
+	case hasPathPrefix(template, "/entries"), hasPathPrefix(template, "/annotations"):
+		return permissionEntriesWrite
+	default:
+		return permissionFeedsAdmin
+	}
+}
+
+func hasPathPrefix(path, prefix string) bool {
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// isGrantedPermission returns true if the API key grants the permission.
+func isGrantedPermission(apiKey *model.APIKey, permission int) bool {
+	switch permission {
+	case permissionRead:
+		return true
+	case permissionEntriesWrite:
+		return apiKey.HasScope(model.APIKeyScopeEntriesWrite)
+	case permissionFeedsAdmin:
+		return apiKey.HasScope(model.APIKeyScopeFeedsAdmin)
+	default:
+		return !apiKey.IsRestricted()
+	}
+}
+
+// isAllowedByAPIKey checks the scopes and the categories of the API key against the matched route.
+func (m *middleware) isAllowedByAPIKey(r *http.Request, apiKey *model.APIKey) bool {
+	if !apiKey.IsRestricted() {
+		return true
+	}
+
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return false
+	}
+
+	template, err := route.GetPathTemplate()
+	if err != nil {
+		return false
+	}
+	template = openAPIPath(strings.TrimPrefix(template, "/v1"))
+
+	if !isGrantedPermission(apiKey, routePermission(r.Method, template)) {
+		return false
+	}
+
+	if len(apiKey.CategoryIDs) == 0 || categoryFilteredRoutes[r.Method+" "+template] {
+		return true
+	}
+
+	// Other routes must reference a category, a feed or an entry that belongs to an allowed category.
+	vars := mux.Vars(r)
+	resolved := false
+	for name, resolve := range map[string]func(id int64) int64{
+		"categoryID": func(id int64) int64 { return id },
+		"feedID":     func(id int64) int64 { return m.store.FeedCategoryID(apiKey.UserID, id) },
+		"entryID":    func(id int64) int64 { return m.store.EntryCategoryID(apiKey.UserID, id) },
+	} {
+		value, found := vars[name]
+		if !found {
+			continue
+		}
+
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || !apiKey.AllowsCategory(resolve(id)) {
+			return false
+		}
+		resolved = true
+	}
+
+	return resolved
+}
+
+// isCategoryAllowed returns true if the API key used for the request gives access to the category.
+func isCategoryAllowed(r *http.Request, categoryID int64) bool {
+	categoryIDs := request.APIKeyCategoryIDs(r)
+	if len(categoryIDs) == 0 {
+		return true
+	}
+
+	for _, id := range categoryIDs {
+		if id == categoryID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,184 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package api // import "miniflux.app/api"
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"miniflux.app/model"
+	"miniflux.app/storage/storagetest"
+
+	"github.com/gorilla/mux"
+)
+
+func TestRoutePermission(t *testing.T) {
+	scenarios := []struct {
+		method     string
+		template   string
+		permission int
+	}{
+		{http.MethodGet, "/me", permissionRead},
+		{http.MethodGet, "/entries", permissionRead},
+		{http.MethodGet, "/feeds/{feedID}/icon", permissionRead},
+		{http.MethodGet, "/export", permissionRead},
+		{http.MethodGet, "/users", permissionAccount},
+		{http.MethodGet, "/api-keys", permissionAccount},
+		{http.MethodDelete, "/sessions/{sessionID}", permissionAccount},
+		{http.MethodPut, "/integrations", permissionAccount},
+		{http.MethodPut, "/entries", permissionEntriesWrite},
+		{http.MethodPut, "/entries/{entryID}/bookmark", permissionEntriesWrite},
+		{http.MethodGet, "/entries/{entryID}/fetch-content", permissionEntriesWrite},
+		{http.MethodPut, "/annotations/{annotationID}", permissionEntriesWrite},
+		{http.MethodPut, "/feeds/{feedID}/mark-all-as-read", permissionEntriesWrite},
+		{http.MethodPut, "/users/{userID}/mark-all-as-read", permissionEntriesWrite},
+		{http.MethodPost, "/feeds", permissionFeedsAdmin},
+		{http.MethodDelete, "/categories/{categoryID}", permissionFeedsAdmin},
+		{http.MethodPut, "/feeds/refresh", permissionFeedsAdmin},
+		{http.MethodPost, "/import", permissionFeedsAdmin},
+	}
+
+	for _, scenario := range scenarios {
+		if permission := routePermission(scenario.method, scenario.template); permission != scenario.permission {
+			t.Errorf(`Unexpected permission for %s %s: got %d instead of %d`, scenario.method, scenario.template, permission, scenario.permission)
+		}
+	}
+}
+
+func TestIsGrantedPermission(t *testing.T) {
+	fullAccess := &model.APIKey{}
+	readOnly := &model.APIKey{Scopes: []string{model.APIKeyScopeReadOnly}}
+	entriesWrite := &model.APIKey{Scopes: []string{model.APIKeyScopeEntriesWrite}}
+	categoryOnly := &model.APIKey{CategoryIDs: []int64{1}}
+
+	scenarios := []struct {
+		apiKey     *model.APIKey
+		permission int
+		expected   bool
+	}{
+		{fullAccess, permissionAccount, true},
+		{fullAccess, permissionFeedsAdmin, true},
+		{readOnly, permissionRead, true},
+		{readOnly, permissionEntriesWrite, false},
+		{readOnly, permissionFeedsAdmin, false},
+		{readOnly, permissionAccount, false},
+		{entriesWrite, permissionEntriesWrite, true},
+		{entriesWrite, permissionFeedsAdmin, false},
+		{categoryOnly, permissionFeedsAdmin, true},
+		{categoryOnly, permissionAccount, false},
+	}
+
+	for i, scenario := range scenarios {
+		if result := isGrantedPermission(scenario.apiKey, scenario.permission); result != scenario.expected {
+			t.Errorf(`Scenario #%d: got %v instead of %v`, i, result, scenario.expected)
+		}
+	}
+}
+
+func TestRestrictedAPIKeys(t *testing.T) {
+	store := storagetest.NewStorage(t)
+	user, feed, entries := createTestFeed(t, store)
+
+	otherCategory, err := store.CreateCategory(user.ID, &model.CategoryRequest{Title: "Private"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router := mux.NewRouter()
+	Serve(router, store, nil)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	newKey := func(description string, scopes []string, categoryIDs []int64) string {
+		apiKey := model.NewAPIKey(user.ID, description)
+		apiKey.Scopes = scopes
+		apiKey.CategoryIDs = categoryIDs
+		if err := store.CreateAPIKey(apiKey); err != nil {
+			t.Fatal(err)
+		}
+		return apiKey.Token
+	}
+
+	send := func(token, method, path, body string) (int, []byte) {
+		request, err := http.NewRequest(method, server.URL+"/v1"+path, bytes.NewBufferString(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		request.Header.Set("X-Auth-Token", token)
+
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer response.Body.Close()
+
+		var buffer bytes.Buffer
+		buffer.ReadFrom(response.Body)
+		return response.StatusCode, buffer.Bytes()
+	}
+
+	scenarios := []struct {
+		token  string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{newKey("Read only", []string{model.APIKeyScopeReadOnly}, nil), http.MethodGet, fmt.Sprintf("/entries/%d", entries[0].ID), "", http.StatusOK},
+		{newKey("Read only 2", []string{model.APIKeyScopeReadOnly}, nil), http.MethodPut, fmt.Sprintf("/entries/%d/bookmark", entries[0].ID), "", http.StatusForbidden},
+		{newKey("Read only 3", []string{model.APIKeyScopeReadOnly}, nil), http.MethodDelete, fmt.Sprintf("/feeds/%d", feed.ID), "", http.StatusForbidden},
+		{newKey("Read only 4", []string{model.APIKeyScopeReadOnly}, nil), http.MethodGet, "/api-keys", "", http.StatusForbidden},
+		{newKey("Entries", []string{model.APIKeyScopeEntriesWrite}, nil), http.MethodPut, fmt.Sprintf("/entries/%d/bookmark", entries[0].ID), "", http.StatusNoContent},
+		{newKey("Entries 2", []string{model.APIKeyScopeEntriesWrite}, nil), http.MethodPut, fmt.Sprintf("/feeds/%d", feed.ID), `{"title": "Renamed"}`, http.StatusForbidden},
+		{newKey("Feeds", []string{model.APIKeyScopeFeedsAdmin}, []int64{feed.Category.ID}), http.MethodPut, fmt.Sprintf("/feeds/%d", feed.ID), `{"title": "Renamed"}`, http.StatusCreated},
+		{newKey("Feeds 2", []string{model.APIKeyScopeFeedsAdmin}, []int64{otherCategory.ID}), http.MethodPut, fmt.Sprintf("/feeds/%d", feed.ID), `{"title": "Renamed"}`, http.StatusForbidden},
+		{newKey("Private", nil, []int64{otherCategory.ID}), http.MethodGet, fmt.Sprintf("/entries/%d", entries[0].ID), "", http.StatusForbidden},
+		{newKey("Private 2", nil, []int64{otherCategory.ID}), http.MethodPut, "/entries", fmt.Sprintf(`{"entry_ids": [%d], "status": "read"}`, entries[0].ID), http.StatusForbidden},
+		{newKey("Private 3", nil, []int64{otherCategory.ID}), http.MethodGet, "/labels", "", http.StatusForbidden},
+		{newKey("Private 4", nil, []int64{otherCategory.ID}), http.MethodGet, "/users", "", http.StatusForbidden},
+	}
+
+	for _, scenario := range scenarios {
+		if status, body := send(scenario.token, scenario.method, scenario.path, scenario.body); status != scenario.status {
+			t.Errorf(`Unexpected status code for %s %s: got %d instead of %d: %s`, scenario.method, scenario.path, status, scenario.status, body)
+		}
+	}
+
+	// Lists are filtered for keys restricted to some categories.
+	token := newKey("Private 5", nil, []int64{otherCategory.ID})
+
+	var categories model.Categories
+	_, body := send(token, http.MethodGet, "/categories", "")
+	if err := json.Unmarshal(body, &categories); err != nil || len(categories) != 1 || categories[0].ID != otherCategory.ID {
+		t.Errorf(`Unexpected categories: %s`, body)
+	}
+
+	var feeds model.Feeds
+	_, body = send(token, http.MethodGet, "/feeds", "")
+	if err := json.Unmarshal(body, &feeds); err != nil || len(feeds) != 0 {
+		t.Errorf(`Unexpected feeds: %s`, body)
+	}
+
+	var counters model.FeedCounters
+	_, body = send(token, http.MethodGet, "/feeds/counters", "")
+	if err := json.Unmarshal(body, &counters); err != nil || len(counters.UnreadCounters) != 0 {
+		t.Errorf(`Unexpected counters: %s`, body)
+	}
+
+	var response entriesResponse
+	_, body = send(token, http.MethodGet, "/entries", "")
+	if err := json.Unmarshal(body, &response); err != nil || response.Total != 0 || len(response.Entries) != 0 {
+		t.Errorf(`Unexpected entries: %s`, body)
+	}
+
+	_, body = send(newKey("Public", nil, []int64{feed.Category.ID}), http.MethodGet, "/entries", "")
+	if err := json.Unmarshal(body, &response); err != nil || response.Total != 2 {
+		t.Errorf(`Unexpected entries: %s`, body)
+	}
+}
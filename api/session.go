@@ -0,0 +1,66 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package api // import "miniflux.app/api"
+
+import (
+	"net/http"
+
+	"miniflux.app/http/request"
+	"miniflux.app/http/response/json"
+	"miniflux.app/model"
+)
+
+func (h *handler) getSessions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.targetUserID(w, r)
+	if !ok {
+		return
+	}
+
+	sessions, err := h.store.UserSessions(userID)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	if sessions == nil {
+		sessions = make(model.UserSessions, 0)
+	}
+
+	json.OK(w, r, sessions)
+}
+
+func (h *handler) removeSession(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.targetUserID(w, r)
+	if !ok {
+		return
+	}
+
+	sessionID := request.RouteInt64Param(r, "sessionID")
+	if !h.store.UserSessionIDExists(userID, sessionID) {
+		json.NotFound(w, r)
+		return
+	}
+
+	if err := h.store.RemoveUserSessionByID(userID, sessionID); err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.NoContent(w, r)
+}
+
+func (h *handler) removeSessions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.targetUserID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.store.RemoveUserSessions(userID); err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.NoContent(w, r)
+}
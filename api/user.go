@@ -202,3 +202,30 @@ func (h *handler) removeUser(w http.ResponseWriter, r *http.Request) {
 	h.store.RemoveUserAsync(user.ID)
 	json.NoContent(w, r)
 }
+
+// targetUserID returns the user given in the route, or the logged user when the route has no user.
+// Only administrators can manage the resources of other users, an error response is sent otherwise.
+func (h *handler) targetUserID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	userID := request.RouteInt64Param(r, "userID")
+	if userID == 0 || userID == request.UserID(r) {
+		return request.UserID(r), true
+	}
+
+	if !request.IsAdminUser(r) {
+		json.Forbidden(w, r)
+		return 0, false
+	}
+
+	user, err := h.store.UserByID(userID)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return 0, false
+	}
+
+	if user == nil {
+		json.NotFound(w, r)
+		return 0, false
+	}
+
+	return userID, true
+}
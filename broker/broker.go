@@ -0,0 +1,167 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package broker // import "miniflux.app/broker"
+
+import (
+	"sync"
+	"time"
+
+	"miniflux.app/model"
+)
+
+// Event types.
+const (
+	EventNewEntries     = "new_entries"
+	EventEntriesStatus  = "entries_status"
+	EventEntriesStarred = "entries_starred"
+	EventFeedError      = "feed_error"
+)
+
+// subscriberBufferSize is the number of events kept for a slow subscriber, newer events are dropped when full.
+const subscriberBufferSize = 32
+
+// Event represents something that happened to the data of a user.
+type Event struct {
+	UserID int64
+	Type   string
+	Data   interface{}
+}
+
+// NewEntriesData is the data of the EventNewEntries event.
+type NewEntriesData struct {
+	FeedID  int64    `json:"feed_id"`
+	Entries []*Entry `json:"entries"`
+}
+
+// Entry represents a new entry in the events, the content is not sent to keep the stream lightweight.
+type Entry struct {
+	ID          int64     `json:"id"`
+	UserID      int64     `json:"user_id"`
+	FeedID      int64     `json:"feed_id"`
+	Status      string    `json:"status"`
+	Hash        string    `json:"hash"`
+	Title       string    `json:"title"`
+	URL         string    `json:"url"`
+	CommentsURL string    `json:"comments_url"`
+	Date        time.Time `json:"published_at"`
+	CreatedAt   time.Time `json:"created_at"`
+	ChangedAt   time.Time `json:"changed_at"`
+	Author      string    `json:"author"`
+	Starred     bool      `json:"starred"`
+	ReadingTime int       `json:"reading_time"`
+	Tags        []string  `json:"tags"`
+}
+
+// NewEntry returns the event representation of an entry.
+func NewEntry(entry *model.Entry) *Entry {
+	return &Entry{
+		ID:          entry.ID,
+		UserID:      entry.UserID,
+		FeedID:      entry.FeedID,
+		Status:      entry.Status,
+		Hash:        entry.Hash,
+		Title:       entry.Title,
+		URL:         entry.URL,
+		CommentsURL: entry.CommentsURL,
+		Date:        entry.Date,
+		CreatedAt:   entry.CreatedAt,
+		ChangedAt:   entry.ChangedAt,
+		Author:      entry.Author,
+		Starred:     entry.Starred,
+		ReadingTime: entry.ReadingTime,
+		Tags:        entry.Tags,
+	}
+}
+
+// EntriesStatusData is the data of the EventEntriesStatus event.
+type EntriesStatusData struct {
+	EntryIDs []int64 `json:"entry_ids"`
+	Status   string  `json:"status"`
+}
+
+// EntriesStarredData is the data of the EventEntriesStarred event.
+type EntriesStarredData struct {
+	EntryIDs []int64 `json:"entry_ids"`
+	Starred  bool    `json:"starred"`
+}
+
+// FeedErrorData is the data of the EventFeedError event.
+type FeedErrorData struct {
+	FeedID            int64  `json:"feed_id"`
+	ParsingErrorMsg   string `json:"parsing_error_message"`
+	ParsingErrorCount int    `json:"parsing_error_count"`
+}
+
+// Broker keeps the list of subscribers of each user.
+type Broker struct {
+	mu          sync.RWMutex
+	subscribers map[int64]map[chan *Event]struct{}
+}
+
+// New returns a new Broker.
+func New() *Broker {
+	return &Broker{subscribers: make(map[int64]map[chan *Event]struct{})}
+}
+
+// Subscribe returns a channel receiving the events of the user and a function to unsubscribe.
+func (b *Broker) Subscribe(userID int64) (<-chan *Event, func()) {
+	ch := make(chan *Event, subscriberBufferSize)
+
+	b.mu.Lock()
+	if b.subscribers[userID] == nil {
+		b.subscribers[userID] = make(map[chan *Event]struct{})
+	}
+	b.subscribers[userID][ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers[userID], ch)
+			if len(b.subscribers[userID]) == 0 {
+				delete(b.subscribers, userID)
+			}
+			b.mu.Unlock()
+		})
+	}
+}
+
+// HasSubscribers returns true if someone is listening to the events of the user.
+func (b *Broker) HasSubscribers(userID int64) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subscribers[userID]) > 0
+}
+
+// Publish sends the event to the subscribers of the user without blocking.
+func (b *Broker) Publish(event *Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for ch := range b.subscribers[event.UserID] {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+var defaultBroker = New()
+
+// Subscribe subscribes to the events of the user on the default broker.
+func Subscribe(userID int64) (<-chan *Event, func()) {
+	return defaultBroker.Subscribe(userID)
+}
+
+// HasSubscribers returns true if someone is listening to the events of the user on the default broker.
+func HasSubscribers(userID int64) bool {
+	return defaultBroker.HasSubscribers(userID)
+}
+
+// Publish sends the event to the subscribers of the default broker.
+func Publish(event *Event) {
+	defaultBroker.Publish(event)
+}
@@ -0,0 +1,65 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package broker // import "miniflux.app/broker"
+
+import "testing"
+
+func TestPublishToUserSubscribers(t *testing.T) {
+	b := New()
+
+	events, unsubscribe := b.Subscribe(1)
+	defer unsubscribe()
+
+	otherEvents, otherUnsubscribe := b.Subscribe(2)
+	defer otherUnsubscribe()
+
+	b.Publish(&Event{UserID: 1, Type: EventEntriesStatus})
+
+	select {
+	case event := <-events:
+		if event.Type != EventEntriesStatus {
+			t.Errorf(`Unexpected event type: %q`, event.Type)
+		}
+	default:
+		t.Fatal(`The subscriber should receive the event`)
+	}
+
+	select {
+	case <-otherEvents:
+		t.Fatal(`The events of other users should not be received`)
+	default:
+	}
+}
+
+func TestUnsubscribe(t *testing.T) {
+	b := New()
+
+	_, unsubscribe := b.Subscribe(1)
+	if !b.HasSubscribers(1) {
+		t.Fatal(`The user should have a subscriber`)
+	}
+
+	unsubscribe()
+	unsubscribe()
+
+	if b.HasSubscribers(1) {
+		t.Fatal(`The user should not have subscribers anymore`)
+	}
+}
+
+func TestPublishDoesNotBlock(t *testing.T) {
+	b := New()
+
+	events, unsubscribe := b.Subscribe(1)
+	defer unsubscribe()
+
+	for i := 0; i < subscriberBufferSize*2; i++ {
+		b.Publish(&Event{UserID: 1, Type: EventNewEntries})
+	}
+
+	if len(events) != subscriberBufferSize {
+		t.Fatalf(`Unexpected number of buffered events: %d`, len(events))
+	}
+}
@@ -0,0 +1,11 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+/*
+Package broker dispatches user events to the subscribers running in the same process.
+
+The events are not shared between processes: when the scheduler and the workers run in another
+process than the HTTP server, the feed refreshes are not sent to the event stream.
+*/
+package broker // import "miniflux.app/broker"
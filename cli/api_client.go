@@ -0,0 +1,407 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package cli // import "miniflux.app/cli"
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	miniflux "miniflux.app/client"
+	"miniflux.app/config"
+)
+
+const (
+	apiClientMaxRetries = 3
+	apiClientBackoff    = time.Second
+)
+
+// clientCommand is a subcommand of "miniflux client", the flags are declared before parsing the arguments.
+type clientCommand struct {
+	usage       string
+	description string
+	setup       func(flags *flag.FlagSet) clientCommandFunc
+}
+
+type clientCommandFunc func(ctx context.Context, client *miniflux.Client, args []string, stdout io.Writer) error
+
+var clientCommands = map[string]*clientCommand{
+	"feeds": {
+		usage:       "[-json]",
+		description: "List the feeds with their number of unread entries",
+		setup:       setupListFeeds,
+	},
+	"add-feed": {
+		usage:       "[-category ID] [-crawler] URL",
+		description: "Subscribe to a feed, the first category is used by default",
+		setup:       setupAddFeed,
+	},
+	"refresh": {
+		usage:       "[-feed ID | -category ID]",
+		description: "Refresh all feeds, a feed or the feeds of a category in the background",
+		setup:       setupRefresh,
+	},
+	"unread": {
+		usage:       "[-feed ID | -category ID] [-limit N] [-json]",
+		description: "List the unread entries",
+		setup:       setupListUnreadEntries,
+	},
+	"mark-read": {
+		usage:       "ENTRY_ID... | -feed ID | -category ID | -all",
+		description: "Mark entries as read",
+		setup:       setupMarkAsRead,
+	},
+	"star": {
+		usage:       "ENTRY_ID...",
+		description: "Star entries",
+		setup:       func(flags *flag.FlagSet) clientCommandFunc { return setStarred(true) },
+	},
+	"unstar": {
+		usage:       "ENTRY_ID...",
+		description: "Unstar entries",
+		setup:       func(flags *flag.FlagSet) clientCommandFunc { return setStarred(false) },
+	},
+	"import": {
+		usage:       "FILE",
+		description: "Import the feeds of an OPML file",
+		setup:       setupImport,
+	},
+	"export": {
+		usage:       "[FILE]",
+		description: "Export the feeds as OPML, to the standard output by default",
+		setup:       setupExport,
+	},
+}
+
+// runClientCommand runs a command against the REST API of the instance given by the configuration.
+func runClientCommand(args []string, stdout, stderr io.Writer) error {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "-help" {
+		printClientUsage(stderr)
+		if len(args) == 0 {
+			return errors.New("missing command")
+		}
+		return nil
+	}
+
+	command, found := clientCommands[args[0]]
+	if !found {
+		printClientUsage(stderr)
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+
+	flags := flag.NewFlagSet("miniflux client "+args[0], flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: miniflux client %s %s\n\n%s.\n", args[0], command.usage, command.description)
+		flags.PrintDefaults()
+	}
+
+	run := command.setup(flags)
+	if err := flags.Parse(args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	client, err := newAPIClient(config.Opts)
+	if err != nil {
+		return err
+	}
+
+	return run(context.Background(), client, flags.Args(), stdout)
+}
+
+func printClientUsage(w io.Writer) {
+	names := make([]string, 0, len(clientCommands))
+	for name := range clientCommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w, "Usage: miniflux [-c FILE] client COMMAND [OPTIONS]")
+	fmt.Fprintln(w, "\nThe instance is configured with CLIENT_URL and CLIENT_API_KEY, or CLIENT_USERNAME and CLIENT_PASSWORD.")
+	fmt.Fprintln(w, "\nCommands:")
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, name := range names {
+		fmt.Fprintf(tw, "  %s %s\t%s\n", name, clientCommands[name].usage, clientCommands[name].description)
+	}
+	tw.Flush()
+}
+
+func newAPIClient(opts *config.Options) (*miniflux.Client, error) {
+	var credentials miniflux.Option
+	switch {
+	case opts.ClientAPIKey() != "":
+		credentials = miniflux.WithAPIKey(opts.ClientAPIKey())
+	case opts.ClientUsername() != "" && opts.ClientPassword() != "":
+		credentials = miniflux.WithCredentials(opts.ClientUsername(), opts.ClientPassword())
+	default:
+		return nil, errors.New("CLIENT_API_KEY, or CLIENT_USERNAME and CLIENT_PASSWORD, must be defined")
+	}
+
+	return miniflux.NewClientWithOptions(
+		opts.ClientURL(),
+		credentials,
+		miniflux.WithRetry(apiClientMaxRetries, apiClientBackoff),
+	), nil
+}
+
+func setupListFeeds(flags *flag.FlagSet) clientCommandFunc {
+	asJSON := flags.Bool("json", false, "Print the feeds as JSON")
+
+	return func(ctx context.Context, client *miniflux.Client, args []string, stdout io.Writer) error {
+		feeds, err := client.FeedsContext(ctx)
+		if err != nil {
+			return err
+		}
+
+		if *asJSON {
+			return printJSON(stdout, feeds)
+		}
+
+		counters, err := client.FetchCountersContext(ctx)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tCATEGORY\tUNREAD\tTITLE\tFEED URL")
+		for _, feed := range feeds {
+			category := ""
+			if feed.Category != nil {
+				category = feed.Category.Title
+			}
+			fmt.Fprintf(w, "%d\t%s\t%d\t%s\t%s\n", feed.ID, category, counters.UnreadCounters[feed.ID], feed.Title, feed.FeedURL)
+		}
+		return w.Flush()
+	}
+}
+
+func setupAddFeed(flags *flag.FlagSet) clientCommandFunc {
+	categoryID := flags.Int64("category", 0, "Category ID")
+	crawler := flags.Bool("crawler", false, "Fetch the original content of the entries")
+
+	return func(ctx context.Context, client *miniflux.Client, args []string, stdout io.Writer) error {
+		if len(args) != 1 {
+			return errors.New("a feed URL is required")
+		}
+
+		if *categoryID == 0 {
+			categories, err := client.CategoriesContext(ctx)
+			if err != nil {
+				return err
+			}
+
+			if len(categories) == 0 {
+				return errors.New("no category found")
+			}
+			*categoryID = categories[0].ID
+		}
+
+		feedID, err := client.CreateFeedContext(ctx, &miniflux.FeedCreationRequest{
+			FeedURL:    args[0],
+			CategoryID: *categoryID,
+			Crawler:    *crawler,
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(stdout, "Feed #%d created\n", feedID)
+		return nil
+	}
+}
+
+func setupRefresh(flags *flag.FlagSet) clientCommandFunc {
+	feedID := flags.Int64("feed", 0, "Feed ID")
+	categoryID := flags.Int64("category", 0, "Category ID")
+
+	return func(ctx context.Context, client *miniflux.Client, args []string, stdout io.Writer) error {
+		// Refreshing the feeds again is harmless, the requests can be retried.
+		ctx = miniflux.AllowRetry(ctx)
+
+		switch {
+		case *feedID > 0:
+			return client.RefreshFeedContext(ctx, *feedID)
+		case *categoryID > 0:
+			return client.RefreshCategoryContext(ctx, *categoryID)
+		default:
+			return client.RefreshAllFeedsContext(ctx)
+		}
+	}
+}
+
+func setupListUnreadEntries(flags *flag.FlagSet) clientCommandFunc {
+	feedID := flags.Int64("feed", 0, "Feed ID")
+	categoryID := flags.Int64("category", 0, "Category ID")
+	limit := flags.Int("limit", 0, "Maximum number of entries, all unread entries by default")
+	asJSON := flags.Bool("json", false, "Print the entries as JSON")
+
+	return func(ctx context.Context, client *miniflux.Client, args []string, stdout io.Writer) error {
+		filter := &miniflux.Filter{
+			Status:     miniflux.EntryStatusUnread,
+			FeedID:     *feedID,
+			CategoryID: *categoryID,
+			Order:      "published_at",
+			Direction:  "desc",
+		}
+
+		if *limit > 0 && *limit < 100 {
+			filter.Limit = *limit
+		}
+
+		iterator := client.EntriesIteratorContext(ctx, filter)
+		entries := miniflux.Entries{}
+		for (*limit <= 0 || len(entries) < *limit) && iterator.Next() {
+			entries = append(entries, iterator.Entry())
+		}
+
+		if err := iterator.Err(); err != nil {
+			return err
+		}
+
+		if *asJSON {
+			return printJSON(stdout, entries)
+		}
+
+		w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tDATE\tFEED\tTITLE")
+		for _, entry := range entries {
+			feedTitle := ""
+			if entry.Feed != nil {
+				feedTitle = entry.Feed.Title
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", entry.ID, entry.Date.Local().Format("2006-01-02 15:04"), feedTitle, entry.Title)
+		}
+		return w.Flush()
+	}
+}
+
+func setupMarkAsRead(flags *flag.FlagSet) clientCommandFunc {
+	feedID := flags.Int64("feed", 0, "Mark all entries of the feed as read")
+	categoryID := flags.Int64("category", 0, "Mark all entries of the category as read")
+	all := flags.Bool("all", false, "Mark all entries as read")
+
+	return func(ctx context.Context, client *miniflux.Client, args []string, stdout io.Writer) error {
+		// Marking the entries as read again is harmless, the requests can be retried.
+		ctx = miniflux.AllowRetry(ctx)
+
+		switch {
+		case *feedID > 0:
+			return client.MarkFeedAsReadContext(ctx, *feedID)
+		case *categoryID > 0:
+			return client.MarkCategoryAsReadContext(ctx, *categoryID)
+		case *all:
+			user, err := client.MeContext(ctx)
+			if err != nil {
+				return err
+			}
+			return client.MarkAllAsReadContext(ctx, user.ID)
+		}
+
+		entryIDs, err := parseEntryIDs(args)
+		if err != nil {
+			return err
+		}
+		return client.UpdateEntriesContext(ctx, entryIDs, miniflux.EntryStatusRead)
+	}
+}
+
+// setStarred stars or unstars entries, the API only toggles the bookmark so the current state is checked first.
+func setStarred(starred bool) clientCommandFunc {
+	return func(ctx context.Context, client *miniflux.Client, args []string, stdout io.Writer) error {
+		entryIDs, err := parseEntryIDs(args)
+		if err != nil {
+			return err
+		}
+
+		for _, entryID := range entryIDs {
+			entry, err := client.EntryContext(ctx, entryID)
+			if err != nil {
+				return fmt.Errorf("entry #%d: %w", entryID, err)
+			}
+
+			if entry.Starred != starred {
+				if err := client.ToggleBookmarkContext(ctx, entryID); err != nil {
+					return fmt.Errorf("entry #%d: %w", entryID, err)
+				}
+			}
+		}
+		return nil
+	}
+}
+
+func setupImport(flags *flag.FlagSet) clientCommandFunc {
+	return func(ctx context.Context, client *miniflux.Client, args []string, stdout io.Writer) error {
+		if len(args) != 1 {
+			return errors.New("an OPML file is required")
+		}
+
+		fp, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+
+		if err := client.ImportContext(ctx, fp); err != nil {
+			return err
+		}
+
+		fmt.Fprintln(stdout, "Feeds imported")
+		return nil
+	}
+}
+
+func setupExport(flags *flag.FlagSet) clientCommandFunc {
+	return func(ctx context.Context, client *miniflux.Client, args []string, stdout io.Writer) error {
+		if len(args) > 1 {
+			return errors.New("too many arguments")
+		}
+
+		opml, err := client.ExportContext(ctx)
+		if err != nil {
+			return err
+		}
+
+		if len(args) == 1 {
+			return os.WriteFile(args[0], opml, 0644)
+		}
+
+		_, err = stdout.Write(opml)
+		return err
+	}
+}
+
+func parseEntryIDs(args []string) ([]int64, error) {
+	if len(args) == 0 {
+		return nil, errors.New("at least one entry ID is required")
+	}
+
+	entryIDs := make([]int64, 0, len(args))
+	for _, arg := range args {
+		entryID, err := strconv.ParseInt(strings.TrimPrefix(arg, "#"), 10, 64)
+		if err != nil || entryID <= 0 {
+			return nil, fmt.Errorf("invalid entry ID %q", arg)
+		}
+		entryIDs = append(entryIDs, entryID)
+	}
+	return entryIDs, nil
+}
+
+func printJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
@@ -0,0 +1,169 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package cli // import "miniflux.app/cli"
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"miniflux.app/config"
+)
+
+func newTestAPIServer(t *testing.T, handler http.HandlerFunc) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Auth-Token") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	os.Clearenv()
+	os.Setenv("CLIENT_URL", server.URL)
+	os.Setenv("CLIENT_API_KEY", "secret")
+
+	var err error
+	if config.Opts, err = config.NewParser().ParseEnvironmentVariables(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func runTestClientCommand(t *testing.T, args ...string) (string, error) {
+	var stdout bytes.Buffer
+	err := runClientCommand(args, &stdout, io.Discard)
+	return stdout.String(), err
+}
+
+func TestClientCommandWithoutCredentials(t *testing.T) {
+	os.Clearenv()
+
+	var err error
+	if config.Opts, err = config.NewParser().ParseEnvironmentVariables(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := runTestClientCommand(t, "feeds"); err == nil || !strings.Contains(err.Error(), "CLIENT_API_KEY") {
+		t.Fatalf(`Unexpected error: %v`, err)
+	}
+
+	if _, err := runTestClientCommand(t, "unknown"); err == nil {
+		t.Fatal(`An unknown command should fail`)
+	}
+}
+
+func TestClientCommandListFeeds(t *testing.T) {
+	newTestAPIServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/feeds":
+			fmt.Fprint(w, `[{"id": 7, "title": "Example", "feed_url": "https://example.org/feed.xml", "category": {"id": 1, "title": "News"}}]`)
+		case "/v1/feeds/counters":
+			fmt.Fprint(w, `{"reads": {}, "unreads": {"7": 3}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	output, err := runTestClientCommand(t, "feeds")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 2 || strings.Join(strings.Fields(lines[1]), " ") != "7 News 3 Example https://example.org/feed.xml" {
+		t.Fatalf(`Unexpected output: %q`, output)
+	}
+
+	output, err = runTestClientCommand(t, "feeds", "-json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var feeds []map[string]interface{}
+	if err := json.Unmarshal([]byte(output), &feeds); err != nil || len(feeds) != 1 {
+		t.Fatalf(`Unexpected JSON output: %q (%v)`, output, err)
+	}
+}
+
+func TestClientCommandUnreadEntries(t *testing.T) {
+	newTestAPIServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("status") != "unread" || r.URL.Query().Get("feed_id") != "7" {
+			t.Errorf(`Unexpected query: %s`, r.URL.RawQuery)
+		}
+
+		if r.URL.Query().Get("cursor") == "" {
+			fmt.Fprint(w, `{"total": 3, "entries": [{"id": 3, "title": "Third"}, {"id": 2, "title": "Second"}], "next_cursor": "next"}`)
+			return
+		}
+		fmt.Fprint(w, `{"total": 3, "entries": [{"id": 1, "title": "First"}]}`)
+	})
+
+	output, err := runTestClientCommand(t, "unread", "-feed", "7", "-json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var entries []struct {
+		ID int64 `json:"id"`
+	}
+	if err := json.Unmarshal([]byte(output), &entries); err != nil || len(entries) != 3 {
+		t.Fatalf(`Unexpected output: %q (%v)`, output, err)
+	}
+
+	output, err = runTestClientCommand(t, "unread", "-feed", "7", "-limit", "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if lines := strings.Split(strings.TrimSpace(output), "\n"); len(lines) != 2 || !strings.Contains(lines[1], "Third") {
+		t.Fatalf(`Unexpected output: %q`, output)
+	}
+}
+
+func TestClientCommandMarkAsReadAndStar(t *testing.T) {
+	var requests []string
+	newTestAPIServer(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, strings.TrimSpace(r.Method+" "+r.URL.Path+" "+string(body)))
+
+		switch r.URL.Path {
+		case "/v1/entries/1":
+			fmt.Fprint(w, `{"id": 1, "starred": true}`)
+		case "/v1/entries/2":
+			fmt.Fprint(w, `{"id": 2, "starred": false}`)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+
+	if _, err := runTestClientCommand(t, "mark-read", "1", "#2"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := runTestClientCommand(t, "star", "1", "2"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		`PUT /v1/entries {"entry_ids":[1,2],"status":"read"}`,
+		`GET /v1/entries/1`,
+		`GET /v1/entries/2`,
+		`PUT /v1/entries/2/bookmark`,
+	}
+	if strings.Join(requests, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf(`Unexpected requests: %q`, requests)
+	}
+
+	if _, err := runTestClientCommand(t, "mark-read", "abc"); err == nil {
+		t.Fatal(`An invalid entry ID should be rejected`)
+	}
+}
@@ -7,6 +7,7 @@ package cli // import "miniflux.app/cli"
 import (
 	"flag"
 	"fmt"
+	"os"
 
 	"miniflux.app/config"
 	"miniflux.app/database"
@@ -28,7 +29,14 @@ const (
 	flagDebugModeHelp       = "Show debug logs"
 	flagConfigFileHelp      = "Load configuration file"
 	flagConfigDumpHelp      = "Print parsed configuration values"
+	flagConfigCheckHelp     = "Report unknown keys, invalid values and deprecated options of the configuration"
+	flagConfigDiffHelp      = "Print the configuration values different from the default values"
+	flagStorageStatsHelp    = "Show the disk usage of the database by table, user and feed"
+	flagDBMaintenanceHelp   = `Run database maintenance operations, comma-separated values among "vacuum", "analyze" and "reindex"`
+	flagPurgeRemovedHelp    = "Delete the content of the removed entries of the given feed ID"
 	flagHealthCheckHelp     = `Perform a health check on the given endpoint (the value "auto" try to guess the health check endpoint).`
+	flagRefreshFeedsHelp    = `Refresh the feeds once and exit, the value is "batch", "all", "user:USERNAME" or "category:ID"`
+	flagCleanupHelp         = "Run the cleanup tasks once and exit"
 )
 
 // Parse parses command line arguments.
@@ -45,7 +53,14 @@ func Parse() {
 		flagDebugMode       bool
 		flagConfigFile      string
 		flagConfigDump      bool
+		flagConfigCheck     bool
+		flagConfigDiff      bool
 		flagHealthCheck     string
+		flagStorageStats    bool
+		flagDBMaintenance   string
+		flagPurgeRemoved    int64
+		flagRefreshFeeds    string
+		flagCleanup         bool
 	)
 
 	flag.BoolVar(&flagInfo, "info", false, flagInfoHelp)
@@ -61,19 +76,18 @@ func Parse() {
 	flag.StringVar(&flagConfigFile, "config-file", "", flagConfigFileHelp)
 	flag.StringVar(&flagConfigFile, "c", "", flagConfigFileHelp)
 	flag.BoolVar(&flagConfigDump, "config-dump", false, flagConfigDumpHelp)
+	flag.BoolVar(&flagConfigCheck, "config-check", false, flagConfigCheckHelp)
+	flag.BoolVar(&flagConfigDiff, "config-diff", false, flagConfigDiffHelp)
 	flag.StringVar(&flagHealthCheck, "healthcheck", "", flagHealthCheckHelp)
+	flag.BoolVar(&flagStorageStats, "storage-stats", false, flagStorageStatsHelp)
+	flag.StringVar(&flagDBMaintenance, "database-maintenance", "", flagDBMaintenanceHelp)
+	flag.Int64Var(&flagPurgeRemoved, "purge-removed-entries", 0, flagPurgeRemovedHelp)
+	flag.StringVar(&flagRefreshFeeds, "refresh-feeds", "", flagRefreshFeedsHelp)
+	flag.BoolVar(&flagCleanup, "cleanup", false, flagCleanupHelp)
 	flag.Parse()
 
-	cfg := config.NewParser()
-
-	if flagConfigFile != "" {
-		config.Opts, err = cfg.ParseFile(flagConfigFile)
-		if err != nil {
-			logger.Fatal("%v", err)
-		}
-	}
-
-	config.Opts, err = cfg.ParseEnvironmentVariables()
+	var problems []*config.Problem
+	config.Opts, problems, err = parseOptions(flagConfigFile)
 	if err != nil {
 		logger.Fatal("%v", err)
 	}
@@ -83,6 +97,18 @@ func Parse() {
 		return
 	}
 
+	if flagConfigCheck {
+		if !checkConfig(problems, os.Stdout) {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if flagConfigDiff {
+		diffConfig(config.Opts, os.Stdout)
+		return
+	}
+
 	if config.Opts.LogDateTime() {
 		logger.EnableDateTime()
 	}
@@ -91,6 +117,10 @@ func Parse() {
 		logger.EnableDebug()
 	}
 
+	for _, problem := range problems {
+		logger.Info("Configuration: %s", problem)
+	}
+
 	if flagHealthCheck != "" {
 		doHealthCheck(flagHealthCheck)
 		return
@@ -106,6 +136,14 @@ func Parse() {
 		return
 	}
 
+	if flag.Arg(0) == "client" {
+		if err := runClientCommand(flag.Args()[1:], os.Stdout, os.Stderr); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	if config.Opts.IsDefaultDatabaseURL() {
 		logger.Info("The default value for DATABASE_URL is used")
 	}
@@ -167,6 +205,21 @@ func Parse() {
 		return
 	}
 
+	if flagStorageStats {
+		showStorageStatistics(store)
+		return
+	}
+
+	if flagDBMaintenance != "" {
+		runDatabaseMaintenance(store, flagDBMaintenance)
+		return
+	}
+
+	if flagPurgeRemoved > 0 {
+		purgeRemovedEntries(store, flagPurgeRemoved)
+		return
+	}
+
 	if flagResetPassword {
 		resetPassword(store)
 		return
@@ -183,10 +236,27 @@ func Parse() {
 		logger.Fatal(`You must run the SQL migrations, %v`, err)
 	}
 
+	if flag.Arg(0) == "user" {
+		if err := runUserCommand(store, flag.Args()[1:], os.Stdout, os.Stderr); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			db.Close()
+			os.Exit(1)
+		}
+		return
+	}
+
+	if flagRefreshFeeds != "" || flagCleanup {
+		if exitCode := runOnce(store, flagRefreshFeeds, flagCleanup, os.Stdout, os.Stderr); exitCode != runOnceSuccess {
+			db.Close()
+			os.Exit(exitCode)
+		}
+		return
+	}
+
 	// Create admin user and start the daemon.
 	if config.Opts.CreateAdmin() {
 		createAdmin(store)
 	}
 
-	startDaemon(store)
+	startDaemon(store, flagConfigFile)
 }
@@ -0,0 +1,96 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package cli // import "miniflux.app/cli"
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"miniflux.app/config"
+	"miniflux.app/logger"
+)
+
+// parseOptions reads the configuration file, if any, then the environment variables that take precedence.
+func parseOptions(configFile string) (*config.Options, []*config.Problem, error) {
+	parser := config.NewParser()
+
+	if configFile != "" {
+		if _, err := parser.ParseFile(configFile); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	opts, err := parser.ParseEnvironmentVariables()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return opts, parser.Problems(), nil
+}
+
+// checkConfig prints the problems of the configuration and returns false if one of them is an error.
+func checkConfig(problems []*config.Problem, w io.Writer) bool {
+	valid := true
+	for _, problem := range problems {
+		if problem.IsError() {
+			valid = false
+		}
+		fmt.Fprintln(w, problem)
+	}
+
+	if valid {
+		fmt.Fprintln(w, "The configuration is valid")
+	}
+	return valid
+}
+
+// diffConfig prints the options with a value different from the default value.
+func diffConfig(opts *config.Options, w io.Writer) {
+	for _, change := range config.NewOptions().Diff(opts) {
+		fmt.Fprintf(w, "%s=%v (default: %v)\n", change.Key, change.NewValue, change.OldValue)
+	}
+}
+
+// reloadConfig applies the reloadable options of the configuration, the environment variables are not changed
+// by a reload so only the changes of the configuration file are taken into account.
+func reloadConfig(configFile string) {
+	opts, problems, err := parseOptions(configFile)
+	if err != nil {
+		logger.Error("[Config] Unable to reload the configuration: %v", err)
+		return
+	}
+
+	for _, problem := range problems {
+		if problem.IsError() {
+			logger.Error("[Config] The configuration is not reloaded, %s", problem)
+			return
+		}
+	}
+
+	applied, ignored := config.Opts.Reload(opts)
+	for _, key := range applied {
+		switch {
+		case key == "DEBUG" && config.Opts.HasDebugMode():
+			logger.EnableDebug()
+		case key == "DEBUG":
+			logger.DisableDebug()
+		case key == "LOG_DATE_TIME" && config.Opts.LogDateTime():
+			logger.EnableDateTime()
+		case key == "LOG_DATE_TIME":
+			logger.DisableDateTime()
+		}
+	}
+
+	if len(applied) == 0 {
+		logger.Info("[Config] Configuration reloaded without change")
+	} else {
+		logger.Info("[Config] Configuration reloaded, options changed: %s", strings.Join(applied, ", "))
+	}
+
+	if len(ignored) > 0 {
+		logger.Info("[Config] These options require a restart: %s", strings.Join(ignored, ", "))
+	}
+}
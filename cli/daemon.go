@@ -22,13 +22,22 @@ import (
 	"miniflux.app/worker"
 )
 
-func startDaemon(store *storage.Storage) {
+func startDaemon(store *storage.Storage, configFile string) {
 	logger.Info("Starting Miniflux...")
 
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt)
 	signal.Notify(stop, syscall.SIGTERM)
 
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	go func() {
+		for range reload {
+			logger.Info("Reloading the configuration...")
+			reloadConfig(configFile)
+		}
+	}()
+
 	pool := worker.NewPool(store, config.Opts.WorkerPoolSize())
 
 	if config.Opts.HasSchedulerService() && !config.Opts.HasMaintenanceMode() {
@@ -41,8 +50,12 @@ func startDaemon(store *storage.Storage) {
 	}
 
 	if config.Opts.HasMetricsCollector() {
-		collector := metric.NewCollector(store, config.Opts.MetricsRefreshInterval())
+		collector := metric.NewCollector(store, config.Opts.MetricsRefreshInterval(), config.Opts.MetricsStorageRefreshInterval())
 		go collector.GatherStorageMetrics()
+
+		if config.Opts.MetricsStorageRefreshInterval() > 0 {
+			go collector.GatherStorageStatistics()
+		}
 	}
 
 	if systemd.HasNotifySocket() {
@@ -0,0 +1,92 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package cli // import "miniflux.app/cli"
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"miniflux.app/model"
+	"miniflux.app/storage"
+)
+
+const storageStatisticsFeedLimit = 20
+
+func showStorageStatistics(store *storage.Storage) {
+	statistics, err := store.StorageStatistics(storageStatisticsFeedLimit)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("Database Size:", formatBytes(statistics.DatabaseSize))
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "\nTABLE\tROWS\tTABLE SIZE\tINDEX SIZE")
+	for _, table := range statistics.Tables {
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", table.Name, table.RowCount, formatBytes(table.TableSize), formatBytes(table.IndexSize))
+	}
+
+	fmt.Fprintln(w, "\nINDEX\tTABLE\tSIZE")
+	for _, index := range statistics.Indexes {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", index.Name, index.TableName, formatBytes(index.Size))
+	}
+
+	fmt.Fprintln(w, "\nUSER\tFEEDS\tENTRIES\tREMOVED\tCONTENT SIZE")
+	for _, user := range statistics.Users {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%s\n", user.Username, user.FeedCount, user.EntryCount, user.RemovedEntryCount, formatBytes(user.ContentSize))
+	}
+
+	fmt.Fprintln(w, "\nFEED ID\tUSER\tENTRIES\tREMOVED\tCONTENT SIZE\tTITLE")
+	for _, feed := range statistics.Feeds {
+		fmt.Fprintf(w, "%d\t%s\t%d\t%d\t%s\t%s\n", feed.FeedID, feed.Username, feed.EntryCount, feed.RemovedEntryCount, formatBytes(feed.ContentSize), feed.Title)
+	}
+
+	w.Flush()
+}
+
+func runDatabaseMaintenance(store *storage.Storage, operations string) {
+	for _, operation := range strings.Split(operations, ",") {
+		operation = strings.ToLower(strings.TrimSpace(operation))
+		if !model.IsValidDatabaseOperation(operation) {
+			fmt.Fprintf(os.Stderr, "Invalid database maintenance operation: %q\n", operation)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Running %s...\n", strings.ToUpper(operation))
+		if err := store.RunDatabaseMaintenance(operation); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+	}
+}
+
+func purgeRemovedEntries(store *storage.Storage, feedID int64) {
+	count, err := store.PurgeRemovedEntries(feedID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Purged the content of %d removed entries of feed #%d\n", count, feedID)
+}
+
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,122 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package cli // import "miniflux.app/cli"
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"miniflux.app/config"
+	"miniflux.app/model"
+	"miniflux.app/service/scheduler"
+	"miniflux.app/storage"
+	"miniflux.app/worker"
+)
+
+// Exit codes of the one-shot mode.
+const (
+	runOnceSuccess        = 0
+	runOnceFatalError     = 1
+	runOncePartialFailure = 2
+)
+
+// refreshScope describes which feeds are refreshed by the one-shot mode.
+type refreshScope struct {
+	kind  string
+	value string
+}
+
+func parseRefreshScope(value string) (*refreshScope, error) {
+	kind, argument, _ := strings.Cut(strings.TrimSpace(value), ":")
+
+	switch kind {
+	case "batch", "all":
+		if argument != "" {
+			return nil, fmt.Errorf("the scope %q does not take an argument", kind)
+		}
+	case "user":
+		if argument == "" {
+			return nil, fmt.Errorf(`the scope "user" requires a username, for example "user:admin"`)
+		}
+	case "category":
+		if _, err := strconv.ParseInt(argument, 10, 64); err != nil {
+			return nil, fmt.Errorf(`the scope "category" requires a category ID, for example "category:42"`)
+		}
+	default:
+		return nil, fmt.Errorf(`invalid refresh scope %q, valid values are "batch", "all", "user:USERNAME" and "category:ID"`, value)
+	}
+
+	return &refreshScope{kind: kind, value: argument}, nil
+}
+
+func (r *refreshScope) jobs(store *storage.Storage) (model.JobList, error) {
+	switch r.kind {
+	case "all":
+		return store.NewAllFeedsBatch()
+	case "user":
+		user, err := store.UserByUsername(r.value)
+		if err != nil {
+			return nil, err
+		}
+		if user == nil {
+			return nil, fmt.Errorf("user %q not found", r.value)
+		}
+		return store.NewUserBatch(user.ID, store.CountFeeds(user.ID))
+	case "category":
+		categoryID, _ := strconv.ParseInt(r.value, 10, 64)
+		userID := store.CategoryUserID(categoryID)
+		if userID == 0 {
+			return nil, fmt.Errorf("category #%d not found", categoryID)
+		}
+		return store.NewCategoryBatch(userID, categoryID, store.CountFeeds(userID))
+	default:
+		return store.NewBatch(config.Opts.BatchSize())
+	}
+}
+
+// runOnce refreshes the feeds of the given scope and runs the cleanup tasks without starting the daemon.
+// It returns the exit code of the process.
+func runOnce(store *storage.Storage, scope string, cleanup bool, stdout, stderr io.Writer) int {
+	exitCode := runOnceSuccess
+
+	if scope != "" {
+		refresh, err := parseRefreshScope(scope)
+		if err != nil {
+			fmt.Fprintf(stderr, "%v\n", err)
+			return runOnceFatalError
+		}
+
+		jobs, err := refresh.jobs(store)
+		if err != nil {
+			fmt.Fprintf(stderr, "%v\n", err)
+			return runOnceFatalError
+		}
+
+		startTime := time.Now()
+		pool := worker.NewPool(store, config.Opts.WorkerPoolSize())
+		pool.Push(jobs)
+		pool.Wait()
+
+		// The new entries may have queued webhook deliveries that would never be sent otherwise.
+		scheduler.DeliverPendingWebhooks(store)
+
+		succeeded, failed := pool.Stats()
+		fmt.Fprintf(stdout, "Refreshed %d feeds in %s: %d succeeded, %d failed\n", len(jobs), time.Since(startTime).Round(time.Millisecond), succeeded, failed)
+
+		if failed > 0 {
+			exitCode = runOncePartialFailure
+		}
+	}
+
+	if cleanup {
+		scheduler.RunCleanupTasks(store)
+		fmt.Fprintln(stdout, "Cleanup tasks completed")
+	}
+
+	return exitCode
+}
@@ -0,0 +1,100 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package cli // import "miniflux.app/cli"
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"miniflux.app/model"
+	"miniflux.app/storage/storagetest"
+)
+
+func TestParseRefreshScope(t *testing.T) {
+	scenarios := map[string]*refreshScope{
+		"batch":         {kind: "batch"},
+		"all":           {kind: "all"},
+		"user:admin":    {kind: "user", value: "admin"},
+		"category:42":   {kind: "category", value: "42"},
+		"all:admin":     nil,
+		"user:":         nil,
+		"category:news": nil,
+		"everything":    nil,
+	}
+
+	for value, expected := range scenarios {
+		scope, err := parseRefreshScope(value)
+		if expected == nil {
+			if err == nil {
+				t.Errorf(`The scope %q should be rejected`, value)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf(`The scope %q should be valid: %v`, value, err)
+		} else if *scope != *expected {
+			t.Errorf(`Unexpected scope for %q: %+v`, value, scope)
+		}
+	}
+}
+
+func TestRunOnce(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/feed.xml" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/rss+xml")
+		fmt.Fprint(w, `<?xml version="1.0"?><rss version="2.0"><channel><title>Example</title><link>https://example.org/</link>`+
+			`<item><title>Entry</title><link>https://example.org/entry</link><guid>1</guid></item></channel></rss>`)
+	}))
+	defer server.Close()
+
+	os.Clearenv()
+	os.Setenv("WORKER_POOL_SIZE", "2")
+
+	store := storagetest.NewStorage(t)
+	user := storagetest.CreateUser(t, store, false)
+
+	category, err := store.FirstCategory(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, feedURL := range []string{server.URL + "/feed.xml", server.URL + "/missing.xml"} {
+		if err := store.CreateFeed(&model.Feed{UserID: user.ID, Category: category, FeedURL: feedURL, SiteURL: server.URL, Title: "Example"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var stdout bytes.Buffer
+	if exitCode := runOnce(store, "user:admin", true, &stdout, io.Discard); exitCode != runOncePartialFailure {
+		t.Fatalf(`Unexpected exit code %d: %q`, exitCode, stdout.String())
+	}
+
+	if !strings.Contains(stdout.String(), "Refreshed 2 feeds in") || !strings.Contains(stdout.String(), "1 succeeded, 1 failed") {
+		t.Errorf(`Unexpected summary: %q`, stdout.String())
+	}
+
+	if !strings.Contains(stdout.String(), "Cleanup tasks completed") {
+		t.Errorf(`The cleanup tasks should have been run: %q`, stdout.String())
+	}
+
+	if count, err := store.NewEntryQueryBuilder(user.ID).CountEntries(); err != nil || count != 1 {
+		t.Errorf(`The entry of the valid feed should be stored, got %d entries (%v)`, count, err)
+	}
+
+	if exitCode := runOnce(store, "user:unknown", false, io.Discard, io.Discard); exitCode != runOnceFatalError {
+		t.Errorf(`An unknown user should be a fatal error, got %d`, exitCode)
+	}
+}
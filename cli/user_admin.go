@@ -0,0 +1,352 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package cli // import "miniflux.app/cli"
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"miniflux.app/model"
+	"miniflux.app/reader/opml"
+	"miniflux.app/storage"
+)
+
+// userCommand is a subcommand of "miniflux user", it works directly on the database.
+type userCommand struct {
+	usage       string
+	description string
+	setup       func(flags *flag.FlagSet) userCommandFunc
+}
+
+type userCommandFunc func(store *storage.Storage, args []string, stdout io.Writer) error
+
+var userCommands = map[string]*userCommand{
+	"list": {
+		usage:       "[-json]",
+		description: "List the users",
+		setup:       setupListUsers,
+	},
+	"delete": {
+		usage:       "USERNAME",
+		description: "Delete a user and all its data",
+		setup:       func(flags *flag.FlagSet) userCommandFunc { return deleteUser },
+	},
+	"set-admin": {
+		usage:       "USERNAME",
+		description: "Grant the administrator role to a user",
+		setup:       func(flags *flag.FlagSet) userCommandFunc { return setUserAdmin(true) },
+	},
+	"unset-admin": {
+		usage:       "USERNAME",
+		description: "Revoke the administrator role of a user",
+		setup:       func(flags *flag.FlagSet) userCommandFunc { return setUserAdmin(false) },
+	},
+	"logout": {
+		usage:       "USERNAME",
+		description: "Remove the sessions of a user, the API keys are kept",
+		setup:       func(flags *flag.FlagSet) userCommandFunc { return logoutUser },
+	},
+	"unlink-oauth2": {
+		usage:       "[-provider google|oidc] USERNAME",
+		description: "Unlink the OAuth2 accounts of a user, all providers by default",
+		setup:       setupUnlinkOAuth2,
+	},
+	"export": {
+		usage:       "[-opml] USERNAME [FILE]",
+		description: "Export the settings, the categories and the feeds of a user as JSON, to the standard output by default",
+		setup:       setupExportUser,
+	},
+}
+
+// userExport is the document written by the "export" command.
+type userExport struct {
+	User       *model.User      `json:"user"`
+	Categories model.Categories `json:"categories"`
+	Feeds      model.Feeds      `json:"feeds"`
+}
+
+// runUserCommand runs a user administration command against the database.
+func runUserCommand(store *storage.Storage, args []string, stdout, stderr io.Writer) error {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "-help" {
+		printUserUsage(stderr)
+		if len(args) == 0 {
+			return errors.New("missing command")
+		}
+		return nil
+	}
+
+	command, found := userCommands[args[0]]
+	if !found {
+		printUserUsage(stderr)
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+
+	flags := flag.NewFlagSet("miniflux user "+args[0], flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: miniflux user %s %s\n\n%s.\n", args[0], command.usage, command.description)
+		flags.PrintDefaults()
+	}
+
+	run := command.setup(flags)
+	if err := flags.Parse(args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	return run(store, flags.Args(), stdout)
+}
+
+func printUserUsage(w io.Writer) {
+	names := make([]string, 0, len(userCommands))
+	for name := range userCommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w, "Usage: miniflux [-c FILE] user COMMAND [OPTIONS]")
+	fmt.Fprintln(w, "\nCommands:")
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, name := range names {
+		fmt.Fprintf(tw, "  %s %s\t%s\n", name, userCommands[name].usage, userCommands[name].description)
+	}
+	tw.Flush()
+}
+
+// findUser returns the user matching the only argument of a command.
+func findUser(store *storage.Storage, args []string) (*model.User, error) {
+	if len(args) != 1 {
+		return nil, errors.New("a username is required")
+	}
+
+	user, err := store.UserByUsername(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		return nil, fmt.Errorf("user %q not found", args[0])
+	}
+
+	return user, nil
+}
+
+// isLastAdmin returns true when the given user is the only administrator.
+func isLastAdmin(store *storage.Storage, user *model.User) (bool, error) {
+	if !user.IsAdmin {
+		return false, nil
+	}
+
+	users, err := store.Users()
+	if err != nil {
+		return false, err
+	}
+
+	for _, other := range users {
+		if other.IsAdmin && other.ID != user.ID {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func setupListUsers(flags *flag.FlagSet) userCommandFunc {
+	asJSON := flags.Bool("json", false, "Print the users as JSON")
+
+	return func(store *storage.Storage, args []string, stdout io.Writer) error {
+		users, err := store.Users()
+		if err != nil {
+			return err
+		}
+
+		if *asJSON {
+			return printJSON(stdout, users)
+		}
+
+		tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tUSERNAME\tADMIN\tOAUTH2\tLAST LOGIN")
+		for _, user := range users {
+			lastLogin := "never"
+			if user.LastLoginAt != nil {
+				lastLogin = user.LastLoginAt.Format("2006-01-02 15:04")
+			}
+
+			fmt.Fprintf(tw, "%d\t%s\t%t\t%s\t%s\n", user.ID, user.Username, user.IsAdmin, linkedOAuth2Providers(user), lastLogin)
+		}
+		return tw.Flush()
+	}
+}
+
+func linkedOAuth2Providers(user *model.User) string {
+	switch {
+	case user.GoogleID != "" && user.OpenIDConnectID != "":
+		return "google,oidc"
+	case user.GoogleID != "":
+		return "google"
+	case user.OpenIDConnectID != "":
+		return "oidc"
+	default:
+		return "-"
+	}
+}
+
+func deleteUser(store *storage.Storage, args []string, stdout io.Writer) error {
+	user, err := findUser(store, args)
+	if err != nil {
+		return err
+	}
+
+	if lastAdmin, err := isLastAdmin(store, user); err != nil {
+		return err
+	} else if lastAdmin {
+		return fmt.Errorf("user %q is the last administrator and cannot be deleted", user.Username)
+	}
+
+	if err := store.RemoveUser(user.ID); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "User %q deleted\n", user.Username)
+	return nil
+}
+
+func setUserAdmin(isAdmin bool) userCommandFunc {
+	return func(store *storage.Storage, args []string, stdout io.Writer) error {
+		user, err := findUser(store, args)
+		if err != nil {
+			return err
+		}
+
+		if !isAdmin {
+			if lastAdmin, err := isLastAdmin(store, user); err != nil {
+				return err
+			} else if lastAdmin {
+				return fmt.Errorf("user %q is the last administrator", user.Username)
+			}
+		}
+
+		user.IsAdmin = isAdmin
+		if err := store.UpdateUser(user); err != nil {
+			return err
+		}
+
+		if isAdmin {
+			fmt.Fprintf(stdout, "User %q is now an administrator\n", user.Username)
+		} else {
+			fmt.Fprintf(stdout, "User %q is no longer an administrator\n", user.Username)
+		}
+		return nil
+	}
+}
+
+func logoutUser(store *storage.Storage, args []string, stdout io.Writer) error {
+	user, err := findUser(store, args)
+	if err != nil {
+		return err
+	}
+
+	if err := store.RemoveUserSessions(user.ID); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "Sessions of user %q removed\n", user.Username)
+	return nil
+}
+
+func setupUnlinkOAuth2(flags *flag.FlagSet) userCommandFunc {
+	provider := flags.String("provider", "", `Unlink only the given provider, "google" or "oidc"`)
+
+	return func(store *storage.Storage, args []string, stdout io.Writer) error {
+		if *provider != "" && *provider != "google" && *provider != "oidc" {
+			return fmt.Errorf("invalid provider %q", *provider)
+		}
+
+		user, err := findUser(store, args)
+		if err != nil {
+			return err
+		}
+
+		// Like in the settings, the account must remain reachable with a password.
+		hasPassword, err := store.HasPassword(user.ID)
+		if err != nil {
+			return err
+		}
+
+		if !hasPassword {
+			return fmt.Errorf("user %q has no password, set one with -reset-password first", user.Username)
+		}
+
+		if *provider == "" || *provider == "google" {
+			user.GoogleID = ""
+		}
+
+		if *provider == "" || *provider == "oidc" {
+			user.OpenIDConnectID = ""
+		}
+
+		if err := store.UpdateUser(user); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(stdout, "OAuth2 accounts of user %q unlinked\n", user.Username)
+		return nil
+	}
+}
+
+func setupExportUser(flags *flag.FlagSet) userCommandFunc {
+	asOPML := flags.Bool("opml", false, "Export only the feeds as OPML")
+
+	return func(store *storage.Storage, args []string, stdout io.Writer) error {
+		if len(args) == 0 || len(args) > 2 {
+			return errors.New("a username and an optional file are required")
+		}
+
+		user, err := findUser(store, args[:1])
+		if err != nil {
+			return err
+		}
+
+		output := stdout
+		if len(args) == 2 {
+			file, err := os.Create(args[1])
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			output = file
+		}
+
+		if *asOPML {
+			document, err := opml.NewHandler(store).Export(user.ID)
+			if err != nil {
+				return err
+			}
+
+			_, err = io.WriteString(output, document)
+			return err
+		}
+
+		categories, err := store.Categories(user.ID)
+		if err != nil {
+			return err
+		}
+
+		feeds, err := store.Feeds(user.ID)
+		if err != nil {
+			return err
+		}
+
+		return printJSON(output, &userExport{User: user, Categories: categories, Feeds: feeds})
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package cli // import "miniflux.app/cli"
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"miniflux.app/model"
+	"miniflux.app/storage"
+	"miniflux.app/storage/storagetest"
+)
+
+func runTestUserCommand(t *testing.T, store *storage.Storage, args ...string) (string, error) {
+	var stdout bytes.Buffer
+	err := runUserCommand(store, args, &stdout, io.Discard)
+	return stdout.String(), err
+}
+
+func TestUserCommandAdminRole(t *testing.T) {
+	os.Clearenv()
+	store := storagetest.NewStorage(t)
+
+	if _, err := store.CreateUser(&model.UserCreationRequest{Username: "admin", Password: "test123", IsAdmin: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := store.CreateUser(&model.UserCreationRequest{Username: "alice", Password: "test123"}); err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := runTestUserCommand(t, store, "list")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if lines := strings.Split(strings.TrimSpace(output), "\n"); len(lines) != 3 || strings.Join(strings.Fields(lines[2]), " ") != "2 alice false - never" {
+		t.Fatalf(`Unexpected output: %q`, output)
+	}
+
+	if _, err := runTestUserCommand(t, store, "unset-admin", "admin"); err == nil {
+		t.Fatal(`The last administrator should be kept`)
+	}
+
+	if _, err := runTestUserCommand(t, store, "delete", "admin"); err == nil {
+		t.Fatal(`The last administrator should not be deleted`)
+	}
+
+	if _, err := runTestUserCommand(t, store, "set-admin", "alice"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := runTestUserCommand(t, store, "delete", "admin"); err != nil {
+		t.Fatal(err)
+	}
+
+	users, err := store.Users()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(users) != 1 || users[0].Username != "alice" || !users[0].IsAdmin {
+		t.Fatalf(`Unexpected users: %+v`, users)
+	}
+
+	if _, err := runTestUserCommand(t, store, "delete", "unknown"); err == nil {
+		t.Fatal(`An unknown user should be rejected`)
+	}
+}
+
+func TestUserCommandSessionsAndOAuth2(t *testing.T) {
+	os.Clearenv()
+	store := storagetest.NewStorage(t)
+
+	if _, err := store.CreateUser(&model.UserCreationRequest{Username: "alice", Password: "test123", GoogleID: "g1", OpenIDConnectID: "o1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := store.CreateUser(&model.UserCreationRequest{Username: "bob", OpenIDConnectID: "o2"}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, userID, err := store.CreateUserSessionFromUsername("alice", "Go", "127.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := runTestUserCommand(t, store, "logout", "alice"); err != nil {
+		t.Fatal(err)
+	}
+
+	if sessions, err := store.UserSessions(userID); err != nil || len(sessions) != 0 {
+		t.Fatalf(`The sessions should be removed: %v (%v)`, sessions, err)
+	}
+
+	if _, err := runTestUserCommand(t, store, "unlink-oauth2", "-provider", "google", "alice"); err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := store.UserByUsername("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if user.GoogleID != "" || user.OpenIDConnectID != "o1" {
+		t.Fatalf(`Only the Google account should be unlinked: %+v`, user)
+	}
+
+	if _, err := runTestUserCommand(t, store, "unlink-oauth2", "bob"); err == nil {
+		t.Fatal(`A user without password should keep the OAuth2 accounts`)
+	}
+}
+
+func TestUserCommandExport(t *testing.T) {
+	os.Clearenv()
+	store := storagetest.NewStorage(t)
+
+	user, err := store.CreateUser(&model.UserCreationRequest{Username: "alice", Password: "test123"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	category, err := store.FirstCategory(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.CreateFeed(&model.Feed{UserID: user.ID, Category: category, FeedURL: "https://example.org/feed.xml", SiteURL: "https://example.org/", Title: "Example"}); err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := runTestUserCommand(t, store, "export", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var export userExport
+	if err := json.Unmarshal([]byte(output), &export); err != nil {
+		t.Fatal(err)
+	}
+
+	if export.User.Username != "alice" || len(export.Categories) != 1 || len(export.Feeds) != 1 {
+		t.Fatalf(`Unexpected export: %q`, output)
+	}
+
+	output, err = runTestUserCommand(t, store, "export", "-opml", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(output, `xmlUrl="https://example.org/feed.xml"`) {
+		t.Fatalf(`Unexpected OPML export: %q`, output)
+	}
+}
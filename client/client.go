@@ -5,6 +5,7 @@
 package client // import "miniflux.app/client"
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -20,20 +21,34 @@ type Client struct {
 
 // New returns a new Miniflux client.
 func New(endpoint string, credentials ...string) *Client {
+	if len(credentials) == 2 {
+		return NewClientWithOptions(endpoint, WithCredentials(credentials[0], credentials[1]))
+	}
+	return NewClientWithOptions(endpoint, WithAPIKey(credentials[0]))
+}
+
+// NewClientWithOptions returns a new Miniflux client configured with the given options.
+func NewClientWithOptions(endpoint string, options ...Option) *Client {
 	// Web gives "API Endpoint = https://miniflux.app/v1/", it doesn't work (/v1/v1/me)
 	endpoint = strings.TrimSuffix(endpoint, "/")
 	endpoint = strings.TrimSuffix(endpoint, "/v1")
 	// trim to https://miniflux.app
 
-	if len(credentials) == 2 {
-		return &Client{request: &request{endpoint: endpoint, username: credentials[0], password: credentials[1]}}
+	client := &Client{request: &request{endpoint: endpoint, client: defaultHTTPClient()}}
+	for _, option := range options {
+		option(client)
 	}
-	return &Client{request: &request{endpoint: endpoint, apiKey: credentials[0]}}
+	return client
 }
 
 // Me returns the logged user information.
 func (c *Client) Me() (*User, error) {
-	body, err := c.request.Get("/v1/me")
+	return c.MeContext(context.Background())
+}
+
+// MeContext returns the logged user information.
+func (c *Client) MeContext(ctx context.Context) (*User, error) {
+	body, err := c.request.Get(ctx, "/v1/me")
 	if err != nil {
 		return nil, err
 	}
@@ -50,7 +65,12 @@ func (c *Client) Me() (*User, error) {
 
 // Users returns all users.
 func (c *Client) Users() (Users, error) {
-	body, err := c.request.Get("/v1/users")
+	return c.UsersContext(context.Background())
+}
+
+// UsersContext returns all users.
+func (c *Client) UsersContext(ctx context.Context) (Users, error) {
+	body, err := c.request.Get(ctx, "/v1/users")
 	if err != nil {
 		return nil, err
 	}
@@ -67,7 +87,12 @@ func (c *Client) Users() (Users, error) {
 
 // UserByID returns a single user.
 func (c *Client) UserByID(userID int64) (*User, error) {
-	body, err := c.request.Get(fmt.Sprintf("/v1/users/%d", userID))
+	return c.UserByIDContext(context.Background(), userID)
+}
+
+// UserByIDContext returns a single user.
+func (c *Client) UserByIDContext(ctx context.Context, userID int64) (*User, error) {
+	body, err := c.request.Get(ctx, fmt.Sprintf("/v1/users/%d", userID))
 	if err != nil {
 		return nil, err
 	}
@@ -84,7 +109,12 @@ func (c *Client) UserByID(userID int64) (*User, error) {
 
 // UserByUsername returns a single user.
 func (c *Client) UserByUsername(username string) (*User, error) {
-	body, err := c.request.Get(fmt.Sprintf("/v1/users/%s", username))
+	return c.UserByUsernameContext(context.Background(), username)
+}
+
+// UserByUsernameContext returns a single user.
+func (c *Client) UserByUsernameContext(ctx context.Context, username string) (*User, error) {
+	body, err := c.request.Get(ctx, fmt.Sprintf("/v1/users/%s", username))
 	if err != nil {
 		return nil, err
 	}
@@ -101,7 +131,12 @@ func (c *Client) UserByUsername(username string) (*User, error) {
 
 // CreateUser creates a new user in the system.
 func (c *Client) CreateUser(username, password string, isAdmin bool) (*User, error) {
-	body, err := c.request.Post("/v1/users", &UserCreationRequest{
+	return c.CreateUserContext(context.Background(), username, password, isAdmin)
+}
+
+// CreateUserContext creates a new user in the system.
+func (c *Client) CreateUserContext(ctx context.Context, username, password string, isAdmin bool) (*User, error) {
+	body, err := c.request.Post(ctx, "/v1/users", &UserCreationRequest{
 		Username: username,
 		Password: password,
 		IsAdmin:  isAdmin,
@@ -122,7 +157,12 @@ func (c *Client) CreateUser(username, password string, isAdmin bool) (*User, err
 
 // UpdateUser updates a user in the system.
 func (c *Client) UpdateUser(userID int64, userChanges *UserModificationRequest) (*User, error) {
-	body, err := c.request.Put(fmt.Sprintf("/v1/users/%d", userID), userChanges)
+	return c.UpdateUserContext(context.Background(), userID, userChanges)
+}
+
+// UpdateUserContext updates a user in the system.
+func (c *Client) UpdateUserContext(ctx context.Context, userID int64, userChanges *UserModificationRequest) (*User, error) {
+	body, err := c.request.Put(ctx, fmt.Sprintf("/v1/users/%d", userID), userChanges)
 	if err != nil {
 		return nil, err
 	}
@@ -137,20 +177,267 @@ func (c *Client) UpdateUser(userID int64, userChanges *UserModificationRequest)
 	return u, nil
 }
 
+// APIKeys returns the API keys of the logged user.
+func (c *Client) APIKeys() (APIKeys, error) {
+	return c.APIKeysContext(context.Background())
+}
+
+// APIKeysContext returns the API keys of the logged user.
+func (c *Client) APIKeysContext(ctx context.Context) (APIKeys, error) {
+	return c.fetchAPIKeys(ctx, "/v1/api-keys")
+}
+
+// UserAPIKeys returns the API keys of a user (admin only).
+func (c *Client) UserAPIKeys(userID int64) (APIKeys, error) {
+	return c.UserAPIKeysContext(context.Background(), userID)
+}
+
+// UserAPIKeysContext returns the API keys of a user (admin only).
+func (c *Client) UserAPIKeysContext(ctx context.Context, userID int64) (APIKeys, error) {
+	return c.fetchAPIKeys(ctx, fmt.Sprintf("/v1/users/%d/api-keys", userID))
+}
+
+// CreateAPIKey creates an API key for the logged user, the token is only returned once.
+func (c *Client) CreateAPIKey(description string) (*APIKey, error) {
+	return c.CreateAPIKeyContext(context.Background(), description)
+}
+
+// CreateAPIKeyContext creates an API key for the logged user, the token is only returned once.
+func (c *Client) CreateAPIKeyContext(ctx context.Context, description string) (*APIKey, error) {
+	return c.createAPIKey(ctx, "/v1/api-keys", &APIKeyCreationRequest{Description: description})
+}
+
+// CreateUserAPIKey creates an API key for a user (admin only), the token is only returned once.
+func (c *Client) CreateUserAPIKey(userID int64, description string) (*APIKey, error) {
+	return c.CreateUserAPIKeyContext(context.Background(), userID, description)
+}
+
+// CreateUserAPIKeyContext creates an API key for a user (admin only), the token is only returned once.
+func (c *Client) CreateUserAPIKeyContext(ctx context.Context, userID int64, description string) (*APIKey, error) {
+	return c.createAPIKey(ctx, fmt.Sprintf("/v1/users/%d/api-keys", userID), &APIKeyCreationRequest{Description: description})
+}
+
+// CreateRestrictedAPIKey creates an API key limited to some scopes or categories for the logged user.
+func (c *Client) CreateRestrictedAPIKey(apiKeyCreationRequest *APIKeyCreationRequest) (*APIKey, error) {
+	return c.CreateRestrictedAPIKeyContext(context.Background(), apiKeyCreationRequest)
+}
+
+// CreateRestrictedAPIKeyContext creates an API key limited to some scopes or categories for the logged user.
+func (c *Client) CreateRestrictedAPIKeyContext(ctx context.Context, apiKeyCreationRequest *APIKeyCreationRequest) (*APIKey, error) {
+	return c.createAPIKey(ctx, "/v1/api-keys", apiKeyCreationRequest)
+}
+
+// DeleteAPIKey revokes an API key of the logged user.
+func (c *Client) DeleteAPIKey(keyID int64) error {
+	return c.DeleteAPIKeyContext(context.Background(), keyID)
+}
+
+// DeleteAPIKeyContext revokes an API key of the logged user.
+func (c *Client) DeleteAPIKeyContext(ctx context.Context, keyID int64) error {
+	return c.request.Delete(ctx, fmt.Sprintf("/v1/api-keys/%d", keyID))
+}
+
+// DeleteUserAPIKey revokes an API key of a user (admin only).
+func (c *Client) DeleteUserAPIKey(userID, keyID int64) error {
+	return c.DeleteUserAPIKeyContext(context.Background(), userID, keyID)
+}
+
+// DeleteUserAPIKeyContext revokes an API key of a user (admin only).
+func (c *Client) DeleteUserAPIKeyContext(ctx context.Context, userID, keyID int64) error {
+	return c.request.Delete(ctx, fmt.Sprintf("/v1/users/%d/api-keys/%d", userID, keyID))
+}
+
+// Sessions returns the web sessions of the logged user.
+func (c *Client) Sessions() (Sessions, error) {
+	return c.SessionsContext(context.Background())
+}
+
+// SessionsContext returns the web sessions of the logged user.
+func (c *Client) SessionsContext(ctx context.Context) (Sessions, error) {
+	return c.fetchSessions(ctx, "/v1/sessions")
+}
+
+// UserSessions returns the web sessions of a user (admin only).
+func (c *Client) UserSessions(userID int64) (Sessions, error) {
+	return c.UserSessionsContext(context.Background(), userID)
+}
+
+// UserSessionsContext returns the web sessions of a user (admin only).
+func (c *Client) UserSessionsContext(ctx context.Context, userID int64) (Sessions, error) {
+	return c.fetchSessions(ctx, fmt.Sprintf("/v1/users/%d/sessions", userID))
+}
+
+// DeleteSession revokes a web session of the logged user.
+func (c *Client) DeleteSession(sessionID int64) error {
+	return c.DeleteSessionContext(context.Background(), sessionID)
+}
+
+// DeleteSessionContext revokes a web session of the logged user.
+func (c *Client) DeleteSessionContext(ctx context.Context, sessionID int64) error {
+	return c.request.Delete(ctx, fmt.Sprintf("/v1/sessions/%d", sessionID))
+}
+
+// DeleteSessions revokes all web sessions of the logged user.
+func (c *Client) DeleteSessions() error {
+	return c.DeleteSessionsContext(context.Background())
+}
+
+// DeleteSessionsContext revokes all web sessions of the logged user.
+func (c *Client) DeleteSessionsContext(ctx context.Context) error {
+	return c.request.Delete(ctx, "/v1/sessions")
+}
+
+// DeleteUserSession revokes a web session of a user (admin only).
+func (c *Client) DeleteUserSession(userID, sessionID int64) error {
+	return c.DeleteUserSessionContext(context.Background(), userID, sessionID)
+}
+
+// DeleteUserSessionContext revokes a web session of a user (admin only).
+func (c *Client) DeleteUserSessionContext(ctx context.Context, userID, sessionID int64) error {
+	return c.request.Delete(ctx, fmt.Sprintf("/v1/users/%d/sessions/%d", userID, sessionID))
+}
+
+// DeleteUserSessions revokes all web sessions of a user (admin only).
+func (c *Client) DeleteUserSessions(userID int64) error {
+	return c.DeleteUserSessionsContext(context.Background(), userID)
+}
+
+// DeleteUserSessionsContext revokes all web sessions of a user (admin only).
+func (c *Client) DeleteUserSessionsContext(ctx context.Context, userID int64) error {
+	return c.request.Delete(ctx, fmt.Sprintf("/v1/users/%d/sessions", userID))
+}
+
+func (c *Client) fetchAPIKeys(ctx context.Context, path string) (APIKeys, error) {
+	body, err := c.request.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var apiKeys APIKeys
+	decoder := json.NewDecoder(body)
+	if err := decoder.Decode(&apiKeys); err != nil {
+		return nil, fmt.Errorf("miniflux: response error (%v)", err)
+	}
+
+	return apiKeys, nil
+}
+
+func (c *Client) createAPIKey(ctx context.Context, path string, apiKeyCreationRequest *APIKeyCreationRequest) (*APIKey, error) {
+	body, err := c.request.Post(ctx, path, apiKeyCreationRequest)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var apiKey *APIKey
+	decoder := json.NewDecoder(body)
+	if err := decoder.Decode(&apiKey); err != nil {
+		return nil, fmt.Errorf("miniflux: response error (%v)", err)
+	}
+
+	return apiKey, nil
+}
+
+func (c *Client) fetchSessions(ctx context.Context, path string) (Sessions, error) {
+	body, err := c.request.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var sessions Sessions
+	decoder := json.NewDecoder(body)
+	if err := decoder.Decode(&sessions); err != nil {
+		return nil, fmt.Errorf("miniflux: response error (%v)", err)
+	}
+
+	return sessions, nil
+}
+
+// Integrations returns the integration settings of the logged user.
+func (c *Client) Integrations() (*Integration, error) {
+	return c.IntegrationsContext(context.Background())
+}
+
+// IntegrationsContext returns the integration settings of the logged user.
+func (c *Client) IntegrationsContext(ctx context.Context) (*Integration, error) {
+	body, err := c.request.Get(ctx, "/v1/integrations")
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var integration *Integration
+	decoder := json.NewDecoder(body)
+	if err := decoder.Decode(&integration); err != nil {
+		return nil, fmt.Errorf("miniflux: response error (%v)", err)
+	}
+
+	return integration, nil
+}
+
+// UpdateIntegrations updates the integration settings of the logged user.
+func (c *Client) UpdateIntegrations(integrationChanges *IntegrationModificationRequest) (*Integration, error) {
+	return c.UpdateIntegrationsContext(context.Background(), integrationChanges)
+}
+
+// UpdateIntegrationsContext updates the integration settings of the logged user.
+func (c *Client) UpdateIntegrationsContext(ctx context.Context, integrationChanges *IntegrationModificationRequest) (*Integration, error) {
+	body, err := c.request.Put(ctx, "/v1/integrations", integrationChanges)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var integration *Integration
+	decoder := json.NewDecoder(body)
+	if err := decoder.Decode(&integration); err != nil {
+		return nil, fmt.Errorf("miniflux: response error (%v)", err)
+	}
+
+	return integration, nil
+}
+
 // DeleteUser removes a user from the system.
 func (c *Client) DeleteUser(userID int64) error {
-	return c.request.Delete(fmt.Sprintf("/v1/users/%d", userID))
+	return c.DeleteUserContext(context.Background(), userID)
+}
+
+// DeleteUserContext removes a user from the system.
+func (c *Client) DeleteUserContext(ctx context.Context, userID int64) error {
+	return c.request.Delete(ctx, fmt.Sprintf("/v1/users/%d", userID))
 }
 
 // MarkAllAsRead marks all unread entries as read for a given user.
 func (c *Client) MarkAllAsRead(userID int64) error {
-	_, err := c.request.Put(fmt.Sprintf("/v1/users/%d/mark-all-as-read", userID), nil)
+	return c.MarkAllAsReadContext(context.Background(), userID)
+}
+
+// MarkAllAsReadContext marks all unread entries as read for a given user.
+func (c *Client) MarkAllAsReadContext(ctx context.Context, userID int64) error {
+	_, err := c.request.Put(ctx, fmt.Sprintf("/v1/users/%d/mark-all-as-read", userID), nil)
 	return err
 }
 
 // Discover try to find subscriptions from a website.
 func (c *Client) Discover(url string) (Subscriptions, error) {
-	body, err := c.request.Post("/v1/discover", map[string]string{"url": url})
+	return c.DiscoverContext(context.Background(), url)
+}
+
+// DiscoverContext try to find subscriptions from a website.
+func (c *Client) DiscoverContext(ctx context.Context, url string) (Subscriptions, error) {
+	return c.DiscoverWithOptionsContext(ctx, &DiscoveryRequest{URL: url})
+}
+
+// DiscoverWithOptions try to find subscriptions from a website with a custom user agent, cookie or credentials.
+func (c *Client) DiscoverWithOptions(discoveryRequest *DiscoveryRequest) (Subscriptions, error) {
+	return c.DiscoverWithOptionsContext(context.Background(), discoveryRequest)
+}
+
+// DiscoverWithOptionsContext try to find subscriptions from a website with a custom user agent, cookie or credentials.
+func (c *Client) DiscoverWithOptionsContext(ctx context.Context, discoveryRequest *DiscoveryRequest) (Subscriptions, error) {
+	body, err := c.request.Post(ctx, "/v1/discover", discoveryRequest)
 	if err != nil {
 		return nil, err
 	}
@@ -167,7 +454,12 @@ func (c *Client) Discover(url string) (Subscriptions, error) {
 
 // Categories gets the list of categories.
 func (c *Client) Categories() (Categories, error) {
-	body, err := c.request.Get("/v1/categories")
+	return c.CategoriesContext(context.Background())
+}
+
+// CategoriesContext gets the list of categories.
+func (c *Client) CategoriesContext(ctx context.Context) (Categories, error) {
+	body, err := c.request.Get(ctx, "/v1/categories")
 	if err != nil {
 		return nil, err
 	}
@@ -184,7 +476,12 @@ func (c *Client) Categories() (Categories, error) {
 
 // CreateCategory creates a new category.
 func (c *Client) CreateCategory(title string) (*Category, error) {
-	body, err := c.request.Post("/v1/categories", map[string]interface{}{
+	return c.CreateCategoryContext(context.Background(), title)
+}
+
+// CreateCategoryContext creates a new category.
+func (c *Client) CreateCategoryContext(ctx context.Context, title string) (*Category, error) {
+	body, err := c.request.Post(ctx, "/v1/categories", map[string]interface{}{
 		"title": title,
 	})
 	if err != nil {
@@ -203,7 +500,12 @@ func (c *Client) CreateCategory(title string) (*Category, error) {
 
 // UpdateCategory updates a category.
 func (c *Client) UpdateCategory(categoryID int64, title string) (*Category, error) {
-	body, err := c.request.Put(fmt.Sprintf("/v1/categories/%d", categoryID), map[string]interface{}{
+	return c.UpdateCategoryContext(context.Background(), categoryID, title)
+}
+
+// UpdateCategoryContext updates a category.
+func (c *Client) UpdateCategoryContext(ctx context.Context, categoryID int64, title string) (*Category, error) {
+	body, err := c.request.Put(ctx, fmt.Sprintf("/v1/categories/%d", categoryID), map[string]interface{}{
 		"title": title,
 	})
 	if err != nil {
@@ -222,13 +524,23 @@ func (c *Client) UpdateCategory(categoryID int64, title string) (*Category, erro
 
 // MarkCategoryAsRead marks all unread entries in a category as read.
 func (c *Client) MarkCategoryAsRead(categoryID int64) error {
-	_, err := c.request.Put(fmt.Sprintf("/v1/categories/%d/mark-all-as-read", categoryID), nil)
+	return c.MarkCategoryAsReadContext(context.Background(), categoryID)
+}
+
+// MarkCategoryAsReadContext marks all unread entries in a category as read.
+func (c *Client) MarkCategoryAsReadContext(ctx context.Context, categoryID int64) error {
+	_, err := c.request.Put(ctx, fmt.Sprintf("/v1/categories/%d/mark-all-as-read", categoryID), nil)
 	return err
 }
 
 // CategoryFeeds gets feeds of a category.
 func (c *Client) CategoryFeeds(categoryID int64) (Feeds, error) {
-	body, err := c.request.Get(fmt.Sprintf("/v1/categories/%d/feeds", categoryID))
+	return c.CategoryFeedsContext(context.Background(), categoryID)
+}
+
+// CategoryFeedsContext gets feeds of a category.
+func (c *Client) CategoryFeedsContext(ctx context.Context, categoryID int64) (Feeds, error) {
+	body, err := c.request.Get(ctx, fmt.Sprintf("/v1/categories/%d/feeds", categoryID))
 	if err != nil {
 		return nil, err
 	}
@@ -243,20 +555,181 @@ func (c *Client) CategoryFeeds(categoryID int64) (Feeds, error) {
 	return feeds, nil
 }
 
+// CreateCategoryWithOptions creates a new category, it can be hidden from the unread list.
+func (c *Client) CreateCategoryWithOptions(categoryRequest *CategoryRequest) (*Category, error) {
+	return c.CreateCategoryWithOptionsContext(context.Background(), categoryRequest)
+}
+
+// CreateCategoryWithOptionsContext creates a new category, it can be hidden from the unread list.
+func (c *Client) CreateCategoryWithOptionsContext(ctx context.Context, categoryRequest *CategoryRequest) (*Category, error) {
+	body, err := c.request.Post(ctx, "/v1/categories", categoryRequest)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var category *Category
+	if err := json.NewDecoder(body).Decode(&category); err != nil {
+		return nil, fmt.Errorf("miniflux: response error (%v)", err)
+	}
+
+	return category, nil
+}
+
+// UpdateCategoryWithOptions updates the title of a category and whether it is hidden from the unread list.
+func (c *Client) UpdateCategoryWithOptions(categoryID int64, categoryRequest *CategoryRequest) (*Category, error) {
+	return c.UpdateCategoryWithOptionsContext(context.Background(), categoryID, categoryRequest)
+}
+
+// UpdateCategoryWithOptionsContext updates the title of a category and whether it is hidden from the unread list.
+func (c *Client) UpdateCategoryWithOptionsContext(ctx context.Context, categoryID int64, categoryRequest *CategoryRequest) (*Category, error) {
+	body, err := c.request.Put(ctx, fmt.Sprintf("/v1/categories/%d", categoryID), categoryRequest)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var category *Category
+	if err := json.NewDecoder(body).Decode(&category); err != nil {
+		return nil, fmt.Errorf("miniflux: response error (%v)", err)
+	}
+
+	return category, nil
+}
+
 // DeleteCategory removes a category.
 func (c *Client) DeleteCategory(categoryID int64) error {
-	return c.request.Delete(fmt.Sprintf("/v1/categories/%d", categoryID))
+	return c.DeleteCategoryContext(context.Background(), categoryID)
+}
+
+// DeleteCategoryContext removes a category.
+func (c *Client) DeleteCategoryContext(ctx context.Context, categoryID int64) error {
+	return c.request.Delete(ctx, fmt.Sprintf("/v1/categories/%d", categoryID))
 }
 
 // RefreshCategory refreshes a category.
 func (c *Client) RefreshCategory(categoryID int64) error {
-	_, err := c.request.Put(fmt.Sprintf("/v1/categories/%d/refresh", categoryID), nil)
+	return c.RefreshCategoryContext(context.Background(), categoryID)
+}
+
+// RefreshCategoryContext refreshes a category.
+func (c *Client) RefreshCategoryContext(ctx context.Context, categoryID int64) error {
+	_, err := c.request.Put(ctx, fmt.Sprintf("/v1/categories/%d/refresh", categoryID), nil)
 	return err
 }
 
+// Labels gets the list of labels.
+func (c *Client) Labels() (Labels, error) {
+	return c.LabelsContext(context.Background())
+}
+
+// LabelsContext gets the list of labels.
+func (c *Client) LabelsContext(ctx context.Context) (Labels, error) {
+	body, err := c.request.Get(ctx, "/v1/labels")
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var labels Labels
+	decoder := json.NewDecoder(body)
+	if err := decoder.Decode(&labels); err != nil {
+		return nil, fmt.Errorf("miniflux: response error (%v)", err)
+	}
+
+	return labels, nil
+}
+
+// CreateLabel creates a new label.
+func (c *Client) CreateLabel(title string) (*Label, error) {
+	return c.CreateLabelContext(context.Background(), title)
+}
+
+// CreateLabelContext creates a new label.
+func (c *Client) CreateLabelContext(ctx context.Context, title string) (*Label, error) {
+	body, err := c.request.Post(ctx, "/v1/labels", map[string]interface{}{
+		"title": title,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var label *Label
+	decoder := json.NewDecoder(body)
+	if err := decoder.Decode(&label); err != nil {
+		return nil, fmt.Errorf("miniflux: response error (%v)", err)
+	}
+
+	return label, nil
+}
+
+// UpdateLabel updates a label.
+func (c *Client) UpdateLabel(labelID int64, title string) (*Label, error) {
+	return c.UpdateLabelContext(context.Background(), labelID, title)
+}
+
+// UpdateLabelContext updates a label.
+func (c *Client) UpdateLabelContext(ctx context.Context, labelID int64, title string) (*Label, error) {
+	body, err := c.request.Put(ctx, fmt.Sprintf("/v1/labels/%d", labelID), map[string]interface{}{
+		"title": title,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var label *Label
+	decoder := json.NewDecoder(body)
+	if err := decoder.Decode(&label); err != nil {
+		return nil, fmt.Errorf("miniflux: response error (%v)", err)
+	}
+
+	return label, nil
+}
+
+// DeleteLabel removes a label.
+func (c *Client) DeleteLabel(labelID int64) error {
+	return c.DeleteLabelContext(context.Background(), labelID)
+}
+
+// DeleteLabelContext removes a label.
+func (c *Client) DeleteLabelContext(ctx context.Context, labelID int64) error {
+	return c.request.Delete(ctx, fmt.Sprintf("/v1/labels/%d", labelID))
+}
+
+// LabelEntries fetch entries having the given label.
+func (c *Client) LabelEntries(labelID int64, filter *Filter) (*EntryResultSet, error) {
+	return c.LabelEntriesContext(context.Background(), labelID, filter)
+}
+
+// LabelEntriesContext fetch entries having the given label.
+func (c *Client) LabelEntriesContext(ctx context.Context, labelID int64, filter *Filter) (*EntryResultSet, error) {
+	path := buildFilterQueryString(fmt.Sprintf("/v1/labels/%d/entries", labelID), filter)
+
+	body, err := c.request.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var result EntryResultSet
+	decoder := json.NewDecoder(body)
+	if err := decoder.Decode(&result); err != nil {
+		return nil, fmt.Errorf("miniflux: response error (%v)", err)
+	}
+
+	return &result, nil
+}
+
 // Feeds gets all feeds.
 func (c *Client) Feeds() (Feeds, error) {
-	body, err := c.request.Get("/v1/feeds")
+	return c.FeedsContext(context.Background())
+}
+
+// FeedsContext gets all feeds.
+func (c *Client) FeedsContext(ctx context.Context) (Feeds, error) {
+	body, err := c.request.Get(ctx, "/v1/feeds")
 	if err != nil {
 		return nil, err
 	}
@@ -273,7 +746,12 @@ func (c *Client) Feeds() (Feeds, error) {
 
 // Export creates OPML file.
 func (c *Client) Export() ([]byte, error) {
-	body, err := c.request.Get("/v1/export")
+	return c.ExportContext(context.Background())
+}
+
+// ExportContext creates OPML file.
+func (c *Client) ExportContext(ctx context.Context) ([]byte, error) {
+	body, err := c.request.Get(ctx, "/v1/export")
 	if err != nil {
 		return nil, err
 	}
@@ -289,13 +767,44 @@ func (c *Client) Export() ([]byte, error) {
 
 // Import imports an OPML file.
 func (c *Client) Import(f io.ReadCloser) error {
-	_, err := c.request.PostFile("/v1/import", f)
+	return c.ImportContext(context.Background(), f)
+}
+
+// ImportContext imports an OPML file.
+func (c *Client) ImportContext(ctx context.Context, f io.ReadCloser) error {
+	_, err := c.request.PostFile(ctx, "/v1/import", f)
 	return err
 }
 
+// Icon gets an icon by its ID, see Feed.Icon.
+func (c *Client) Icon(iconID int64) (*FeedIcon, error) {
+	return c.IconContext(context.Background(), iconID)
+}
+
+// IconContext gets an icon by its ID, see Feed.Icon.
+func (c *Client) IconContext(ctx context.Context, iconID int64) (*FeedIcon, error) {
+	body, err := c.request.Get(ctx, fmt.Sprintf("/v1/icons/%d", iconID))
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var icon *FeedIcon
+	if err := json.NewDecoder(body).Decode(&icon); err != nil {
+		return nil, fmt.Errorf("miniflux: response error (%v)", err)
+	}
+
+	return icon, nil
+}
+
 // Feed gets a feed.
 func (c *Client) Feed(feedID int64) (*Feed, error) {
-	body, err := c.request.Get(fmt.Sprintf("/v1/feeds/%d", feedID))
+	return c.FeedContext(context.Background(), feedID)
+}
+
+// FeedContext gets a feed.
+func (c *Client) FeedContext(ctx context.Context, feedID int64) (*Feed, error) {
+	body, err := c.request.Get(ctx, fmt.Sprintf("/v1/feeds/%d", feedID))
 	if err != nil {
 		return nil, err
 	}
@@ -312,7 +821,12 @@ func (c *Client) Feed(feedID int64) (*Feed, error) {
 
 // CreateFeed creates a new feed.
 func (c *Client) CreateFeed(feedCreationRequest *FeedCreationRequest) (int64, error) {
-	body, err := c.request.Post("/v1/feeds", feedCreationRequest)
+	return c.CreateFeedContext(context.Background(), feedCreationRequest)
+}
+
+// CreateFeedContext creates a new feed.
+func (c *Client) CreateFeedContext(ctx context.Context, feedCreationRequest *FeedCreationRequest) (int64, error) {
+	body, err := c.request.Post(ctx, "/v1/feeds", feedCreationRequest)
 	if err != nil {
 		return 0, err
 	}
@@ -333,7 +847,12 @@ func (c *Client) CreateFeed(feedCreationRequest *FeedCreationRequest) (int64, er
 
 // UpdateFeed updates a feed.
 func (c *Client) UpdateFeed(feedID int64, feedChanges *FeedModificationRequest) (*Feed, error) {
-	body, err := c.request.Put(fmt.Sprintf("/v1/feeds/%d", feedID), feedChanges)
+	return c.UpdateFeedContext(context.Background(), feedID, feedChanges)
+}
+
+// UpdateFeedContext updates a feed.
+func (c *Client) UpdateFeedContext(ctx context.Context, feedID int64, feedChanges *FeedModificationRequest) (*Feed, error) {
+	body, err := c.request.Put(ctx, fmt.Sprintf("/v1/feeds/%d", feedID), feedChanges)
 	if err != nil {
 		return nil, err
 	}
@@ -349,30 +868,55 @@ func (c *Client) UpdateFeed(feedID int64, feedChanges *FeedModificationRequest)
 
 // MarkFeedAsRead marks all unread entries of the feed as read.
 func (c *Client) MarkFeedAsRead(feedID int64) error {
-	_, err := c.request.Put(fmt.Sprintf("/v1/feeds/%d/mark-all-as-read", feedID), nil)
+	return c.MarkFeedAsReadContext(context.Background(), feedID)
+}
+
+// MarkFeedAsReadContext marks all unread entries of the feed as read.
+func (c *Client) MarkFeedAsReadContext(ctx context.Context, feedID int64) error {
+	_, err := c.request.Put(ctx, fmt.Sprintf("/v1/feeds/%d/mark-all-as-read", feedID), nil)
 	return err
 }
 
 // RefreshAllFeeds refreshes all feeds.
 func (c *Client) RefreshAllFeeds() error {
-	_, err := c.request.Put("/v1/feeds/refresh", nil)
+	return c.RefreshAllFeedsContext(context.Background())
+}
+
+// RefreshAllFeedsContext refreshes all feeds.
+func (c *Client) RefreshAllFeedsContext(ctx context.Context) error {
+	_, err := c.request.Put(ctx, "/v1/feeds/refresh", nil)
 	return err
 }
 
 // RefreshFeed refreshes a feed.
 func (c *Client) RefreshFeed(feedID int64) error {
-	_, err := c.request.Put(fmt.Sprintf("/v1/feeds/%d/refresh", feedID), nil)
+	return c.RefreshFeedContext(context.Background(), feedID)
+}
+
+// RefreshFeedContext refreshes a feed.
+func (c *Client) RefreshFeedContext(ctx context.Context, feedID int64) error {
+	_, err := c.request.Put(ctx, fmt.Sprintf("/v1/feeds/%d/refresh", feedID), nil)
 	return err
 }
 
 // DeleteFeed removes a feed.
 func (c *Client) DeleteFeed(feedID int64) error {
-	return c.request.Delete(fmt.Sprintf("/v1/feeds/%d", feedID))
+	return c.DeleteFeedContext(context.Background(), feedID)
+}
+
+// DeleteFeedContext removes a feed.
+func (c *Client) DeleteFeedContext(ctx context.Context, feedID int64) error {
+	return c.request.Delete(ctx, fmt.Sprintf("/v1/feeds/%d", feedID))
 }
 
 // FeedIcon gets a feed icon.
 func (c *Client) FeedIcon(feedID int64) (*FeedIcon, error) {
-	body, err := c.request.Get(fmt.Sprintf("/v1/feeds/%d/icon", feedID))
+	return c.FeedIconContext(context.Background(), feedID)
+}
+
+// FeedIconContext gets a feed icon.
+func (c *Client) FeedIconContext(ctx context.Context, feedID int64) (*FeedIcon, error) {
+	body, err := c.request.Get(ctx, fmt.Sprintf("/v1/feeds/%d/icon", feedID))
 	if err != nil {
 		return nil, err
 	}
@@ -389,7 +933,12 @@ func (c *Client) FeedIcon(feedID int64) (*FeedIcon, error) {
 
 // FeedEntry gets a single feed entry.
 func (c *Client) FeedEntry(feedID, entryID int64) (*Entry, error) {
-	body, err := c.request.Get(fmt.Sprintf("/v1/feeds/%d/entries/%d", feedID, entryID))
+	return c.FeedEntryContext(context.Background(), feedID, entryID)
+}
+
+// FeedEntryContext gets a single feed entry.
+func (c *Client) FeedEntryContext(ctx context.Context, feedID, entryID int64) (*Entry, error) {
+	body, err := c.request.Get(ctx, fmt.Sprintf("/v1/feeds/%d/entries/%d", feedID, entryID))
 	if err != nil {
 		return nil, err
 	}
@@ -406,7 +955,12 @@ func (c *Client) FeedEntry(feedID, entryID int64) (*Entry, error) {
 
 // CategoryEntry gets a single category entry.
 func (c *Client) CategoryEntry(categoryID, entryID int64) (*Entry, error) {
-	body, err := c.request.Get(fmt.Sprintf("/v1/categories/%d/entries/%d", categoryID, entryID))
+	return c.CategoryEntryContext(context.Background(), categoryID, entryID)
+}
+
+// CategoryEntryContext gets a single category entry.
+func (c *Client) CategoryEntryContext(ctx context.Context, categoryID, entryID int64) (*Entry, error) {
+	body, err := c.request.Get(ctx, fmt.Sprintf("/v1/categories/%d/entries/%d", categoryID, entryID))
 	if err != nil {
 		return nil, err
 	}
@@ -423,7 +977,12 @@ func (c *Client) CategoryEntry(categoryID, entryID int64) (*Entry, error) {
 
 // Entry gets a single entry.
 func (c *Client) Entry(entryID int64) (*Entry, error) {
-	body, err := c.request.Get(fmt.Sprintf("/v1/entries/%d", entryID))
+	return c.EntryContext(context.Background(), entryID)
+}
+
+// EntryContext gets a single entry.
+func (c *Client) EntryContext(ctx context.Context, entryID int64) (*Entry, error) {
+	body, err := c.request.Get(ctx, fmt.Sprintf("/v1/entries/%d", entryID))
 	if err != nil {
 		return nil, err
 	}
@@ -438,11 +997,41 @@ func (c *Client) Entry(entryID int64) (*Entry, error) {
 	return entry, nil
 }
 
+// FetchEntryOriginalContent downloads the original web page of an entry and returns the extracted content.
+// The content of the entry is replaced on the server.
+func (c *Client) FetchEntryOriginalContent(entryID int64) (string, error) {
+	return c.FetchEntryOriginalContentContext(context.Background(), entryID)
+}
+
+// FetchEntryOriginalContentContext downloads the original web page of an entry and returns the extracted content.
+// The content of the entry is replaced on the server.
+func (c *Client) FetchEntryOriginalContentContext(ctx context.Context, entryID int64) (string, error) {
+	body, err := c.request.Get(ctx, fmt.Sprintf("/v1/entries/%d/fetch-content", entryID))
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+
+	var response struct {
+		Content string `json:"content"`
+	}
+	if err := json.NewDecoder(body).Decode(&response); err != nil {
+		return "", fmt.Errorf("miniflux: response error (%v)", err)
+	}
+
+	return response.Content, nil
+}
+
 // Entries fetch entries.
 func (c *Client) Entries(filter *Filter) (*EntryResultSet, error) {
+	return c.EntriesContext(context.Background(), filter)
+}
+
+// EntriesContext fetch entries.
+func (c *Client) EntriesContext(ctx context.Context, filter *Filter) (*EntryResultSet, error) {
 	path := buildFilterQueryString("/v1/entries", filter)
 
-	body, err := c.request.Get(path)
+	body, err := c.request.Get(ctx, path)
 	if err != nil {
 		return nil, err
 	}
@@ -459,9 +1048,14 @@ func (c *Client) Entries(filter *Filter) (*EntryResultSet, error) {
 
 // FeedEntries fetch feed entries.
 func (c *Client) FeedEntries(feedID int64, filter *Filter) (*EntryResultSet, error) {
+	return c.FeedEntriesContext(context.Background(), feedID, filter)
+}
+
+// FeedEntriesContext fetch feed entries.
+func (c *Client) FeedEntriesContext(ctx context.Context, feedID int64, filter *Filter) (*EntryResultSet, error) {
 	path := buildFilterQueryString(fmt.Sprintf("/v1/feeds/%d/entries", feedID), filter)
 
-	body, err := c.request.Get(path)
+	body, err := c.request.Get(ctx, path)
 	if err != nil {
 		return nil, err
 	}
@@ -478,9 +1072,14 @@ func (c *Client) FeedEntries(feedID int64, filter *Filter) (*EntryResultSet, err
 
 // CategoryEntries fetch entries of a category.
 func (c *Client) CategoryEntries(categoryID int64, filter *Filter) (*EntryResultSet, error) {
+	return c.CategoryEntriesContext(context.Background(), categoryID, filter)
+}
+
+// CategoryEntriesContext fetch entries of a category.
+func (c *Client) CategoryEntriesContext(ctx context.Context, categoryID int64, filter *Filter) (*EntryResultSet, error) {
 	path := buildFilterQueryString(fmt.Sprintf("/v1/categories/%d/entries", categoryID), filter)
 
-	body, err := c.request.Get(path)
+	body, err := c.request.Get(ctx, path)
 	if err != nil {
 		return nil, err
 	}
@@ -497,24 +1096,214 @@ func (c *Client) CategoryEntries(categoryID int64, filter *Filter) (*EntryResult
 
 // UpdateEntries updates the status of a list of entries.
 func (c *Client) UpdateEntries(entryIDs []int64, status string) error {
+	return c.UpdateEntriesContext(context.Background(), entryIDs, status)
+}
+
+// UpdateEntriesContext updates the status of a list of entries.
+func (c *Client) UpdateEntriesContext(ctx context.Context, entryIDs []int64, status string) error {
 	type payload struct {
 		EntryIDs []int64 `json:"entry_ids"`
 		Status   string  `json:"status"`
 	}
 
-	_, err := c.request.Put("/v1/entries", &payload{EntryIDs: entryIDs, Status: status})
+	_, err := c.request.Put(ctx, "/v1/entries", &payload{EntryIDs: entryIDs, Status: status})
 	return err
 }
 
 // ToggleBookmark toggles entry bookmark value.
 func (c *Client) ToggleBookmark(entryID int64) error {
-	_, err := c.request.Put(fmt.Sprintf("/v1/entries/%d/bookmark", entryID), nil)
+	return c.ToggleBookmarkContext(context.Background(), entryID)
+}
+
+// ToggleBookmarkContext toggles entry bookmark value.
+func (c *Client) ToggleBookmarkContext(ctx context.Context, entryID int64) error {
+	_, err := c.request.Put(ctx, fmt.Sprintf("/v1/entries/%d/bookmark", entryID), nil)
 	return err
 }
 
-// FetchCounters
+// UpdateEntryLabels replaces the labels of an entry, unknown labels are created.
+func (c *Client) UpdateEntryLabels(entryID int64, labels []string) error {
+	return c.UpdateEntryLabelsContext(context.Background(), entryID, labels)
+}
+
+// UpdateEntryLabelsContext replaces the labels of an entry, unknown labels are created.
+func (c *Client) UpdateEntryLabelsContext(ctx context.Context, entryID int64, labels []string) error {
+	type payload struct {
+		Labels []string `json:"labels"`
+	}
+
+	_, err := c.request.Put(ctx, fmt.Sprintf("/v1/entries/%d/labels", entryID), &payload{Labels: labels})
+	return err
+}
+
+// EntryRevisions fetches the previous versions of an entry.
+func (c *Client) EntryRevisions(entryID int64) (EntryRevisions, error) {
+	return c.EntryRevisionsContext(context.Background(), entryID)
+}
+
+// EntryRevisionsContext fetches the previous versions of an entry.
+func (c *Client) EntryRevisionsContext(ctx context.Context, entryID int64) (EntryRevisions, error) {
+	body, err := c.request.Get(ctx, fmt.Sprintf("/v1/entries/%d/revisions", entryID))
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var revisions EntryRevisions
+	decoder := json.NewDecoder(body)
+	if err := decoder.Decode(&revisions); err != nil {
+		return nil, fmt.Errorf("miniflux: response error (%v)", err)
+	}
+
+	return revisions, nil
+}
+
+// EntryRevisionDiff fetches the changes introduced after an entry revision.
+func (c *Client) EntryRevisionDiff(entryID, revisionID int64) (*EntryRevisionDiff, error) {
+	return c.EntryRevisionDiffContext(context.Background(), entryID, revisionID)
+}
+
+// EntryRevisionDiffContext fetches the changes introduced after an entry revision.
+func (c *Client) EntryRevisionDiffContext(ctx context.Context, entryID, revisionID int64) (*EntryRevisionDiff, error) {
+	body, err := c.request.Get(ctx, fmt.Sprintf("/v1/entries/%d/revisions/%d/diff", entryID, revisionID))
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var revisionDiff *EntryRevisionDiff
+	decoder := json.NewDecoder(body)
+	if err := decoder.Decode(&revisionDiff); err != nil {
+		return nil, fmt.Errorf("miniflux: response error (%v)", err)
+	}
+
+	return revisionDiff, nil
+}
+
+// EntryAnnotations gets the highlights and notes of an entry.
+func (c *Client) EntryAnnotations(entryID int64) (Annotations, error) {
+	return c.EntryAnnotationsContext(context.Background(), entryID)
+}
+
+// EntryAnnotationsContext gets the highlights and notes of an entry.
+func (c *Client) EntryAnnotationsContext(ctx context.Context, entryID int64) (Annotations, error) {
+	body, err := c.request.Get(ctx, fmt.Sprintf("/v1/entries/%d/annotations", entryID))
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var annotations Annotations
+	decoder := json.NewDecoder(body)
+	if err := decoder.Decode(&annotations); err != nil {
+		return nil, fmt.Errorf("miniflux: response error (%v)", err)
+	}
+
+	return annotations, nil
+}
+
+// CreateAnnotation adds a highlight or a note to an entry.
+func (c *Client) CreateAnnotation(entryID int64, annotationRequest *AnnotationRequest) (*Annotation, error) {
+	return c.CreateAnnotationContext(context.Background(), entryID, annotationRequest)
+}
+
+// CreateAnnotationContext adds a highlight or a note to an entry.
+func (c *Client) CreateAnnotationContext(ctx context.Context, entryID int64, annotationRequest *AnnotationRequest) (*Annotation, error) {
+	body, err := c.request.Post(ctx, fmt.Sprintf("/v1/entries/%d/annotations", entryID), annotationRequest)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var annotation *Annotation
+	decoder := json.NewDecoder(body)
+	if err := decoder.Decode(&annotation); err != nil {
+		return nil, fmt.Errorf("miniflux: response error (%v)", err)
+	}
+
+	return annotation, nil
+}
+
+// UpdateAnnotation updates the note of an annotation.
+func (c *Client) UpdateAnnotation(annotationID int64, note string) (*Annotation, error) {
+	return c.UpdateAnnotationContext(context.Background(), annotationID, note)
+}
+
+// UpdateAnnotationContext updates the note of an annotation.
+func (c *Client) UpdateAnnotationContext(ctx context.Context, annotationID int64, note string) (*Annotation, error) {
+	body, err := c.request.Put(ctx, fmt.Sprintf("/v1/annotations/%d", annotationID), map[string]interface{}{
+		"note": note,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var annotation *Annotation
+	decoder := json.NewDecoder(body)
+	if err := decoder.Decode(&annotation); err != nil {
+		return nil, fmt.Errorf("miniflux: response error (%v)", err)
+	}
+
+	return annotation, nil
+}
+
+// DeleteAnnotation removes an annotation.
+func (c *Client) DeleteAnnotation(annotationID int64) error {
+	return c.DeleteAnnotationContext(context.Background(), annotationID)
+}
+
+// DeleteAnnotationContext removes an annotation.
+func (c *Client) DeleteAnnotationContext(ctx context.Context, annotationID int64) error {
+	return c.request.Delete(ctx, fmt.Sprintf("/v1/annotations/%d", annotationID))
+}
+
+// Annotations gets all highlights and notes, an optional full-text search query can be given.
+func (c *Client) Annotations(search string) (Annotations, error) {
+	return c.AnnotationsContext(context.Background(), search)
+}
+
+// AnnotationsContext gets all highlights and notes, an optional full-text search query can be given.
+func (c *Client) AnnotationsContext(ctx context.Context, search string) (Annotations, error) {
+	body, err := c.request.Get(ctx, buildAnnotationQueryString("/v1/annotations", search))
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var annotations Annotations
+	decoder := json.NewDecoder(body)
+	if err := decoder.Decode(&annotations); err != nil {
+		return nil, fmt.Errorf("miniflux: response error (%v)", err)
+	}
+
+	return annotations, nil
+}
+
+// ExportAnnotations exports highlights and notes as a Markdown document.
+func (c *Client) ExportAnnotations(search string) ([]byte, error) {
+	return c.ExportAnnotationsContext(context.Background(), search)
+}
+
+// ExportAnnotationsContext exports highlights and notes as a Markdown document.
+func (c *Client) ExportAnnotationsContext(ctx context.Context, search string) ([]byte, error) {
+	body, err := c.request.Get(ctx, buildAnnotationQueryString("/v1/annotations/export", search))
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	return io.ReadAll(body)
+}
+
+// FetchCounters fetches the number of read and unread entries of each feed.
 func (c *Client) FetchCounters() (*FeedCounters, error) {
-	body, err := c.request.Get("/v1/feeds/counters")
+	return c.FetchCountersContext(context.Background())
+}
+
+// FetchCountersContext fetches the number of read and unread entries of each feed.
+func (c *Client) FetchCountersContext(ctx context.Context) (*FeedCounters, error) {
+	body, err := c.request.Get(ctx, "/v1/feeds/counters")
 	if err != nil {
 		return nil, err
 	}
@@ -529,6 +1318,22 @@ func (c *Client) FetchCounters() (*FeedCounters, error) {
 	return &result, nil
 }
 
+// OpenAPISpecification returns the OpenAPI document describing the API of the server.
+func (c *Client) OpenAPISpecification() ([]byte, error) {
+	return c.OpenAPISpecificationContext(context.Background())
+}
+
+// OpenAPISpecificationContext returns the OpenAPI document describing the API of the server.
+func (c *Client) OpenAPISpecificationContext(ctx context.Context) ([]byte, error) {
+	body, err := c.request.Get(ctx, "/v1/openapi.json")
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	return io.ReadAll(body)
+}
+
 func buildFilterQueryString(path string, filter *Filter) string {
 	if filter != nil {
 		values := url.Values{}
@@ -549,7 +1354,9 @@ func buildFilterQueryString(path string, filter *Filter) string {
 			values.Set("limit", strconv.Itoa(filter.Limit))
 		}
 
-		if filter.Offset >= 0 {
+		if filter.Cursor != "" {
+			values.Set("cursor", filter.Cursor)
+		} else if filter.Offset >= 0 {
 			values.Set("offset", strconv.Itoa(filter.Offset))
 		}
 
@@ -585,12 +1392,30 @@ func buildFilterQueryString(path string, filter *Filter) string {
 			values.Set("feed_id", strconv.FormatInt(filter.FeedID, 10))
 		}
 
+		if filter.LabelID > 0 {
+			values.Set("label_id", strconv.FormatInt(filter.LabelID, 10))
+		}
+
 		for _, status := range filter.Statuses {
 			values.Add("status", status)
 		}
 
+		for _, tag := range filter.Tags {
+			values.Add("tags", tag)
+		}
+
 		path = fmt.Sprintf("%s?%s", path, values.Encode())
 	}
 
 	return path
 }
+
+func buildAnnotationQueryString(path, search string) string {
+	if search == "" {
+		return path
+	}
+
+	values := url.Values{}
+	values.Set("search", search)
+	return fmt.Sprintf("%s?%s", path, values.Encode())
+}
@@ -0,0 +1,323 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package client // import "miniflux.app/client"
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/v1/feeds/42" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error_message":"Feed not found"}`)
+			return
+		}
+
+		w.WriteHeader(http.StatusConflict)
+		fmt.Fprint(w, `{"error_message":"Duplicate"}`)
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "token")
+	_, err := client.Feed(42)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf(`The error should match ErrNotFound: %v`, err)
+	}
+
+	var apiError *APIError
+	if !errors.As(err, &apiError) || apiError.StatusCode != http.StatusNotFound || apiError.Message != "Feed not found" {
+		t.Fatalf(`The error should be an APIError with the message of the server: %#v`, err)
+	}
+
+	_, err = client.Feed(43)
+	if !errors.As(err, &apiError) {
+		t.Fatalf(`The error should be an APIError: %T`, err)
+	}
+
+	if apiError.StatusCode != http.StatusConflict || apiError.Message != "Duplicate" {
+		t.Errorf(`Unexpected error: %+v`, apiError)
+	}
+
+	if errors.Is(err, ErrForbidden) {
+		t.Error(`The error should not match ErrForbidden`)
+	}
+}
+
+func TestAPIErrorMessages(t *testing.T) {
+	scenarios := []struct {
+		err      *APIError
+		expected string
+	}{
+		{&APIError{StatusCode: 400, Message: "Invalid URL"}, "miniflux: bad request (Invalid URL)"},
+		{&APIError{StatusCode: 401}, "miniflux: unauthorized (bad credentials)"},
+		{&APIError{StatusCode: 500, Message: "Database error"}, "miniflux: internal server error: Database error"},
+		{&APIError{StatusCode: 503}, "miniflux: internal server error"},
+		{&APIError{StatusCode: 409, Message: "Duplicate"}, "miniflux: status code=409 (Duplicate)"},
+	}
+
+	for _, scenario := range scenarios {
+		if scenario.err.Error() != scenario.expected {
+			t.Errorf(`Unexpected message for status %d: got %q instead of %q`, scenario.err.StatusCode, scenario.err.Error(), scenario.expected)
+		}
+	}
+}
+
+func TestRetry(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&attempts, 1) {
+		case 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			fmt.Fprint(w, `{"id":1,"username":"admin"}`)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClientWithOptions(server.URL, WithAPIKey("token"), WithRetry(2, time.Millisecond))
+	user, err := client.Me()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if user.Username != "admin" || atomic.LoadInt32(&attempts) != 3 {
+		t.Errorf(`Unexpected result after %d attempts: %+v`, attempts, user)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewClientWithOptions(server.URL, WithAPIKey("token"), WithRetry(1, time.Millisecond))
+	if _, err := client.Me(); !errors.Is(err, ErrServerError) {
+		t.Fatalf(`Unexpected error: %v`, err)
+	}
+
+	if atomic.LoadInt32(&attempts) != 2 {
+		t.Errorf(`The request should have been sent twice instead of %d times`, attempts)
+	}
+}
+
+func TestRetryResendsBody(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Title string `json:"title"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Title != "News" {
+			t.Errorf(`Unexpected body: %+v (%v)`, body, err)
+		}
+
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id":7,"title":"News"}`)
+	}))
+	defer server.Close()
+
+	client := NewClientWithOptions(server.URL, WithAPIKey("token"), WithRetry(3, time.Millisecond))
+	category, err := client.CreateCategoryContext(AllowRetry(context.Background()), "News")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if category.ID != 7 || atomic.LoadInt32(&attempts) != 2 {
+		t.Errorf(`Unexpected category after %d attempts: %+v`, attempts, category)
+	}
+}
+
+func TestRetrySkipsNonIdempotentRequests(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := NewClientWithOptions(server.URL, WithAPIKey("token"), WithRetry(3, time.Millisecond))
+	if _, err := client.CreateCategory("News"); !errors.Is(err, ErrServerError) {
+		t.Fatalf(`Unexpected error: %v`, err)
+	}
+
+	if atomic.LoadInt32(&attempts) != 1 {
+		t.Errorf(`A POST request should not be retried without AllowRetry, it was sent %d times`, attempts)
+	}
+}
+
+func TestRetryStopsWithContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	client := NewClientWithOptions(server.URL, WithAPIKey("token"), WithRetry(5, time.Millisecond))
+	if _, err := client.MeContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf(`Unexpected error: %v`, err)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	r := &request{backoff: time.Second}
+
+	if delay := r.retryDelay(0, ""); delay != time.Second {
+		t.Errorf(`Unexpected first delay: %v`, delay)
+	}
+
+	if delay := r.retryDelay(3, ""); delay != 8*time.Second {
+		t.Errorf(`Unexpected fourth delay: %v`, delay)
+	}
+
+	if delay := r.retryDelay(30, ""); delay != maxBackoff {
+		t.Errorf(`The delay should be capped: %v`, delay)
+	}
+
+	if delay := r.retryDelay(0, "3"); delay != 3*time.Second {
+		t.Errorf(`The Retry-After header should be used: %v`, delay)
+	}
+}
+
+func TestWithHTTPClient(t *testing.T) {
+	var transportHeader string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		transportHeader = r.Header.Get("X-Transport")
+		fmt.Fprint(w, `[]`)
+	}))
+	defer server.Close()
+
+	httpClient := &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		r.Header.Set("X-Transport", "custom")
+		return http.DefaultTransport.RoundTrip(r)
+	})}
+
+	client := NewClientWithOptions(server.URL, WithAPIKey("token"), WithHTTPClient(httpClient))
+	if _, err := client.Feeds(); err != nil {
+		t.Fatal(err)
+	}
+
+	if transportHeader != "custom" {
+		t.Error(`The custom HTTP client should be used`)
+	}
+}
+
+func TestEntriesIterator(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("status") != EntryStatusUnread {
+			t.Errorf(`The filter should be sent: %s`, r.URL.RawQuery)
+		}
+
+		page, _ := strconv.Atoi(r.URL.Query().Get("cursor"))
+		switch page {
+		case 0:
+			fmt.Fprint(w, `{"total":3,"entries":[{"id":1},{"id":2}],"next_cursor":"1"}`)
+		default:
+			fmt.Fprint(w, `{"total":3,"entries":[{"id":3}]}`)
+		}
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "token")
+	entries := client.EntriesIteratorContext(context.Background(), &Filter{Status: EntryStatusUnread, Limit: 2})
+
+	var ids []int64
+	for entries.Next() {
+		ids = append(ids, entries.Entry().ID)
+	}
+
+	if err := entries.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ids) != 3 || ids[0] != 1 || ids[2] != 3 {
+		t.Errorf(`Unexpected entries: %v`, ids)
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestEventStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "retry: 5000\n\n")
+		fmt.Fprint(w, "event: counters\ndata: {\"unread_count\":3}\n\n")
+		fmt.Fprint(w, ": keep-alive\n\n")
+		fmt.Fprint(w, "event: new_entries\ndata: [1,\ndata: 2]\n\n")
+	}))
+	defer server.Close()
+
+	events, err := New(server.URL, "token").Events()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer events.Close()
+
+	var received []*Event
+	for events.Next() {
+		received = append(received, events.Event())
+	}
+
+	if err := events.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(received) != 2 {
+		t.Fatalf(`Unexpected events: %+v`, received)
+	}
+
+	if received[0].Type != EventCounters || string(received[0].Data) != `{"unread_count":3}` {
+		t.Errorf(`Unexpected first event: %s %s`, received[0].Type, received[0].Data)
+	}
+
+	if received[1].Type != EventNewEntries || string(received[1].Data) != "[1,\n2]" {
+		t.Errorf(`Unexpected second event: %s %s`, received[1].Type, received[1].Data)
+	}
+}
+
+func TestFilterTags(t *testing.T) {
+	path := buildFilterQueryString("/v1/entries", &Filter{Limit: -1, Offset: -1, Tags: []string{"go", "rss"}})
+	if path != "/v1/entries?tags=go&tags=rss" {
+		t.Errorf(`Unexpected query string: %s`, path)
+	}
+}
+
+func TestCategoryRequest(t *testing.T) {
+	payload, err := json.Marshal(&CategoryRequest{Title: "News", HideGlobally: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(payload) != `{"hide_globally":"on","title":"News"}` {
+		t.Errorf(`Unexpected payload: %s`, payload)
+	}
+}
@@ -29,5 +29,42 @@ This one discover subscriptions on a website:
 		return
 	}
 	fmt.Println(subscriptions)
+
+This one iterates over all unread entries, one page at a time:
+
+	entries := client.EntriesIterator(&miniflux.Filter{Status: miniflux.EntryStatusUnread, Limit: 50})
+	for entries.Next() {
+		fmt.Println(entries.Entry().Title)
+	}
+	if err := entries.Err(); err != nil {
+		fmt.Println(err)
+	}
+
+Every method has a variant accepting a context, the client can also be configured with options:
+
+	client := miniflux.NewClientWithOptions(
+		"https://api.example.org",
+		miniflux.WithAPIKey("my-secret-token"),
+		miniflux.WithHTTPClient(&http.Client{Timeout: 10 * time.Second}),
+		miniflux.WithRetry(3, time.Second),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	feed, err := client.FeedContext(ctx, 42)
+
+The API errors are returned as *APIError, they match ErrNotAuthorized, ErrForbidden, ErrNotFound and ErrServerError with errors.Is:
+
+	if errors.Is(err, miniflux.ErrNotFound) {
+		fmt.Println("The feed does not exist")
+	}
+
+They also carry the status code and the message sent by the server:
+
+	var apiError *miniflux.APIError
+	if errors.As(err, &apiError) {
+		fmt.Println(apiError.StatusCode, apiError.Message)
+	}
 */
 package client // import "miniflux.app/client"
@@ -0,0 +1,75 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package client // import "miniflux.app/client"
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// List of exposed errors.
+var (
+	ErrNotAuthorized = errors.New("miniflux: unauthorized (bad credentials)")
+	ErrForbidden     = errors.New("miniflux: access forbidden")
+	ErrServerError   = errors.New("miniflux: internal server error")
+	ErrNotFound      = errors.New("miniflux: resource not found")
+)
+
+// APIError is returned when the API responds with an error status code.
+//
+// The errors still match ErrNotAuthorized, ErrForbidden, ErrNotFound and ErrServerError with errors.Is:
+//
+//	if errors.Is(err, miniflux.ErrNotFound) {
+//		...
+//	}
+type APIError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+
+	// Message is the error message sent by the API, it can be empty.
+	Message string
+}
+
+func (e *APIError) Error() string {
+	switch {
+	case e.StatusCode == http.StatusBadRequest && e.Message != "":
+		return fmt.Sprintf("miniflux: bad request (%s)", e.Message)
+	case e.StatusCode == http.StatusBadRequest:
+		return "miniflux: bad request"
+	case e.StatusCode == http.StatusInternalServerError && e.Message != "":
+		return "miniflux: internal server error: " + e.Message
+	}
+
+	if sentinel := e.sentinel(); sentinel != nil {
+		return sentinel.Error()
+	}
+
+	if e.Message != "" {
+		return fmt.Sprintf("miniflux: status code=%d (%s)", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("miniflux: status code=%d", e.StatusCode)
+}
+
+// Is reports whether the error corresponds to one of the exposed errors.
+func (e *APIError) Is(target error) bool {
+	sentinel := e.sentinel()
+	return sentinel != nil && sentinel == target
+}
+
+func (e *APIError) sentinel() error {
+	switch {
+	case e.StatusCode == http.StatusUnauthorized:
+		return ErrNotAuthorized
+	case e.StatusCode == http.StatusForbidden:
+		return ErrForbidden
+	case e.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case e.StatusCode >= http.StatusInternalServerError:
+		return ErrServerError
+	default:
+		return nil
+	}
+}
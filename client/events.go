@@ -0,0 +1,99 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package client // import "miniflux.app/client"
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+const maxEventSize = 1024 * 1024
+
+// EventStream reads the Server-Sent Events sent by the server until it is closed.
+type EventStream struct {
+	body    io.ReadCloser
+	scanner *bufio.Scanner
+	event   *Event
+	err     error
+}
+
+// Events opens the event stream of the logged user, the stream must be closed by the caller.
+func (c *Client) Events() (*EventStream, error) {
+	return c.EventsContext(context.Background())
+}
+
+// EventsContext opens the event stream of the logged user, the stream is closed when the context is canceled.
+func (c *Client) EventsContext(ctx context.Context) (*EventStream, error) {
+	body, err := c.request.Stream(ctx, "/v1/events")
+	if err != nil {
+		return nil, err
+	}
+
+	if body == nil {
+		body = io.NopCloser(strings.NewReader(""))
+	}
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 4096), maxEventSize)
+	return &EventStream{body: body, scanner: scanner}, nil
+}
+
+// Next waits for the next event, it returns false when the stream is closed or when an error occurred.
+func (s *EventStream) Next() bool {
+	var eventType string
+	var data []string
+
+	for s.scanner.Scan() {
+		line := s.scanner.Text()
+		if line == "" {
+			if len(data) == 0 {
+				eventType = ""
+				continue
+			}
+
+			if eventType == "" {
+				eventType = "message"
+			}
+			s.event = &Event{Type: eventType, Data: json.RawMessage(strings.Join(data, "\n"))}
+			return true
+		}
+
+		// Comments are used to keep the connection alive.
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			eventType = value
+		case "data":
+			data = append(data, value)
+		}
+	}
+
+	s.event = nil
+	s.err = s.scanner.Err()
+	return false
+}
+
+// Event returns the current event.
+func (s *EventStream) Event() *Event {
+	return s.event
+}
+
+// Err returns the error that stopped the stream, if any.
+func (s *EventStream) Err() error {
+	return s.err
+}
+
+// Close closes the connection to the server.
+func (s *EventStream) Close() error {
+	return s.body.Close()
+}
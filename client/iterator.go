@@ -0,0 +1,104 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package client // import "miniflux.app/client"
+
+import "context"
+
+// EntryIterator walks through a list of entries page by page using the pagination cursor returned by the API.
+type EntryIterator struct {
+	fetch   func(filter *Filter) (*EntryResultSet, error)
+	filter  Filter
+	entries Entries
+	entry   *Entry
+	done    bool
+	err     error
+}
+
+// EntriesIterator returns an iterator over the entries matching the filter.
+func (c *Client) EntriesIterator(filter *Filter) *EntryIterator {
+	return c.EntriesIteratorContext(context.Background(), filter)
+}
+
+// EntriesIteratorContext returns an iterator over the entries matching the filter.
+// The context applies to every page fetched by the iterator.
+func (c *Client) EntriesIteratorContext(ctx context.Context, filter *Filter) *EntryIterator {
+	return newEntryIterator(func(filter *Filter) (*EntryResultSet, error) {
+		return c.EntriesContext(ctx, filter)
+	}, filter)
+}
+
+// FeedEntriesIterator returns an iterator over the entries of a feed matching the filter.
+func (c *Client) FeedEntriesIterator(feedID int64, filter *Filter) *EntryIterator {
+	return c.FeedEntriesIteratorContext(context.Background(), feedID, filter)
+}
+
+// FeedEntriesIteratorContext returns an iterator over the entries of a feed matching the filter.
+func (c *Client) FeedEntriesIteratorContext(ctx context.Context, feedID int64, filter *Filter) *EntryIterator {
+	return newEntryIterator(func(filter *Filter) (*EntryResultSet, error) {
+		return c.FeedEntriesContext(ctx, feedID, filter)
+	}, filter)
+}
+
+// CategoryEntriesIterator returns an iterator over the entries of a category matching the filter.
+func (c *Client) CategoryEntriesIterator(categoryID int64, filter *Filter) *EntryIterator {
+	return c.CategoryEntriesIteratorContext(context.Background(), categoryID, filter)
+}
+
+// CategoryEntriesIteratorContext returns an iterator over the entries of a category matching the filter.
+func (c *Client) CategoryEntriesIteratorContext(ctx context.Context, categoryID int64, filter *Filter) *EntryIterator {
+	return newEntryIterator(func(filter *Filter) (*EntryResultSet, error) {
+		return c.CategoryEntriesContext(ctx, categoryID, filter)
+	}, filter)
+}
+
+func newEntryIterator(fetch func(filter *Filter) (*EntryResultSet, error), filter *Filter) *EntryIterator {
+	iterator := &EntryIterator{fetch: fetch, filter: Filter{Limit: 100}}
+	if filter != nil {
+		iterator.filter = *filter
+	}
+
+	// The cursor is only returned when the page size is bounded.
+	if iterator.filter.Limit <= 0 {
+		iterator.filter.Limit = 100
+	}
+	iterator.filter.Offset = -1
+
+	return iterator
+}
+
+// Next advances the iterator to the next entry, fetching the next page when needed.
+// It returns false when there are no more entries or when an error occurred.
+func (it *EntryIterator) Next() bool {
+	for len(it.entries) == 0 {
+		if it.done || it.err != nil {
+			it.entry = nil
+			return false
+		}
+
+		result, err := it.fetch(&it.filter)
+		if err != nil {
+			it.err = err
+			continue
+		}
+
+		it.entries = result.Entries
+		it.filter.Cursor = result.NextCursor
+		it.done = result.NextCursor == ""
+	}
+
+	it.entry = it.entries[0]
+	it.entries = it.entries[1:]
+	return true
+}
+
+// Entry returns the current entry.
+func (it *EntryIterator) Entry() *Entry {
+	return it.entry
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *EntryIterator) Err() error {
+	return it.err
+}
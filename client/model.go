@@ -5,6 +5,7 @@
 package client // import "miniflux.app/client"
 
 import (
+	"encoding/json"
 	"fmt"
 	"time"
 )
@@ -84,11 +85,143 @@ type UserModificationRequest struct {
 // Users represents a list of users.
 type Users []User
 
+// APIKey represents an API key, the token is only returned when the key is created.
+type APIKey struct {
+	ID          int64      `json:"id"`
+	UserID      int64      `json:"user_id"`
+	Token       string     `json:"token,omitempty"`
+	Description string     `json:"description"`
+	Scopes      []string   `json:"scopes"`
+	CategoryIDs []int64    `json:"category_ids"`
+	LastUsedAt  *time.Time `json:"last_used_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// API key scopes, a key without scopes has full access.
+const (
+	APIKeyScopeReadOnly     = "read_only"
+	APIKeyScopeEntriesWrite = "entries_write"
+	APIKeyScopeFeedsAdmin   = "feeds_admin"
+)
+
+// APIKeyCreationRequest represents the request to create an API key.
+type APIKeyCreationRequest struct {
+	Description string   `json:"description"`
+	Scopes      []string `json:"scopes,omitempty"`
+	CategoryIDs []int64  `json:"category_ids,omitempty"`
+}
+
+// APIKeys represents a list of API keys.
+type APIKeys []*APIKey
+
+// Session represents a web session of a user.
+type Session struct {
+	ID         int64      `json:"id"`
+	UserID     int64      `json:"user_id"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	UserAgent  string     `json:"user_agent"`
+	IP         string     `json:"ip"`
+}
+
+// Sessions represents a list of sessions.
+type Sessions []*Session
+
+// Integration represents the integration settings of a user, secrets are never returned by the API.
+type Integration struct {
+	UserID                int64  `json:"user_id"`
+	PinboardEnabled       bool   `json:"pinboard_enabled"`
+	PinboardTags          string `json:"pinboard_tags"`
+	PinboardMarkAsUnread  bool   `json:"pinboard_mark_as_unread"`
+	InstapaperEnabled     bool   `json:"instapaper_enabled"`
+	InstapaperUsername    string `json:"instapaper_username"`
+	FeverEnabled          bool   `json:"fever_enabled"`
+	FeverUsername         string `json:"fever_username"`
+	GoogleReaderEnabled   bool   `json:"googlereader_enabled"`
+	GoogleReaderUsername  string `json:"googlereader_username"`
+	NextcloudNewsEnabled  bool   `json:"nextcloud_news_enabled"`
+	NextcloudNewsUsername string `json:"nextcloud_news_username"`
+	TTRSSEnabled          bool   `json:"ttrss_enabled"`
+	TTRSSUsername         string `json:"ttrss_username"`
+	WallabagEnabled       bool   `json:"wallabag_enabled"`
+	WallabagOnlyURL       bool   `json:"wallabag_only_url"`
+	WallabagURL           string `json:"wallabag_url"`
+	WallabagClientID      string `json:"wallabag_client_id"`
+	WallabagUsername      string `json:"wallabag_username"`
+	NunuxKeeperEnabled    bool   `json:"nunux_keeper_enabled"`
+	NunuxKeeperURL        string `json:"nunux_keeper_url"`
+	EspialEnabled         bool   `json:"espial_enabled"`
+	EspialURL             string `json:"espial_url"`
+	EspialTags            string `json:"espial_tags"`
+	PocketEnabled         bool   `json:"pocket_enabled"`
+	TelegramBotEnabled    bool   `json:"telegram_bot_enabled"`
+	TelegramBotChatID     string `json:"telegram_bot_chat_id"`
+	LinkdingEnabled       bool   `json:"linkding_enabled"`
+	LinkdingURL           string `json:"linkding_url"`
+	MatrixBotEnabled      bool   `json:"matrix_bot_enabled"`
+	MatrixBotUser         string `json:"matrix_bot_user"`
+	MatrixBotURL          string `json:"matrix_bot_url"`
+	MatrixBotChatID       string `json:"matrix_bot_chat_id"`
+}
+
+// IntegrationModificationRequest represents the request to update the integration settings.
+// Secrets are write-only, an empty string removes them.
+type IntegrationModificationRequest struct {
+	PinboardEnabled       *bool   `json:"pinboard_enabled"`
+	PinboardToken         *string `json:"pinboard_token"`
+	PinboardTags          *string `json:"pinboard_tags"`
+	PinboardMarkAsUnread  *bool   `json:"pinboard_mark_as_unread"`
+	InstapaperEnabled     *bool   `json:"instapaper_enabled"`
+	InstapaperUsername    *string `json:"instapaper_username"`
+	InstapaperPassword    *string `json:"instapaper_password"`
+	FeverEnabled          *bool   `json:"fever_enabled"`
+	FeverUsername         *string `json:"fever_username"`
+	FeverPassword         *string `json:"fever_password"`
+	GoogleReaderEnabled   *bool   `json:"googlereader_enabled"`
+	GoogleReaderUsername  *string `json:"googlereader_username"`
+	GoogleReaderPassword  *string `json:"googlereader_password"`
+	NextcloudNewsEnabled  *bool   `json:"nextcloud_news_enabled"`
+	NextcloudNewsUsername *string `json:"nextcloud_news_username"`
+	NextcloudNewsPassword *string `json:"nextcloud_news_password"`
+	TTRSSEnabled          *bool   `json:"ttrss_enabled"`
+	TTRSSUsername         *string `json:"ttrss_username"`
+	TTRSSPassword         *string `json:"ttrss_password"`
+	WallabagEnabled       *bool   `json:"wallabag_enabled"`
+	WallabagOnlyURL       *bool   `json:"wallabag_only_url"`
+	WallabagURL           *string `json:"wallabag_url"`
+	WallabagClientID      *string `json:"wallabag_client_id"`
+	WallabagClientSecret  *string `json:"wallabag_client_secret"`
+	WallabagUsername      *string `json:"wallabag_username"`
+	WallabagPassword      *string `json:"wallabag_password"`
+	NunuxKeeperEnabled    *bool   `json:"nunux_keeper_enabled"`
+	NunuxKeeperURL        *string `json:"nunux_keeper_url"`
+	NunuxKeeperAPIKey     *string `json:"nunux_keeper_api_key"`
+	EspialEnabled         *bool   `json:"espial_enabled"`
+	EspialURL             *string `json:"espial_url"`
+	EspialAPIKey          *string `json:"espial_api_key"`
+	EspialTags            *string `json:"espial_tags"`
+	PocketEnabled         *bool   `json:"pocket_enabled"`
+	PocketAccessToken     *string `json:"pocket_access_token"`
+	PocketConsumerKey     *string `json:"pocket_consumer_key"`
+	TelegramBotEnabled    *bool   `json:"telegram_bot_enabled"`
+	TelegramBotToken      *string `json:"telegram_bot_token"`
+	TelegramBotChatID     *string `json:"telegram_bot_chat_id"`
+	LinkdingEnabled       *bool   `json:"linkding_enabled"`
+	LinkdingURL           *string `json:"linkding_url"`
+	LinkdingAPIKey        *string `json:"linkding_api_key"`
+	MatrixBotEnabled      *bool   `json:"matrix_bot_enabled"`
+	MatrixBotUser         *string `json:"matrix_bot_user"`
+	MatrixBotPassword     *string `json:"matrix_bot_password"`
+	MatrixBotURL          *string `json:"matrix_bot_url"`
+	MatrixBotChatID       *string `json:"matrix_bot_chat_id"`
+}
+
 // Category represents a feed category.
 type Category struct {
-	ID     int64  `json:"id,omitempty"`
-	Title  string `json:"title,omitempty"`
-	UserID int64  `json:"user_id,omitempty"`
+	ID           int64  `json:"id,omitempty"`
+	Title        string `json:"title,omitempty"`
+	UserID       int64  `json:"user_id,omitempty"`
+	HideGlobally bool   `json:"hide_globally"`
 }
 
 func (c Category) String() string {
@@ -98,6 +231,36 @@ func (c Category) String() string {
 // Categories represents a list of categories.
 type Categories []*Category
 
+// CategoryRequest represents a request to create or update a category.
+type CategoryRequest struct {
+	Title        string
+	HideGlobally bool
+}
+
+// MarshalJSON implements the json.Marshaler interface, the API expects a non-empty string to hide a category.
+func (c CategoryRequest) MarshalJSON() ([]byte, error) {
+	hideGlobally := ""
+	if c.HideGlobally {
+		hideGlobally = "on"
+	}
+
+	return json.Marshal(map[string]string{"title": c.Title, "hide_globally": hideGlobally})
+}
+
+// Label represents a user-defined entry label.
+type Label struct {
+	ID     int64  `json:"id,omitempty"`
+	Title  string `json:"title,omitempty"`
+	UserID int64  `json:"user_id,omitempty"`
+}
+
+func (l Label) String() string {
+	return fmt.Sprintf("#%d %s", l.ID, l.Title)
+}
+
+// Labels represents a list of labels.
+type Labels []*Label
+
 // Subscription represents a feed subscription.
 type Subscription struct {
 	Title string `json:"title"`
@@ -112,33 +275,54 @@ func (s Subscription) String() string {
 // Subscriptions represents a list of subscriptions.
 type Subscriptions []*Subscription
 
+// DiscoveryRequest represents a request to discover the feeds of a website.
+type DiscoveryRequest struct {
+	URL                         string `json:"url"`
+	UserAgent                   string `json:"user_agent"`
+	Cookie                      string `json:"cookie"`
+	Username                    string `json:"username"`
+	Password                    string `json:"password"`
+	FetchViaProxy               bool   `json:"fetch_via_proxy"`
+	AllowSelfSignedCertificates bool   `json:"allow_self_signed_certificates"`
+}
+
 // Feed represents a Miniflux feed.
 type Feed struct {
-	ID                          int64     `json:"id"`
-	UserID                      int64     `json:"user_id"`
-	FeedURL                     string    `json:"feed_url"`
-	SiteURL                     string    `json:"site_url"`
-	Title                       string    `json:"title"`
-	CheckedAt                   time.Time `json:"checked_at,omitempty"`
-	EtagHeader                  string    `json:"etag_header,omitempty"`
-	LastModifiedHeader          string    `json:"last_modified_header,omitempty"`
-	ParsingErrorMsg             string    `json:"parsing_error_message,omitempty"`
-	ParsingErrorCount           int       `json:"parsing_error_count,omitempty"`
-	Disabled                    bool      `json:"disabled"`
-	IgnoreHTTPCache             bool      `json:"ignore_http_cache"`
-	AllowSelfSignedCertificates bool      `json:"allow_self_signed_certificates"`
-	FetchViaProxy               bool      `json:"fetch_via_proxy"`
-	ScraperRules                string    `json:"scraper_rules"`
-	RewriteRules                string    `json:"rewrite_rules"`
-	BlocklistRules              string    `json:"blocklist_rules"`
-	KeeplistRules               string    `json:"keeplist_rules"`
-	Crawler                     bool      `json:"crawler"`
-	UserAgent                   string    `json:"user_agent"`
-	Cookie                      string    `json:"cookie"`
-	Username                    string    `json:"username"`
-	Password                    string    `json:"password"`
-	Category                    *Category `json:"category,omitempty"`
-	HideGlobally                bool      `json:"hide_globally"`
+	ID                          int64              `json:"id"`
+	UserID                      int64              `json:"user_id"`
+	FeedURL                     string             `json:"feed_url"`
+	SiteURL                     string             `json:"site_url"`
+	Title                       string             `json:"title"`
+	CheckedAt                   time.Time          `json:"checked_at,omitempty"`
+	EtagHeader                  string             `json:"etag_header,omitempty"`
+	LastModifiedHeader          string             `json:"last_modified_header,omitempty"`
+	ParsingErrorMsg             string             `json:"parsing_error_message,omitempty"`
+	ParsingErrorCount           int                `json:"parsing_error_count,omitempty"`
+	Disabled                    bool               `json:"disabled"`
+	IgnoreHTTPCache             bool               `json:"ignore_http_cache"`
+	AllowSelfSignedCertificates bool               `json:"allow_self_signed_certificates"`
+	FetchViaProxy               bool               `json:"fetch_via_proxy"`
+	ScraperRules                string             `json:"scraper_rules"`
+	RewriteRules                string             `json:"rewrite_rules"`
+	BlocklistRules              string             `json:"blocklist_rules"`
+	KeeplistRules               string             `json:"keeplist_rules"`
+	Crawler                     bool               `json:"crawler"`
+	UserAgent                   string             `json:"user_agent"`
+	Cookie                      string             `json:"cookie"`
+	Username                    string             `json:"username"`
+	Password                    string             `json:"password"`
+	Category                    *Category          `json:"category,omitempty"`
+	HideGlobally                bool               `json:"hide_globally"`
+	MarkUpdatedEntriesUnread    bool               `json:"mark_updated_entries_unread"`
+	URLRewriteRules             string             `json:"urlrewrite_rules"`
+	NextCheckAt                 time.Time          `json:"next_check_at"`
+	Icon                        *FeedIconReference `json:"icon"`
+}
+
+// FeedIconReference identifies the icon of a feed, the icon can be fetched with Client.Icon.
+type FeedIconReference struct {
+	FeedID int64 `json:"feed_id"`
+	IconID int64 `json:"icon_id"`
 }
 
 // FeedCreationRequest represents the request to create a feed.
@@ -158,6 +342,7 @@ type FeedCreationRequest struct {
 	RewriteRules                string `json:"rewrite_rules"`
 	BlocklistRules              string `json:"blocklist_rules"`
 	KeeplistRules               string `json:"keeplist_rules"`
+	URLRewriteRules             string `json:"urlrewrite_rules"`
 	HideGlobally                bool   `json:"hide_globally"`
 }
 
@@ -170,6 +355,7 @@ type FeedModificationRequest struct {
 	RewriteRules                *string `json:"rewrite_rules"`
 	BlocklistRules              *string `json:"blocklist_rules"`
 	KeeplistRules               *string `json:"keeplist_rules"`
+	URLRewriteRules             *string `json:"urlrewrite_rules"`
 	Crawler                     *bool   `json:"crawler"`
 	UserAgent                   *string `json:"user_agent"`
 	Cookie                      *string `json:"cookie"`
@@ -181,6 +367,7 @@ type FeedModificationRequest struct {
 	AllowSelfSignedCertificates *bool   `json:"allow_self_signed_certificates"`
 	FetchViaProxy               *bool   `json:"fetch_via_proxy"`
 	HideGlobally                *bool   `json:"hide_globally"`
+	MarkUpdatedEntriesUnread    *bool   `json:"mark_updated_entries_unread"`
 }
 
 // FeedIcon represents the feed icon.
@@ -200,30 +387,87 @@ type Feeds []*Feed
 
 // Entry represents a subscription item in the system.
 type Entry struct {
-	ID          int64      `json:"id"`
-	UserID      int64      `json:"user_id"`
-	FeedID      int64      `json:"feed_id"`
-	Status      string     `json:"status"`
-	Hash        string     `json:"hash"`
-	Title       string     `json:"title"`
-	URL         string     `json:"url"`
-	CommentsURL string     `json:"comments_url"`
-	Date        time.Time  `json:"published_at"`
-	CreatedAt   time.Time  `json:"created_at"`
-	ChangedAt   time.Time  `json:"changed_at"`
-	Content     string     `json:"content"`
-	Author      string     `json:"author"`
-	ShareCode   string     `json:"share_code"`
-	Starred     bool       `json:"starred"`
-	ReadingTime int        `json:"reading_time"`
-	Enclosures  Enclosures `json:"enclosures,omitempty"`
-	Feed        *Feed      `json:"feed,omitempty"`
-	Tags        []string   `json:"tags"`
+	ID            int64       `json:"id"`
+	UserID        int64       `json:"user_id"`
+	FeedID        int64       `json:"feed_id"`
+	Status        string      `json:"status"`
+	Hash          string      `json:"hash"`
+	Title         string      `json:"title"`
+	URL           string      `json:"url"`
+	CommentsURL   string      `json:"comments_url"`
+	Date          time.Time   `json:"published_at"`
+	CreatedAt     time.Time   `json:"created_at"`
+	ChangedAt     time.Time   `json:"changed_at"`
+	Content       string      `json:"content"`
+	Author        string      `json:"author"`
+	ShareCode     string      `json:"share_code"`
+	Starred       bool        `json:"starred"`
+	ReadingTime   int         `json:"reading_time"`
+	Enclosures    Enclosures  `json:"enclosures,omitempty"`
+	Feed          *Feed       `json:"feed,omitempty"`
+	Tags          []string    `json:"tags"`
+	Labels        []string    `json:"labels"`
+	Annotations   Annotations `json:"annotations,omitempty"`
+	Language      string      `json:"language"`
+	Snippet       string      `json:"snippet,omitempty"`
+	RevisionCount int         `json:"revision_count"`
 }
 
 // Entries represents a list of entries.
 type Entries []*Entry
 
+// EntryRevision represents a previous version of an entry.
+type EntryRevision struct {
+	ID        int64     `json:"id"`
+	EntryID   int64     `json:"entry_id"`
+	Title     string    `json:"title"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// EntryRevisions represents a list of revisions, sorted from the most recent to the oldest.
+type EntryRevisions []*EntryRevision
+
+// DiffRow represents one line of a side-by-side comparison.
+type DiffRow struct {
+	Operation string `json:"operation"`
+	Left      string `json:"left"`
+	Right     string `json:"right"`
+}
+
+// EntryRevisionDiff represents the changes between a revision and the version that replaced it.
+type EntryRevisionDiff struct {
+	RevisionID     int64      `json:"revision_id"`
+	NextRevisionID int64      `json:"next_revision_id"`
+	CreatedAt      time.Time  `json:"created_at"`
+	Title          []*DiffRow `json:"title"`
+	Content        []*DiffRow `json:"content"`
+}
+
+// Annotation represents a highlight and/or a note attached to an entry.
+type Annotation struct {
+	ID          int64     `json:"id"`
+	UserID      int64     `json:"user_id"`
+	EntryID     int64     `json:"entry_id"`
+	Quote       string    `json:"quote"`
+	StartOffset int       `json:"start_offset"`
+	EndOffset   int       `json:"end_offset"`
+	Note        string    `json:"note"`
+	CreatedAt   time.Time `json:"created_at"`
+	ChangedAt   time.Time `json:"changed_at"`
+}
+
+// AnnotationRequest represents the request to create an annotation.
+type AnnotationRequest struct {
+	Quote       string `json:"quote"`
+	StartOffset int    `json:"start_offset"`
+	EndOffset   int    `json:"end_offset"`
+	Note        string `json:"note"`
+}
+
+// Annotations represents a list of annotations.
+type Annotations []*Annotation
+
 // Enclosure represents an attachment.
 type Enclosure struct {
 	ID       int64  `json:"id"`
@@ -257,11 +501,30 @@ type Filter struct {
 	Search        string
 	CategoryID    int64
 	FeedID        int64
+	LabelID       int64
 	Statuses      []string
+	Tags          []string
+	Cursor        string
 }
 
 // EntryResultSet represents the response when fetching entries.
 type EntryResultSet struct {
-	Total   int     `json:"total"`
-	Entries Entries `json:"entries"`
+	Total      int     `json:"total"`
+	Entries    Entries `json:"entries"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+}
+
+// Event types sent by the event stream.
+const (
+	EventCounters       = "counters"
+	EventNewEntries     = "new_entries"
+	EventEntriesStatus  = "entries_status"
+	EventEntriesStarred = "entries_starred"
+	EventFeedError      = "feed_error"
+)
+
+// Event represents a message of the event stream, the data depends on the type of event.
+type Event struct {
+	Type string
+	Data json.RawMessage
 }
@@ -0,0 +1,60 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package client // import "miniflux.app/client"
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Option configures a client created with NewClientWithOptions.
+type Option func(*Client)
+
+// WithCredentials authenticates the requests with a username and a password.
+func WithCredentials(username, password string) Option {
+	return func(c *Client) {
+		c.request.username = username
+		c.request.password = password
+	}
+}
+
+// WithAPIKey authenticates the requests with an API key.
+func WithAPIKey(apiKey string) Option {
+	return func(c *Client) {
+		c.request.apiKey = apiKey
+	}
+}
+
+// WithHTTPClient uses a custom HTTP client to send the requests, for example to change the timeout or the transport.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) {
+		if client != nil {
+			c.request.client = client
+		}
+	}
+}
+
+// WithRetry retries the requests rejected with a 429 or a 5xx status code up to maxRetries times.
+// The delay starts at backoff and doubles after each attempt, unless the server sends a Retry-After header.
+//
+// Only the GET, HEAD and DELETE requests are retried: a POST or PUT request failing with a server error
+// may have been partially processed. They can be retried one by one with AllowRetry.
+func WithRetry(maxRetries int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.request.maxRetries = maxRetries
+		c.request.backoff = backoff
+	}
+}
+
+type allowRetryKey struct{}
+
+// AllowRetry returns a context allowing the retry of the POST and PUT requests sent with it,
+// when the client is configured with WithRetry and the request is safe to send again:
+//
+//	category, err := client.CreateCategoryContext(miniflux.AllowRetry(ctx), "News")
+func AllowRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, allowRetryKey{}, true)
+}
@@ -0,0 +1,157 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package client // import "miniflux.app/client"
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"miniflux.app/api"
+
+	"github.com/gorilla/mux"
+)
+
+// clientCalls invokes every method of the client, the responses are ignored.
+// A method must be added here when a route is added to the API.
+var clientCalls = []func(c *Client){
+	func(c *Client) { c.Me() },
+	func(c *Client) { c.Users() },
+	func(c *Client) { c.UserByID(1) },
+	func(c *Client) { c.UserByUsername("admin") },
+	func(c *Client) { c.CreateUser("admin", "secret", true) },
+	func(c *Client) { c.UpdateUser(1, &UserModificationRequest{}) },
+	func(c *Client) { c.DeleteUser(1) },
+	func(c *Client) { c.MarkAllAsRead(1) },
+	func(c *Client) { c.APIKeys() },
+	func(c *Client) { c.UserAPIKeys(1) },
+	func(c *Client) { c.CreateAPIKey("Script") },
+	func(c *Client) { c.CreateUserAPIKey(1, "Script") },
+	func(c *Client) { c.DeleteAPIKey(1) },
+	func(c *Client) { c.DeleteUserAPIKey(1, 1) },
+	func(c *Client) { c.Sessions() },
+	func(c *Client) { c.UserSessions(1) },
+	func(c *Client) { c.DeleteSession(1) },
+	func(c *Client) { c.DeleteSessions() },
+	func(c *Client) { c.DeleteUserSession(1, 1) },
+	func(c *Client) { c.DeleteUserSessions(1) },
+	func(c *Client) { c.Integrations() },
+	func(c *Client) { c.UpdateIntegrations(&IntegrationModificationRequest{}) },
+	func(c *Client) { c.Discover("https://example.org/") },
+	func(c *Client) { c.Categories() },
+	func(c *Client) { c.CreateCategory("News") },
+	func(c *Client) { c.UpdateCategory(1, "News") },
+	func(c *Client) { c.MarkCategoryAsRead(1) },
+	func(c *Client) { c.CategoryFeeds(1) },
+	func(c *Client) { c.RefreshCategory(1) },
+	func(c *Client) { c.CategoryEntries(1, nil) },
+	func(c *Client) { c.CategoryEntry(1, 1) },
+	func(c *Client) { c.DeleteCategory(1) },
+	func(c *Client) { c.Labels() },
+	func(c *Client) { c.CreateLabel("Later") },
+	func(c *Client) { c.UpdateLabel(1, "Later") },
+	func(c *Client) { c.DeleteLabel(1) },
+	func(c *Client) { c.LabelEntries(1, nil) },
+	func(c *Client) { c.Feeds() },
+	func(c *Client) { c.FetchCounters() },
+	func(c *Client) { c.CreateFeed(&FeedCreationRequest{}) },
+	func(c *Client) { c.RefreshAllFeeds() },
+	func(c *Client) { c.RefreshFeed(1) },
+	func(c *Client) { c.Feed(1) },
+	func(c *Client) { c.UpdateFeed(1, &FeedModificationRequest{}) },
+	func(c *Client) { c.DeleteFeed(1) },
+	func(c *Client) { c.FeedIcon(1) },
+	func(c *Client) { c.Icon(1) },
+	func(c *Client) { c.MarkFeedAsRead(1) },
+	func(c *Client) { c.Export() },
+	func(c *Client) { c.Import(io.NopCloser(strings.NewReader("<opml/>"))) },
+	func(c *Client) { c.FeedEntries(1, nil) },
+	func(c *Client) { c.FeedEntry(1, 1) },
+	func(c *Client) { c.Entries(nil) },
+	func(c *Client) { c.UpdateEntries([]int64{1}, EntryStatusRead) },
+	func(c *Client) { c.Entry(1) },
+	func(c *Client) { c.ToggleBookmark(1) },
+	func(c *Client) { c.UpdateEntryLabels(1, []string{"Later"}) },
+	func(c *Client) { c.FetchEntryOriginalContent(1) },
+	func(c *Client) { c.EntryAnnotations(1) },
+	func(c *Client) { c.CreateAnnotation(1, &AnnotationRequest{}) },
+	func(c *Client) { c.EntryRevisions(1) },
+	func(c *Client) { c.EntryRevisionDiff(1, 1) },
+	func(c *Client) { c.Annotations("") },
+	func(c *Client) { c.ExportAnnotations("") },
+	func(c *Client) { c.UpdateAnnotation(1, "Note") },
+	func(c *Client) { c.DeleteAnnotation(1) },
+	func(c *Client) { c.OpenAPISpecification() },
+	func(c *Client) {
+		if events, err := c.Events(); err == nil {
+			events.Close()
+		}
+	},
+}
+
+func TestEveryAPIRouteHasAClientMethod(t *testing.T) {
+	router := mux.NewRouter()
+	api.Serve(router, nil, nil)
+
+	declared := make(map[string]bool)
+	err := router.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		template, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+
+		methods, err := route.GetMethods()
+		if err != nil {
+			return nil
+		}
+
+		for _, method := range methods {
+			if method != http.MethodOptions {
+				declared[method+" "+template] = true
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	called := make(map[string]bool)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var match mux.RouteMatch
+		if router.Match(r, &match) && match.Route != nil {
+			if template, err := match.Route.GetPathTemplate(); err == nil {
+				mu.Lock()
+				called[r.Method+" "+template] = true
+				mu.Unlock()
+			}
+		}
+		fmt.Fprint(w, "null")
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "token")
+	for _, call := range clientCalls {
+		call(client)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(declared) == 0 {
+		t.Fatal(`No route has been declared`)
+	}
+
+	for route := range declared {
+		if !called[route] {
+			t.Errorf(`The route %s has no client method`, route)
+		}
+	}
+}
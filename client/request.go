@@ -6,27 +6,20 @@ package client // import "miniflux.app/client"
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"errors"
-	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 )
 
 const (
 	userAgent      = "Miniflux Client Library"
 	defaultTimeout = 80
-)
-
-// List of exposed errors.
-var (
-	ErrNotAuthorized = errors.New("miniflux: unauthorized (bad credentials)")
-	ErrForbidden     = errors.New("miniflux: access forbidden")
-	ErrServerError   = errors.New("miniflux: internal server error")
-	ErrNotFound      = errors.New("miniflux: resource not found")
+	maxBackoff     = 5 * time.Minute
 )
 
 type errorResponse struct {
@@ -34,34 +27,47 @@ type errorResponse struct {
 }
 
 type request struct {
-	endpoint string
-	username string
-	password string
-	apiKey   string
+	endpoint   string
+	username   string
+	password   string
+	apiKey     string
+	client     *http.Client
+	maxRetries int
+	backoff    time.Duration
 }
 
-func (r *request) Get(path string) (io.ReadCloser, error) {
-	return r.execute(http.MethodGet, path, nil)
+func (r *request) Get(ctx context.Context, path string) (io.ReadCloser, error) {
+	return r.execute(ctx, http.MethodGet, path, nil)
 }
 
-func (r *request) Post(path string, data interface{}) (io.ReadCloser, error) {
-	return r.execute(http.MethodPost, path, data)
+func (r *request) Post(ctx context.Context, path string, data interface{}) (io.ReadCloser, error) {
+	return r.execute(ctx, http.MethodPost, path, data)
 }
 
-func (r *request) PostFile(path string, f io.ReadCloser) (io.ReadCloser, error) {
-	return r.execute(http.MethodPost, path, f)
+func (r *request) PostFile(ctx context.Context, path string, f io.ReadCloser) (io.ReadCloser, error) {
+	return r.execute(ctx, http.MethodPost, path, f)
 }
 
-func (r *request) Put(path string, data interface{}) (io.ReadCloser, error) {
-	return r.execute(http.MethodPut, path, data)
+func (r *request) Put(ctx context.Context, path string, data interface{}) (io.ReadCloser, error) {
+	return r.execute(ctx, http.MethodPut, path, data)
 }
 
-func (r *request) Delete(path string) error {
-	_, err := r.execute(http.MethodDelete, path, nil)
+func (r *request) Delete(ctx context.Context, path string) error {
+	_, err := r.execute(ctx, http.MethodDelete, path, nil)
 	return err
 }
 
-func (r *request) execute(method, path string, data interface{}) (io.ReadCloser, error) {
+// Stream sends a GET request without the timeout of the HTTP client, the response body is read until the context is canceled.
+func (r *request) Stream(ctx context.Context, path string) (io.ReadCloser, error) {
+	client := *r.client
+	client.Timeout = 0
+
+	streamRequest := *r
+	streamRequest.client = &client
+	return streamRequest.execute(ctx, http.MethodGet, path, nil)
+}
+
+func (r *request) execute(ctx context.Context, method, path string, data interface{}) (io.ReadCloser, error) {
 	if r.endpoint[len(r.endpoint)-1:] == "/" {
 		r.endpoint = r.endpoint[:len(r.endpoint)-1]
 	}
@@ -71,76 +77,126 @@ func (r *request) execute(method, path string, data interface{}) (io.ReadCloser,
 		return nil, err
 	}
 
-	request := &http.Request{
-		URL:    u,
-		Method: method,
-		Header: r.buildHeaders(),
-	}
-
-	if r.username != "" && r.password != "" {
-		request.SetBasicAuth(r.username, r.password)
-	}
-
+	// The payload is kept in memory to be sent again when the request is retried.
+	var payload []byte
 	if data != nil {
 		switch data := data.(type) {
 		case io.ReadCloser:
-			request.Body = data
+			payload, err = io.ReadAll(data)
+			data.Close()
+			if err != nil {
+				return nil, err
+			}
 		default:
-			request.Body = io.NopCloser(bytes.NewBuffer(r.toJSON(data)))
+			payload = r.toJSON(data)
 		}
 	}
 
-	client := r.buildClient()
-	response, err := client.Do(request)
+	for attempt := 0; ; attempt++ {
+		response, err := r.send(ctx, method, u, data != nil, payload)
+		if err != nil {
+			return nil, err
+		}
+
+		if attempt >= r.maxRetries || !isRetryable(response.StatusCode) || !canRetry(ctx, method) {
+			return r.handleResponse(response)
+		}
+
+		delay := r.retryDelay(attempt, response.Header.Get("Retry-After"))
+		response.Body.Close()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (r *request) send(ctx context.Context, method string, u *url.URL, hasBody bool, payload []byte) (*http.Response, error) {
+	var body io.Reader
+	if hasBody {
+		body = bytes.NewReader(payload)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, u.String(), body)
 	if err != nil {
 		return nil, err
 	}
+	request.Header = r.buildHeaders()
 
-	switch response.StatusCode {
-	case http.StatusUnauthorized:
-		response.Body.Close()
-		return nil, ErrNotAuthorized
-	case http.StatusForbidden:
-		response.Body.Close()
-		return nil, ErrForbidden
-	case http.StatusInternalServerError:
-		defer response.Body.Close()
+	if r.username != "" && r.password != "" {
+		request.SetBasicAuth(r.username, r.password)
+	}
 
-		var resp errorResponse
-		decoder := json.NewDecoder(response.Body)
-		// If we failed to decode, just return a generic ErrServerError
-		if err := decoder.Decode(&resp); err != nil {
-			return nil, ErrServerError
-		}
-		return nil, errors.New("miniflux: internal server error: " + resp.ErrorMessage)
-	case http.StatusNotFound:
-		response.Body.Close()
-		return nil, ErrNotFound
-	case http.StatusNoContent:
+	return r.client.Do(request)
+}
+
+func (r *request) handleResponse(response *http.Response) (io.ReadCloser, error) {
+	switch {
+	case response.StatusCode == http.StatusNoContent:
 		response.Body.Close()
 		return nil, nil
-	case http.StatusBadRequest:
+	case response.StatusCode >= http.StatusBadRequest:
 		defer response.Body.Close()
 
+		// The message is optional, the error still carries the status code when the body is not JSON.
 		var resp errorResponse
-		decoder := json.NewDecoder(response.Body)
-		if err := decoder.Decode(&resp); err != nil {
-			return nil, fmt.Errorf("miniflux: bad request error (%v)", err)
+		json.NewDecoder(response.Body).Decode(&resp)
+		return nil, &APIError{StatusCode: response.StatusCode, Message: resp.ErrorMessage}
+	}
+
+	return response.Body, nil
+}
+
+// retryDelay returns the delay before the next attempt, the Retry-After header takes precedence over the exponential backoff.
+func (r *request) retryDelay(attempt int, retryAfter string) time.Duration {
+	if retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+			return capBackoff(time.Duration(seconds) * time.Second)
 		}
 
-		return nil, fmt.Errorf("miniflux: bad request (%s)", resp.ErrorMessage)
+		if date, err := http.ParseTime(retryAfter); err == nil {
+			return capBackoff(time.Until(date))
+		}
 	}
 
-	if response.StatusCode > 400 {
-		response.Body.Close()
-		return nil, fmt.Errorf("miniflux: status code=%d", response.StatusCode)
+	delay := r.backoff
+	for i := 0; i < attempt && delay < maxBackoff; i++ {
+		delay *= 2
 	}
+	return capBackoff(delay)
+}
 
-	return response.Body, nil
+func capBackoff(delay time.Duration) time.Duration {
+	if delay < 0 {
+		return 0
+	}
+	if delay > maxBackoff {
+		return maxBackoff
+	}
+	return delay
+}
+
+func isRetryable(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError
+}
+
+// canRetry reports whether the request can be sent again, the requests that are not idempotent must be allowed with AllowRetry.
+func canRetry(ctx context.Context, method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodDelete:
+		return true
+	default:
+		allowed, _ := ctx.Value(allowRetryKey{}).(bool)
+		return allowed
+	}
 }
 
-func (r *request) buildClient() http.Client {
-	return http.Client{
+func defaultHTTPClient() *http.Client {
+	return &http.Client{
 		Timeout: time.Duration(defaultTimeout * time.Second),
 	}
 }
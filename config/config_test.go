@@ -515,6 +515,41 @@ func TestCleanupRemoveSessionsDays(t *testing.T) {
 	}
 }
 
+func TestDefaultEntryRevisionsLimitValue(t *testing.T) {
+	os.Clearenv()
+
+	parser := NewParser()
+	opts, err := parser.ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatalf(`Parsing failure: %v`, err)
+	}
+
+	expected := 10
+	result := opts.EntryRevisionsLimit()
+
+	if result != expected {
+		t.Fatalf(`Unexpected ENTRY_REVISIONS_LIMIT value, got %v instead of %v`, result, expected)
+	}
+}
+
+func TestEntryRevisionsLimit(t *testing.T) {
+	os.Clearenv()
+	os.Setenv("ENTRY_REVISIONS_LIMIT", "3")
+
+	parser := NewParser()
+	opts, err := parser.ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatalf(`Parsing failure: %v`, err)
+	}
+
+	expected := 3
+	result := opts.EntryRevisionsLimit()
+
+	if result != expected {
+		t.Fatalf(`Unexpected ENTRY_REVISIONS_LIMIT value, got %v instead of %v`, result, expected)
+	}
+}
+
 func TestDefaultWorkerPoolSizeValue(t *testing.T) {
 	os.Clearenv()
 
@@ -585,6 +620,41 @@ func TestPollingFrequency(t *testing.T) {
 	}
 }
 
+func TestDefaultMetricsStorageRefreshInterval(t *testing.T) {
+	os.Clearenv()
+
+	parser := NewParser()
+	opts, err := parser.ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatalf(`Parsing failure: %v`, err)
+	}
+
+	expected := defaultMetricsStorageRefreshInterval
+	result := opts.MetricsStorageRefreshInterval()
+
+	if result != expected {
+		t.Fatalf(`Unexpected METRICS_STORAGE_REFRESH_INTERVAL value, got %v instead of %v`, result, expected)
+	}
+}
+
+func TestMetricsStorageRefreshInterval(t *testing.T) {
+	os.Clearenv()
+	os.Setenv("METRICS_STORAGE_REFRESH_INTERVAL", "600")
+
+	parser := NewParser()
+	opts, err := parser.ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatalf(`Parsing failure: %v`, err)
+	}
+
+	expected := 600
+	result := opts.MetricsStorageRefreshInterval()
+
+	if result != expected {
+		t.Fatalf(`Unexpected METRICS_STORAGE_REFRESH_INTERVAL value, got %v instead of %v`, result, expected)
+	}
+}
+
 func TestDefaultBatchSizeValue(t *testing.T) {
 	os.Clearenv()
 
@@ -1617,6 +1687,41 @@ func TestFetchYouTubeWatchTime(t *testing.T) {
 	}
 }
 
+func TestClientURL(t *testing.T) {
+	os.Clearenv()
+	os.Setenv("CLIENT_URL", "https://reader.example.org/")
+	os.Setenv("CLIENT_API_KEY", "secret")
+
+	parser := NewParser()
+	opts, err := parser.ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatalf(`Parsing failure: %v`, err)
+	}
+
+	if opts.ClientURL() != "https://reader.example.org/" {
+		t.Fatalf(`Unexpected CLIENT_URL value, got %q`, opts.ClientURL())
+	}
+
+	if opts.ClientAPIKey() != "secret" {
+		t.Fatalf(`Unexpected CLIENT_API_KEY value, got %q`, opts.ClientAPIKey())
+	}
+}
+
+func TestDefaultClientURLValue(t *testing.T) {
+	os.Clearenv()
+	os.Setenv("BASE_URL", "https://example.org/folder/")
+
+	parser := NewParser()
+	opts, err := parser.ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatalf(`Parsing failure: %v`, err)
+	}
+
+	if opts.ClientURL() != "https://example.org/folder" {
+		t.Fatalf(`The base URL should be used by default, got %q`, opts.ClientURL())
+	}
+}
+
 func TestParseConfigDumpOutput(t *testing.T) {
 	os.Clearenv()
 
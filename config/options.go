@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"miniflux.app/version"
@@ -46,6 +47,7 @@ const (
 	defaultCleanupArchiveUnreadDays           = 180
 	defaultCleanupArchiveBatchSize            = 10000
 	defaultCleanupRemoveSessionsDays          = 30
+	defaultEntryRevisionsLimit                = 10
 	defaultProxyHTTPClientTimeout             = 120
 	defaultProxyOption                        = "http-only"
 	defaultProxyMediaTypes                    = "image"
@@ -71,11 +73,16 @@ const (
 	defaultMaintenanceMessage                 = "Miniflux is currently under maintenance"
 	defaultMetricsCollector                   = false
 	defaultMetricsRefreshInterval             = 60
+	defaultMetricsStorageRefreshInterval      = 3600
 	defaultMetricsAllowedNetworks             = "127.0.0.1/8"
 	defaultMetricsUsername                    = ""
 	defaultMetricsPassword                    = ""
 	defaultWatchdog                           = true
 	defaultInvidiousInstance                  = "yewtu.be"
+	defaultClientURL                          = ""
+	defaultClientAPIKey                       = ""
+	defaultClientUsername                     = ""
+	defaultClientPassword                     = ""
 )
 
 var defaultHTTPClientUserAgent = "Mozilla/5.0 (compatible; Miniflux/" + version.Version + "; +https://miniflux.app)"
@@ -87,7 +94,12 @@ type Option struct {
 }
 
 // Options contains configuration options.
+//
+// The options listed in ReloadableOptions can be changed by Reload while the application is running,
+// their fields are protected by the mutex.
 type Options struct {
+	mu sync.RWMutex
+
 	HTTPS                              bool
 	logDateTime                        bool
 	hsts                               bool
@@ -112,6 +124,7 @@ type Options struct {
 	cleanupArchiveUnreadDays           int
 	cleanupArchiveBatchSize            int
 	cleanupRemoveSessionsDays          int
+	entryRevisionsLimit                int
 	pollingFrequency                   int
 	batchSize                          int
 	pollingScheduler                   string
@@ -145,12 +158,17 @@ type Options struct {
 	maintenanceMessage                 string
 	metricsCollector                   bool
 	metricsRefreshInterval             int
+	metricsStorageRefreshInterval      int
 	metricsAllowedNetworks             []string
 	metricsUsername                    string
 	metricsPassword                    string
 	watchdog                           bool
 	invidiousInstance                  string
 	proxyPrivateKey                    []byte
+	clientURL                          string
+	clientAPIKey                       string
+	clientUsername                     string
+	clientPassword                     string
 }
 
 // NewOptions returns Options with default values.
@@ -183,6 +201,7 @@ func NewOptions() *Options {
 		cleanupArchiveUnreadDays:           defaultCleanupArchiveUnreadDays,
 		cleanupArchiveBatchSize:            defaultCleanupArchiveBatchSize,
 		cleanupRemoveSessionsDays:          defaultCleanupRemoveSessionsDays,
+		entryRevisionsLimit:                defaultEntryRevisionsLimit,
 		pollingFrequency:                   defaultPollingFrequency,
 		batchSize:                          defaultBatchSize,
 		pollingScheduler:                   defaultPollingScheduler,
@@ -214,17 +233,24 @@ func NewOptions() *Options {
 		maintenanceMessage:                 defaultMaintenanceMessage,
 		metricsCollector:                   defaultMetricsCollector,
 		metricsRefreshInterval:             defaultMetricsRefreshInterval,
+		metricsStorageRefreshInterval:      defaultMetricsStorageRefreshInterval,
 		metricsAllowedNetworks:             []string{defaultMetricsAllowedNetworks},
 		metricsUsername:                    defaultMetricsUsername,
 		metricsPassword:                    defaultMetricsPassword,
 		watchdog:                           defaultWatchdog,
 		invidiousInstance:                  defaultInvidiousInstance,
 		proxyPrivateKey:                    randomKey,
+		clientURL:                          defaultClientURL,
+		clientAPIKey:                       defaultClientAPIKey,
+		clientUsername:                     defaultClientUsername,
+		clientPassword:                     defaultClientPassword,
 	}
 }
 
 // LogDateTime returns true if the date/time should be displayed in log messages.
 func (o *Options) LogDateTime() bool {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
 	return o.logDateTime
 }
 
@@ -240,6 +266,8 @@ func (o *Options) MaintenanceMessage() string {
 
 // HasDebugMode returns true if debug mode is enabled.
 func (o *Options) HasDebugMode() bool {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
 	return o.debug
 }
 
@@ -333,6 +361,11 @@ func (o *Options) CleanupRemoveSessionsDays() int {
 	return o.cleanupRemoveSessionsDays
 }
 
+// EntryRevisionsLimit returns the maximum number of previous versions kept for each entry.
+func (o *Options) EntryRevisionsLimit() int {
+	return o.entryRevisionsLimit
+}
+
 // WorkerPoolSize returns the number of background worker.
 func (o *Options) WorkerPoolSize() int {
 	return o.workerPoolSize
@@ -340,6 +373,8 @@ func (o *Options) WorkerPoolSize() int {
 
 // PollingFrequency returns the interval to refresh feeds in the background.
 func (o *Options) PollingFrequency() int {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
 	return o.pollingFrequency
 }
 
@@ -431,21 +466,29 @@ func (o *Options) FetchYouTubeWatchTime() bool {
 
 // ProxyOption returns "none" to never proxy, "http-only" to proxy non-HTTPS, "all" to always proxy.
 func (o *Options) ProxyOption() string {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
 	return o.proxyOption
 }
 
 // ProxyMediaTypes returns a slice of media types to proxy.
 func (o *Options) ProxyMediaTypes() []string {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
 	return o.proxyMediaTypes
 }
 
 // ProxyUrl returns a string of a URL to use to proxy image requests
 func (o *Options) ProxyUrl() string {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
 	return o.proxyUrl
 }
 
 // ProxyHTTPClientTimeout returns the time limit in seconds before the proxy HTTP client cancel the request.
 func (o *Options) ProxyHTTPClientTimeout() int {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
 	return o.proxyHTTPClientTimeout
 }
 
@@ -479,6 +522,8 @@ func (o *Options) HTTPClientMaxBodySize() int64 {
 
 // HTTPClientProxy returns the proxy URL for HTTP client.
 func (o *Options) HTTPClientProxy() string {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
 	return o.httpClientProxy
 }
 
@@ -514,8 +559,15 @@ func (o *Options) MetricsRefreshInterval() int {
 	return o.metricsRefreshInterval
 }
 
+// MetricsStorageRefreshInterval returns the refresh interval of the storage statistics in seconds.
+func (o *Options) MetricsStorageRefreshInterval() int {
+	return o.metricsStorageRefreshInterval
+}
+
 // MetricsAllowedNetworks returns the list of networks allowed to connect to the metrics endpoint.
 func (o *Options) MetricsAllowedNetworks() []string {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
 	return o.metricsAllowedNetworks
 }
 
@@ -547,8 +599,34 @@ func (o *Options) ProxyPrivateKey() []byte {
 	return o.proxyPrivateKey
 }
 
+// ClientURL returns the URL of the Miniflux instance used by the command line client, the base URL by default.
+func (o *Options) ClientURL() string {
+	if o.clientURL == "" {
+		return o.baseURL
+	}
+	return o.clientURL
+}
+
+// ClientAPIKey returns the API key used by the command line client.
+func (o *Options) ClientAPIKey() string {
+	return o.clientAPIKey
+}
+
+// ClientUsername returns the username used by the command line client when there is no API key.
+func (o *Options) ClientUsername() string {
+	return o.clientUsername
+}
+
+// ClientPassword returns the password used by the command line client when there is no API key.
+func (o *Options) ClientPassword() string {
+	return o.clientPassword
+}
+
 // SortedOptions returns options as a list of key value pairs, sorted by keys.
 func (o *Options) SortedOptions(redactSecret bool) []*Option {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
 	var keyValues = map[string]interface{}{
 		"ADMIN_PASSWORD":                         redactSecretValue(o.adminPassword, redactSecret),
 		"ADMIN_USERNAME":                         o.adminUsername,
@@ -564,6 +642,10 @@ func (o *Options) SortedOptions(redactSecret bool) []*Option {
 		"CLEANUP_ARCHIVE_BATCH_SIZE":             o.cleanupArchiveBatchSize,
 		"CLEANUP_FREQUENCY_HOURS":                o.cleanupFrequencyHours,
 		"CLEANUP_REMOVE_SESSIONS_DAYS":           o.cleanupRemoveSessionsDays,
+		"CLIENT_API_KEY":                         redactSecretValue(o.clientAPIKey, redactSecret),
+		"CLIENT_PASSWORD":                        redactSecretValue(o.clientPassword, redactSecret),
+		"CLIENT_URL":                             o.clientURL,
+		"CLIENT_USERNAME":                        o.clientUsername,
 		"CREATE_ADMIN":                           o.createAdmin,
 		"DATABASE_MAX_CONNS":                     o.databaseMaxConns,
 		"DATABASE_MIN_CONNS":                     o.databaseMinConns,
@@ -573,6 +655,7 @@ func (o *Options) SortedOptions(redactSecret bool) []*Option {
 		"DISABLE_HSTS":                           !o.hsts,
 		"DISABLE_SCHEDULER_SERVICE":              !o.schedulerService,
 		"DISABLE_HTTP_SERVICE":                   !o.httpService,
+		"ENTRY_REVISIONS_LIMIT":                  o.entryRevisionsLimit,
 		"FETCH_YOUTUBE_WATCH_TIME":               o.fetchYouTubeWatchTime,
 		"HTTPS":                                  o.HTTPS,
 		"HTTP_CLIENT_MAX_BODY_SIZE":              o.httpClientMaxBodySize,
@@ -590,6 +673,7 @@ func (o *Options) SortedOptions(redactSecret bool) []*Option {
 		"METRICS_ALLOWED_NETWORKS":               strings.Join(o.metricsAllowedNetworks, ","),
 		"METRICS_COLLECTOR":                      o.metricsCollector,
 		"METRICS_REFRESH_INTERVAL":               o.metricsRefreshInterval,
+		"METRICS_STORAGE_REFRESH_INTERVAL":       o.metricsStorageRefreshInterval,
 		"METRICS_USERNAME":                       o.metricsUsername,
 		"METRICS_PASSWORD":                       redactSecretValue(o.metricsPassword, redactSecret),
 		"OAUTH2_CLIENT_ID":                       o.oauth2ClientID,
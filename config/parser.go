@@ -13,13 +13,18 @@ import (
 	"io"
 	url_parser "net/url"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 )
 
 // Parser handles configuration parsing.
 type Parser struct {
-	opts *Options
+	opts     *Options
+	problems []*Problem
+
+	// reportUnknownKeys is enabled for configuration files, the environment contains unrelated variables.
+	reportUnknownKeys bool
 }
 
 // NewParser returns a new Parser.
@@ -39,6 +44,7 @@ func (p *Parser) ParseEnvironmentVariables() (*Options, error) {
 }
 
 // ParseFile loads configuration values from a local file.
+// Files with the extension ".yaml" or ".yml" are parsed as YAML, other files contain KEY=VALUE lines.
 func (p *Parser) ParseFile(filename string) (*Options, error) {
 	fp, err := os.Open(filename)
 	if err != nil {
@@ -46,13 +52,31 @@ func (p *Parser) ParseFile(filename string) (*Options, error) {
 	}
 	defer fp.Close()
 
-	err = p.parseLines(p.parseFileContent(fp))
+	var lines []string
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".yaml", ".yml":
+		if lines, err = parseYAMLContent(fp); err != nil {
+			return nil, err
+		}
+	default:
+		lines = p.parseFileContent(fp)
+	}
+
+	p.reportUnknownKeys = true
+	defer func() { p.reportUnknownKeys = false }()
+
+	err = p.parseLines(lines)
 	if err != nil {
 		return nil, err
 	}
 	return p.opts, nil
 }
 
+// Problems returns the unknown keys, the invalid values and the deprecated options found while parsing.
+func (p *Parser) Problems() []*Problem {
+	return p.problems
+}
+
 func (p *Parser) parseFileContent(r io.Reader) (lines []string) {
 	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
@@ -72,13 +96,17 @@ func (p *Parser) parseLines(lines []string) (err error) {
 		key := strings.TrimSpace(fields[0])
 		value := strings.TrimSpace(fields[1])
 
+		if replacement, found := deprecatedOptions[key]; found {
+			p.addProblem(ProblemDeprecatedOption, key, fmt.Sprintf("use %s instead", replacement))
+		}
+
 		switch key {
 		case "LOG_DATE_TIME":
-			p.opts.logDateTime = parseBool(value, defaultLogDateTime)
+			p.opts.logDateTime = p.parseBool(key, value, defaultLogDateTime)
 		case "DEBUG":
-			p.opts.debug = parseBool(value, defaultDebug)
+			p.opts.debug = p.parseBool(key, value, defaultDebug)
 		case "SERVER_TIMING_HEADER":
-			p.opts.serverTimingHeader = parseBool(value, defaultTiming)
+			p.opts.serverTimingHeader = p.parseBool(key, value, defaultTiming)
 		case "BASE_URL":
 			p.opts.baseURL, p.opts.rootURL, p.opts.basePath, err = parseBaseURL(value)
 			if err != nil {
@@ -91,23 +119,23 @@ func (p *Parser) parseLines(lines []string) (err error) {
 		case "DATABASE_URL":
 			p.opts.databaseURL = parseString(value, defaultDatabaseURL)
 		case "DATABASE_URL_FILE":
-			p.opts.databaseURL = readSecretFile(value, defaultDatabaseURL)
+			p.opts.databaseURL = p.readSecretFile(key, value, defaultDatabaseURL)
 		case "DATABASE_MAX_CONNS":
-			p.opts.databaseMaxConns = parseInt(value, defaultDatabaseMaxConns)
+			p.opts.databaseMaxConns = p.parseInt(key, value, defaultDatabaseMaxConns)
 		case "DATABASE_MIN_CONNS":
-			p.opts.databaseMinConns = parseInt(value, defaultDatabaseMinConns)
+			p.opts.databaseMinConns = p.parseInt(key, value, defaultDatabaseMinConns)
 		case "DATABASE_CONNECTION_LIFETIME":
-			p.opts.databaseConnectionLifetime = parseInt(value, defaultDatabaseConnectionLifetime)
+			p.opts.databaseConnectionLifetime = p.parseInt(key, value, defaultDatabaseConnectionLifetime)
 		case "RUN_MIGRATIONS":
-			p.opts.runMigrations = parseBool(value, defaultRunMigrations)
+			p.opts.runMigrations = p.parseBool(key, value, defaultRunMigrations)
 		case "DISABLE_HSTS":
-			p.opts.hsts = !parseBool(value, defaultHSTS)
+			p.opts.hsts = !p.parseBool(key, value, defaultHSTS)
 		case "HTTPS":
-			p.opts.HTTPS = parseBool(value, defaultHTTPS)
+			p.opts.HTTPS = p.parseBool(key, value, defaultHTTPS)
 		case "DISABLE_SCHEDULER_SERVICE":
-			p.opts.schedulerService = !parseBool(value, defaultSchedulerService)
+			p.opts.schedulerService = !p.parseBool(key, value, defaultSchedulerService)
 		case "DISABLE_HTTP_SERVICE":
-			p.opts.httpService = !parseBool(value, defaultHTTPService)
+			p.opts.httpService = !p.parseBool(key, value, defaultHTTPService)
 		case "CERT_FILE":
 			p.opts.certFile = parseString(value, defaultCertFile)
 		case "KEY_FILE":
@@ -115,36 +143,41 @@ func (p *Parser) parseLines(lines []string) (err error) {
 		case "CERT_DOMAIN":
 			p.opts.certDomain = parseString(value, defaultCertDomain)
 		case "CLEANUP_FREQUENCY_HOURS":
-			p.opts.cleanupFrequencyHours = parseInt(value, defaultCleanupFrequencyHours)
+			p.opts.cleanupFrequencyHours = p.parseInt(key, value, defaultCleanupFrequencyHours)
 		case "CLEANUP_ARCHIVE_READ_DAYS":
-			p.opts.cleanupArchiveReadDays = parseInt(value, defaultCleanupArchiveReadDays)
+			p.opts.cleanupArchiveReadDays = p.parseInt(key, value, defaultCleanupArchiveReadDays)
 		case "CLEANUP_ARCHIVE_UNREAD_DAYS":
-			p.opts.cleanupArchiveUnreadDays = parseInt(value, defaultCleanupArchiveUnreadDays)
+			p.opts.cleanupArchiveUnreadDays = p.parseInt(key, value, defaultCleanupArchiveUnreadDays)
 		case "CLEANUP_ARCHIVE_BATCH_SIZE":
-			p.opts.cleanupArchiveBatchSize = parseInt(value, defaultCleanupArchiveBatchSize)
+			p.opts.cleanupArchiveBatchSize = p.parseInt(key, value, defaultCleanupArchiveBatchSize)
 		case "CLEANUP_REMOVE_SESSIONS_DAYS":
-			p.opts.cleanupRemoveSessionsDays = parseInt(value, defaultCleanupRemoveSessionsDays)
+			p.opts.cleanupRemoveSessionsDays = p.parseInt(key, value, defaultCleanupRemoveSessionsDays)
+		case "ENTRY_REVISIONS_LIMIT":
+			p.opts.entryRevisionsLimit = p.parseInt(key, value, defaultEntryRevisionsLimit)
 		case "WORKER_POOL_SIZE":
-			p.opts.workerPoolSize = parseInt(value, defaultWorkerPoolSize)
+			p.opts.workerPoolSize = p.parseInt(key, value, defaultWorkerPoolSize)
 		case "POLLING_FREQUENCY":
-			p.opts.pollingFrequency = parseInt(value, defaultPollingFrequency)
+			p.opts.pollingFrequency = p.parseInt(key, value, defaultPollingFrequency)
 		case "BATCH_SIZE":
-			p.opts.batchSize = parseInt(value, defaultBatchSize)
+			p.opts.batchSize = p.parseInt(key, value, defaultBatchSize)
 		case "POLLING_SCHEDULER":
 			p.opts.pollingScheduler = strings.ToLower(parseString(value, defaultPollingScheduler))
+			p.checkChoice(key, p.opts.pollingScheduler, "round_robin", "entry_frequency")
 		case "SCHEDULER_ENTRY_FREQUENCY_MAX_INTERVAL":
-			p.opts.schedulerEntryFrequencyMaxInterval = parseInt(value, defaultSchedulerEntryFrequencyMaxInterval)
+			p.opts.schedulerEntryFrequencyMaxInterval = p.parseInt(key, value, defaultSchedulerEntryFrequencyMaxInterval)
 		case "SCHEDULER_ENTRY_FREQUENCY_MIN_INTERVAL":
-			p.opts.schedulerEntryFrequencyMinInterval = parseInt(value, defaultSchedulerEntryFrequencyMinInterval)
+			p.opts.schedulerEntryFrequencyMinInterval = p.parseInt(key, value, defaultSchedulerEntryFrequencyMinInterval)
 		case "POLLING_PARSING_ERROR_LIMIT":
-			p.opts.pollingParsingErrorLimit = parseInt(value, defaultPollingParsingErrorLimit)
+			p.opts.pollingParsingErrorLimit = p.parseInt(key, value, defaultPollingParsingErrorLimit)
 		// kept for compatibility purpose
 		case "PROXY_IMAGES":
 			p.opts.proxyOption = parseString(value, defaultProxyOption)
+			p.checkChoice(key, p.opts.proxyOption, "none", "http-only", "all")
 		case "PROXY_HTTP_CLIENT_TIMEOUT":
-			p.opts.proxyHTTPClientTimeout = parseInt(value, defaultProxyHTTPClientTimeout)
+			p.opts.proxyHTTPClientTimeout = p.parseInt(key, value, defaultProxyHTTPClientTimeout)
 		case "PROXY_OPTION":
 			p.opts.proxyOption = parseString(value, defaultProxyOption)
+			p.checkChoice(key, p.opts.proxyOption, "none", "http-only", "all")
 		case "PROXY_MEDIA_TYPES":
 			p.opts.proxyMediaTypes = parseStringList(value, []string{defaultProxyMediaTypes})
 		// kept for compatibility purpose
@@ -153,77 +186,97 @@ func (p *Parser) parseLines(lines []string) (err error) {
 		case "PROXY_URL":
 			p.opts.proxyUrl = parseString(value, defaultProxyUrl)
 		case "CREATE_ADMIN":
-			p.opts.createAdmin = parseBool(value, defaultCreateAdmin)
+			p.opts.createAdmin = p.parseBool(key, value, defaultCreateAdmin)
 		case "ADMIN_USERNAME":
 			p.opts.adminUsername = parseString(value, defaultAdminUsername)
 		case "ADMIN_USERNAME_FILE":
-			p.opts.adminUsername = readSecretFile(value, defaultAdminUsername)
+			p.opts.adminUsername = p.readSecretFile(key, value, defaultAdminUsername)
 		case "ADMIN_PASSWORD":
 			p.opts.adminPassword = parseString(value, defaultAdminPassword)
 		case "ADMIN_PASSWORD_FILE":
-			p.opts.adminPassword = readSecretFile(value, defaultAdminPassword)
+			p.opts.adminPassword = p.readSecretFile(key, value, defaultAdminPassword)
 		case "POCKET_CONSUMER_KEY":
 			p.opts.pocketConsumerKey = parseString(value, defaultPocketConsumerKey)
 		case "POCKET_CONSUMER_KEY_FILE":
-			p.opts.pocketConsumerKey = readSecretFile(value, defaultPocketConsumerKey)
+			p.opts.pocketConsumerKey = p.readSecretFile(key, value, defaultPocketConsumerKey)
 		case "OAUTH2_USER_CREATION":
-			p.opts.oauth2UserCreationAllowed = parseBool(value, defaultOAuth2UserCreation)
+			p.opts.oauth2UserCreationAllowed = p.parseBool(key, value, defaultOAuth2UserCreation)
 		case "OAUTH2_CLIENT_ID":
 			p.opts.oauth2ClientID = parseString(value, defaultOAuth2ClientID)
 		case "OAUTH2_CLIENT_ID_FILE":
-			p.opts.oauth2ClientID = readSecretFile(value, defaultOAuth2ClientID)
+			p.opts.oauth2ClientID = p.readSecretFile(key, value, defaultOAuth2ClientID)
 		case "OAUTH2_CLIENT_SECRET":
 			p.opts.oauth2ClientSecret = parseString(value, defaultOAuth2ClientSecret)
 		case "OAUTH2_CLIENT_SECRET_FILE":
-			p.opts.oauth2ClientSecret = readSecretFile(value, defaultOAuth2ClientSecret)
+			p.opts.oauth2ClientSecret = p.readSecretFile(key, value, defaultOAuth2ClientSecret)
 		case "OAUTH2_REDIRECT_URL":
 			p.opts.oauth2RedirectURL = parseString(value, defaultOAuth2RedirectURL)
 		case "OAUTH2_OIDC_DISCOVERY_ENDPOINT":
 			p.opts.oauth2OidcDiscoveryEndpoint = parseString(value, defaultOAuth2OidcDiscoveryEndpoint)
 		case "OAUTH2_PROVIDER":
 			p.opts.oauth2Provider = parseString(value, defaultOAuth2Provider)
+			p.checkChoice(key, p.opts.oauth2Provider, "", "google", "oidc")
 		case "HTTP_CLIENT_TIMEOUT":
-			p.opts.httpClientTimeout = parseInt(value, defaultHTTPClientTimeout)
+			p.opts.httpClientTimeout = p.parseInt(key, value, defaultHTTPClientTimeout)
 		case "HTTP_CLIENT_MAX_BODY_SIZE":
-			p.opts.httpClientMaxBodySize = int64(parseInt(value, defaultHTTPClientMaxBodySize) * 1024 * 1024)
+			p.opts.httpClientMaxBodySize = int64(p.parseInt(key, value, defaultHTTPClientMaxBodySize) * 1024 * 1024)
 		case "HTTP_CLIENT_PROXY":
 			p.opts.httpClientProxy = parseString(value, defaultHTTPClientProxy)
 		case "HTTP_CLIENT_USER_AGENT":
 			p.opts.httpClientUserAgent = parseString(value, defaultHTTPClientUserAgent)
 		case "HTTP_SERVER_TIMEOUT":
-			p.opts.httpServerTimeout = parseInt(value, defaultHTTPServerTimeout)
+			p.opts.httpServerTimeout = p.parseInt(key, value, defaultHTTPServerTimeout)
 		case "AUTH_PROXY_HEADER":
 			p.opts.authProxyHeader = parseString(value, defaultAuthProxyHeader)
 		case "AUTH_PROXY_USER_CREATION":
-			p.opts.authProxyUserCreation = parseBool(value, defaultAuthProxyUserCreation)
+			p.opts.authProxyUserCreation = p.parseBool(key, value, defaultAuthProxyUserCreation)
 		case "MAINTENANCE_MODE":
-			p.opts.maintenanceMode = parseBool(value, defaultMaintenanceMode)
+			p.opts.maintenanceMode = p.parseBool(key, value, defaultMaintenanceMode)
 		case "MAINTENANCE_MESSAGE":
 			p.opts.maintenanceMessage = parseString(value, defaultMaintenanceMessage)
 		case "METRICS_COLLECTOR":
-			p.opts.metricsCollector = parseBool(value, defaultMetricsCollector)
+			p.opts.metricsCollector = p.parseBool(key, value, defaultMetricsCollector)
 		case "METRICS_REFRESH_INTERVAL":
-			p.opts.metricsRefreshInterval = parseInt(value, defaultMetricsRefreshInterval)
+			p.opts.metricsRefreshInterval = p.parseInt(key, value, defaultMetricsRefreshInterval)
+		case "METRICS_STORAGE_REFRESH_INTERVAL":
+			p.opts.metricsStorageRefreshInterval = p.parseInt(key, value, defaultMetricsStorageRefreshInterval)
 		case "METRICS_ALLOWED_NETWORKS":
 			p.opts.metricsAllowedNetworks = parseStringList(value, []string{defaultMetricsAllowedNetworks})
+			p.checkNetworks(key, p.opts.metricsAllowedNetworks)
 		case "METRICS_USERNAME":
 			p.opts.metricsUsername = parseString(value, defaultMetricsUsername)
 		case "METRICS_USERNAME_FILE":
-			p.opts.metricsUsername = readSecretFile(value, defaultMetricsUsername)
+			p.opts.metricsUsername = p.readSecretFile(key, value, defaultMetricsUsername)
 		case "METRICS_PASSWORD":
 			p.opts.metricsPassword = parseString(value, defaultMetricsPassword)
 		case "METRICS_PASSWORD_FILE":
-			p.opts.metricsPassword = readSecretFile(value, defaultMetricsPassword)
+			p.opts.metricsPassword = p.readSecretFile(key, value, defaultMetricsPassword)
 		case "FETCH_YOUTUBE_WATCH_TIME":
-			p.opts.fetchYouTubeWatchTime = parseBool(value, defaultFetchYouTubeWatchTime)
+			p.opts.fetchYouTubeWatchTime = p.parseBool(key, value, defaultFetchYouTubeWatchTime)
 		case "WATCHDOG":
-			p.opts.watchdog = parseBool(value, defaultWatchdog)
+			p.opts.watchdog = p.parseBool(key, value, defaultWatchdog)
 		case "INVIDIOUS_INSTANCE":
 			p.opts.invidiousInstance = parseString(value, defaultInvidiousInstance)
+		case "CLIENT_URL":
+			p.opts.clientURL = parseString(value, defaultClientURL)
+		case "CLIENT_API_KEY":
+			p.opts.clientAPIKey = parseString(value, defaultClientAPIKey)
+		case "CLIENT_API_KEY_FILE":
+			p.opts.clientAPIKey = p.readSecretFile(key, value, defaultClientAPIKey)
+		case "CLIENT_USERNAME":
+			p.opts.clientUsername = parseString(value, defaultClientUsername)
+		case "CLIENT_PASSWORD":
+			p.opts.clientPassword = parseString(value, defaultClientPassword)
+		case "CLIENT_PASSWORD_FILE":
+			p.opts.clientPassword = p.readSecretFile(key, value, defaultClientPassword)
 		case "PROXY_PRIVATE_KEY":
 			randomKey := make([]byte, 16)
 			rand.Read(randomKey)
 			p.opts.proxyPrivateKey = parseBytes(value, randomKey)
+		default:
+			if p.reportUnknownKeys {
+				p.addProblem(ProblemUnknownKey, key, "this option does not exist")
+			}
 		}
 	}
 
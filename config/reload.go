@@ -0,0 +1,83 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package config // import "miniflux.app/config"
+
+import (
+	"fmt"
+)
+
+// ReloadableOptions are the options applied by Reload without restarting the application.
+var ReloadableOptions = []string{
+	"DEBUG",
+	"HTTP_CLIENT_PROXY",
+	"LOG_DATE_TIME",
+	"METRICS_ALLOWED_NETWORKS",
+	"POLLING_FREQUENCY",
+	"PROXY_HTTP_CLIENT_TIMEOUT",
+	"PROXY_MEDIA_TYPES",
+	"PROXY_OPTION",
+	"PROXY_URL",
+}
+
+// OptionChange is an option with a different value in two sets of options, the secrets are redacted.
+type OptionChange struct {
+	Key      string
+	OldValue interface{}
+	NewValue interface{}
+}
+
+// Diff returns the options of o that are different in other, sorted by keys.
+func (o *Options) Diff(other *Options) []*OptionChange {
+	oldValues, oldRedactedValues := o.SortedOptions(false), o.SortedOptions(true)
+	newValues, newRedactedValues := other.SortedOptions(false), other.SortedOptions(true)
+
+	var changes []*OptionChange
+	for i, option := range oldValues {
+		// The private key is generated randomly when it is not defined.
+		if option.Key == "PROXY_PRIVATE_KEY" {
+			continue
+		}
+
+		if fmt.Sprint(option.Value) != fmt.Sprint(newValues[i].Value) {
+			changes = append(changes, &OptionChange{Key: option.Key, OldValue: oldRedactedValues[i].Value, NewValue: newRedactedValues[i].Value})
+		}
+	}
+	return changes
+}
+
+// Reload applies the reloadable options of other.
+// It returns the keys of the options applied, and the keys of the options that require a restart.
+func (o *Options) Reload(other *Options) (applied, ignored []string) {
+	reloadable := make(map[string]bool, len(ReloadableOptions))
+	for _, key := range ReloadableOptions {
+		reloadable[key] = true
+	}
+
+	for _, change := range o.Diff(other) {
+		if reloadable[change.Key] {
+			applied = append(applied, change.Key)
+		} else {
+			ignored = append(ignored, change.Key)
+		}
+	}
+
+	other.mu.RLock()
+	defer other.mu.RUnlock()
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.debug = other.debug
+	o.httpClientProxy = other.httpClientProxy
+	o.logDateTime = other.logDateTime
+	o.metricsAllowedNetworks = other.metricsAllowedNetworks
+	o.pollingFrequency = other.pollingFrequency
+	o.proxyHTTPClientTimeout = other.proxyHTTPClientTimeout
+	o.proxyMediaTypes = other.proxyMediaTypes
+	o.proxyOption = other.proxyOption
+	o.proxyUrl = other.proxyUrl
+
+	return applied, ignored
+}
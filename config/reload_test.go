@@ -0,0 +1,71 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package config // import "miniflux.app/config"
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestOptionsDiff(t *testing.T) {
+	os.Clearenv()
+	os.Setenv("POLLING_FREQUENCY", "30")
+	os.Setenv("DATABASE_URL", "postgres://miniflux:secret@db/miniflux")
+
+	opts, err := NewParser().ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changes := NewOptions().Diff(opts)
+	if len(changes) != 2 {
+		t.Fatalf(`Unexpected changes: %+v`, changes)
+	}
+
+	if changes[0].Key != "DATABASE_URL" || changes[0].NewValue != "<secret>" {
+		t.Errorf(`The secrets should be redacted: %+v`, changes[0])
+	}
+
+	if changes[1].Key != "POLLING_FREQUENCY" || changes[1].OldValue != defaultPollingFrequency || changes[1].NewValue != 30 {
+		t.Errorf(`Unexpected change: %+v`, changes[1])
+	}
+}
+
+func TestOptionsReload(t *testing.T) {
+	os.Clearenv()
+
+	opts, err := NewParser().ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	os.Setenv("POLLING_FREQUENCY", "15")
+	os.Setenv("PROXY_OPTION", "all")
+	os.Setenv("METRICS_ALLOWED_NETWORKS", "10.0.0.0/8")
+	os.Setenv("WORKER_POOL_SIZE", "20")
+
+	reloaded, err := NewParser().ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	applied, ignored := opts.Reload(reloaded)
+	if strings.Join(applied, ",") != "METRICS_ALLOWED_NETWORKS,POLLING_FREQUENCY,PROXY_OPTION" {
+		t.Errorf(`Unexpected applied options: %v`, applied)
+	}
+
+	if strings.Join(ignored, ",") != "WORKER_POOL_SIZE" {
+		t.Errorf(`Unexpected ignored options: %v`, ignored)
+	}
+
+	if opts.PollingFrequency() != 15 || opts.ProxyOption() != "all" || opts.MetricsAllowedNetworks()[0] != "10.0.0.0/8" {
+		t.Errorf(`The reloadable options should be applied`)
+	}
+
+	if opts.WorkerPoolSize() != defaultWorkerPoolSize {
+		t.Errorf(`The worker pool size requires a restart, got %d`, opts.WorkerPoolSize())
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package config // import "miniflux.app/config"
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// List of problem kinds reported by the parser.
+const (
+	ProblemUnknownKey       = "unknown"
+	ProblemInvalidValue     = "invalid"
+	ProblemDeprecatedOption = "deprecated"
+)
+
+// deprecatedOptions maps the options kept for compatibility purpose to their replacement.
+var deprecatedOptions = map[string]string{
+	"PROXY_IMAGES":    "PROXY_OPTION",
+	"PROXY_IMAGE_URL": "PROXY_URL",
+}
+
+// Problem is an issue found in the configuration, the default value is used for invalid values.
+type Problem struct {
+	Kind    string
+	Key     string
+	Message string
+}
+
+func (p *Problem) String() string {
+	return fmt.Sprintf("%s option %s: %s", p.Kind, p.Key, p.Message)
+}
+
+// IsError returns true when the problem prevents the option from being applied.
+func (p *Problem) IsError() bool {
+	return p.Kind != ProblemDeprecatedOption
+}
+
+func (p *Parser) addProblem(kind, key, message string) {
+	p.problems = append(p.problems, &Problem{Kind: kind, Key: key, Message: message})
+}
+
+func (p *Parser) parseInt(key, value string, fallback int) int {
+	if value != "" {
+		if _, err := strconv.Atoi(value); err != nil {
+			p.addProblem(ProblemInvalidValue, key, fmt.Sprintf("%q is not an integer, the default value %d is used", value, fallback))
+		}
+	}
+	return parseInt(value, fallback)
+}
+
+func (p *Parser) parseBool(key, value string, fallback bool) bool {
+	switch strings.ToLower(value) {
+	case "", "1", "0", "yes", "no", "true", "false", "on", "off":
+	default:
+		p.addProblem(ProblemInvalidValue, key, fmt.Sprintf("%q is not a boolean, it is considered as false", value))
+	}
+	return parseBool(value, fallback)
+}
+
+func (p *Parser) readSecretFile(key, filename, fallback string) string {
+	if _, err := os.Stat(filename); filename != "" && err != nil {
+		p.addProblem(ProblemInvalidValue, key, fmt.Sprintf("unable to read the file %q", filename))
+	}
+	return readSecretFile(filename, fallback)
+}
+
+func (p *Parser) checkChoice(key, value string, choices ...string) {
+	for _, choice := range choices {
+		if value == choice {
+			return
+		}
+	}
+	p.addProblem(ProblemInvalidValue, key, fmt.Sprintf("%q is not one of %q", value, choices))
+}
+
+func (p *Parser) checkNetworks(key string, networks []string) {
+	for _, network := range networks {
+		if _, _, err := net.ParseCIDR(network); err != nil {
+			p.addProblem(ProblemInvalidValue, key, fmt.Sprintf("%q is not a valid network", network))
+		}
+	}
+}
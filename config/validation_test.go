@@ -0,0 +1,131 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package config // import "miniflux.app/config"
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTestConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	filename := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(filename, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+func TestParseYAMLConfigFile(t *testing.T) {
+	filename := writeTestConfigFile(t, "miniflux.yaml", `
+# This is a comment
+debug: true
+polling-frequency: 30
+BATCH_SIZE: 50
+database:
+  url: postgres://miniflux:secret@db/miniflux
+  max_conns: 42
+metrics_allowed_networks:
+  - 10.0.0.0/8
+  - 192.168.0.0/16
+proxy_url:
+`)
+
+	os.Clearenv()
+
+	parser := NewParser()
+	opts, err := parser.ParseFile(filename)
+	if err != nil {
+		t.Fatalf(`Parsing failure: %v`, err)
+	}
+
+	if !opts.HasDebugMode() || opts.PollingFrequency() != 30 || opts.BatchSize() != 50 {
+		t.Errorf(`Unexpected values: debug=%v polling=%d batch=%d`, opts.HasDebugMode(), opts.PollingFrequency(), opts.BatchSize())
+	}
+
+	if opts.DatabaseURL() != "postgres://miniflux:secret@db/miniflux" || opts.DatabaseMaxConns() != 42 {
+		t.Errorf(`Unexpected database values: %q %d`, opts.DatabaseURL(), opts.DatabaseMaxConns())
+	}
+
+	if networks := strings.Join(opts.MetricsAllowedNetworks(), ","); networks != "10.0.0.0/8,192.168.0.0/16" {
+		t.Errorf(`Unexpected networks: %q`, networks)
+	}
+
+	if opts.ProxyUrl() != defaultProxyUrl {
+		t.Errorf(`An empty value should use the default value, got %q`, opts.ProxyUrl())
+	}
+
+	if problems := parser.Problems(); len(problems) != 0 {
+		t.Errorf(`Unexpected problems: %v`, problems)
+	}
+}
+
+func TestParseInvalidYAMLConfigFile(t *testing.T) {
+	os.Clearenv()
+
+	for _, content := range []string{"debug: [true", "metrics_allowed_networks:\n  - {a: b}"} {
+		filename := writeTestConfigFile(t, "miniflux.yml", content)
+		if _, err := NewParser().ParseFile(filename); err == nil {
+			t.Errorf(`The file should be rejected: %q`, content)
+		}
+	}
+}
+
+func TestConfigProblems(t *testing.T) {
+	filename := writeTestConfigFile(t, "miniflux.conf", `
+POLLING_FREQENCY=30
+BATCH_SIZE=many
+DEBUG=maybe
+PROXY_IMAGES=all
+POLLING_SCHEDULER=random
+METRICS_ALLOWED_NETWORKS=127.0.0.1/8,localhost
+ADMIN_PASSWORD_FILE=/does/not/exist
+`)
+
+	os.Clearenv()
+	os.Setenv("PATH", "/usr/bin")
+	os.Setenv("WORKER_POOL_SIZE", "ten")
+
+	parser := NewParser()
+	if _, err := parser.ParseFile(filename); err != nil {
+		t.Fatal(err)
+	}
+
+	opts, err := parser.ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if opts.BatchSize() != defaultBatchSize || opts.WorkerPoolSize() != defaultWorkerPoolSize {
+		t.Errorf(`The default values should be used for invalid integers`)
+	}
+
+	var reported []string
+	for _, problem := range parser.Problems() {
+		reported = append(reported, problem.Kind+" "+problem.Key)
+	}
+
+	expected := []string{
+		"unknown POLLING_FREQENCY",
+		"invalid BATCH_SIZE",
+		"invalid DEBUG",
+		"deprecated PROXY_IMAGES",
+		"invalid POLLING_SCHEDULER",
+		"invalid METRICS_ALLOWED_NETWORKS",
+		"invalid ADMIN_PASSWORD_FILE",
+		"invalid WORKER_POOL_SIZE",
+	}
+
+	if strings.Join(reported, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf(`Unexpected problems: %q`, reported)
+	}
+
+	if parser.Problems()[3].IsError() || !parser.Problems()[0].IsError() {
+		t.Errorf(`Only the deprecated options should not be errors`)
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package config // import "miniflux.app/config"
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// parseYAMLContent converts a YAML document to KEY=VALUE lines.
+//
+// The keys are the names of the environment variables, in any case. Nested keys are joined with an underscore,
+// and lists are joined with a comma:
+//
+//	polling_frequency: 30
+//	database:
+//	  url: postgres://miniflux:secret@db/miniflux
+//	  max_conns: 20
+//	metrics_allowed_networks:
+//	  - 127.0.0.1/8
+//	  - 10.0.0.0/8
+func parseYAMLContent(r io.Reader) ([]string, error) {
+	var document map[string]interface{}
+	if err := yaml.NewDecoder(r).Decode(&document); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("config: unable to parse the YAML file: %v", err)
+	}
+
+	var lines []string
+	if err := flattenYAML("", document, &lines); err != nil {
+		return nil, err
+	}
+	sort.Strings(lines)
+	return lines, nil
+}
+
+func flattenYAML(prefix string, node map[string]interface{}, lines *[]string) error {
+	for name, value := range node {
+		key := strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+		if prefix != "" {
+			key = prefix + "_" + key
+		}
+
+		switch value := value.(type) {
+		case map[string]interface{}:
+			if err := flattenYAML(key, value, lines); err != nil {
+				return err
+			}
+		case []interface{}:
+			items := make([]string, 0, len(value))
+			for _, item := range value {
+				if !isYAMLScalar(item) {
+					return fmt.Errorf("config: the list %s must contain only scalar values", key)
+				}
+				items = append(items, fmt.Sprint(item))
+			}
+			*lines = append(*lines, key+"="+strings.Join(items, ","))
+		case nil:
+			*lines = append(*lines, key+"=")
+		default:
+			if !isYAMLScalar(value) {
+				return fmt.Errorf("config: unsupported value for %s", key)
+			}
+			*lines = append(*lines, fmt.Sprintf("%s=%v", key, value))
+		}
+	}
+	return nil
+}
+
+func isYAMLScalar(value interface{}) bool {
+	switch value.(type) {
+	case string, bool, int, int64, uint64, float64:
+		return true
+	default:
+		return false
+	}
+}
@@ -7,15 +7,31 @@ package database // import "miniflux.app/database"
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	// Postgresql driver import
 	_ "github.com/lib/pq"
 )
 
+// Supported database drivers.
+const (
+	PostgreSQL = "postgres"
+	SQLite     = "sqlite"
+)
+
+const sqliteScheme = "sqlite://"
+
 // NewConnectionPool configures the database connection pool.
+// A database URL starting with "sqlite://" or "file:" opens a SQLite database, otherwise PostgreSQL is used.
 func NewConnectionPool(dsn string, minConnections, maxConnections int, connectionLifetime time.Duration) (*sql.DB, error) {
-	db, err := sql.Open("postgres", dsn)
+	driverName := PostgreSQL
+	if isSQLiteURL(dsn) {
+		driverName = sqliteDriverName
+		dsn = sqliteDSN(dsn)
+	}
+
+	db, err := sql.Open(driverName, dsn)
 	if err != nil {
 		return nil, err
 	}
@@ -27,8 +43,24 @@ func NewConnectionPool(dsn string, minConnections, maxConnections int, connectio
 	return db, nil
 }
 
+// isSQLiteURL returns true if the database URL refers to a SQLite database.
+func isSQLiteURL(dsn string) bool {
+	return strings.HasPrefix(dsn, sqliteScheme) || strings.HasPrefix(dsn, "file:")
+}
+
+// DriverName returns the database driver used by the connection pool.
+func DriverName(db *sql.DB) string {
+	if _, ok := db.Driver().(*sqliteDriver); ok {
+		return SQLite
+	}
+	return PostgreSQL
+}
+
 // Migrate executes database migrations.
 func Migrate(db *sql.DB) error {
+	migrations := driverMigrations(db)
+	schemaVersion := len(migrations)
+
 	var currentVersion int
 	db.QueryRow(`SELECT version FROM schema_version`).Scan(&currentVersion)
 
@@ -69,6 +101,8 @@ func Migrate(db *sql.DB) error {
 
 // IsSchemaUpToDate checks if the database schema is up to date.
 func IsSchemaUpToDate(db *sql.DB) error {
+	schemaVersion := len(driverMigrations(db))
+
 	var currentVersion int
 	db.QueryRow(`SELECT version FROM schema_version`).Scan(&currentVersion)
 	if currentVersion < schemaVersion {
@@ -76,3 +110,10 @@ func IsSchemaUpToDate(db *sql.DB) error {
 	}
 	return nil
 }
+
+func driverMigrations(db *sql.DB) []func(tx *sql.Tx) error {
+	if DriverName(db) == SQLite {
+		return sqliteMigrations
+	}
+	return migrations
+}
@@ -8,9 +8,8 @@ import (
 	"database/sql"
 )
 
-var schemaVersion = len(migrations)
-
 // Order is important. Add new migrations at the end of the list.
+// Schema changes must also be added to the SQLite migrations.
 var migrations = []func(tx *sql.Tx) error{
 	func(tx *sql.Tx) (err error) {
 		sql := `
@@ -653,4 +652,231 @@ var migrations = []func(tx *sql.Tx) error{
 		_, err = tx.Exec(sql)
 		return err
 	},
+	func(tx *sql.Tx) (err error) {
+		// The document vectors of the existing entries are not rebuilt: their language is unknown,
+		// they are indexed again with the entry language when the feed refresh updates them.
+		_, err = tx.Exec(`
+			ALTER TABLE entries ADD COLUMN language text not null default '';
+		`)
+		return
+	},
+	func(tx *sql.Tx) (err error) {
+		sql := `
+			CREATE TABLE labels (
+				id bigserial not null,
+				user_id int not null,
+				title text not null,
+				primary key (id),
+				foreign key (user_id) references users(id) on delete cascade
+			);
+
+			CREATE UNIQUE INDEX labels_user_id_lower_title_idx ON labels (user_id, lower(title));
+
+			CREATE TABLE entry_labels (
+				entry_id bigint not null,
+				label_id bigint not null,
+				primary key (entry_id, label_id),
+				foreign key (entry_id) references entries(id) on delete cascade,
+				foreign key (label_id) references labels(id) on delete cascade
+			);
+
+			CREATE INDEX entry_labels_label_id_idx ON entry_labels (label_id);
+		`
+		_, err = tx.Exec(sql)
+		return err
+	},
+	func(tx *sql.Tx) (err error) {
+		sql := `
+			CREATE TABLE entry_annotations (
+				id bigserial not null,
+				user_id int not null,
+				entry_id bigint not null,
+				quote text not null default '',
+				start_offset int not null default 0,
+				end_offset int not null default 0,
+				note text not null default '',
+				created_at timestamp with time zone not null default now(),
+				changed_at timestamp with time zone not null default now(),
+				primary key (id),
+				foreign key (user_id) references users(id) on delete cascade,
+				foreign key (entry_id) references entries(id) on delete cascade
+			);
+
+			CREATE INDEX entry_annotations_entry_id_idx ON entry_annotations (entry_id);
+			CREATE INDEX entry_annotations_user_id_idx ON entry_annotations (user_id);
+		`
+		_, err = tx.Exec(sql)
+		return err
+	},
+	func(tx *sql.Tx) (err error) {
+		sql := `
+			ALTER TABLE feeds ADD COLUMN mark_updated_entries_unread bool not null default 'f';
+			ALTER TABLE entries ADD COLUMN revision_count int not null default 0;
+
+			CREATE TABLE entry_revisions (
+				id bigserial not null,
+				entry_id bigint not null,
+				title text not null default '',
+				content text not null default '',
+				created_at timestamp with time zone not null default now(),
+				primary key (id),
+				foreign key (entry_id) references entries(id) on delete cascade
+			);
+
+			CREATE INDEX entry_revisions_entry_id_idx ON entry_revisions (entry_id);
+		`
+		_, err = tx.Exec(sql)
+		return err
+	},
+	func(tx *sql.Tx) (err error) {
+		sql := `
+			CREATE TABLE webhooks (
+				id bigserial not null,
+				user_id int not null,
+				url text not null,
+				secret text not null,
+				events text[] not null default '{}',
+				created_at timestamp with time zone not null default now(),
+				primary key (id),
+				foreign key (user_id) references users(id) on delete cascade
+			);
+
+			CREATE INDEX webhooks_user_id_idx ON webhooks (user_id);
+
+			CREATE TABLE webhook_deliveries (
+				id bigserial not null,
+				webhook_id bigint not null,
+				event text not null,
+				payload text not null,
+				status text not null default 'pending',
+				attempts int not null default 0,
+				response_status int not null default 0,
+				error_msg text not null default '',
+				next_attempt_at timestamp with time zone not null default now(),
+				last_attempt_at timestamp with time zone,
+				created_at timestamp with time zone not null default now(),
+				primary key (id),
+				foreign key (webhook_id) references webhooks(id) on delete cascade
+			);
+
+			CREATE INDEX webhook_deliveries_webhook_id_idx ON webhook_deliveries (webhook_id);
+			CREATE INDEX webhook_deliveries_status_next_attempt_at_idx ON webhook_deliveries (status, next_attempt_at);
+		`
+		_, err = tx.Exec(sql)
+		return err
+	},
+	func(tx *sql.Tx) (err error) {
+		sql := `
+			ALTER TABLE api_keys ADD COLUMN scopes text[] not null default '{}';
+			ALTER TABLE api_keys ADD COLUMN category_ids bigint[] not null default '{}';
+		`
+		_, err = tx.Exec(sql)
+		return err
+	},
+	func(tx *sql.Tx) (err error) {
+		sql := `
+			ALTER TABLE users ADD COLUMN changed_at timestamp with time zone not null default now();
+		`
+		_, err = tx.Exec(sql)
+		return err
+	},
+	func(tx *sql.Tx) (err error) {
+		sql := `
+			ALTER TABLE integrations ADD COLUMN nextcloud_news_enabled bool default false;
+			ALTER TABLE integrations ADD COLUMN nextcloud_news_username text default '';
+			ALTER TABLE integrations ADD COLUMN nextcloud_news_password text default '';
+		`
+		_, err = tx.Exec(sql)
+		return err
+	},
+	func(tx *sql.Tx) (err error) {
+		sql := `
+			ALTER TABLE integrations ADD COLUMN ttrss_enabled bool default false;
+			ALTER TABLE integrations ADD COLUMN ttrss_username text default '';
+			ALTER TABLE integrations ADD COLUMN ttrss_password text default '';
+		`
+		_, err = tx.Exec(sql)
+		return err
+	},
+	func(tx *sql.Tx) (err error) {
+		sql := `ALTER TABLE integrations ADD COLUMN ttrss_session_nonce text default '';`
+		_, err = tx.Exec(sql)
+		return err
+	},
+	func(tx *sql.Tx) (err error) {
+		// The response bodies of the endpoints are no longer stored, they could contain internal data.
+		sql := `
+			UPDATE webhook_deliveries SET error_msg='webhook: unexpected status code ' || response_status WHERE error_msg <> '' AND response_status > 0;
+			UPDATE webhook_deliveries SET error_msg='webhook: unable to send request' WHERE error_msg <> '' AND response_status = 0;
+		`
+		_, err = tx.Exec(sql)
+		return err
+	},
+	func(tx *sql.Tx) (err error) {
+		sql := `
+			ALTER TABLE user_sessions ADD COLUMN last_used_at timestamp with time zone;
+			UPDATE user_sessions SET last_used_at=created_at;
+		`
+		_, err = tx.Exec(sql)
+		return err
+	},
+	func(tx *sql.Tx) (err error) {
+		// The configuration used to build the search vectors of an entry is stored, the search queries
+		// are parsed with the same configuration. The existing vectors are rebuilt with the entry language.
+		if _, err = tx.Exec(`ALTER TABLE entries ADD COLUMN search_config regconfig NOT NULL DEFAULT 'simple'`); err != nil {
+			return err
+		}
+
+		configs, err := languageTextSearchConfigs(tx, `SELECT DISTINCT language FROM entries`)
+		if err != nil {
+			return err
+		}
+
+		for language, config := range configs {
+			_, err = tx.Exec(`
+				UPDATE
+					entries
+				SET
+					search_config = $1::regconfig,
+					document_vectors = setweight(to_tsvector($1::regconfig, left(coalesce(title, ''), 500000)), 'A') || setweight(to_tsvector($1::regconfig, left(coalesce(content, ''), 500000)), 'B')
+				WHERE
+					language = $2 AND document_vectors IS NOT NULL
+			`, config, language)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	},
+	func(tx *sql.Tx) (err error) {
+		// The annotations are indexed with the language of the user.
+		if _, err = tx.Exec(`ALTER TABLE entry_annotations ADD COLUMN document_vectors tsvector`); err != nil {
+			return err
+		}
+
+		configs, err := languageTextSearchConfigs(tx, `SELECT DISTINCT language FROM users`)
+		if err != nil {
+			return err
+		}
+
+		for language, config := range configs {
+			_, err = tx.Exec(`
+				UPDATE
+					entry_annotations a
+				SET
+					document_vectors = to_tsvector($1::regconfig, a.quote || ' ' || a.note)
+				FROM
+					users u
+				WHERE
+					u.id = a.user_id AND u.language = $2
+			`, config, language)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.Exec(`CREATE INDEX entry_annotations_document_vectors_idx ON entry_annotations USING gin(document_vectors)`)
+		return err
+	},
 }
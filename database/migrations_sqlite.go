@@ -0,0 +1,394 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package database // import "miniflux.app/database"
+
+import (
+	"database/sql"
+)
+
+// sqliteMigrations are applied to SQLite databases, the first one creates the whole schema.
+// Order is important. Add new migrations at the end of the list.
+var sqliteMigrations = []func(tx *sql.Tx) error{
+	func(tx *sql.Tx) (err error) {
+		sql := `
+			CREATE TABLE schema_version (
+				version text not null
+			);
+
+			CREATE TABLE users (
+				id integer primary key autoincrement,
+				username text not null unique,
+				password text,
+				is_admin boolean default false,
+				language text default 'en_US',
+				timezone text default 'UTC',
+				theme text default 'light_serif',
+				last_login_at timestamp,
+				entry_direction text default 'asc' check (entry_direction in ('asc', 'desc')),
+				keyboard_shortcuts boolean default true,
+				entries_per_page int default 100,
+				show_reading_time boolean default true,
+				entry_swipe boolean default true,
+				stylesheet text not null default '',
+				google_id text not null default '',
+				openid_connect_id text not null default '',
+				display_mode text default 'standalone' check (display_mode in ('fullscreen', 'standalone', 'minimal-ui', 'browser')),
+				entry_order text default 'published_at' check (entry_order in ('published_at', 'created_at')),
+				default_reading_speed int default 265,
+				cjk_reading_speed int default 500,
+				default_home_page text default 'unread',
+				categories_sorting_order text not null default 'unread_count',
+				gesture_nav text default 'tap'
+			);
+
+			CREATE UNIQUE INDEX users_google_id_idx ON users(google_id) WHERE google_id <> '';
+			CREATE UNIQUE INDEX users_openid_connect_id_idx ON users(openid_connect_id) WHERE openid_connect_id <> '';
+
+			CREATE TABLE user_sessions (
+				id integer primary key autoincrement,
+				user_id int not null,
+				token text not null unique,
+				created_at timestamp default (now()),
+				user_agent text,
+				ip text,
+				unique (user_id, token),
+				foreign key (user_id) references users(id) on delete cascade
+			);
+
+			CREATE TABLE sessions (
+				id text not null,
+				data text not null,
+				created_at timestamp not null default (now()),
+				primary key(id)
+			);
+
+			CREATE TABLE categories (
+				id integer primary key autoincrement,
+				user_id int not null,
+				title text not null,
+				hide_globally boolean not null default false,
+				unique (user_id, title),
+				foreign key (user_id) references users(id) on delete cascade
+			);
+
+			CREATE TABLE feeds (
+				id integer primary key autoincrement,
+				user_id int not null,
+				category_id int not null,
+				title text not null,
+				feed_url text not null,
+				site_url text not null,
+				checked_at timestamp default (now()),
+				next_check_at timestamp default (now()),
+				etag_header text default '',
+				last_modified_header text default '',
+				parsing_error_msg text default '',
+				parsing_error_count int default 0,
+				scraper_rules text default '',
+				rewrite_rules text default '',
+				crawler boolean default false,
+				username text default '',
+				password text default '',
+				user_agent text default '',
+				cookie text default '',
+				disabled boolean default false,
+				ignore_http_cache boolean default false,
+				fetch_via_proxy boolean default false,
+				blocklist_rules text not null default '',
+				keeplist_rules text not null default '',
+				url_rewrite_rules text not null default '',
+				allow_self_signed_certificates boolean not null default false,
+				hide_globally boolean not null default false,
+				mark_updated_entries_unread boolean not null default false,
+				unique (user_id, feed_url),
+				foreign key (user_id) references users(id) on delete cascade,
+				foreign key (category_id) references categories(id) on delete cascade
+			);
+
+			CREATE INDEX feeds_user_category_idx ON feeds(user_id, category_id);
+
+			CREATE TABLE entries (
+				id integer primary key autoincrement,
+				user_id int not null,
+				feed_id bigint not null,
+				hash text not null,
+				published_at timestamp not null,
+				created_at timestamp not null default (now()),
+				changed_at timestamp not null,
+				title text not null,
+				url text not null,
+				comments_url text default '',
+				author text,
+				content text,
+				status text default 'unread' check (status in ('unread', 'read', 'removed')),
+				starred boolean default false,
+				share_code text not null default '',
+				reading_time int not null default 0,
+				tags text default '[]',
+				language text not null default '',
+				revision_count int not null default 0,
+				unique (feed_id, hash),
+				foreign key (user_id) references users(id) on delete cascade,
+				foreign key (feed_id) references feeds(id) on delete cascade
+			);
+
+			CREATE INDEX entries_feed_idx ON entries(feed_id);
+			CREATE INDEX entries_user_status_idx ON entries(user_id, status);
+			CREATE UNIQUE INDEX entries_share_code_idx ON entries(share_code) WHERE share_code <> '';
+			CREATE INDEX entries_user_feed_idx ON entries(user_id, feed_id);
+			CREATE INDEX entries_id_user_status_idx ON entries(id, user_id, status);
+			CREATE INDEX entries_feed_id_status_hash_idx ON entries(feed_id, status, hash);
+			CREATE INDEX entries_user_id_status_starred_idx ON entries(user_id, status, starred);
+			CREATE INDEX entries_feed_url_idx ON entries(feed_id, url);
+			CREATE INDEX entries_user_status_feed_idx ON entries(user_id, status, feed_id);
+			CREATE INDEX entries_user_status_changed_idx ON entries(user_id, status, changed_at);
+
+			CREATE VIRTUAL TABLE entries_fts USING fts5(title, content, tokenize='unicode61 remove_diacritics 2');
+
+			CREATE TRIGGER entries_fts_insert AFTER INSERT ON entries BEGIN
+				INSERT INTO entries_fts (rowid, title, content) VALUES (new.id, new.title, strip_tags(new.content));
+			END;
+
+			CREATE TRIGGER entries_fts_delete AFTER DELETE ON entries BEGIN
+				DELETE FROM entries_fts WHERE rowid = old.id;
+			END;
+
+			CREATE TRIGGER entries_fts_update AFTER UPDATE OF title, content ON entries BEGIN
+				DELETE FROM entries_fts WHERE rowid = old.id;
+				INSERT INTO entries_fts (rowid, title, content) VALUES (new.id, new.title, strip_tags(new.content));
+			END;
+
+			CREATE TABLE enclosures (
+				id integer primary key autoincrement,
+				user_id int not null,
+				entry_id bigint not null,
+				url text not null,
+				size bigint default 0,
+				mime_type text default '',
+				foreign key (user_id) references users(id) on delete cascade,
+				foreign key (entry_id) references entries(id) on delete cascade
+			);
+
+			CREATE INDEX enclosures_user_entry_url_idx ON enclosures(user_id, entry_id, url);
+
+			CREATE TABLE icons (
+				id integer primary key autoincrement,
+				hash text not null unique,
+				mime_type text not null,
+				content blob not null
+			);
+
+			CREATE TABLE feed_icons (
+				feed_id bigint not null,
+				icon_id bigint not null,
+				primary key(feed_id, icon_id),
+				foreign key (feed_id) references feeds(id) on delete cascade,
+				foreign key (icon_id) references icons(id) on delete cascade
+			);
+
+			CREATE TABLE integrations (
+				user_id int not null,
+				pinboard_enabled boolean default false,
+				pinboard_token text default '',
+				pinboard_tags text default 'miniflux',
+				pinboard_mark_as_unread boolean default false,
+				instapaper_enabled boolean default false,
+				instapaper_username text default '',
+				instapaper_password text default '',
+				fever_enabled boolean default false,
+				fever_username text default '',
+				fever_token text default '',
+				wallabag_enabled boolean default false,
+				wallabag_only_url boolean default false,
+				wallabag_url text default '',
+				wallabag_client_id text default '',
+				wallabag_client_secret text default '',
+				wallabag_username text default '',
+				wallabag_password text default '',
+				nunux_keeper_enabled boolean default false,
+				nunux_keeper_url text default '',
+				nunux_keeper_api_key text default '',
+				pocket_enabled boolean default false,
+				pocket_access_token text default '',
+				pocket_consumer_key text default '',
+				telegram_bot_enabled boolean default false,
+				telegram_bot_token text default '',
+				telegram_bot_chat_id text default '',
+				googlereader_enabled boolean default false,
+				googlereader_username text default '',
+				googlereader_password text default '',
+				espial_enabled boolean default false,
+				espial_url text default '',
+				espial_api_key text default '',
+				espial_tags text default 'miniflux',
+				linkding_enabled boolean default false,
+				linkding_url text default '',
+				linkding_api_key text default '',
+				matrix_bot_enabled boolean default false,
+				matrix_bot_user text default '',
+				matrix_bot_password text default '',
+				matrix_bot_url text default '',
+				matrix_bot_chat_id text default '',
+				primary key(user_id)
+			);
+
+			CREATE TABLE api_keys (
+				id integer primary key autoincrement,
+				user_id int not null references users(id) on delete cascade,
+				token text not null unique,
+				description text not null,
+				last_used_at timestamp,
+				created_at timestamp default (now()),
+				unique (user_id, description)
+			);
+
+			CREATE TABLE acme_cache (
+				key varchar(400) not null primary key,
+				data blob not null,
+				updated_at timestamp not null
+			);
+
+			CREATE TABLE labels (
+				id integer primary key autoincrement,
+				user_id int not null,
+				title text not null,
+				foreign key (user_id) references users(id) on delete cascade
+			);
+
+			CREATE UNIQUE INDEX labels_user_id_lower_title_idx ON labels (user_id, lower(title));
+
+			CREATE TABLE entry_labels (
+				entry_id bigint not null,
+				label_id bigint not null,
+				primary key (entry_id, label_id),
+				foreign key (entry_id) references entries(id) on delete cascade,
+				foreign key (label_id) references labels(id) on delete cascade
+			);
+
+			CREATE INDEX entry_labels_label_id_idx ON entry_labels (label_id);
+
+			CREATE TABLE entry_annotations (
+				id integer primary key autoincrement,
+				user_id int not null,
+				entry_id bigint not null,
+				quote text not null default '',
+				start_offset int not null default 0,
+				end_offset int not null default 0,
+				note text not null default '',
+				created_at timestamp not null default (now()),
+				changed_at timestamp not null default (now()),
+				foreign key (user_id) references users(id) on delete cascade,
+				foreign key (entry_id) references entries(id) on delete cascade
+			);
+
+			CREATE INDEX entry_annotations_entry_id_idx ON entry_annotations (entry_id);
+			CREATE INDEX entry_annotations_user_id_idx ON entry_annotations (user_id);
+
+			CREATE TABLE entry_revisions (
+				id integer primary key autoincrement,
+				entry_id bigint not null,
+				title text not null default '',
+				content text not null default '',
+				created_at timestamp not null default (now()),
+				foreign key (entry_id) references entries(id) on delete cascade
+			);
+
+			CREATE INDEX entry_revisions_entry_id_idx ON entry_revisions (entry_id);
+		`
+		_, err = tx.Exec(sql)
+		return err
+	},
+	func(tx *sql.Tx) (err error) {
+		sql := `
+			CREATE TABLE webhooks (
+				id integer primary key autoincrement,
+				user_id int not null,
+				url text not null,
+				secret text not null,
+				events text not null default '[]',
+				created_at timestamp not null default (now()),
+				foreign key (user_id) references users(id) on delete cascade
+			);
+
+			CREATE INDEX webhooks_user_id_idx ON webhooks (user_id);
+
+			CREATE TABLE webhook_deliveries (
+				id integer primary key autoincrement,
+				webhook_id bigint not null,
+				event text not null,
+				payload text not null,
+				status text not null default 'pending',
+				attempts int not null default 0,
+				response_status int not null default 0,
+				error_msg text not null default '',
+				next_attempt_at timestamp not null default (now()),
+				last_attempt_at timestamp,
+				created_at timestamp not null default (now()),
+				foreign key (webhook_id) references webhooks(id) on delete cascade
+			);
+
+			CREATE INDEX webhook_deliveries_webhook_id_idx ON webhook_deliveries (webhook_id);
+			CREATE INDEX webhook_deliveries_status_next_attempt_at_idx ON webhook_deliveries (status, next_attempt_at);
+		`
+		_, err = tx.Exec(sql)
+		return err
+	},
+	func(tx *sql.Tx) (err error) {
+		sql := `
+			ALTER TABLE api_keys ADD COLUMN scopes text not null default '[]';
+			ALTER TABLE api_keys ADD COLUMN category_ids text not null default '[]';
+		`
+		_, err = tx.Exec(sql)
+		return err
+	},
+	func(tx *sql.Tx) (err error) {
+		sql := `
+			ALTER TABLE users ADD COLUMN changed_at timestamp not null default '1970-01-01 00:00:00';
+			UPDATE users SET changed_at=now();
+		`
+		_, err = tx.Exec(sql)
+		return err
+	},
+	func(tx *sql.Tx) (err error) {
+		sql := `
+			ALTER TABLE integrations ADD COLUMN nextcloud_news_enabled boolean default false;
+			ALTER TABLE integrations ADD COLUMN nextcloud_news_username text default '';
+			ALTER TABLE integrations ADD COLUMN nextcloud_news_password text default '';
+		`
+		_, err = tx.Exec(sql)
+		return err
+	},
+	func(tx *sql.Tx) (err error) {
+		sql := `
+			ALTER TABLE integrations ADD COLUMN ttrss_enabled boolean default false;
+			ALTER TABLE integrations ADD COLUMN ttrss_username text default '';
+			ALTER TABLE integrations ADD COLUMN ttrss_password text default '';
+		`
+		_, err = tx.Exec(sql)
+		return err
+	},
+	func(tx *sql.Tx) (err error) {
+		sql := `ALTER TABLE integrations ADD COLUMN ttrss_session_nonce text default '';`
+		_, err = tx.Exec(sql)
+		return err
+	},
+	func(tx *sql.Tx) (err error) {
+		// The response bodies of the endpoints are no longer stored, they could contain internal data.
+		sql := `
+			UPDATE webhook_deliveries SET error_msg='webhook: unexpected status code ' || response_status WHERE error_msg <> '' AND response_status > 0;
+			UPDATE webhook_deliveries SET error_msg='webhook: unable to send request' WHERE error_msg <> '' AND response_status = 0;
+		`
+		_, err = tx.Exec(sql)
+		return err
+	},
+	func(tx *sql.Tx) (err error) {
+		sql := `
+			ALTER TABLE user_sessions ADD COLUMN last_used_at timestamp;
+			UPDATE user_sessions SET last_used_at=created_at;
+		`
+		_, err = tx.Exec(sql)
+		return err
+	},
+}
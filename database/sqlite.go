@@ -0,0 +1,118 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package database // import "miniflux.app/database"
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+	"modernc.org/sqlite"
+)
+
+// sqliteTimeFormat is the format used to store timestamps in SQLite.
+// Timestamps are always stored in UTC so they can be compared as text.
+const sqliteTimeFormat = "2006-01-02 15:04:05.999999"
+
+// sqliteDriverName is the name of the wrapped driver, "sqlite" is already registered by the SQLite package.
+const sqliteDriverName = "miniflux_sqlite"
+
+func init() {
+	// Functions are only registered on the driver instance used by the "sqlite" database/sql driver.
+	db, _ := sql.Open("sqlite", "")
+	sql.Register(sqliteDriverName, &sqliteDriver{db.Driver()})
+
+	sqlite.MustRegisterScalarFunction("now", 0, func(ctx *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		return time.Now().UTC().Format(sqliteTimeFormat), nil
+	})
+
+	sqlite.MustRegisterDeterministicScalarFunction("strip_tags", 1, func(ctx *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		switch value := args[0].(type) {
+		case string:
+			return stripTags(value), nil
+		case []byte:
+			return stripTags(string(value)), nil
+		default:
+			return "", nil
+		}
+	})
+}
+
+// sqliteDSN converts a "sqlite://" database URL to a data source name understood by the SQLite driver.
+func sqliteDSN(dsn string) string {
+	dsn = strings.TrimPrefix(dsn, sqliteScheme)
+
+	separator := "?"
+	if strings.Contains(dsn, "?") {
+		separator = "&"
+	}
+
+	return dsn + separator + "_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_pragma=busy_timeout(10000)&_txlock=immediate"
+}
+
+// stripTags returns the text content of an HTML document, it is used to feed the full-text search index.
+func stripTags(input string) string {
+	var buffer strings.Builder
+	tokenizer := html.NewTokenizer(strings.NewReader(input))
+
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return strings.Join(strings.Fields(buffer.String()), " ")
+		case html.TextToken:
+			buffer.Write(tokenizer.Text())
+			buffer.WriteByte(' ')
+		default:
+			buffer.WriteByte(' ')
+		}
+	}
+}
+
+// sqliteDriver wraps the SQLite driver to store timestamps in UTC with a format that can be compared as text.
+type sqliteDriver struct {
+	driver driver.Driver
+}
+
+func (d *sqliteDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sqliteConn{conn.(sqliteDriverConn)}, nil
+}
+
+type sqliteDriverConn interface {
+	driver.Conn
+	driver.ConnBeginTx
+	driver.ConnPrepareContext
+	driver.ExecerContext
+	driver.QueryerContext
+	driver.Pinger
+}
+
+type sqliteConn struct {
+	sqliteDriverConn
+}
+
+// CheckNamedValue implements driver.NamedValueChecker.
+func (c *sqliteConn) CheckNamedValue(value *driver.NamedValue) error {
+	switch v := value.Value.(type) {
+	case time.Time:
+		value.Value = v.UTC().Format(sqliteTimeFormat)
+		return nil
+	case *time.Time:
+		if v == nil {
+			value.Value = nil
+		} else {
+			value.Value = v.UTC().Format(sqliteTimeFormat)
+		}
+		return nil
+	}
+
+	return driver.ErrSkip
+}
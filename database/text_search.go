@@ -0,0 +1,127 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package database // import "miniflux.app/database"
+
+import (
+	"database/sql"
+	"sort"
+	"strings"
+)
+
+// DefaultTextSearchConfig is used when the language is unknown or has no dedicated PostgreSQL configuration.
+const DefaultTextSearchConfig = "simple"
+
+// textSearchConfigs maps ISO 639-1 language codes to built-in PostgreSQL text search configurations.
+var textSearchConfigs = map[string]string{
+	"ar": "arabic",
+	"ca": "catalan",
+	"da": "danish",
+	"de": "german",
+	"el": "greek",
+	"en": "english",
+	"es": "spanish",
+	"eu": "basque",
+	"fi": "finnish",
+	"fr": "french",
+	"ga": "irish",
+	"hi": "hindi",
+	"hu": "hungarian",
+	"hy": "armenian",
+	"id": "indonesian",
+	"it": "italian",
+	"lt": "lithuanian",
+	"ne": "nepali",
+	"nl": "dutch",
+	"no": "norwegian",
+	"pt": "portuguese",
+	"ro": "romanian",
+	"ru": "russian",
+	"sr": "serbian",
+	"sv": "swedish",
+	"ta": "tamil",
+	"tr": "turkish",
+	"yi": "yiddish",
+}
+
+// TextSearchConfig returns the PostgreSQL text search configuration for an entry language ("de")
+// or a user locale ("de_DE"). Some of them are missing from older PostgreSQL versions, see InstalledTextSearchConfigs.
+func TextSearchConfig(language string) string {
+	code := strings.ToLower(language)
+	if index := strings.IndexAny(code, "_-"); index != -1 {
+		code = code[:index]
+	}
+
+	if config, found := textSearchConfigs[code]; found {
+		return config
+	}
+
+	return DefaultTextSearchConfig
+}
+
+// TextSearchConfigs returns the sorted list of the text search configurations used for the known languages.
+func TextSearchConfigs() []string {
+	configs := []string{DefaultTextSearchConfig}
+	for _, config := range textSearchConfigs {
+		configs = append(configs, config)
+	}
+
+	sort.Strings(configs)
+	return configs
+}
+
+// Queryer is implemented by *sql.DB and *sql.Tx.
+type Queryer interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// InstalledTextSearchConfigs returns the text search configurations available in the PostgreSQL database.
+func InstalledTextSearchConfigs(q Queryer) (map[string]bool, error) {
+	rows, err := q.Query(`SELECT cfgname FROM pg_ts_config`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	installed := map[string]bool{DefaultTextSearchConfig: true}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		installed[name] = true
+	}
+
+	return installed, rows.Err()
+}
+
+// languageTextSearchConfigs returns the installed text search configuration of each language returned by the query.
+func languageTextSearchConfigs(tx *sql.Tx, query string) (map[string]string, error) {
+	installed, err := InstalledTextSearchConfigs(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	configs := make(map[string]string)
+	for rows.Next() {
+		var language string
+		if err := rows.Scan(&language); err != nil {
+			return nil, err
+		}
+
+		config := TextSearchConfig(language)
+		if !installed[config] {
+			config = DefaultTextSearchConfig
+		}
+		configs[language] = config
+	}
+
+	return configs, rows.Err()
+}
@@ -0,0 +1,137 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package diff // import "miniflux.app/diff"
+
+// Row operations.
+const (
+	OperationEqual   = "equal"
+	OperationInsert  = "insert"
+	OperationDelete  = "delete"
+	OperationReplace = "replace"
+)
+
+// Row represents one line of a side-by-side comparison.
+type Row struct {
+	Operation string `json:"operation"`
+	Left      string `json:"left"`
+	Right     string `json:"right"`
+}
+
+// Rows represents a side-by-side comparison.
+type Rows []*Row
+
+// Changed returns true if at least one row is not equal on both sides.
+func (r Rows) Changed() bool {
+	for _, row := range r {
+		if row.Operation != OperationEqual {
+			return true
+		}
+	}
+	return false
+}
+
+// maxTableSize limits the memory used by the comparison of very long documents.
+const maxTableSize = 4000000
+
+// Lines compares two lists of lines using the longest common subsequence.
+//
+// Consecutive deleted and inserted lines are paired into replace rows,
+// so the result can be displayed side by side.
+func Lines(left, right []string) Rows {
+	var rows Rows
+
+	prefix := 0
+	for prefix < len(left) && prefix < len(right) && left[prefix] == right[prefix] {
+		rows = append(rows, &Row{Operation: OperationEqual, Left: left[prefix], Right: right[prefix]})
+		prefix++
+	}
+	left, right = left[prefix:], right[prefix:]
+
+	suffix := 0
+	for suffix < len(left) && suffix < len(right) && left[len(left)-1-suffix] == right[len(right)-1-suffix] {
+		suffix++
+	}
+
+	rows = append(rows, compare(left[:len(left)-suffix], right[:len(right)-suffix])...)
+
+	for k := len(left) - suffix; k < len(left); k++ {
+		rows = append(rows, &Row{Operation: OperationEqual, Left: left[k], Right: right[len(right)-len(left)+k]})
+	}
+
+	return rows
+}
+
+func compare(left, right []string) Rows {
+	n, m := len(left), len(right)
+
+	var rows Rows
+	var deleted, inserted []string
+
+	flush := func() {
+		for k := 0; k < len(deleted) || k < len(inserted); k++ {
+			switch {
+			case k < len(deleted) && k < len(inserted):
+				rows = append(rows, &Row{Operation: OperationReplace, Left: deleted[k], Right: inserted[k]})
+			case k < len(deleted):
+				rows = append(rows, &Row{Operation: OperationDelete, Left: deleted[k]})
+			default:
+				rows = append(rows, &Row{Operation: OperationInsert, Right: inserted[k]})
+			}
+		}
+		deleted, inserted = nil, nil
+	}
+
+	if (n+1)*(m+1) > maxTableSize {
+		deleted, inserted = left, right
+		flush()
+		return rows
+	}
+
+	// lengths[i][j] is the length of the longest common subsequence of left[i:] and right[j:].
+	lengths := make([][]int, n+1)
+	for i := range lengths {
+		lengths[i] = make([]int, m+1)
+	}
+
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if left[i] == right[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else if lengths[i+1][j] >= lengths[i][j+1] {
+				lengths[i][j] = lengths[i+1][j]
+			} else {
+				lengths[i][j] = lengths[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case left[i] == right[j]:
+			flush()
+			rows = append(rows, &Row{Operation: OperationEqual, Left: left[i], Right: right[j]})
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			deleted = append(deleted, left[i])
+			i++
+		default:
+			inserted = append(inserted, right[j])
+			j++
+		}
+	}
+
+	deleted = append(deleted, left[i:]...)
+	inserted = append(inserted, right[j:]...)
+	flush()
+
+	return rows
+}
+
+// HTML compares two HTML documents line by line, using their text content.
+func HTML(left, right string) Rows {
+	return Lines(TextLines(left), TextLines(right))
+}
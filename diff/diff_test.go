@@ -0,0 +1,104 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package diff // import "miniflux.app/diff"
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLinesWithIdenticalInput(t *testing.T) {
+	rows := Lines([]string{"a", "b"}, []string{"a", "b"})
+
+	expected := Rows{
+		{Operation: OperationEqual, Left: "a", Right: "a"},
+		{Operation: OperationEqual, Left: "b", Right: "b"},
+	}
+
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf(`Unexpected rows: %+v`, rows)
+	}
+
+	if rows.Changed() {
+		t.Error(`Identical input should not be reported as changed`)
+	}
+}
+
+func TestLinesWithInsertion(t *testing.T) {
+	rows := Lines([]string{"a", "c"}, []string{"a", "b", "c"})
+
+	expected := Rows{
+		{Operation: OperationEqual, Left: "a", Right: "a"},
+		{Operation: OperationInsert, Right: "b"},
+		{Operation: OperationEqual, Left: "c", Right: "c"},
+	}
+
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf(`Unexpected rows: %+v`, rows)
+	}
+
+	if !rows.Changed() {
+		t.Error(`Insertion should be reported as changed`)
+	}
+}
+
+func TestLinesWithDeletion(t *testing.T) {
+	rows := Lines([]string{"a", "b", "c"}, []string{"a", "c"})
+
+	expected := Rows{
+		{Operation: OperationEqual, Left: "a", Right: "a"},
+		{Operation: OperationDelete, Left: "b"},
+		{Operation: OperationEqual, Left: "c", Right: "c"},
+	}
+
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf(`Unexpected rows: %+v`, rows)
+	}
+}
+
+func TestLinesWithReplacement(t *testing.T) {
+	rows := Lines([]string{"a", "b", "c", "d"}, []string{"a", "B", "d", "e"})
+
+	expected := Rows{
+		{Operation: OperationEqual, Left: "a", Right: "a"},
+		{Operation: OperationReplace, Left: "b", Right: "B"},
+		{Operation: OperationDelete, Left: "c"},
+		{Operation: OperationEqual, Left: "d", Right: "d"},
+		{Operation: OperationInsert, Right: "e"},
+	}
+
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf(`Unexpected rows: %+v`, rows)
+	}
+}
+
+func TestLinesWithEmptyInput(t *testing.T) {
+	rows := Lines(nil, []string{"a"})
+
+	expected := Rows{
+		{Operation: OperationInsert, Right: "a"},
+	}
+
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf(`Unexpected rows: %+v`, rows)
+	}
+
+	if rows := Lines(nil, nil); len(rows) != 0 {
+		t.Errorf(`Unexpected rows: %+v`, rows)
+	}
+}
+
+func TestHTML(t *testing.T) {
+	rows := HTML(`<p>Terms</p><p>Old clause</p>`, `<p>Terms</p><p>New <b>clause</b></p>`)
+
+	expected := Rows{
+		{Operation: OperationEqual, Left: "Terms", Right: "Terms"},
+		{Operation: OperationReplace, Left: "Old clause", Right: "New clause"},
+	}
+
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf(`Unexpected rows: %+v`, rows)
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package diff // import "miniflux.app/diff"
+
+import (
+	"io"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+var blockElements = map[string]bool{
+	"address":    true,
+	"article":    true,
+	"aside":      true,
+	"blockquote": true,
+	"br":         true,
+	"dd":         true,
+	"div":        true,
+	"dl":         true,
+	"dt":         true,
+	"figcaption": true,
+	"figure":     true,
+	"footer":     true,
+	"h1":         true,
+	"h2":         true,
+	"h3":         true,
+	"h4":         true,
+	"h5":         true,
+	"h6":         true,
+	"header":     true,
+	"hr":         true,
+	"li":         true,
+	"ol":         true,
+	"p":          true,
+	"pre":        true,
+	"section":    true,
+	"table":      true,
+	"td":         true,
+	"th":         true,
+	"tr":         true,
+	"ul":         true,
+}
+
+// TextLines converts an HTML document to a list of non-empty text lines.
+//
+// Each block element starts a new line and whitespaces are collapsed.
+func TextLines(input string) []string {
+	var lines []string
+	var buffer strings.Builder
+
+	flush := func() {
+		line := strings.Join(strings.Fields(buffer.String()), " ")
+		if line != "" {
+			lines = append(lines, line)
+		}
+		buffer.Reset()
+	}
+
+	tokenizer := html.NewTokenizer(strings.NewReader(input))
+	for {
+		if tokenizer.Next() == html.ErrorToken {
+			if tokenizer.Err() != io.EOF {
+				return nil
+			}
+			flush()
+			return lines
+		}
+
+		token := tokenizer.Token()
+		switch token.Type {
+		case html.TextToken:
+			buffer.WriteString(token.Data)
+		case html.StartTagToken, html.EndTagToken, html.SelfClosingTagToken:
+			if blockElements[token.Data] {
+				flush()
+			}
+		}
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package diff // import "miniflux.app/diff"
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTextLines(t *testing.T) {
+	input := `<h1>Title</h1><p>Some <a href="#">inline</a>   text</p><ul><li>One</li><li>Two</li></ul>Line<br>Break`
+	expected := []string{"Title", "Some inline text", "One", "Two", "Line", "Break"}
+	output := TextLines(input)
+
+	if !reflect.DeepEqual(output, expected) {
+		t.Errorf(`Unexpected lines: %q`, output)
+	}
+}
+
+func TestTextLinesWithPlainText(t *testing.T) {
+	expected := []string{"Plain text"}
+	output := TextLines("  Plain\n text ")
+
+	if !reflect.DeepEqual(output, expected) {
+		t.Errorf(`Unexpected lines: %q`, output)
+	}
+}
+
+func TestTextLinesWithEmptyInput(t *testing.T) {
+	if output := TextLines(""); len(output) != 0 {
+		t.Errorf(`Unexpected lines: %q`, output)
+	}
+}
@@ -43,6 +43,8 @@ func (h *handler) serve(w http.ResponseWriter, r *http.Request) {
 		h.handleFeeds(w, r)
 	case request.HasQueryParam(r, "favicons"):
 		h.handleFavicons(w, r)
+	case request.HasQueryParam(r, "links"):
+		h.handleLinks(w, r)
 	case request.HasQueryParam(r, "unread_item_ids"):
 		h.handleUnreadItems(w, r)
 	case request.HasQueryParam(r, "saved_item_ids"):
@@ -144,6 +146,7 @@ func (h *handler) handleFeeds(w http.ResponseWriter, r *http.Request) {
 	for _, f := range feeds {
 		subscripion := feed{
 			ID:          f.ID,
+			FaviconID:   faviconID(f),
 			Title:       f.Title,
 			URL:         f.FeedURL,
 			SiteURL:     f.SiteURL,
@@ -151,10 +154,6 @@ func (h *handler) handleFeeds(w http.ResponseWriter, r *http.Request) {
 			LastUpdated: f.CheckedAt.Unix(),
 		}
 
-		if f.Icon != nil {
-			subscripion.FaviconID = f.Icon.IconID
-		}
-
 		result.Feeds = append(result.Feeds, subscripion)
 	}
 
@@ -181,6 +180,9 @@ The data member of a favicon object can be used with the data: protocol to embed
 A PHP/HTML example:
 
 	echo '<img src="data:'.$favicon['data'].'">';
+
+Every feed has a favicon, a fallback favicon is generated when the feed does not have an icon.
+The stored icons have even IDs and the fallback favicons have odd IDs.
 */
 func (h *handler) handleFavicons(w http.ResponseWriter, r *http.Request) {
 	userID := request.UserID(r)
@@ -192,14 +194,168 @@ func (h *handler) handleFavicons(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	feeds, err := h.store.Feeds(userID)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
 	var result faviconsResponse
 	for _, i := range icons {
 		result.Favicons = append(result.Favicons, favicon{
-			ID:   i.ID,
+			ID:   storedFaviconID(i.ID),
 			Data: i.DataURL(),
 		})
 	}
 
+	for _, f := range feeds {
+		if f.Icon == nil || f.Icon.IconID == 0 {
+			icon, err := newFallbackFavicon(f)
+			if err != nil {
+				json.ServerError(w, r, err)
+				return
+			}
+
+			result.Favicons = append(result.Favicons, favicon{
+				ID:   fallbackFaviconID(f.ID),
+				Data: icon.DataURL(),
+			})
+		}
+	}
+
+	result.SetCommonValues()
+	json.OK(w, r, result)
+}
+
+/*
+A request with the links argument will return one additional member:
+
+	links contains an array of link objects
+
+A link object has the following members:
+
+	id (positive integer)
+	feed_id (positive integer) only use when is_item equals 1
+	item_id (positive integer) only use when is_item equals 1
+	temperature (positive float)
+	is_item (boolean integer)
+	is_local (boolean integer) used to determine if the source feed and favicon should be displayed
+	is_saved (boolean integer) only use when is_item equals 1
+	title (utf-8 string)
+	url (utf-8 string)
+	item_ids (string/comma-separated list of positive integers)
+
+When requesting hot links you can control the range and offset by specifying a length of days for each
+as well as a page to fetch additional hot links. A request with just the links argument is equivalent to:
+
+	?api&links&offset=0&range=7&page=1
+
+Or the seven day period starting now. The following:
+
+	?api&links&offset=30&range=30&page=2
+
+Or the second page of hot links from the thirty day period ending thirty days ago.
+
+Hot links are recalculated on every fetch, the IDs are the positions of the links in the ranking.
+The range is limited to 30 days, the offset to 365 days and only the 1000 most recent entries of the period are used.
+*/
+func (h *handler) handleLinks(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	offset := request.QueryIntParam(r, "offset", 0)
+	days := request.QueryIntParam(r, "range", 7)
+	page := request.QueryIntParam(r, "page", 1)
+	logger.Debug("[Fever] Fetching hot links of %d days with an offset of %d days for user #%d", days, offset, userID)
+
+	switch {
+	case offset < 0:
+		offset = 0
+	case offset > maxLinksOffsetDays:
+		offset = maxLinksOffsetDays
+	}
+
+	switch {
+	case days <= 0:
+		days = 7
+	case days > maxLinksRangeDays:
+		days = maxLinksRangeDays
+	}
+
+	if page <= 0 {
+		page = 1
+	}
+
+	to := time.Now().AddDate(0, 0, -offset)
+	from := to.AddDate(0, 0, -days)
+
+	entries, err := h.store.EntriesContent(userID, from, to, maxLinksEntries)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	links := findHotLinks(entries, from, to)
+	start := (page - 1) * linksPerPage
+	if start > len(links) {
+		start = len(links)
+	}
+	end := start + linksPerPage
+	if end > len(links) {
+		end = len(links)
+	}
+	links = links[start:end]
+
+	// The links to the entries of the user are displayed with their feed.
+	items := make(map[string]*model.Entry)
+	if len(links) > 0 {
+		urls := make([]string, 0, len(links))
+		for _, link := range links {
+			urls = append(urls, link.URL)
+		}
+
+		builder := h.store.NewEntryQueryBuilder(userID)
+		builder.WithoutStatus(model.EntryStatusRemoved)
+		builder.WithURLs(urls)
+		linkedEntries, err := builder.GetEntries()
+		if err != nil {
+			json.ServerError(w, r, err)
+			return
+		}
+
+		for _, entry := range linkedEntries {
+			items[entry.URL] = entry
+		}
+	}
+
+	var result linksResponse
+	result.Links = make([]link, 0, len(links))
+	for i, hotLink := range links {
+		var itemIDs []string
+		for _, entryID := range hotLink.EntryIDs {
+			itemIDs = append(itemIDs, strconv.FormatInt(entryID, 10))
+		}
+
+		l := link{
+			ID:          int64(start + i + 1),
+			Temperature: hotLink.Temperature,
+			Title:       hotLink.Title,
+			URL:         hotLink.URL,
+			ItemIDs:     strings.Join(itemIDs, ","),
+		}
+
+		if entry, found := items[hotLink.URL]; found {
+			l.FeedID = entry.FeedID
+			l.ItemID = entry.ID
+			l.IsItem = 1
+			l.IsLocal = 1
+			l.Title = entry.Title
+			if entry.Starred {
+				l.IsSaved = 1
+			}
+		}
+
+		result.Links = append(result.Links, l)
+	}
+
 	result.SetCommonValues()
 	json.OK(w, r, result)
 }
@@ -401,6 +557,7 @@ func (h *handler) handleWriteItems(w http.ResponseWriter, r *http.Request) {
 	builder := h.store.NewEntryQueryBuilder(userID)
 	builder.WithEntryID(entryID)
 	builder.WithoutStatus(model.EntryStatusRemoved)
+	builder.WithAnnotations()
 
 	entry, err := builder.GetEntry()
 	if err != nil {
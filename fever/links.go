@@ -0,0 +1,121 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package fever // import "miniflux.app/fever"
+
+import (
+	"math"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"miniflux.app/model"
+	urllib "miniflux.app/url"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const (
+	// A link becomes hot when several entries of the period reference it.
+	minHotLinkSources = 2
+	linksPerPage      = 50
+
+	// The period and the number of entries used to rank the links are limited, the content of each entry is parsed.
+	maxLinksRangeDays  = 30
+	maxLinksOffsetDays = 365
+	maxLinksEntries    = 1000
+)
+
+type hotLink struct {
+	URL         string
+	Title       string
+	Temperature float64
+	EntryIDs    []int64
+}
+
+// findHotLinks ranks the URLs linked from several entries published between the two dates.
+// Each entry adds between 0.5 and 1 degree to the temperature of a link, the most recent entries weigh more.
+func findHotLinks(entries model.Entries, from, to time.Time) []*hotLink {
+	links := make(map[string]*hotLink)
+	period := to.Sub(from).Seconds()
+
+	for _, entry := range entries {
+		weight := 1.0
+		if period > 0 {
+			weight = 0.5 + 0.5*math.Min(math.Max(entry.Date.Sub(from).Seconds()/period, 0), 1)
+		}
+
+		for linkURL, title := range entryLinks(entry) {
+			link, found := links[linkURL]
+			if !found {
+				link = &hotLink{URL: linkURL}
+				links[linkURL] = link
+			}
+
+			if link.Title == "" {
+				link.Title = title
+			}
+			link.Temperature += weight
+			link.EntryIDs = append(link.EntryIDs, entry.ID)
+		}
+	}
+
+	var result []*hotLink
+	for _, link := range links {
+		if len(link.EntryIDs) < minHotLinkSources {
+			continue
+		}
+
+		link.Temperature = math.Round(link.Temperature*10) / 10
+		if link.Title == "" {
+			link.Title = link.URL
+		}
+		result = append(result, link)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Temperature != result[j].Temperature {
+			return result[i].Temperature > result[j].Temperature
+		}
+		return result[i].URL < result[j].URL
+	})
+	return result
+}
+
+// entryLinks returns the absolute URLs linked from the content of the entry with the text of the links.
+// The fragments are removed and the links to the entry itself are ignored.
+func entryLinks(entry *model.Entry) map[string]string {
+	links := make(map[string]string)
+
+	document, err := goquery.NewDocumentFromReader(strings.NewReader(entry.Content))
+	if err != nil {
+		return links
+	}
+
+	document.Find("a[href]").Each(func(i int, anchor *goquery.Selection) {
+		href, _ := anchor.Attr("href")
+		absoluteURL, err := urllib.AbsoluteURL(entry.URL, strings.TrimSpace(href))
+		if err != nil {
+			return
+		}
+
+		parsedURL, err := url.Parse(absoluteURL)
+		if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") {
+			return
+		}
+
+		parsedURL.Fragment = ""
+		linkURL := parsedURL.String()
+		if linkURL == entry.URL {
+			return
+		}
+
+		if title := strings.TrimSpace(anchor.Text()); links[linkURL] == "" {
+			links[linkURL] = title
+		}
+	})
+
+	return links
+}
@@ -0,0 +1,58 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package fever // import "miniflux.app/fever"
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"miniflux.app/model"
+)
+
+func TestFindHotLinks(t *testing.T) {
+	to := time.Date(2023, time.May, 8, 0, 0, 0, 0, time.UTC)
+	from := to.AddDate(0, 0, -7)
+
+	entries := model.Entries{
+		{ID: 3, URL: "https://example.org/3", Date: to, Content: `<a href="https://example.com/article#comments">Article</a> <a href="/local">Local</a>`},
+		{ID: 2, URL: "https://example.org/2", Date: from.AddDate(0, 0, 3), Content: `<a href="https://example.com/article">Other title</a> <a href="https://example.org/3">Previous</a>`},
+		{ID: 1, URL: "https://example.org/1", Date: from, Content: `<a href="https://example.com/article"></a> <a href="https://example.org/local">Local</a> <a href="https://example.org/1">Self</a> <a href="mailto:someone@example.org">Mail</a>`},
+	}
+
+	links := findHotLinks(entries, from, to)
+	if len(links) != 2 {
+		t.Fatalf(`Unexpected hot links: %+v`, links)
+	}
+
+	if links[0].URL != "https://example.com/article" || links[0].Title != "Article" || links[0].Temperature != 2.2 || !reflect.DeepEqual(links[0].EntryIDs, []int64{3, 2, 1}) {
+		t.Errorf(`Unexpected first link: %+v`, links[0])
+	}
+
+	if links[1].URL != "https://example.org/local" || links[1].Temperature != 1.5 || !reflect.DeepEqual(links[1].EntryIDs, []int64{3, 1}) {
+		t.Errorf(`Unexpected second link: %+v`, links[1])
+	}
+}
+
+func TestFallbackFavicon(t *testing.T) {
+	feed := &model.Feed{ID: 42, SiteURL: "https://example.org/"}
+	if id := faviconID(feed); id != 85 {
+		t.Errorf(`Unexpected fallback favicon ID: %d`, id)
+	}
+
+	icon, err := newFallbackFavicon(feed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if icon.ID != 85 || icon.MimeType != "image/png" || len(icon.Content) == 0 {
+		t.Errorf(`Unexpected fallback favicon: %+v`, icon)
+	}
+
+	feed.Icon = &model.FeedIcon{FeedID: 42, IconID: 85}
+	if id := faviconID(feed); id != 170 {
+		t.Errorf(`The stored icon should be used without colliding with the fallback favicon: %d`, id)
+	}
+}
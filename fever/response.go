@@ -65,6 +65,11 @@ type faviconsResponse struct {
 	Favicons []favicon `json:"favicons"`
 }
 
+type linksResponse struct {
+	baseResponse
+	Links []link `json:"links"`
+}
+
 type itemsResponse struct {
 	baseResponse
 	Items []item `json:"items"`
@@ -113,6 +118,19 @@ type item struct {
 	CreatedAt int64  `json:"created_on_time"`
 }
 
+type link struct {
+	ID          int64   `json:"id"`
+	FeedID      int64   `json:"feed_id"`
+	ItemID      int64   `json:"item_id"`
+	Temperature float64 `json:"temperature"`
+	IsItem      int     `json:"is_item"`
+	IsLocal     int     `json:"is_local"`
+	IsSaved     int     `json:"is_saved"`
+	Title       string  `json:"title"`
+	URL         string  `json:"url"`
+	ItemIDs     string  `json:"item_ids"`
+}
+
 type favicon struct {
 	ID   int64  `json:"id"`
 	Data string `json:"data"`
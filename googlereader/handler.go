@@ -17,12 +17,14 @@ import (
 	"miniflux.app/config"
 	"miniflux.app/http/request"
 	"miniflux.app/http/response/json"
+	"miniflux.app/http/response/xml"
 	"miniflux.app/http/route"
 	"miniflux.app/integration"
 	"miniflux.app/logger"
 	"miniflux.app/model"
 	"miniflux.app/proxy"
 	mff "miniflux.app/reader/handler"
+	"miniflux.app/reader/opml"
 	mfs "miniflux.app/reader/subscription"
 	"miniflux.app/storage"
 	"miniflux.app/url"
@@ -94,6 +96,8 @@ const (
 	ParamDestination = "dest"
 	// ParamContinuation -  name of the parameter for callers to pass to receive the next page of results
 	ParamContinuation = "c"
+	// ParamTimestamp - name of the parameter containing the timestamp in microseconds used by mark-all-as-read
+	ParamTimestamp = "ts"
 )
 
 // StreamType represents the possible stream types
@@ -219,6 +223,12 @@ func Serve(router *mux.Router, store *storage.Storage) {
 	sr.HandleFunc("/subscription/quickadd", handler.quickAdd).Methods(http.MethodPost).Name("QuickAdd")
 	sr.HandleFunc("/stream/items/ids", handler.streamItemIDs).Methods(http.MethodGet).Name("StreamItemIDs")
 	sr.HandleFunc("/stream/items/contents", handler.streamItemContents).Methods(http.MethodPost).Name("StreamItemsContents")
+	sr.HandleFunc("/stream/contents", handler.streamContents).Methods(http.MethodGet).Name("StreamContents")
+	sr.HandleFunc("/stream/contents/{streamID:.+}", handler.streamContents).Methods(http.MethodGet).Name("StreamContentsByID")
+	sr.HandleFunc("/unread-count", handler.unreadCount).Methods(http.MethodGet).Name("UnreadCount")
+	sr.HandleFunc("/mark-all-as-read", handler.markAllAsRead).Methods(http.MethodPost).Name("MarkAllAsRead")
+	sr.HandleFunc("/subscription/export", handler.subscriptionExport).Methods(http.MethodGet).Name("SubscriptionExport")
+	sr.HandleFunc("/subscription/import", handler.subscriptionImport).Methods(http.MethodPost).Name("SubscriptionImport")
 	sr.PathPrefix("/").HandlerFunc(handler.serve).Methods(http.MethodPost, http.MethodGet).Name("GoogleReaderApiEndpoint")
 }
 
@@ -250,6 +260,7 @@ func getStreamFilterModifiers(r *http.Request) (RequestModifiers, error) {
 
 	result.Count = request.QueryIntParam(r, ParamStreamMaxItems, 0)
 	result.Offset = request.QueryIntParam(r, ParamContinuation, 0)
+	result.ContinuationToken = request.QueryStringParam(r, ParamContinuation, "")
 	result.StartTime = request.QueryInt64Param(r, ParamStreamStartTime, int64(0))
 	result.StopTime = request.QueryInt64Param(r, ParamStreamStopTime, int64(0))
 	return result, nil
@@ -303,6 +314,20 @@ func getStreams(streamIDs []string, userID int64) ([]Stream, error) {
 	return streams, nil
 }
 
+// splitLabelStreams separates user labels from the other stream tags.
+func splitLabelStreams(streams []Stream) ([]string, []Stream) {
+	labels := make([]string, 0)
+	others := make([]Stream, 0, len(streams))
+	for _, s := range streams {
+		if s.Type == LabelStream {
+			labels = append(labels, s.ID)
+		} else {
+			others = append(others, s)
+		}
+	}
+	return labels, others
+}
+
 func checkAndSimplifyTags(addTags []Stream, removeTags []Stream) (map[StreamType]bool, error) {
 	tags := make(map[StreamType]bool)
 	for _, s := range addTags {
@@ -423,6 +448,13 @@ func (h *handler) editTag(w http.ResponseWriter, r *http.Request) {
 		json.ServerError(w, r, err)
 		return
 	}
+	addLabels, addTags := splitLabelStreams(addTags)
+	removeLabels, removeTags := splitLabelStreams(removeTags)
+	if verr := validator.ValidateEntryLabels(h.store, userID, addLabels); verr != nil {
+		logger.Error("[GoogleReader][/edit-tag] [ClientIP=%s] %v", clientIP, verr.Error())
+		json.BadRequest(w, r, verr.Error())
+		return
+	}
 	tags, err := checkAndSimplifyTags(addTags, removeTags)
 	if err != nil {
 		logger.Error("[GoogleReader][/edit-tag] [ClientIP=%s] %v", clientIP, err)
@@ -451,11 +483,13 @@ func (h *handler) editTag(w http.ResponseWriter, r *http.Request) {
 	}
 
 	n := 0
+	entryIDs := make([]int64, 0, len(entries))
 	readEntryIDs := make([]int64, 0)
 	unreadEntryIDs := make([]int64, 0)
 	starredEntryIDs := make([]int64, 0)
 	unstarredEntryIDs := make([]int64, 0)
 	for _, entry := range entries {
+		entryIDs = append(entryIDs, entry.ID)
 		if read, exists := tags[ReadStream]; exists {
 			if read && entry.Status == model.EntryStatusUnread {
 				readEntryIDs = append(readEntryIDs, entry.ID)
@@ -511,6 +545,22 @@ func (h *handler) editTag(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	for _, label := range addLabels {
+		if err := h.store.AddEntriesLabel(userID, entryIDs, label); err != nil {
+			logger.Error("[GoogleReader][/edit-tag] [ClientIP=%s] %v", clientIP, err)
+			json.ServerError(w, r, err)
+			return
+		}
+	}
+
+	for _, label := range removeLabels {
+		if err := h.store.RemoveEntriesLabel(userID, entryIDs, label); err != nil {
+			logger.Error("[GoogleReader][/edit-tag] [ClientIP=%s] %v", clientIP, err)
+			json.ServerError(w, r, err)
+			return
+		}
+	}
+
 	if len(entries) > 0 {
 		settings, err := h.store.Integration(userID)
 		if err != nil {
@@ -521,6 +571,13 @@ func (h *handler) editTag(w http.ResponseWriter, r *http.Request) {
 
 		for _, entry := range entries {
 			e := entry
+			e.Annotations, err = h.store.EntryAnnotations(userID, e.ID)
+			if err != nil {
+				logger.Error("[GoogleReader][/edit-tag] [ClientIP=%s] %v", clientIP, err)
+				json.ServerError(w, r, err)
+				return
+			}
+
 			go func() {
 				integration.SendEntry(e, settings)
 			}()
@@ -594,6 +651,9 @@ func getOrCreateCategory(category Stream, store *storage.Storage, userID int64)
 		catRequest := model.CategoryRequest{
 			Title: category.ID,
 		}
+		if verr := validator.ValidateCategoryCreation(store, userID, &catRequest); verr != nil {
+			return nil, verr.Error()
+		}
 		return store.CreateCategory(userID, &catRequest)
 	}
 }
@@ -775,10 +835,6 @@ func (h *handler) streamItemContents(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	userReadingList := fmt.Sprintf(UserStreamPrefix, userID) + ReadingList
-	userRead := fmt.Sprintf(UserStreamPrefix, userID) + Read
-	userStarred := fmt.Sprintf(UserStreamPrefix, userID) + Starred
-
 	itemIDs, err := getItemIDs(r)
 	if err != nil {
 		logger.Error("[GoogleReader][/stream/items/contents] [ClientIP=%s] %v", clientIP, err)
@@ -822,18 +878,28 @@ func (h *handler) streamItemContents(w http.ResponseWriter, r *http.Request) {
 		},
 		Author: user.Username,
 	}
+	result.Items = h.contentItems(r, userID, entries)
+	json.OK(w, r, result)
+}
+
+// contentItems converts the entries to the items returned by the stream contents endpoints.
+func (h *handler) contentItems(r *http.Request, userID int64, entries model.Entries) []contentItem {
+	userReadingList := fmt.Sprintf(UserStreamPrefix, userID) + ReadingList
+	userRead := fmt.Sprintf(UserStreamPrefix, userID) + Read
+	userStarred := fmt.Sprintf(UserStreamPrefix, userID) + Starred
+	proxyOption := config.Opts.ProxyOption()
+
 	contentItems := make([]contentItem, len(entries))
 	for i, entry := range entries {
-		enclosures := make([]contentItemEnclosure, len(entry.Enclosures))
-		for _, enclosure := range entry.Enclosures {
-			enclosures = append(enclosures, contentItemEnclosure{URL: enclosure.URL, Type: enclosure.MimeType})
-		}
 		categories := make([]string, 0)
 		categories = append(categories, userReadingList)
 		if entry.Feed.Category.Title != "" {
 			categories = append(categories, fmt.Sprintf(UserLabelPrefix, userID)+entry.Feed.Category.Title)
 		}
-		if entry.Starred {
+		for _, label := range entry.Labels {
+			categories = append(categories, fmt.Sprintf(UserLabelPrefix, userID)+label)
+		}
+		if entry.Status == model.EntryStatusRead {
 			categories = append(categories, userRead)
 		}
 
@@ -842,25 +908,27 @@ func (h *handler) streamItemContents(w http.ResponseWriter, r *http.Request) {
 		}
 
 		entry.Content = proxy.AbsoluteProxyRewriter(h.router, r.Host, entry.Content)
-		proxyOption := config.Opts.ProxyOption()
 
-		for i := range entry.Enclosures {
-			if proxyOption == "all" || proxyOption != "none" && !url.IsHTTPS(entry.Enclosures[i].URL) {
+		enclosures := make([]contentItemEnclosure, 0, len(entry.Enclosures))
+		for _, enclosure := range entry.Enclosures {
+			enclosureURL := enclosure.URL
+			if proxyOption == "all" || proxyOption != "none" && !url.IsHTTPS(enclosureURL) {
 				for _, mediaType := range config.Opts.ProxyMediaTypes() {
-					if strings.HasPrefix(entry.Enclosures[i].MimeType, mediaType+"/") {
-						entry.Enclosures[i].URL = proxy.AbsoluteProxifyURL(h.router, r.Host, entry.Enclosures[i].URL)
+					if strings.HasPrefix(enclosure.MimeType, mediaType+"/") {
+						enclosureURL = proxy.AbsoluteProxifyURL(h.router, r.Host, enclosureURL)
 						break
 					}
 				}
 			}
+			enclosures = append(enclosures, contentItemEnclosure{URL: enclosureURL, Type: enclosure.MimeType})
 		}
 
 		contentItems[i] = contentItem{
 			ID:            fmt.Sprintf(EntryIDLong, entry.ID),
 			Title:         entry.Title,
 			Author:        entry.Author,
-			TimestampUsec: fmt.Sprintf("%d", entry.Date.UnixNano()/(int64(time.Microsecond)/int64(time.Nanosecond))),
-			CrawlTimeMsec: fmt.Sprintf("%d", entry.Date.UnixNano()/(int64(time.Microsecond)/int64(time.Nanosecond))),
+			TimestampUsec: fmt.Sprintf("%d", entry.Date.UnixMicro()),
+			CrawlTimeMsec: fmt.Sprintf("%d", entry.Date.UnixMilli()),
 			Published:     entry.Date.Unix(),
 			Updated:       entry.Date.Unix(),
 			Categories:    categories,
@@ -891,8 +959,7 @@ func (h *handler) streamItemContents(w http.ResponseWriter, r *http.Request) {
 			Enclosure: enclosures,
 		}
 	}
-	result.Items = contentItems
-	json.OK(w, r, result)
+	return contentItems
 }
 
 func (h *handler) disableTag(w http.ResponseWriter, r *http.Request) {
@@ -914,19 +981,36 @@ func (h *handler) disableTag(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	titles := make([]string, len(streams))
-	for i, stream := range streams {
+	titles := make([]string, 0, len(streams))
+	for _, stream := range streams {
 		if stream.Type != LabelStream {
 			json.BadRequest(w, r, errors.New("only labels are supported"))
 			return
 		}
-		titles[i] = stream.ID
+
+		label, err := h.store.LabelByTitle(userID, stream.ID)
+		if err != nil {
+			json.ServerError(w, r, err)
+			return
+		}
+
+		if label != nil {
+			if err := h.store.RemoveLabel(userID, label.ID); err != nil {
+				json.ServerError(w, r, err)
+				return
+			}
+			continue
+		}
+
+		titles = append(titles, stream.ID)
 	}
 
-	err = h.store.RemoveAndReplaceCategoriesByName(userID, titles)
-	if err != nil {
-		json.ServerError(w, r, err)
-		return
+	if len(titles) > 0 {
+		err = h.store.RemoveAndReplaceCategoriesByName(userID, titles)
+		if err != nil {
+			json.ServerError(w, r, err)
+			return
+		}
 	}
 
 	OK(w, r)
@@ -967,6 +1051,28 @@ func (h *handler) renameTag(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	label, err := h.store.LabelByTitle(userID, source.ID)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+	if label != nil {
+		labelRequest := model.LabelRequest{
+			Title: destination.ID,
+		}
+		if verr := validator.ValidateLabelModification(h.store, userID, label.ID, &labelRequest); verr != nil {
+			json.BadRequest(w, r, verr.Error())
+			return
+		}
+		labelRequest.Patch(label)
+		if err := h.store.UpdateLabel(label); err != nil {
+			json.ServerError(w, r, err)
+			return
+		}
+		OK(w, r)
+		return
+	}
+
 	category, err := h.store.CategoryByTitle(userID, source.ID)
 	if err != nil {
 		json.ServerError(w, r, err)
@@ -1022,6 +1128,18 @@ func (h *handler) tagList(w http.ResponseWriter, r *http.Request) {
 			Type:  "folder",
 		})
 	}
+	labels, err := h.store.Labels(userID)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+	for _, label := range labels {
+		result.Tags = append(result.Tags, subscriptionCategory{
+			ID:    fmt.Sprintf(UserLabelPrefix, userID) + label.Title,
+			Label: label.Title,
+			Type:  "tag",
+		})
+	}
 	json.OK(w, r, result)
 }
 
@@ -1119,6 +1237,8 @@ func (h *handler) streamItemIDs(w http.ResponseWriter, r *http.Request) {
 		h.handleReadStream(w, r, rm)
 	case FeedStream:
 		h.handleFeedStream(w, r, rm)
+	case LabelStream:
+		h.handleLabelStream(w, r, rm)
 	default:
 		dump, _ := httputil.DumpRequest(r, true)
 		logger.Info("[GoogleReader][/stream/items/ids] [ClientIP=%s] Unknown Stream: %s", clientIP, dump)
@@ -1313,3 +1433,495 @@ func (h *handler) handleFeedStream(w http.ResponseWriter, r *http.Request, rm Re
 
 	json.OK(w, r, streamIDResponse{itemRefs, continuation})
 }
+
+func (h *handler) handleLabelStream(w http.ResponseWriter, r *http.Request, rm RequestModifiers) {
+	clientIP := request.ClientIP(r)
+	title := rm.Streams[0].ID
+
+	builder := h.store.NewEntryQueryBuilder(rm.UserID)
+	builder.WithoutStatus(model.EntryStatusRemoved)
+
+	// A label stream is either a user label or a category (folder).
+	label, err := h.store.LabelByTitle(rm.UserID, title)
+	if err != nil {
+		logger.Error("[GoogleReader][/stream/items/ids#label] [ClientIP=%s] %v", clientIP, err)
+		json.ServerError(w, r, err)
+		return
+	}
+
+	if label != nil {
+		builder.WithLabelID(label.ID)
+	} else {
+		category, err := h.store.CategoryByTitle(rm.UserID, title)
+		if err != nil {
+			logger.Error("[GoogleReader][/stream/items/ids#label] [ClientIP=%s] %v", clientIP, err)
+			json.ServerError(w, r, err)
+			return
+		}
+
+		if category == nil {
+			json.OK(w, r, streamIDResponse{make([]itemRef, 0), 0})
+			return
+		}
+
+		builder.WithCategoryID(category.ID)
+	}
+
+	builder.WithLimit(rm.Count)
+	builder.WithOffset(rm.Offset)
+	builder.WithOrder(model.DefaultSortingOrder)
+	builder.WithDirection(rm.SortDirection)
+	if rm.StartTime > 0 {
+		builder.AfterDate(time.Unix(rm.StartTime, 0))
+	}
+	if rm.StopTime > 0 {
+		builder.BeforeDate(time.Unix(rm.StopTime, 0))
+	}
+
+	rawEntryIDs, err := builder.GetEntryIDs()
+	if err != nil {
+		logger.Error("[GoogleReader][/stream/items/ids#label] [ClientIP=%s] %v", clientIP, err)
+		json.ServerError(w, r, err)
+		return
+	}
+	var itemRefs = make([]itemRef, 0)
+	for _, entryID := range rawEntryIDs {
+		formattedID := strconv.FormatInt(entryID, 10)
+		itemRefs = append(itemRefs, itemRef{ID: formattedID})
+	}
+
+	totalEntries, err := builder.CountEntries()
+	if err != nil {
+		logger.Error("[GoogleReader][/stream/items/ids#label] [ClientIP=%s] %v", clientIP, err)
+		json.ServerError(w, r, err)
+		return
+	}
+	continuation := 0
+	if len(itemRefs)+rm.Offset < totalEntries {
+		continuation = len(itemRefs) + rm.Offset
+	}
+
+	json.OK(w, r, streamIDResponse{itemRefs, continuation})
+}
+
+func (h *handler) unreadCount(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	clientIP := request.ClientIP(r)
+
+	logger.Info("[GoogleReader][/unread-count][ClientIP=%s] Incoming Request for userID #%d", clientIP, userID)
+
+	if err := checkOutputFormat(w, r); err != nil {
+		logger.Error("[GoogleReader][/unread-count] [ClientIP=%s] %v", clientIP, err)
+		json.ServerError(w, r, err)
+		return
+	}
+
+	feedCounters, err := h.store.FeedUnreadCounters(userID)
+	if err != nil {
+		logger.Error("[GoogleReader][/unread-count] [ClientIP=%s] %v", clientIP, err)
+		json.ServerError(w, r, err)
+		return
+	}
+
+	categoryCounters, err := h.store.CategoryUnreadCounters(userID)
+	if err != nil {
+		logger.Error("[GoogleReader][/unread-count] [ClientIP=%s] %v", clientIP, err)
+		json.ServerError(w, r, err)
+		return
+	}
+
+	labelCounters, err := h.store.LabelUnreadCounters(userID)
+	if err != nil {
+		logger.Error("[GoogleReader][/unread-count] [ClientIP=%s] %v", clientIP, err)
+		json.ServerError(w, r, err)
+		return
+	}
+
+	result := unreadCountResponse{UnreadCounts: make([]unreadCount, 0)}
+	readingList := unreadCount{ID: fmt.Sprintf(UserStreamPrefix, userID) + ReadingList}
+	var newestEntryDate time.Time
+	for _, counter := range feedCounters {
+		result.UnreadCounts = append(result.UnreadCounts, newUnreadCount(fmt.Sprintf(FeedPrefix+"%d", counter.ID), counter))
+		readingList.Count += counter.Count
+		if counter.NewestEntryDate.After(newestEntryDate) {
+			newestEntryDate = counter.NewestEntryDate
+		}
+	}
+
+	// A label stream resolves to the user label before the category having the same title.
+	labelTitles := make(map[string]bool)
+	for _, counter := range labelCounters {
+		labelTitles[counter.Title] = true
+		result.UnreadCounts = append(result.UnreadCounts, newUnreadCount(fmt.Sprintf(UserLabelPrefix, userID)+counter.Title, counter))
+	}
+	for _, counter := range categoryCounters {
+		if !labelTitles[counter.Title] {
+			result.UnreadCounts = append(result.UnreadCounts, newUnreadCount(fmt.Sprintf(UserLabelPrefix, userID)+counter.Title, counter))
+		}
+	}
+
+	readingList.NewestItemTimestampUsec = fmt.Sprintf("%d", newestEntryDate.UnixMicro())
+	if readingList.Count > 0 {
+		result.UnreadCounts = append(result.UnreadCounts, readingList)
+	}
+	result.Max = readingList.Count
+
+	json.OK(w, r, result)
+}
+
+func newUnreadCount(streamID string, counter *model.UnreadCounter) unreadCount {
+	return unreadCount{
+		ID:                      streamID,
+		Count:                   counter.Count,
+		NewestItemTimestampUsec: fmt.Sprintf("%d", counter.NewestEntryDate.UnixMicro()),
+	}
+}
+
+func (h *handler) markAllAsRead(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	clientIP := request.ClientIP(r)
+
+	logger.Info("[GoogleReader][/mark-all-as-read][ClientIP=%s] Incoming Request for userID #%d", clientIP, userID)
+
+	if err := r.ParseForm(); err != nil {
+		logger.Error("[GoogleReader][/mark-all-as-read] [ClientIP=%s] %v", clientIP, err)
+		json.BadRequest(w, r, err)
+		return
+	}
+
+	stream, err := getStream(r.Form.Get(ParamStreamID), userID)
+	if err != nil {
+		logger.Error("[GoogleReader][/mark-all-as-read] [ClientIP=%s] %v", clientIP, err)
+		json.BadRequest(w, r, err)
+		return
+	}
+
+	// Only the entries published before the timestamp are marked as read, the client did not see the newer ones.
+	before := time.Now()
+	if value := r.Form.Get(ParamTimestamp); value != "" {
+		timestamp, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || timestamp <= 0 {
+			err := fmt.Errorf("invalid timestamp: %s", value)
+			logger.Error("[GoogleReader][/mark-all-as-read] [ClientIP=%s] %v", clientIP, err)
+			json.BadRequest(w, r, err)
+			return
+		}
+		before = time.UnixMicro(timestamp)
+	}
+
+	switch stream.Type {
+	case FeedStream:
+		feedID, err := strconv.ParseInt(stream.ID, 10, 64)
+		if err != nil {
+			logger.Error("[GoogleReader][/mark-all-as-read] [ClientIP=%s] %v", clientIP, err)
+			json.BadRequest(w, r, err)
+			return
+		}
+		err = h.store.MarkFeedAsRead(userID, feedID, before)
+		if err != nil {
+			logger.Error("[GoogleReader][/mark-all-as-read] [ClientIP=%s] %v", clientIP, err)
+			json.ServerError(w, r, err)
+			return
+		}
+	case LabelStream:
+		label, err := h.store.LabelByTitle(userID, stream.ID)
+		if err != nil {
+			logger.Error("[GoogleReader][/mark-all-as-read] [ClientIP=%s] %v", clientIP, err)
+			json.ServerError(w, r, err)
+			return
+		}
+
+		if label != nil {
+			builder := h.store.NewEntryQueryBuilder(userID)
+			builder.WithLabelID(label.ID)
+			if err := h.markEntriesAsRead(userID, builder, before); err != nil {
+				logger.Error("[GoogleReader][/mark-all-as-read] [ClientIP=%s] %v", clientIP, err)
+				json.ServerError(w, r, err)
+				return
+			}
+			break
+		}
+
+		category, err := h.store.CategoryByTitle(userID, stream.ID)
+		if err != nil {
+			logger.Error("[GoogleReader][/mark-all-as-read] [ClientIP=%s] %v", clientIP, err)
+			json.ServerError(w, r, err)
+			return
+		}
+
+		if category == nil {
+			err := fmt.Errorf("unknown label: %s", stream.ID)
+			logger.Error("[GoogleReader][/mark-all-as-read] [ClientIP=%s] %v", clientIP, err)
+			json.BadRequest(w, r, err)
+			return
+		}
+
+		if err := h.store.MarkCategoryAsRead(userID, category.ID, before); err != nil {
+			logger.Error("[GoogleReader][/mark-all-as-read] [ClientIP=%s] %v", clientIP, err)
+			json.ServerError(w, r, err)
+			return
+		}
+	case ReadingListStream:
+		if r.Form.Get(ParamTimestamp) == "" {
+			err = h.store.MarkAllAsRead(userID)
+		} else {
+			err = h.markEntriesAsRead(userID, h.store.NewEntryQueryBuilder(userID), before)
+		}
+		if err != nil {
+			logger.Error("[GoogleReader][/mark-all-as-read] [ClientIP=%s] %v", clientIP, err)
+			json.ServerError(w, r, err)
+			return
+		}
+	default:
+		err := fmt.Errorf("unsupported stream type: %s", stream.Type)
+		logger.Error("[GoogleReader][/mark-all-as-read] [ClientIP=%s] %v", clientIP, err)
+		json.BadRequest(w, r, err)
+		return
+	}
+
+	OK(w, r)
+}
+
+// markEntriesAsRead marks as read the unread entries matched by the builder and published before the given date.
+func (h *handler) markEntriesAsRead(userID int64, builder *storage.EntryQueryBuilder, before time.Time) error {
+	builder.WithStatus(model.EntryStatusUnread)
+	builder.BeforeDate(before)
+
+	entryIDs, err := builder.GetEntryIDs()
+	if err != nil {
+		return err
+	}
+
+	if len(entryIDs) == 0 {
+		return nil
+	}
+
+	return h.store.SetEntriesStatus(userID, entryIDs, model.EntryStatusRead)
+}
+
+func (h *handler) streamContents(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	clientIP := request.ClientIP(r)
+
+	logger.Info("[GoogleReader][/stream/contents][ClientIP=%s] Incoming Request for userID #%d", clientIP, userID)
+
+	if err := checkOutputFormat(w, r); err != nil {
+		logger.Error("[GoogleReader][/stream/contents] [ClientIP=%s] %v", clientIP, err)
+		json.ServerError(w, r, err)
+		return
+	}
+
+	rm, err := getStreamFilterModifiers(r)
+	if err != nil {
+		logger.Error("[GoogleReader][/stream/contents] [ClientIP=%s] %v", clientIP, err)
+		json.BadRequest(w, r, err)
+		return
+	}
+
+	// The stream is part of the path, clients using the query string are also supported.
+	streamID := mux.Vars(r)["streamID"]
+	if streamID != "" {
+		stream, err := getStream(streamID, userID)
+		if err != nil {
+			logger.Error("[GoogleReader][/stream/contents] [ClientIP=%s] %v", clientIP, err)
+			json.BadRequest(w, r, err)
+			return
+		}
+		rm.Streams = []Stream{stream}
+	} else if len(rm.Streams) == 1 {
+		streamID = request.QueryStringParam(r, ParamStreamID, "")
+	} else {
+		streamID = fmt.Sprintf(UserStreamPrefix, userID) + ReadingList
+		rm.Streams = []Stream{{ReadingListStream, ""}}
+	}
+
+	var cursor *model.EntryCursor
+	if rm.ContinuationToken != "" {
+		if cursor, err = model.ParseEntryCursor(rm.ContinuationToken); err != nil {
+			logger.Error("[GoogleReader][/stream/contents] [ClientIP=%s] %v", clientIP, err)
+			json.BadRequest(w, r, err)
+			return
+		}
+	}
+
+	user, err := h.store.UserByID(userID)
+	if err != nil {
+		logger.Error("[GoogleReader][/stream/contents] [ClientIP=%s] %v", clientIP, err)
+		json.ServerError(w, r, err)
+		return
+	}
+
+	result := streamContentItems{
+		Direction: "ltr",
+		ID:        streamID,
+		Title:     rm.Streams[0].ID,
+		Alternate: make([]contentHREFType, 0),
+		Updated:   time.Now().Unix(),
+		Self: []contentHREF{
+			{
+				HREF: config.Opts.BaseURL() + r.URL.RequestURI(),
+			},
+		},
+		Author: user.Username,
+		Items:  make([]contentItem, 0),
+	}
+
+	builder := h.store.NewEntryQueryBuilder(userID)
+	builder.WithoutStatus(model.EntryStatusRemoved)
+
+	switch rm.Streams[0].Type {
+	case ReadingListStream:
+		result.Title = ReadingList
+	case StarredStream:
+		result.Title = Starred
+		builder.WithStarred(true)
+	case ReadStream:
+		result.Title = Read
+		builder.WithStatus(model.EntryStatusRead)
+	case FeedStream:
+		feedID, err := strconv.ParseInt(rm.Streams[0].ID, 10, 64)
+		if err != nil {
+			logger.Error("[GoogleReader][/stream/contents] [ClientIP=%s] %v", clientIP, err)
+			json.BadRequest(w, r, err)
+			return
+		}
+
+		feed, err := h.store.FeedByID(userID, feedID)
+		if err != nil {
+			logger.Error("[GoogleReader][/stream/contents] [ClientIP=%s] %v", clientIP, err)
+			json.ServerError(w, r, err)
+			return
+		}
+
+		if feed == nil {
+			json.NotFound(w, r)
+			return
+		}
+
+		result.Title = feed.Title
+		result.Alternate = []contentHREFType{{HREF: feed.SiteURL, Type: "text/html"}}
+		builder.WithFeedID(feedID)
+	case LabelStream:
+		label, err := h.store.LabelByTitle(userID, rm.Streams[0].ID)
+		if err != nil {
+			logger.Error("[GoogleReader][/stream/contents] [ClientIP=%s] %v", clientIP, err)
+			json.ServerError(w, r, err)
+			return
+		}
+
+		if label != nil {
+			builder.WithLabelID(label.ID)
+			break
+		}
+
+		category, err := h.store.CategoryByTitle(userID, rm.Streams[0].ID)
+		if err != nil {
+			logger.Error("[GoogleReader][/stream/contents] [ClientIP=%s] %v", clientIP, err)
+			json.ServerError(w, r, err)
+			return
+		}
+
+		if category == nil {
+			json.OK(w, r, result)
+			return
+		}
+
+		builder.WithCategoryID(category.ID)
+	default:
+		err := fmt.Errorf("unsupported stream type: %s", rm.Streams[0].Type)
+		logger.Error("[GoogleReader][/stream/contents] [ClientIP=%s] %v", clientIP, err)
+		json.BadRequest(w, r, err)
+		return
+	}
+
+	for _, s := range rm.ExcludeTargets {
+		switch s.Type {
+		case ReadStream:
+			builder.WithStatus(model.EntryStatusUnread)
+		case StarredStream:
+			builder.WithStarred(false)
+		default:
+			logger.Info("[GoogleReader][/stream/contents][ClientIP=%s] xt filter type: %#v", clientIP, s)
+		}
+	}
+
+	for _, s := range rm.FilterTargets {
+		switch s.Type {
+		case ReadStream:
+			builder.WithStatus(model.EntryStatusRead)
+		case StarredStream:
+			builder.WithStarred(true)
+		default:
+			logger.Info("[GoogleReader][/stream/contents][ClientIP=%s] it filter type: %#v", clientIP, s)
+		}
+	}
+
+	if rm.StartTime > 0 {
+		builder.AfterDate(time.Unix(rm.StartTime, 0))
+	}
+	if rm.StopTime > 0 {
+		builder.BeforeDate(time.Unix(rm.StopTime, 0))
+	}
+
+	// The continuation token is the position of the last entry of the page,
+	// pages are stable when new entries are received between two requests.
+	count := rm.Count
+	if count <= 0 {
+		count = 20
+	} else if count > 1000 {
+		count = 1000
+	}
+
+	builder.WithDirection(rm.SortDirection)
+	builder.WithCursor(cursor)
+	builder.WithLimit(count)
+
+	entries, err := builder.GetEntries()
+	if err != nil {
+		logger.Error("[GoogleReader][/stream/contents] [ClientIP=%s] %v", clientIP, err)
+		json.ServerError(w, r, err)
+		return
+	}
+
+	result.Items = h.contentItems(r, userID, entries)
+	if len(entries) == count {
+		result.Continuation = model.NewEntryCursor(entries[len(entries)-1]).String()
+	}
+
+	json.OK(w, r, result)
+}
+
+func (h *handler) subscriptionExport(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	clientIP := request.ClientIP(r)
+
+	logger.Info("[GoogleReader][/subscription/export][ClientIP=%s] Incoming Request for userID #%d", clientIP, userID)
+
+	opmlHandler := opml.NewHandler(h.store)
+	opml, err := opmlHandler.Export(userID)
+	if err != nil {
+		logger.Error("[GoogleReader][/subscription/export] [ClientIP=%s] %v", clientIP, err)
+		json.ServerError(w, r, err)
+		return
+	}
+
+	xml.OK(w, r, opml)
+}
+
+func (h *handler) subscriptionImport(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	clientIP := request.ClientIP(r)
+
+	logger.Info("[GoogleReader][/subscription/import][ClientIP=%s] Incoming Request for userID #%d", clientIP, userID)
+
+	opmlHandler := opml.NewHandler(h.store)
+	err := opmlHandler.Import(userID, r.Body)
+	defer r.Body.Close()
+	if err != nil {
+		logger.Error("[GoogleReader][/subscription/import] [ClientIP=%s] %v", clientIP, err)
+		json.BadRequest(w, r, err)
+		return
+	}
+
+	OK(w, r)
+}
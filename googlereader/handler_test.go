@@ -0,0 +1,332 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package googlereader // import "miniflux.app/googlereader"
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"miniflux.app/model"
+	"miniflux.app/storage"
+	"miniflux.app/storage/storagetest"
+
+	"github.com/gorilla/mux"
+)
+
+type testClient struct {
+	t      *testing.T
+	server *httptest.Server
+	token  string
+}
+
+// newTestClient creates a user with a feed of three entries published one hour apart,
+// and logs in like Reeder or NetNewsWire do before calling the API.
+func newTestClient(t *testing.T) (*testClient, *storage.Storage, *model.Feed, model.Entries) {
+	t.Helper()
+
+	store := storagetest.NewStorage(t)
+	user := storagetest.CreateUser(t, store, false)
+
+	integration, err := store.Integration(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	integration.GoogleReaderEnabled = true
+	integration.GoogleReaderUsername = "reader"
+	integration.GoogleReaderPassword = "secret"
+	if err := store.UpdateIntegration(integration); err != nil {
+		t.Fatal(err)
+	}
+
+	feed := storagetest.CreateFeed(t, store, user)
+
+	now := time.Now().Truncate(time.Second)
+	newEntries := model.Entries{
+		{Hash: "1", Title: "Entry 1", URL: "https://example.org/1", Date: now.Add(-2 * time.Hour), Content: "<p>1</p>"},
+		{Hash: "2", Title: "Entry 2", URL: "https://example.org/2", Date: now.Add(-time.Hour), Content: "<p>2</p>"},
+		{Hash: "3", Title: "Entry 3", URL: "https://example.org/3", Date: now, Content: "<p>3</p>"},
+	}
+	entries := storagetest.RefreshEntries(t, store, feed, newEntries, false)
+
+	router := mux.NewRouter()
+	Serve(router, store)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	client := &testClient{t: t, server: server}
+	response := client.do(http.MethodPost, "/accounts/ClientLogin", url.Values{"Email": {"reader"}, "Passwd": {"secret"}}, nil)
+	for _, line := range strings.Split(string(response), "\n") {
+		if strings.HasPrefix(line, "Auth=") {
+			client.token = strings.TrimPrefix(line, "Auth=")
+		}
+	}
+	if client.token == "" {
+		t.Fatalf(`No token returned by ClientLogin: %s`, response)
+	}
+
+	return client, store, feed, entries
+}
+
+func (c *testClient) send(method, path string, form url.Values, body io.Reader, contentType string) (int, []byte) {
+	c.t.Helper()
+
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+		contentType = "application/x-www-form-urlencoded"
+	}
+
+	request, err := http.NewRequest(method, c.server.URL+path, body)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	if contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+	if c.token != "" {
+		request.Header.Set("Authorization", "GoogleLogin auth="+c.token)
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	defer response.Body.Close()
+
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	return response.StatusCode, data
+}
+
+func (c *testClient) do(method, path string, form url.Values, into interface{}) []byte {
+	c.t.Helper()
+
+	status, data := c.send(method, path, form, nil, "")
+	if status != http.StatusOK {
+		c.t.Fatalf(`Unexpected status code for %s %s: got %d: %s`, method, path, status, data)
+	}
+
+	if into != nil {
+		if err := json.Unmarshal(data, into); err != nil {
+			c.t.Fatalf(`Unable to decode the response of %s %s: %v: %s`, method, path, err, data)
+		}
+	}
+	return data
+}
+
+func (c *testClient) unreadCounts() map[string]unreadCount {
+	var response unreadCountResponse
+	c.do(http.MethodGet, "/reader/api/0/unread-count?output=json", nil, &response)
+
+	counts := make(map[string]unreadCount)
+	for _, count := range response.UnreadCounts {
+		counts[count.ID] = count
+	}
+	return counts
+}
+
+func TestUnreadCount(t *testing.T) {
+	client, store, feed, entries := newTestClient(t)
+
+	if err := store.SetEntryLabels(feed.UserID, entries[0].ID, []string{"Later"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var response unreadCountResponse
+	client.do(http.MethodGet, "/reader/api/0/unread-count?output=json", nil, &response)
+	if response.Max != 3 {
+		t.Errorf(`Unexpected max: got %d`, response.Max)
+	}
+
+	counts := make(map[string]unreadCount)
+	for _, count := range response.UnreadCounts {
+		counts[count.ID] = count
+	}
+
+	newest := fmt.Sprintf("%d", entries[2].Date.UnixMicro())
+	scenarios := []struct {
+		streamID string
+		count    int
+		newest   string
+	}{
+		{fmt.Sprintf("feed/%d", feed.ID), 3, newest},
+		{fmt.Sprintf("user/%d/label/%s", feed.UserID, feed.Category.Title), 3, newest},
+		{fmt.Sprintf("user/%d/label/Later", feed.UserID), 1, fmt.Sprintf("%d", entries[0].Date.UnixMicro())},
+		{fmt.Sprintf("user/%d/state/com.google/reading-list", feed.UserID), 3, newest},
+	}
+
+	for _, scenario := range scenarios {
+		count, found := counts[scenario.streamID]
+		if !found {
+			t.Errorf(`Missing unread count for %s: %+v`, scenario.streamID, response)
+			continue
+		}
+
+		if count.Count != scenario.count || count.NewestItemTimestampUsec != scenario.newest {
+			t.Errorf(`Unexpected unread count for %s: got %+v`, scenario.streamID, count)
+		}
+	}
+
+	if len(response.UnreadCounts) != len(scenarios) {
+		t.Errorf(`Unexpected number of unread counts: %+v`, response)
+	}
+}
+
+func TestMarkAllAsRead(t *testing.T) {
+	client, _, feed, entries := newTestClient(t)
+	readingList := fmt.Sprintf("user/%d/state/com.google/reading-list", feed.UserID)
+	feedStream := fmt.Sprintf("feed/%d", feed.ID)
+
+	// The timestamp sent by the clients is the time of their last sync, newer entries stay unread.
+	ts := fmt.Sprintf("%d", entries[1].Date.Add(-time.Minute).UnixMicro())
+	client.do(http.MethodPost, "/reader/api/0/mark-all-as-read", url.Values{"s": {feedStream}, "ts": {ts}, "T": {client.token}}, nil)
+
+	if count := client.unreadCounts()[feedStream].Count; count != 2 {
+		t.Errorf(`Unexpected unread count after marking the feed as read: got %d`, count)
+	}
+
+	ts = fmt.Sprintf("%d", entries[2].Date.Add(-time.Minute).UnixMicro())
+	client.do(http.MethodPost, "/reader/api/0/mark-all-as-read", url.Values{"s": {"user/-/label/" + feed.Category.Title}, "ts": {ts}, "T": {client.token}}, nil)
+
+	if count := client.unreadCounts()[readingList].Count; count != 1 {
+		t.Errorf(`Unexpected unread count after marking the folder as read: got %d`, count)
+	}
+
+	client.do(http.MethodPost, "/reader/api/0/mark-all-as-read", url.Values{"s": {"user/-/state/com.google/reading-list"}, "T": {client.token}}, nil)
+
+	if counts := client.unreadCounts(); len(counts) != 0 {
+		t.Errorf(`Unexpected unread counts after marking everything as read: %+v`, counts)
+	}
+
+	for _, form := range []url.Values{
+		{"s": {feedStream}, "ts": {"yesterday"}, "T": {client.token}},
+		{"s": {"user/-/label/Unknown"}, "T": {client.token}},
+		{"s": {"user/-/state/com.google/broadcast"}, "T": {client.token}},
+	} {
+		if status, body := client.send(http.MethodPost, "/reader/api/0/mark-all-as-read", form, nil, ""); status != http.StatusBadRequest {
+			t.Errorf(`Unexpected status code for %v: got %d: %s`, form, status, body)
+		}
+	}
+}
+
+func TestStreamContents(t *testing.T) {
+	client, store, feed, entries := newTestClient(t)
+
+	if err := store.SetEntriesStatus(feed.UserID, []int64{entries[0].ID}, model.EntryStatusRead); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reeder pages through the unread items of the reading list.
+	var itemIDs []string
+	continuation := ""
+	for page := 0; page < 5; page++ {
+		path := "/reader/api/0/stream/contents/user/-/state/com.google/reading-list?output=json&n=1&xt=user/-/state/com.google/read"
+		if continuation != "" {
+			path += "&c=" + continuation
+		}
+
+		var response streamContentItems
+		client.do(http.MethodGet, path, nil, &response)
+		for _, item := range response.Items {
+			itemIDs = append(itemIDs, item.ID)
+		}
+
+		if response.Continuation == "" {
+			break
+		}
+		continuation = response.Continuation
+	}
+
+	expected := []string{fmt.Sprintf(EntryIDLong, entries[2].ID), fmt.Sprintf(EntryIDLong, entries[1].ID)}
+	if strings.Join(itemIDs, ",") != strings.Join(expected, ",") {
+		t.Errorf(`Unexpected items: got %v instead of %v`, itemIDs, expected)
+	}
+
+	// NetNewsWire sends the escaped stream ID and reads the oldest items first.
+	var response streamContentItems
+	client.do(http.MethodGet, fmt.Sprintf("/reader/api/0/stream/contents/feed%%2F%d?output=json&r=o&n=20", feed.ID), nil, &response)
+	if response.ID != fmt.Sprintf("feed/%d", feed.ID) || response.Title != feed.Title || response.Continuation != "" || len(response.Items) != 3 {
+		t.Fatalf(`Unexpected feed stream: %+v`, response)
+	}
+
+	item := response.Items[0]
+	if item.ID != fmt.Sprintf(EntryIDLong, entries[0].ID) || item.TimestampUsec != fmt.Sprintf("%d", entries[0].Date.UnixMicro()) || item.Origin.StreamID != fmt.Sprintf("feed/%d", feed.ID) {
+		t.Errorf(`Unexpected item: %+v`, item)
+	}
+
+	readState := fmt.Sprintf("user/%d/state/com.google/read", feed.UserID)
+	if !containsString(item.Categories, readState) || containsString(response.Items[1].Categories, readState) {
+		t.Errorf(`Only the first item should be read: %v, %v`, item.Categories, response.Items[1].Categories)
+	}
+
+	// The stream can also be given in the query string.
+	client.do(http.MethodGet, "/reader/api/0/stream/contents?output=json&s=user/-/state/com.google/starred", nil, &response)
+	if len(response.Items) != 0 {
+		t.Errorf(`Unexpected starred items: %+v`, response.Items)
+	}
+
+	for _, path := range []string{
+		"/reader/api/0/stream/contents/user/-/state/com.google/reading-list?output=json&c=invalid",
+		"/reader/api/0/stream/contents/user/-/state/com.google/like?output=json",
+	} {
+		if status, body := client.send(http.MethodGet, path, nil, nil, ""); status != http.StatusBadRequest {
+			t.Errorf(`Unexpected status code for %s: got %d: %s`, path, status, body)
+		}
+	}
+}
+
+func TestSubscriptionExportAndImport(t *testing.T) {
+	client, store, feed, _ := newTestClient(t)
+
+	export := client.do(http.MethodGet, "/reader/api/0/subscription/export", nil, nil)
+	if !bytes.Contains(export, []byte(feed.FeedURL)) {
+		t.Errorf(`The feed is missing from the export: %s`, export)
+	}
+
+	opml := `<?xml version="1.0" encoding="UTF-8"?>
+	<opml version="2.0">
+		<body>
+			<outline text="News">
+				<outline title="Other" text="Other" xmlUrl="https://example.com/feed.xml" htmlUrl="https://example.com/"></outline>
+			</outline>
+		</body>
+	</opml>`
+
+	status, body := client.send(http.MethodPost, "/reader/api/0/subscription/import", nil, strings.NewReader(opml), "text/xml")
+	if status != http.StatusOK {
+		t.Fatalf(`Unexpected status code: got %d: %s`, status, body)
+	}
+
+	if !store.FeedURLExists(feed.UserID, "https://example.com/feed.xml") {
+		t.Errorf(`The feed has not been imported`)
+	}
+
+	if status, _ := client.send(http.MethodPost, "/reader/api/0/subscription/import", nil, strings.NewReader("<opml"), "text/xml"); status != http.StatusBadRequest {
+		t.Errorf(`Unexpected status code for an invalid document: got %d`, status)
+	}
+
+	client.token = ""
+	if status, _ := client.send(http.MethodPost, "/reader/api/0/subscription/import", nil, strings.NewReader(opml), "text/xml"); status != http.StatusUnauthorized {
+		t.Errorf(`Unexpected status code without token: got %d`, status)
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
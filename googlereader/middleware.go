@@ -123,12 +123,16 @@ func (m *middleware) apiKeyAuth(next http.Handler) http.Handler {
 				return
 			}
 			token = r.Form.Get("T")
-			if token == "" {
+
+			// Requests with a body other than a form, like the OPML import, are authenticated with the header.
+			if token == "" && r.Header.Get("Authorization") == "" {
 				logger.Error("[GoogleReader][Auth] [ClientIP=%s] Post-Form T field is empty", clientIP)
 				Unauthorized(w, r)
 				return
 			}
-		} else {
+		}
+
+		if token == "" {
 			authorization := r.Header.Get("Authorization")
 
 			if authorization == "" {
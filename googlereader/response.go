@@ -70,14 +70,26 @@ type tagsResponse struct {
 }
 
 type streamContentItems struct {
-	Direction string            `json:"direction"`
-	ID        string            `json:"id"`
-	Title     string            `json:"title"`
-	Self      []contentHREF     `json:"self"`
-	Alternate []contentHREFType `json:"alternate"`
-	Updated   int64             `json:"updated"`
-	Items     []contentItem     `json:"items"`
-	Author    string            `json:"author"`
+	Direction    string            `json:"direction"`
+	ID           string            `json:"id"`
+	Title        string            `json:"title"`
+	Self         []contentHREF     `json:"self"`
+	Alternate    []contentHREFType `json:"alternate"`
+	Updated      int64             `json:"updated"`
+	Items        []contentItem     `json:"items"`
+	Author       string            `json:"author"`
+	Continuation string            `json:"continuation,omitempty"`
+}
+
+type unreadCount struct {
+	ID                      string `json:"id"`
+	Count                   int    `json:"count"`
+	NewestItemTimestampUsec string `json:"newestItemTimestampUsec"`
+}
+
+type unreadCountResponse struct {
+	Max          int           `json:"max"`
+	UnreadCounts []unreadCount `json:"unreadcounts"`
 }
 
 type contentItem struct {
@@ -26,6 +26,7 @@ const (
 	PocketRequestTokenContextKey
 	ClientIPContextKey
 	GoogleReaderToken
+	APIKeyCategoryIDsContextKey
 )
 
 // GoolgeReaderToken returns the google reader token if it exists.
@@ -115,6 +116,17 @@ func ClientIP(r *http.Request) string {
 	return getContextStringValue(r, ClientIPContextKey)
 }
 
+// APIKeyCategoryIDs returns the categories allowed by the API key, an empty list means all categories.
+func APIKeyCategoryIDs(r *http.Request) []int64 {
+	if v := r.Context().Value(APIKeyCategoryIDsContextKey); v != nil {
+		if value, valid := v.([]int64); valid {
+			return value
+		}
+	}
+
+	return nil
+}
+
 func getContextStringValue(r *http.Request, key ContextKey) string {
 	if v := r.Context().Value(key); v != nil {
 		value, valid := v.(string)
@@ -73,6 +73,49 @@ func (b *Builder) WithCaching(etag string, duration time.Duration, callback func
 	}
 }
 
+// WithValidators adds the ETag and Last-Modified headers to private resources that must be revalidated.
+// A 304 status code is sent when the client already has the current version, otherwise the callback builds the response.
+func (b *Builder) WithValidators(etag string, lastModified time.Time, callback func(*Builder)) {
+	b.headers["ETag"] = etag
+	b.headers["Last-Modified"] = lastModified.UTC().Format(http.TimeFormat)
+	b.headers["Cache-Control"] = "private, no-cache"
+
+	if b.isNotModified(etag, lastModified) {
+		b.statusCode = http.StatusNotModified
+		b.body = nil
+		b.Write()
+	} else {
+		callback(b)
+	}
+}
+
+// isNotModified evaluates the conditional headers of the request.
+// If-Modified-Since is only used when there is no ETag: HTTP dates have a one second precision,
+// a change made in the same second as the previous response would not be detected.
+func (b *Builder) isNotModified(etag string, lastModified time.Time) bool {
+	if b.r.Method != http.MethodGet && b.r.Method != http.MethodHead {
+		return false
+	}
+
+	if etag != "" {
+		ifNoneMatch := b.r.Header.Get("If-None-Match")
+		for _, candidate := range strings.Split(ifNoneMatch, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || (candidate != "" && strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/")) {
+				return true
+			}
+		}
+		return false
+	}
+
+	ifModifiedSince, err := http.ParseTime(b.r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+
+	return !lastModified.Truncate(time.Second).After(ifModifiedSince)
+}
+
 // Write generates the HTTP response.
 func (b *Builder) Write() {
 	if b.body == nil {
@@ -230,6 +230,125 @@ func TestBuildResponseWithCachingAndEtag(t *testing.T) {
 	}
 }
 
+func TestBuildResponseWithValidators(t *testing.T) {
+	lastModified := time.Date(2023, time.March, 1, 10, 30, 15, 500, time.UTC)
+
+	scenarios := []struct {
+		header         string
+		value          string
+		expectedStatus int
+	}{
+		{"", "", http.StatusOK},
+		{"If-None-Match", `W/"etag"`, http.StatusNotModified},
+		{"If-None-Match", `"etag"`, http.StatusNotModified},
+		{"If-None-Match", `"other", W/"etag"`, http.StatusNotModified},
+		{"If-None-Match", `*`, http.StatusNotModified},
+		{"If-None-Match", `W/"other"`, http.StatusOK},
+		{"If-Modified-Since", lastModified.Format(http.TimeFormat), http.StatusOK},
+		{"If-Modified-Since", lastModified.Add(time.Hour).Format(http.TimeFormat), http.StatusOK},
+	}
+
+	for _, scenario := range scenarios {
+		r, err := http.NewRequest("GET", "/", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if scenario.header != "" {
+			r.Header.Set(scenario.header, scenario.value)
+		}
+
+		w := httptest.NewRecorder()
+
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			New(w, r).WithValidators(`W/"etag"`, lastModified, func(b *Builder) {
+				b.WithBody("body")
+				b.Write()
+			})
+		})
+
+		handler.ServeHTTP(w, r)
+		resp := w.Result()
+
+		if resp.StatusCode != scenario.expectedStatus {
+			t.Errorf(`Unexpected status code for %s: %q, got %d instead of %d`, scenario.header, scenario.value, resp.StatusCode, scenario.expectedStatus)
+		}
+
+		if resp.StatusCode == http.StatusNotModified && w.Body.String() != "" {
+			t.Errorf(`The body of a 304 response should be empty`)
+		}
+
+		if etag := resp.Header.Get("ETag"); etag != `W/"etag"` {
+			t.Errorf(`Unexpected ETag header, got %q`, etag)
+		}
+
+		if date := resp.Header.Get("Last-Modified"); date != "Wed, 01 Mar 2023 10:30:15 GMT" {
+			t.Errorf(`Unexpected Last-Modified header, got %q`, date)
+		}
+
+		if cacheControl := resp.Header.Get("Cache-Control"); cacheControl != "private, no-cache" {
+			t.Errorf(`Unexpected Cache-Control header, got %q`, cacheControl)
+		}
+	}
+}
+
+func TestBuildResponseWithValidatorsIgnoresIfModifiedSinceWhenETagIsSent(t *testing.T) {
+	lastModified := time.Date(2023, time.March, 1, 10, 30, 15, 0, time.UTC)
+
+	r, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Header.Set("If-None-Match", `W/"previous"`)
+	r.Header.Set("If-Modified-Since", lastModified.Format(http.TimeFormat))
+
+	w := httptest.NewRecorder()
+	New(w, r).WithValidators(`W/"etag"`, lastModified, func(b *Builder) {
+		b.WithBody("body")
+		b.Write()
+	})
+
+	if w.Code != http.StatusOK {
+		t.Fatalf(`Unexpected status code, got %d instead of %d`, w.Code, http.StatusOK)
+	}
+}
+
+func TestBuildResponseWithValidatorsWithoutETag(t *testing.T) {
+	lastModified := time.Date(2023, time.March, 1, 10, 30, 15, 500, time.UTC)
+
+	scenarios := []struct {
+		ifModifiedSince string
+		expectedStatus  int
+	}{
+		{"", http.StatusOK},
+		{lastModified.Format(http.TimeFormat), http.StatusNotModified},
+		{lastModified.Add(time.Hour).Format(http.TimeFormat), http.StatusNotModified},
+		{lastModified.Add(-time.Second).Format(http.TimeFormat), http.StatusOK},
+		{"invalid date", http.StatusOK},
+	}
+
+	for _, scenario := range scenarios {
+		r, err := http.NewRequest("GET", "/", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if scenario.ifModifiedSince != "" {
+			r.Header.Set("If-Modified-Since", scenario.ifModifiedSince)
+		}
+
+		w := httptest.NewRecorder()
+		New(w, r).WithValidators("", lastModified, func(b *Builder) {
+			b.WithBody("body")
+			b.Write()
+		})
+
+		if w.Code != scenario.expectedStatus {
+			t.Errorf(`Unexpected status code for If-Modified-Since: %q, got %d instead of %d`, scenario.ifModifiedSince, w.Code, scenario.expectedStatus)
+		}
+	}
+}
+
 func TestBuildResponseWithGzipCompression(t *testing.T) {
 	body := strings.Repeat("a", compressionThreshold+1)
 	r, err := http.NewRequest("GET", "/", nil)
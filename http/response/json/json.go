@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"time"
 
 	"miniflux.app/http/response"
 	"miniflux.app/logger"
@@ -23,6 +24,22 @@ func OK(w http.ResponseWriter, r *http.Request, body interface{}) {
 	builder.Write()
 }
 
+// OKWithValidators sends a JSON response with ETag and Last-Modified headers.
+// The body is only generated when the client does not already have the current version.
+func OKWithValidators(w http.ResponseWriter, r *http.Request, etag string, lastModified time.Time, body func() (interface{}, error)) {
+	response.New(w, r).WithValidators(etag, lastModified, func(b *response.Builder) {
+		data, err := body()
+		if err != nil {
+			ServerError(w, r, err)
+			return
+		}
+
+		b.WithHeader("Content-Type", contentTypeHeader)
+		b.WithBody(toJSON(data))
+		b.Write()
+	})
+}
+
 // Created sends a created response to the client.
 func Created(w http.ResponseWriter, r *http.Request, body interface{}) {
 	builder := response.New(w, r)
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestOKResponse(t *testing.T) {
@@ -46,6 +47,64 @@ func TestOKResponse(t *testing.T) {
 	}
 }
 
+func TestOKWithValidatorsResponse(t *testing.T) {
+	lastModified := time.Date(2023, time.March, 1, 10, 30, 15, 0, time.UTC)
+
+	r, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	OKWithValidators(w, r, `W/"etag"`, lastModified, func() (interface{}, error) {
+		return map[string]string{"key": "value"}, nil
+	})
+
+	if w.Code != http.StatusOK {
+		t.Fatalf(`Unexpected status code, got %d instead of %d`, w.Code, http.StatusOK)
+	}
+
+	expectedBody := `{"key":"value"}`
+	if actualBody := w.Body.String(); actualBody != expectedBody {
+		t.Fatalf(`Unexpected body, got %q instead of %q`, actualBody, expectedBody)
+	}
+
+	if etag := w.Header().Get("ETag"); etag != `W/"etag"` {
+		t.Fatalf(`Unexpected ETag header, got %q`, etag)
+	}
+
+	r.Header.Set("If-None-Match", `W/"etag"`)
+	w = httptest.NewRecorder()
+	OKWithValidators(w, r, `W/"etag"`, lastModified, func() (interface{}, error) {
+		t.Fatal(`The body should not be generated when the resource is not modified`)
+		return nil, nil
+	})
+
+	if w.Code != http.StatusNotModified {
+		t.Fatalf(`Unexpected status code, got %d instead of %d`, w.Code, http.StatusNotModified)
+	}
+}
+
+func TestOKWithValidatorsResponseWithError(t *testing.T) {
+	r, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	OKWithValidators(w, r, `W/"etag"`, time.Now(), func() (interface{}, error) {
+		return nil, errors.New("Some Error")
+	})
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf(`Unexpected status code, got %d instead of %d`, w.Code, http.StatusInternalServerError)
+	}
+
+	if etag := w.Header().Get("ETag"); etag != "" {
+		t.Fatalf(`Error responses should not have an ETag header, got %q`, etag)
+	}
+}
+
 func TestCreatedResponse(t *testing.T) {
 	r, err := http.NewRequest("GET", "/", nil)
 	if err != nil {
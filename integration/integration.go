@@ -5,6 +5,9 @@
 package integration // import "miniflux.app/integration"
 
 import (
+	"html"
+	"strings"
+
 	"miniflux.app/config"
 	"miniflux.app/integration/espial"
 	"miniflux.app/integration/instapaper"
@@ -28,6 +31,7 @@ func SendEntry(entry *model.Entry, integration *model.Integration) {
 		err := client.AddBookmark(
 			entry.URL,
 			entry.Title,
+			entry.Annotations.Markdown(),
 			integration.PinboardTags,
 			integration.PinboardMarkAsUnread,
 		)
@@ -58,7 +62,7 @@ func SendEntry(entry *model.Entry, integration *model.Integration) {
 			integration.WallabagOnlyURL,
 		)
 
-		if err := client.AddEntry(entry.URL, entry.Title, entry.Content); err != nil {
+		if err := client.AddEntry(entry.URL, entry.Title, contentWithAnnotations(entry)); err != nil {
 			logger.Error("[Integration] UserID #%d: %v", integration.UserID, err)
 		}
 	}
@@ -71,7 +75,7 @@ func SendEntry(entry *model.Entry, integration *model.Integration) {
 			integration.NunuxKeeperAPIKey,
 		)
 
-		if err := client.AddEntry(entry.URL, entry.Title, entry.Content); err != nil {
+		if err := client.AddEntry(entry.URL, entry.Title, contentWithAnnotations(entry)); err != nil {
 			logger.Error("[Integration] UserID #%d: %v", integration.UserID, err)
 		}
 	}
@@ -92,6 +96,7 @@ func SendEntry(entry *model.Entry, integration *model.Integration) {
 	if integration.PocketEnabled {
 		logger.Debug("[Integration] Sending Entry #%d %q for User #%d to Pocket", entry.ID, entry.URL, integration.UserID)
 
+		// The Pocket API does not accept notes or highlights, the annotations are not exported.
 		client := pocket.NewClient(config.Opts.PocketConsumerKey(integration.PocketConsumerKey), integration.PocketAccessToken)
 		if err := client.AddURL(entry.URL, entry.Title); err != nil {
 			logger.Error("[Integration] UserID #%d: %v", integration.UserID, err)
@@ -105,7 +110,7 @@ func SendEntry(entry *model.Entry, integration *model.Integration) {
 			integration.LinkdingURL,
 			integration.LinkdingAPIKey,
 		)
-		if err := client.AddEntry(entry.Title, entry.URL); err != nil {
+		if err := client.AddEntry(entry.Title, entry.URL, entry.Annotations.Markdown()); err != nil {
 			logger.Error("[Integration] UserID #%d: %v", integration.UserID, err)
 		}
 	}
@@ -134,3 +139,26 @@ func PushEntry(entry *model.Entry, integration *model.Integration) {
 		}
 	}
 }
+
+// contentWithAnnotations appends the highlights and notes of the user to the entry content.
+func contentWithAnnotations(entry *model.Entry) string {
+	if len(entry.Annotations) == 0 {
+		return entry.Content
+	}
+
+	var builder strings.Builder
+	builder.WriteString(entry.Content)
+	builder.WriteString("<hr>")
+
+	for _, annotation := range entry.Annotations {
+		if annotation.Quote != "" {
+			builder.WriteString("<blockquote>" + html.EscapeString(annotation.Quote) + "</blockquote>")
+		}
+
+		if annotation.Note != "" {
+			builder.WriteString("<p>" + strings.ReplaceAll(html.EscapeString(annotation.Note), "\n", "<br>") + "</p>")
+		}
+	}
+
+	return builder.String()
+}
@@ -15,6 +15,7 @@ import (
 type Document struct {
 	Url   string `json:"url,omitempty"`
 	Title string `json:"title,omitempty"`
+	Notes string `json:"notes,omitempty"`
 }
 
 // Client represents an Linkding client.
@@ -29,7 +30,7 @@ func NewClient(baseURL, apiKey string) *Client {
 }
 
 // AddEntry sends an entry to Linkding.
-func (c *Client) AddEntry(title, url string) error {
+func (c *Client) AddEntry(title, url, notes string) error {
 	if c.baseURL == "" || c.apiKey == "" {
 		return fmt.Errorf("linkding: missing credentials")
 	}
@@ -37,6 +38,7 @@ func (c *Client) AddEntry(title, url string) error {
 	doc := &Document{
 		Url:   url,
 		Title: title,
+		Notes: notes,
 	}
 
 	apiURL, err := getAPIEndpoint(c.baseURL, "/api/bookmarks/")
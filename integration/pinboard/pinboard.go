@@ -22,7 +22,7 @@ func NewClient(authToken string) *Client {
 }
 
 // AddBookmark sends a link to Pinboard.
-func (c *Client) AddBookmark(link, title, tags string, markAsUnread bool) error {
+func (c *Client) AddBookmark(link, title, notes, tags string, markAsUnread bool) error {
 	if c.authToken == "" {
 		return fmt.Errorf("pinboard: missing credentials")
 	}
@@ -37,6 +37,9 @@ func (c *Client) AddBookmark(link, title, tags string, markAsUnread bool) error
 	values.Add("url", link)
 	values.Add("description", title)
 	values.Add("tags", tags)
+	if notes != "" {
+		values.Add("extended", notes)
+	}
 	values.Add("toread", toRead)
 
 	clt := client.New("https://api.pinboard.in/v1/posts/add?" + values.Encode())
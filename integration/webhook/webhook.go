@@ -0,0 +1,182 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package webhook // import "miniflux.app/integration/webhook"
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"miniflux.app/version"
+)
+
+const (
+	defaultClientTimeout = 10 * time.Second
+
+	// MaxAttempts is the number of attempts before a delivery is marked as failed.
+	MaxAttempts = 8
+
+	// retryBaseDelay is the delay before the first retry, it doubles after each attempt.
+	retryBaseDelay = time.Minute
+)
+
+var errPrivateAddress = errors.New("webhook: private and loopback addresses are not allowed")
+
+// Client represents a Webhook client.
+type Client struct {
+	webhookURL string
+	secret     string
+
+	// allowPrivateNetworks disables the address check, it is only used by the tests.
+	allowPrivateNetworks bool
+}
+
+// NewClient returns a new Webhook client.
+func NewClient(webhookURL, secret string) *Client {
+	return &Client{webhookURL: webhookURL, secret: secret}
+}
+
+// Send posts the JSON payload to the webhook, the payload is signed with the webhook secret.
+// The HTTP status code is returned when the server responded.
+func (c *Client) Send(deliveryID int64, eventType, payload string) (int, error) {
+	request, err := http.NewRequest(http.MethodPost, c.webhookURL, bytes.NewBufferString(payload))
+	if err != nil {
+		return 0, fmt.Errorf("webhook: unable to create request: %v", err)
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "Miniflux/"+version.Version)
+	request.Header.Set("X-Miniflux-Event-Type", eventType)
+	request.Header.Set("X-Miniflux-Delivery", strconv.FormatInt(deliveryID, 10))
+	request.Header.Set("X-Miniflux-Signature", Signature(c.secret, payload))
+
+	// The response body is never read: the URL is chosen by the user and the body could leak internal data.
+	response, err := c.httpClient().Do(request)
+	if err != nil {
+		return 0, fmt.Errorf("webhook: unable to send request: %v", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return response.StatusCode, fmt.Errorf("webhook: unexpected status code %d", response.StatusCode)
+	}
+
+	return response.StatusCode, nil
+}
+
+// httpClient returns a client which refuses to connect to private, loopback and link-local addresses.
+// The check is done on the resolved address when dialing, so DNS names and redirects are covered.
+// No proxy is used, otherwise the address of the endpoint would not be checked.
+func (c *Client) httpClient() *http.Client {
+	dialer := &net.Dialer{Timeout: defaultClientTimeout}
+	if !c.allowPrivateNetworks {
+		dialer.Control = func(network, address string, conn syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+
+			if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+				return errPrivateAddress
+			}
+
+			return nil
+		}
+	}
+
+	return &http.Client{
+		Timeout: defaultClientTimeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: defaultClientTimeout,
+		},
+	}
+}
+
+// ErrorMessage returns the error stored for a failed delivery and displayed to the user.
+// Only the status code is kept, the network errors would reveal which internal hosts and ports are reachable.
+func ErrorMessage(statusCode int) string {
+	if statusCode == 0 {
+		return "webhook: unable to send request"
+	}
+	return fmt.Sprintf("webhook: unexpected status code %d", statusCode)
+}
+
+// IsPublicURL returns false if the host of the URL is a private, loopback or link-local address.
+// Host names are resolved, a name which cannot be resolved is accepted since the address is checked again when sending.
+func IsPublicURL(webhookURL string) bool {
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return false
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if host == "" || host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		return isPublicIP(ip)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultClientTimeout)
+	defer cancel()
+
+	addresses, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return true
+	}
+
+	for _, address := range addresses {
+		if !isPublicIP(address.IP) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isPublicIP(ip net.IP) bool {
+	if ip4 := ip.To4(); ip4 != nil {
+		// 0.0.0.0/8 and the shared address space 100.64.0.0/10 are not covered by the net.IP methods.
+		if ip4[0] == 0 || (ip4[0] == 100 && ip4[1]&0xc0 == 64) {
+			return false
+		}
+	}
+
+	return !ip.IsLoopback() &&
+		!ip.IsPrivate() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() &&
+		!ip.IsUnspecified()
+}
+
+// Signature returns the hexadecimal HMAC-SHA256 of the payload.
+func Signature(secret, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// RetryDelay returns the delay before the next attempt, using an exponential backoff.
+func RetryDelay(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	return retryBaseDelay << (attempts - 1)
+}
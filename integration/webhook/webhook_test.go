@@ -0,0 +1,140 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package webhook // import "miniflux.app/integration/webhook"
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignature(t *testing.T) {
+	expected := "f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8"
+	if signature := Signature("key", "The quick brown fox jumps over the lazy dog"); signature != expected {
+		t.Errorf(`Unexpected signature, got %q instead of %q`, signature, expected)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	scenarios := map[int]time.Duration{
+		0: time.Minute,
+		1: time.Minute,
+		2: 2 * time.Minute,
+		3: 4 * time.Minute,
+		8: 128 * time.Minute,
+	}
+
+	for attempts, expected := range scenarios {
+		if delay := RetryDelay(attempts); delay != expected {
+			t.Errorf(`Unexpected delay for %d attempts, got %v instead of %v`, attempts, delay, expected)
+		}
+	}
+}
+
+func TestSend(t *testing.T) {
+	payload := `{"event_type":"entry_read"}`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != payload {
+			t.Errorf(`Unexpected body: %q`, body)
+		}
+
+		if r.Header.Get("X-Miniflux-Event-Type") != "entry_read" {
+			t.Errorf(`Unexpected event type header: %q`, r.Header.Get("X-Miniflux-Event-Type"))
+		}
+
+		if r.Header.Get("X-Miniflux-Delivery") != "42" {
+			t.Errorf(`Unexpected delivery header: %q`, r.Header.Get("X-Miniflux-Delivery"))
+		}
+
+		if r.Header.Get("X-Miniflux-Signature") != Signature("secret", payload) {
+			t.Errorf(`Unexpected signature header: %q`, r.Header.Get("X-Miniflux-Signature"))
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "secret")
+	client.allowPrivateNetworks = true
+
+	statusCode, err := client.Send(42, "entry_read", payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if statusCode != http.StatusNoContent {
+		t.Errorf(`Unexpected status code: %d`, statusCode)
+	}
+}
+
+func TestSendWithServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "secret")
+	client.allowPrivateNetworks = true
+
+	statusCode, err := client.Send(1, "entry_read", "{}")
+	if err == nil {
+		t.Fatal(`An error should be returned`)
+	}
+
+	if strings.Contains(err.Error(), "boom") {
+		t.Errorf(`The response body should not be returned: %v`, err)
+	}
+
+	if statusCode != http.StatusInternalServerError {
+		t.Errorf(`Unexpected status code: %d`, statusCode)
+	}
+}
+
+func TestSendToPrivateAddress(t *testing.T) {
+	requested := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = true
+	}))
+	defer server.Close()
+
+	statusCode, err := NewClient(server.URL, "secret").Send(1, "entry_read", "{}")
+	if err == nil || !strings.Contains(err.Error(), errPrivateAddress.Error()) {
+		t.Fatalf(`Sending to a loopback address should be refused, got %v`, err)
+	}
+
+	if statusCode != 0 || requested {
+		t.Error(`The request should not have been sent`)
+	}
+}
+
+func TestIsPublicURL(t *testing.T) {
+	scenarios := map[string]bool{
+		"https://93.184.216.34/hook":                    true,
+		"https://[2606:2800:220:1:248:1893:25c8:1946]/": true,
+		"http://localhost:8080/hook":                    false,
+		"http://app.localhost/hook":                     false,
+		"http://127.0.0.1/hook":                         false,
+		"http://10.0.0.1/hook":                          false,
+		"http://172.16.4.2/hook":                        false,
+		"http://192.168.1.1/hook":                       false,
+		"http://169.254.169.254/latest/meta-data/":      false,
+		"http://100.64.0.1/hook":                        false,
+		"http://0.0.0.0/hook":                           false,
+		"http://[::1]/hook":                             false,
+		"http://[fd00:ec2::254]/hook":                   false,
+		"http://[::ffff:127.0.0.1]/hook":                false,
+	}
+
+	for webhookURL, expected := range scenarios {
+		if result := IsPublicURL(webhookURL); result != expected {
+			t.Errorf(`Unexpected result for %q, got %v instead of %v`, webhookURL, result, expected)
+		}
+	}
+}
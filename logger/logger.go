@@ -7,11 +7,13 @@ package logger // import "miniflux.app/logger"
 import (
 	"fmt"
 	"os"
+	"sync/atomic"
 	"time"
 )
 
-var requestedLevel = InfoLevel
-var displayDateTime = false
+// The settings can be changed while the application is running when the configuration is reloaded.
+var requestedLevel = uint32(InfoLevel)
+var displayDateTime atomic.Bool
 
 // LogLevel type.
 type LogLevel uint32
@@ -47,39 +49,56 @@ func (level LogLevel) String() string {
 
 // EnableDateTime enables date time in log messages.
 func EnableDateTime() {
-	displayDateTime = true
+	displayDateTime.Store(true)
+}
+
+// DisableDateTime removes date time from log messages.
+func DisableDateTime() {
+	displayDateTime.Store(false)
 }
 
 // EnableDebug increases logging, more verbose (debug)
 func EnableDebug() {
-	requestedLevel = DebugLevel
-	formatMessage(InfoLevel, "Debug mode enabled")
+	if atomic.SwapUint32(&requestedLevel, uint32(DebugLevel)) != uint32(DebugLevel) {
+		formatMessage(InfoLevel, "Debug mode enabled")
+	}
+}
+
+// DisableDebug restores the default logging level.
+func DisableDebug() {
+	if atomic.SwapUint32(&requestedLevel, uint32(InfoLevel)) == uint32(DebugLevel) {
+		formatMessage(InfoLevel, "Debug mode disabled")
+	}
+}
+
+func isLevelEnabled(level LogLevel) bool {
+	return LogLevel(atomic.LoadUint32(&requestedLevel)) >= level
 }
 
 // Debug sends a debug log message.
 func Debug(format string, v ...interface{}) {
-	if requestedLevel >= DebugLevel {
+	if isLevelEnabled(DebugLevel) {
 		formatMessage(DebugLevel, format, v...)
 	}
 }
 
 // Info sends an info log message.
 func Info(format string, v ...interface{}) {
-	if requestedLevel >= InfoLevel {
+	if isLevelEnabled(InfoLevel) {
 		formatMessage(InfoLevel, format, v...)
 	}
 }
 
 // Error sends an error log message.
 func Error(format string, v ...interface{}) {
-	if requestedLevel >= ErrorLevel {
+	if isLevelEnabled(ErrorLevel) {
 		formatMessage(ErrorLevel, format, v...)
 	}
 }
 
 // Fatal sends a fatal log message and stop the execution of the program.
 func Fatal(format string, v ...interface{}) {
-	if requestedLevel >= FatalLevel {
+	if isLevelEnabled(FatalLevel) {
 		formatMessage(FatalLevel, format, v...)
 		os.Exit(1)
 	}
@@ -88,7 +107,7 @@ func Fatal(format string, v ...interface{}) {
 func formatMessage(level LogLevel, format string, v ...interface{}) {
 	var prefix string
 
-	if displayDateTime {
+	if displayDateTime.Load() {
 		prefix = fmt.Sprintf("[%s] [%s] ", time.Now().Format("2006-01-02T15:04:05"), level)
 	} else {
 		prefix = fmt.Sprintf("[%s] ", level)
@@ -5,9 +5,11 @@
 package metric // import "miniflux.app/metric"
 
 import (
+	"strconv"
 	"time"
 
 	"miniflux.app/logger"
+	"miniflux.app/model"
 	"miniflux.app/storage"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -134,16 +136,118 @@ var (
 			Help:      "The total number of connections closed due to SetConnMaxLifetime",
 		},
 	)
+
+	databaseSizeGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "miniflux",
+			Name:      "database_size_bytes",
+			Help:      "Disk space used by the database",
+		},
+	)
+
+	tableRowsGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "miniflux",
+			Name:      "table_rows",
+			Help:      "Number of rows by table",
+		},
+		[]string{"table"},
+	)
+
+	tableSizeGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "miniflux",
+			Name:      "table_size_bytes",
+			Help:      "Disk space used by table, without the indexes",
+		},
+		[]string{"table"},
+	)
+
+	indexSizeGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "miniflux",
+			Name:      "index_size_bytes",
+			Help:      "Disk space used by index",
+		},
+		[]string{"table", "index"},
+	)
+
+	userFeedsGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "miniflux",
+			Name:      "user_feeds",
+			Help:      "Number of feeds by user",
+		},
+		[]string{"username"},
+	)
+
+	userEntriesGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "miniflux",
+			Name:      "user_entries",
+			Help:      "Number of entries by user, including the removed entries",
+		},
+		[]string{"username"},
+	)
+
+	userRemovedEntriesGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "miniflux",
+			Name:      "user_removed_entries",
+			Help:      "Number of removed entries by user",
+		},
+		[]string{"username"},
+	)
+
+	userContentSizeGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "miniflux",
+			Name:      "user_content_size_bytes",
+			Help:      "Size of the entry titles and contents by user",
+		},
+		[]string{"username"},
+	)
+
+	feedEntriesGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "miniflux",
+			Name:      "feed_entries",
+			Help:      "Number of entries of the largest feeds, including the removed entries",
+		},
+		[]string{"feed_id", "username"},
+	)
+
+	feedRemovedEntriesGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "miniflux",
+			Name:      "feed_removed_entries",
+			Help:      "Number of removed entries of the largest feeds",
+		},
+		[]string{"feed_id", "username"},
+	)
+
+	feedContentSizeGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "miniflux",
+			Name:      "feed_content_size_bytes",
+			Help:      "Size of the entry titles and contents of the largest feeds",
+		},
+		[]string{"feed_id", "username"},
+	)
 )
 
+// storageFeedLimit is the number of feeds exported in the storage metrics, to keep the cardinality low.
+const storageFeedLimit = 50
+
 // Collector represents a metric collector.
 type Collector struct {
-	store           *storage.Storage
-	refreshInterval int
+	store                  *storage.Storage
+	refreshInterval        int
+	storageRefreshInterval int
 }
 
 // NewCollector initializes a new metric collector.
-func NewCollector(store *storage.Storage, refreshInterval int) *Collector {
+func NewCollector(store *storage.Storage, refreshInterval, storageRefreshInterval int) *Collector {
 	prometheus.MustRegister(BackgroundFeedRefreshDuration)
 	prometheus.MustRegister(ScraperRequestDuration)
 	prometheus.MustRegister(ArchiveEntriesDuration)
@@ -158,8 +262,19 @@ func NewCollector(store *storage.Storage, refreshInterval int) *Collector {
 	prometheus.MustRegister(dbConnectionsMaxIdleClosedGauge)
 	prometheus.MustRegister(dbConnectionsMaxIdleTimeClosedGauge)
 	prometheus.MustRegister(dbConnectionsMaxLifetimeClosedGauge)
+	prometheus.MustRegister(databaseSizeGauge)
+	prometheus.MustRegister(tableRowsGauge)
+	prometheus.MustRegister(tableSizeGauge)
+	prometheus.MustRegister(indexSizeGauge)
+	prometheus.MustRegister(userFeedsGauge)
+	prometheus.MustRegister(userEntriesGauge)
+	prometheus.MustRegister(userRemovedEntriesGauge)
+	prometheus.MustRegister(userContentSizeGauge)
+	prometheus.MustRegister(feedEntriesGauge)
+	prometheus.MustRegister(feedRemovedEntriesGauge)
+	prometheus.MustRegister(feedContentSizeGauge)
 
-	return &Collector{store, refreshInterval}
+	return &Collector{store, refreshInterval, storageRefreshInterval}
 }
 
 // GatherStorageMetrics polls the database to fetch metrics.
@@ -190,3 +305,62 @@ func (c *Collector) GatherStorageMetrics() {
 		dbConnectionsMaxLifetimeClosedGauge.Set(float64(dbStats.MaxLifetimeClosed))
 	}
 }
+
+// GatherStorageStatistics polls the database to fetch the disk usage by table, user and feed.
+// These queries scan the entries table, they run less often than the other metrics.
+func (c *Collector) GatherStorageStatistics() {
+	for {
+		logger.Debug("[Metric] Collecting storage statistics")
+
+		if statistics, err := c.store.StorageStatistics(storageFeedLimit); err != nil {
+			logger.Error("[Metric] %v", err)
+		} else {
+			setStorageStatistics(statistics)
+		}
+
+		time.Sleep(time.Duration(c.storageRefreshInterval) * time.Second)
+	}
+}
+
+func setStorageStatistics(statistics *model.StorageStatistics) {
+	databaseSizeGauge.Set(float64(statistics.DatabaseSize))
+
+	// Reset the vectors to remove the series of deleted objects.
+	for _, gauge := range []*prometheus.GaugeVec{
+		tableRowsGauge,
+		tableSizeGauge,
+		indexSizeGauge,
+		userFeedsGauge,
+		userEntriesGauge,
+		userRemovedEntriesGauge,
+		userContentSizeGauge,
+		feedEntriesGauge,
+		feedRemovedEntriesGauge,
+		feedContentSizeGauge,
+	} {
+		gauge.Reset()
+	}
+
+	for _, table := range statistics.Tables {
+		tableRowsGauge.WithLabelValues(table.Name).Set(float64(table.RowCount))
+		tableSizeGauge.WithLabelValues(table.Name).Set(float64(table.TableSize))
+	}
+
+	for _, index := range statistics.Indexes {
+		indexSizeGauge.WithLabelValues(index.TableName, index.Name).Set(float64(index.Size))
+	}
+
+	for _, user := range statistics.Users {
+		userFeedsGauge.WithLabelValues(user.Username).Set(float64(user.FeedCount))
+		userEntriesGauge.WithLabelValues(user.Username).Set(float64(user.EntryCount))
+		userRemovedEntriesGauge.WithLabelValues(user.Username).Set(float64(user.RemovedEntryCount))
+		userContentSizeGauge.WithLabelValues(user.Username).Set(float64(user.ContentSize))
+	}
+
+	for _, feed := range statistics.Feeds {
+		feedID := strconv.FormatInt(feed.FeedID, 10)
+		feedEntriesGauge.WithLabelValues(feedID, feed.Username).Set(float64(feed.EntryCount))
+		feedRemovedEntriesGauge.WithLabelValues(feedID, feed.Username).Set(float64(feed.RemovedEntryCount))
+		feedContentSizeGauge.WithLabelValues(feedID, feed.Username).Set(float64(feed.ContentSize))
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package model // import "miniflux.app/model"
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Annotation represents a highlight and/or a note attached to an entry.
+//
+// The offsets are character positions of the highlighted quote in the text content of the entry,
+// the quote itself is kept to anchor the highlight when the content changes.
+type Annotation struct {
+	ID          int64     `json:"id"`
+	UserID      int64     `json:"user_id"`
+	EntryID     int64     `json:"entry_id"`
+	Quote       string    `json:"quote"`
+	StartOffset int       `json:"start_offset"`
+	EndOffset   int       `json:"end_offset"`
+	Note        string    `json:"note"`
+	CreatedAt   time.Time `json:"created_at"`
+	ChangedAt   time.Time `json:"changed_at"`
+	EntryTitle  string    `json:"-"`
+	EntryURL    string    `json:"-"`
+}
+
+func (a *Annotation) String() string {
+	return fmt.Sprintf("ID=%d, UserID=%d, EntryID=%d", a.ID, a.UserID, a.EntryID)
+}
+
+// Markdown returns the annotation as a Markdown fragment.
+func (a *Annotation) Markdown() string {
+	var builder strings.Builder
+
+	if a.Quote != "" {
+		for _, line := range strings.Split(strings.TrimSpace(a.Quote), "\n") {
+			builder.WriteString("> " + strings.TrimSpace(line) + "\n")
+		}
+	}
+
+	if a.Note != "" {
+		if builder.Len() > 0 {
+			builder.WriteString("\n")
+		}
+		builder.WriteString(strings.TrimSpace(a.Note) + "\n")
+	}
+
+	return builder.String()
+}
+
+// AnnotationRequest represents the request to create an annotation.
+type AnnotationRequest struct {
+	Quote       string `json:"quote"`
+	StartOffset int    `json:"start_offset"`
+	EndOffset   int    `json:"end_offset"`
+	Note        string `json:"note"`
+}
+
+// AnnotationModificationRequest represents the request to update an annotation.
+type AnnotationModificationRequest struct {
+	Note *string `json:"note"`
+}
+
+// Patch updates annotation fields.
+func (a *AnnotationModificationRequest) Patch(annotation *Annotation) {
+	if a.Note != nil {
+		annotation.Note = *a.Note
+	}
+}
+
+// Annotations represents a list of annotations.
+type Annotations []*Annotation
+
+// Markdown returns the annotations as a Markdown document, annotations are separated by horizontal rules.
+func (a Annotations) Markdown() string {
+	fragments := make([]string, 0, len(a))
+	for _, annotation := range a {
+		if fragment := annotation.Markdown(); fragment != "" {
+			fragments = append(fragments, fragment)
+		}
+	}
+
+	return strings.Join(fragments, "\n---\n\n")
+}
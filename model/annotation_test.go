@@ -0,0 +1,26 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package model // import "miniflux.app/model"
+
+import "testing"
+
+func TestAnnotationsMarkdown(t *testing.T) {
+	annotations := Annotations{
+		{Quote: "First line\nSecond line", Note: "My note"},
+		{Note: " Only a note "},
+		{Quote: "Only a quote"},
+	}
+
+	expected := "> First line\n> Second line\n\nMy note\n\n---\n\nOnly a note\n\n---\n\n> Only a quote\n"
+	if result := annotations.Markdown(); result != expected {
+		t.Errorf(`Unexpected Markdown, got %q instead of %q`, result, expected)
+	}
+}
+
+func TestEmptyAnnotationsMarkdown(t *testing.T) {
+	if result := (Annotations{}).Markdown(); result != "" {
+		t.Errorf(`Unexpected Markdown, got %q`, result)
+	}
+}
@@ -10,14 +10,49 @@ import (
 	"miniflux.app/crypto"
 )
 
+// API key scopes.
+const (
+	// APIKeyScopeReadOnly only allows reading the content of the account.
+	APIKeyScopeReadOnly = "read_only"
+
+	// APIKeyScopeEntriesWrite allows changing the status, bookmarks, labels and annotations of entries.
+	APIKeyScopeEntriesWrite = "entries_write"
+
+	// APIKeyScopeFeedsAdmin allows managing feeds, categories and labels.
+	APIKeyScopeFeedsAdmin = "feeds_admin"
+)
+
+// APIKeyScopes returns the list of scopes that can be granted to an API key.
+func APIKeyScopes() []string {
+	return []string{
+		APIKeyScopeReadOnly,
+		APIKeyScopeEntriesWrite,
+		APIKeyScopeFeedsAdmin,
+	}
+}
+
+// IsValidAPIKeyScope returns true if the scope is supported.
+func IsValidAPIKeyScope(scope string) bool {
+	for _, supportedScope := range APIKeyScopes() {
+		if scope == supportedScope {
+			return true
+		}
+	}
+	return false
+}
+
 // APIKey represents an application API key.
+// The token is only returned by the API when the key is created.
+// A key without scopes has full access, a key with category IDs only sees the feeds of these categories.
 type APIKey struct {
-	ID          int64
-	UserID      int64
-	Token       string
-	Description string
-	LastUsedAt  *time.Time
-	CreatedAt   time.Time
+	ID          int64      `json:"id"`
+	UserID      int64      `json:"user_id"`
+	Token       string     `json:"token,omitempty"`
+	Description string     `json:"description"`
+	Scopes      []string   `json:"scopes"`
+	CategoryIDs []int64    `json:"category_ids"`
+	LastUsedAt  *time.Time `json:"last_used_at"`
+	CreatedAt   time.Time  `json:"created_at"`
 }
 
 // NewAPIKey initializes a new APIKey.
@@ -26,7 +61,49 @@ func NewAPIKey(userID int64, description string) *APIKey {
 		UserID:      userID,
 		Token:       crypto.GenerateRandomString(32),
 		Description: description,
+		Scopes:      make([]string, 0),
+		CategoryIDs: make([]int64, 0),
+	}
+}
+
+// IsRestricted returns true if the key has scopes or is limited to some categories.
+func (a *APIKey) IsRestricted() bool {
+	return len(a.Scopes) > 0 || len(a.CategoryIDs) > 0
+}
+
+// HasScope returns true if the key grants the given scope, keys without scopes grant every scope.
+func (a *APIKey) HasScope(scope string) bool {
+	if len(a.Scopes) == 0 {
+		return true
+	}
+
+	for _, s := range a.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsCategory returns true if the key gives access to the category.
+func (a *APIKey) AllowsCategory(categoryID int64) bool {
+	if len(a.CategoryIDs) == 0 {
+		return true
+	}
+
+	for _, id := range a.CategoryIDs {
+		if id == categoryID {
+			return true
+		}
 	}
+	return false
+}
+
+// APIKeyCreationRequest represents the request to create an API key.
+type APIKeyCreationRequest struct {
+	Description string   `json:"description"`
+	Scopes      []string `json:"scopes"`
+	CategoryIDs []int64  `json:"category_ids"`
 }
 
 // APIKeys represents a collection of API Key.
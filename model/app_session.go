@@ -30,13 +30,18 @@ func (s SessionData) String() string {
 // Value converts the session data to JSON.
 func (s SessionData) Value() (driver.Value, error) {
 	j, err := json.Marshal(s)
-	return j, err
+	return string(j), err
 }
 
 // Scan converts raw JSON data.
 func (s *SessionData) Scan(src interface{}) error {
-	source, ok := src.([]byte)
-	if !ok {
+	var source []byte
+	switch data := src.(type) {
+	case []byte:
+		source = data
+	case string:
+		source = []byte(data)
+	default:
 		return errors.New("session: unable to assert type of src")
 	}
 
@@ -0,0 +1,58 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package model // import "miniflux.app/model"
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidEntryCursor is returned when a pagination cursor cannot be decoded.
+var ErrInvalidEntryCursor = errors.New("Invalid pagination cursor")
+
+// EntryCursor represents the position of an entry in a list sorted by publication date and ID.
+type EntryCursor struct {
+	PublishedAt time.Time
+	EntryID     int64
+}
+
+// NewEntryCursor returns the cursor pointing after the given entry.
+func NewEntryCursor(entry *Entry) *EntryCursor {
+	return &EntryCursor{PublishedAt: entry.Date, EntryID: entry.ID}
+}
+
+// String returns the opaque representation of the cursor.
+func (c *EntryCursor) String() string {
+	value := strconv.FormatInt(c.PublishedAt.UnixMicro(), 10) + ":" + strconv.FormatInt(c.EntryID, 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(value))
+}
+
+// ParseEntryCursor decodes a cursor returned by EntryCursor.String().
+func ParseEntryCursor(value string) (*EntryCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidEntryCursor
+	}
+
+	timestamp, entryID, found := strings.Cut(string(data), ":")
+	if !found {
+		return nil, ErrInvalidEntryCursor
+	}
+
+	microseconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidEntryCursor
+	}
+
+	id, err := strconv.ParseInt(entryID, 10, 64)
+	if err != nil || id <= 0 {
+		return nil, ErrInvalidEntryCursor
+	}
+
+	return &EntryCursor{PublishedAt: time.UnixMicro(microseconds).UTC(), EntryID: id}, nil
+}
@@ -0,0 +1,36 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package model // import "miniflux.app/model"
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEntryCursor(t *testing.T) {
+	location, _ := time.LoadLocation("America/Montreal")
+	entry := &Entry{ID: 42, Date: time.Date(2023, 5, 4, 10, 30, 15, 123456789, location)}
+
+	cursor, err := ParseEntryCursor(NewEntryCursor(entry).String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cursor.EntryID != 42 {
+		t.Errorf(`Unexpected entry ID, got %d instead of 42`, cursor.EntryID)
+	}
+
+	if !cursor.PublishedAt.Equal(entry.Date.Truncate(time.Microsecond)) {
+		t.Errorf(`Unexpected publication date, got %v instead of %v`, cursor.PublishedAt, entry.Date)
+	}
+}
+
+func TestInvalidEntryCursor(t *testing.T) {
+	for _, value := range []string{"", "invalid!", "MTIzNDU", "YWJjOjQy", "MTIzOmFiYw", "MTIzOjA"} {
+		if _, err := ParseEntryCursor(value); err != ErrInvalidEntryCursor {
+			t.Errorf(`Cursor %q should be invalid, got %v`, value, err)
+		}
+	}
+}
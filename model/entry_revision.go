@@ -0,0 +1,78 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package model // import "miniflux.app/model"
+
+import (
+	"fmt"
+	"time"
+
+	"miniflux.app/diff"
+)
+
+// EntryRevision represents a previous version of an entry.
+type EntryRevision struct {
+	ID        int64     `json:"id"`
+	EntryID   int64     `json:"entry_id"`
+	Title     string    `json:"title"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (r *EntryRevision) String() string {
+	return fmt.Sprintf("ID=%d, EntryID=%d", r.ID, r.EntryID)
+}
+
+// EntryRevisions represents a list of revisions, sorted from the most recent to the oldest.
+type EntryRevisions []*EntryRevision
+
+// EntryRevisionDiff represents the changes between a revision and the version that replaced it.
+type EntryRevisionDiff struct {
+	RevisionID int64 `json:"revision_id"`
+
+	// NextRevisionID is zero when the revision was replaced by the current version of the entry.
+	NextRevisionID int64     `json:"next_revision_id"`
+	CreatedAt      time.Time `json:"created_at"`
+	Title          diff.Rows `json:"title"`
+	Content        diff.Rows `json:"content"`
+}
+
+// Diff compares the revision with the given title and content.
+func (r *EntryRevision) Diff(nextRevisionID int64, title, content string) *EntryRevisionDiff {
+	return &EntryRevisionDiff{
+		RevisionID:     r.ID,
+		NextRevisionID: nextRevisionID,
+		CreatedAt:      r.CreatedAt,
+		Title:          diff.Lines([]string{r.Title}, []string{title}),
+		Content:        diff.HTML(r.Content, content),
+	}
+}
+
+// Diffs compares each revision with the version that replaced it.
+func (r EntryRevisions) Diffs(entry *Entry) []*EntryRevisionDiff {
+	diffs := make([]*EntryRevisionDiff, 0, len(r))
+	for i, revision := range r {
+		diffs = append(diffs, r.diff(i, entry, revision))
+	}
+	return diffs
+}
+
+// RevisionDiff returns the changes introduced after the given revision.
+func (r EntryRevisions) RevisionDiff(entry *Entry, revisionID int64) *EntryRevisionDiff {
+	for i, revision := range r {
+		if revision.ID == revisionID {
+			return r.diff(i, entry, revision)
+		}
+	}
+	return nil
+}
+
+func (r EntryRevisions) diff(index int, entry *Entry, revision *EntryRevision) *EntryRevisionDiff {
+	if index == 0 {
+		return revision.Diff(0, entry.Title, entry.Content)
+	}
+
+	next := r[index-1]
+	return revision.Diff(next.ID, next.Title, next.Content)
+}
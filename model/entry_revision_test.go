@@ -0,0 +1,57 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package model // import "miniflux.app/model"
+
+import (
+	"testing"
+
+	"miniflux.app/diff"
+)
+
+func TestEntryRevisionsDiffs(t *testing.T) {
+	entry := &Entry{Title: "Title v3", Content: "<p>Version 3</p>"}
+	revisions := EntryRevisions{
+		{ID: 2, Title: "Title v2", Content: "<p>Version 2</p>"},
+		{ID: 1, Title: "Title v2", Content: "<p>Version 1</p>"},
+	}
+
+	diffs := revisions.Diffs(entry)
+	if len(diffs) != 2 {
+		t.Fatalf(`Unexpected number of diffs, got %d`, len(diffs))
+	}
+
+	if diffs[0].RevisionID != 2 || diffs[0].NextRevisionID != 0 {
+		t.Errorf(`The most recent revision should be compared with the entry, got %+v`, diffs[0])
+	}
+
+	if diffs[0].Content[0].Left != "Version 2" || diffs[0].Content[0].Right != "Version 3" {
+		t.Errorf(`Unexpected content diff: %+v`, diffs[0].Content[0])
+	}
+
+	if diffs[1].RevisionID != 1 || diffs[1].NextRevisionID != 2 {
+		t.Errorf(`Older revisions should be compared with the next one, got %+v`, diffs[1])
+	}
+
+	if diffs[1].Title.Changed() {
+		t.Errorf(`The title should not be changed: %+v`, diffs[1].Title[0])
+	}
+
+	if diffs[1].Content[0].Operation != diff.OperationReplace {
+		t.Errorf(`Unexpected content diff: %+v`, diffs[1].Content[0])
+	}
+}
+
+func TestEntryRevisionsRevisionDiff(t *testing.T) {
+	entry := &Entry{Title: "Title", Content: "New"}
+	revisions := EntryRevisions{{ID: 5, Title: "Title", Content: "Old"}}
+
+	if result := revisions.RevisionDiff(entry, 5); result == nil || result.RevisionID != 5 {
+		t.Errorf(`Unexpected diff: %+v`, result)
+	}
+
+	if result := revisions.RevisionDiff(entry, 6); result != nil {
+		t.Errorf(`Unknown revisions should not return a diff: %+v`, result)
+	}
+}
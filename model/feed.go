@@ -53,6 +53,7 @@ type Feed struct {
 	Entries                     Entries   `json:"entries,omitempty"`
 	Icon                        *FeedIcon `json:"icon"`
 	HideGlobally                bool      `json:"hide_globally"`
+	MarkUpdatedEntriesUnread    bool      `json:"mark_updated_entries_unread"`
 	UnreadCount                 int       `json:"-"`
 	ReadCount                   int       `json:"-"`
 }
@@ -62,6 +63,14 @@ type FeedCounters struct {
 	UnreadCounters map[int64]int `json:"unreads"`
 }
 
+// UnreadCounter represents the unread entries of a feed, a category or a label.
+type UnreadCounter struct {
+	ID              int64
+	Title           string
+	Count           int
+	NewestEntryDate time.Time
+}
+
 func (f *Feed) String() string {
 	return fmt.Sprintf("ID=%d, UserID=%d, FeedURL=%s, SiteURL=%s, Title=%s, Category={%s}",
 		f.ID,
@@ -166,6 +175,7 @@ type FeedModificationRequest struct {
 	AllowSelfSignedCertificates *bool   `json:"allow_self_signed_certificates"`
 	FetchViaProxy               *bool   `json:"fetch_via_proxy"`
 	HideGlobally                *bool   `json:"hide_globally"`
+	MarkUpdatedEntriesUnread    *bool   `json:"mark_updated_entries_unread"`
 }
 
 // Patch updates a feed with modified values.
@@ -245,6 +255,10 @@ func (f *FeedModificationRequest) Patch(feed *Feed) {
 	if f.HideGlobally != nil {
 		feed.HideGlobally = *f.HideGlobally
 	}
+
+	if f.MarkUpdatedEntriesUnread != nil {
+		feed.MarkUpdatedEntriesUnread = *f.MarkUpdatedEntriesUnread
+	}
 }
 
 // Feeds is a list of feed
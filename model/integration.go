@@ -4,48 +4,305 @@
 
 package model // import "miniflux.app/model"
 
+import (
+	"crypto/md5"
+	"fmt"
+)
+
 // Integration represents user integration settings.
+// Secrets are write-only and never serialized.
 type Integration struct {
-	UserID               int64
-	PinboardEnabled      bool
-	PinboardToken        string
-	PinboardTags         string
-	PinboardMarkAsUnread bool
-	InstapaperEnabled    bool
-	InstapaperUsername   string
-	InstapaperPassword   string
-	FeverEnabled         bool
-	FeverUsername        string
-	FeverToken           string
-	GoogleReaderEnabled  bool
-	GoogleReaderUsername string
-	GoogleReaderPassword string
-	WallabagEnabled      bool
-	WallabagOnlyURL      bool
-	WallabagURL          string
-	WallabagClientID     string
-	WallabagClientSecret string
-	WallabagUsername     string
-	WallabagPassword     string
-	NunuxKeeperEnabled   bool
-	NunuxKeeperURL       string
-	NunuxKeeperAPIKey    string
-	EspialEnabled        bool
-	EspialURL            string
-	EspialAPIKey         string
-	EspialTags           string
-	PocketEnabled        bool
-	PocketAccessToken    string
-	PocketConsumerKey    string
-	TelegramBotEnabled   bool
-	TelegramBotToken     string
-	TelegramBotChatID    string
-	LinkdingEnabled      bool
-	LinkdingURL          string
-	LinkdingAPIKey       string
-	MatrixBotEnabled     bool
-	MatrixBotUser        string
-	MatrixBotPassword    string
-	MatrixBotURL         string
-	MatrixBotChatID      string
+	UserID                int64  `json:"user_id"`
+	PinboardEnabled       bool   `json:"pinboard_enabled"`
+	PinboardToken         string `json:"-"`
+	PinboardTags          string `json:"pinboard_tags"`
+	PinboardMarkAsUnread  bool   `json:"pinboard_mark_as_unread"`
+	InstapaperEnabled     bool   `json:"instapaper_enabled"`
+	InstapaperUsername    string `json:"instapaper_username"`
+	InstapaperPassword    string `json:"-"`
+	FeverEnabled          bool   `json:"fever_enabled"`
+	FeverUsername         string `json:"fever_username"`
+	FeverToken            string `json:"-"`
+	GoogleReaderEnabled   bool   `json:"googlereader_enabled"`
+	GoogleReaderUsername  string `json:"googlereader_username"`
+	GoogleReaderPassword  string `json:"-"`
+	NextcloudNewsEnabled  bool   `json:"nextcloud_news_enabled"`
+	NextcloudNewsUsername string `json:"nextcloud_news_username"`
+	NextcloudNewsPassword string `json:"-"`
+	TTRSSEnabled          bool   `json:"ttrss_enabled"`
+	TTRSSUsername         string `json:"ttrss_username"`
+	TTRSSPassword         string `json:"-"`
+	WallabagEnabled       bool   `json:"wallabag_enabled"`
+	WallabagOnlyURL       bool   `json:"wallabag_only_url"`
+	WallabagURL           string `json:"wallabag_url"`
+	WallabagClientID      string `json:"wallabag_client_id"`
+	WallabagClientSecret  string `json:"-"`
+	WallabagUsername      string `json:"wallabag_username"`
+	WallabagPassword      string `json:"-"`
+	NunuxKeeperEnabled    bool   `json:"nunux_keeper_enabled"`
+	NunuxKeeperURL        string `json:"nunux_keeper_url"`
+	NunuxKeeperAPIKey     string `json:"-"`
+	EspialEnabled         bool   `json:"espial_enabled"`
+	EspialURL             string `json:"espial_url"`
+	EspialAPIKey          string `json:"-"`
+	EspialTags            string `json:"espial_tags"`
+	PocketEnabled         bool   `json:"pocket_enabled"`
+	PocketAccessToken     string `json:"-"`
+	PocketConsumerKey     string `json:"-"`
+	TelegramBotEnabled    bool   `json:"telegram_bot_enabled"`
+	TelegramBotToken      string `json:"-"`
+	TelegramBotChatID     string `json:"telegram_bot_chat_id"`
+	LinkdingEnabled       bool   `json:"linkding_enabled"`
+	LinkdingURL           string `json:"linkding_url"`
+	LinkdingAPIKey        string `json:"-"`
+	MatrixBotEnabled      bool   `json:"matrix_bot_enabled"`
+	MatrixBotUser         string `json:"matrix_bot_user"`
+	MatrixBotPassword     string `json:"-"`
+	MatrixBotURL          string `json:"matrix_bot_url"`
+	MatrixBotChatID       string `json:"matrix_bot_chat_id"`
+}
+
+// IntegrationModificationRequest represents the request to update the integration settings.
+type IntegrationModificationRequest struct {
+	PinboardEnabled       *bool   `json:"pinboard_enabled"`
+	PinboardToken         *string `json:"pinboard_token"`
+	PinboardTags          *string `json:"pinboard_tags"`
+	PinboardMarkAsUnread  *bool   `json:"pinboard_mark_as_unread"`
+	InstapaperEnabled     *bool   `json:"instapaper_enabled"`
+	InstapaperUsername    *string `json:"instapaper_username"`
+	InstapaperPassword    *string `json:"instapaper_password"`
+	FeverEnabled          *bool   `json:"fever_enabled"`
+	FeverUsername         *string `json:"fever_username"`
+	FeverPassword         *string `json:"fever_password"`
+	GoogleReaderEnabled   *bool   `json:"googlereader_enabled"`
+	GoogleReaderUsername  *string `json:"googlereader_username"`
+	GoogleReaderPassword  *string `json:"googlereader_password"`
+	NextcloudNewsEnabled  *bool   `json:"nextcloud_news_enabled"`
+	NextcloudNewsUsername *string `json:"nextcloud_news_username"`
+	NextcloudNewsPassword *string `json:"nextcloud_news_password"`
+	TTRSSEnabled          *bool   `json:"ttrss_enabled"`
+	TTRSSUsername         *string `json:"ttrss_username"`
+	TTRSSPassword         *string `json:"ttrss_password"`
+	WallabagEnabled       *bool   `json:"wallabag_enabled"`
+	WallabagOnlyURL       *bool   `json:"wallabag_only_url"`
+	WallabagURL           *string `json:"wallabag_url"`
+	WallabagClientID      *string `json:"wallabag_client_id"`
+	WallabagClientSecret  *string `json:"wallabag_client_secret"`
+	WallabagUsername      *string `json:"wallabag_username"`
+	WallabagPassword      *string `json:"wallabag_password"`
+	NunuxKeeperEnabled    *bool   `json:"nunux_keeper_enabled"`
+	NunuxKeeperURL        *string `json:"nunux_keeper_url"`
+	NunuxKeeperAPIKey     *string `json:"nunux_keeper_api_key"`
+	EspialEnabled         *bool   `json:"espial_enabled"`
+	EspialURL             *string `json:"espial_url"`
+	EspialAPIKey          *string `json:"espial_api_key"`
+	EspialTags            *string `json:"espial_tags"`
+	PocketEnabled         *bool   `json:"pocket_enabled"`
+	PocketAccessToken     *string `json:"pocket_access_token"`
+	PocketConsumerKey     *string `json:"pocket_consumer_key"`
+	TelegramBotEnabled    *bool   `json:"telegram_bot_enabled"`
+	TelegramBotToken      *string `json:"telegram_bot_token"`
+	TelegramBotChatID     *string `json:"telegram_bot_chat_id"`
+	LinkdingEnabled       *bool   `json:"linkding_enabled"`
+	LinkdingURL           *string `json:"linkding_url"`
+	LinkdingAPIKey        *string `json:"linkding_api_key"`
+	MatrixBotEnabled      *bool   `json:"matrix_bot_enabled"`
+	MatrixBotUser         *string `json:"matrix_bot_user"`
+	MatrixBotPassword     *string `json:"matrix_bot_password"`
+	MatrixBotURL          *string `json:"matrix_bot_url"`
+	MatrixBotChatID       *string `json:"matrix_bot_chat_id"`
+}
+
+// Patch updates the integration settings with the fields of the request which are set.
+func (i *IntegrationModificationRequest) Patch(integration *Integration) {
+	if i.PinboardEnabled != nil {
+		integration.PinboardEnabled = *i.PinboardEnabled
+	}
+
+	if i.PinboardToken != nil {
+		integration.PinboardToken = *i.PinboardToken
+	}
+
+	if i.PinboardTags != nil {
+		integration.PinboardTags = *i.PinboardTags
+	}
+
+	if i.PinboardMarkAsUnread != nil {
+		integration.PinboardMarkAsUnread = *i.PinboardMarkAsUnread
+	}
+
+	if i.InstapaperEnabled != nil {
+		integration.InstapaperEnabled = *i.InstapaperEnabled
+	}
+
+	if i.InstapaperUsername != nil {
+		integration.InstapaperUsername = *i.InstapaperUsername
+	}
+
+	if i.InstapaperPassword != nil {
+		integration.InstapaperPassword = *i.InstapaperPassword
+	}
+
+	if i.FeverEnabled != nil {
+		integration.FeverEnabled = *i.FeverEnabled
+	}
+
+	if i.FeverUsername != nil {
+		integration.FeverUsername = *i.FeverUsername
+	}
+
+	if i.GoogleReaderEnabled != nil {
+		integration.GoogleReaderEnabled = *i.GoogleReaderEnabled
+	}
+
+	if i.GoogleReaderUsername != nil {
+		integration.GoogleReaderUsername = *i.GoogleReaderUsername
+	}
+
+	if i.GoogleReaderPassword != nil {
+		integration.GoogleReaderPassword = *i.GoogleReaderPassword
+	}
+
+	if i.NextcloudNewsEnabled != nil {
+		integration.NextcloudNewsEnabled = *i.NextcloudNewsEnabled
+	}
+
+	if i.NextcloudNewsUsername != nil {
+		integration.NextcloudNewsUsername = *i.NextcloudNewsUsername
+	}
+
+	if i.NextcloudNewsPassword != nil {
+		integration.NextcloudNewsPassword = *i.NextcloudNewsPassword
+	}
+
+	if i.TTRSSEnabled != nil {
+		integration.TTRSSEnabled = *i.TTRSSEnabled
+	}
+
+	if i.TTRSSUsername != nil {
+		integration.TTRSSUsername = *i.TTRSSUsername
+	}
+
+	if i.TTRSSPassword != nil {
+		integration.TTRSSPassword = *i.TTRSSPassword
+	}
+
+	if i.WallabagEnabled != nil {
+		integration.WallabagEnabled = *i.WallabagEnabled
+	}
+
+	if i.WallabagOnlyURL != nil {
+		integration.WallabagOnlyURL = *i.WallabagOnlyURL
+	}
+
+	if i.WallabagURL != nil {
+		integration.WallabagURL = *i.WallabagURL
+	}
+
+	if i.WallabagClientID != nil {
+		integration.WallabagClientID = *i.WallabagClientID
+	}
+
+	if i.WallabagClientSecret != nil {
+		integration.WallabagClientSecret = *i.WallabagClientSecret
+	}
+
+	if i.WallabagUsername != nil {
+		integration.WallabagUsername = *i.WallabagUsername
+	}
+
+	if i.WallabagPassword != nil {
+		integration.WallabagPassword = *i.WallabagPassword
+	}
+
+	if i.NunuxKeeperEnabled != nil {
+		integration.NunuxKeeperEnabled = *i.NunuxKeeperEnabled
+	}
+
+	if i.NunuxKeeperURL != nil {
+		integration.NunuxKeeperURL = *i.NunuxKeeperURL
+	}
+
+	if i.NunuxKeeperAPIKey != nil {
+		integration.NunuxKeeperAPIKey = *i.NunuxKeeperAPIKey
+	}
+
+	if i.EspialEnabled != nil {
+		integration.EspialEnabled = *i.EspialEnabled
+	}
+
+	if i.EspialURL != nil {
+		integration.EspialURL = *i.EspialURL
+	}
+
+	if i.EspialAPIKey != nil {
+		integration.EspialAPIKey = *i.EspialAPIKey
+	}
+
+	if i.EspialTags != nil {
+		integration.EspialTags = *i.EspialTags
+	}
+
+	if i.PocketEnabled != nil {
+		integration.PocketEnabled = *i.PocketEnabled
+	}
+
+	if i.PocketAccessToken != nil {
+		integration.PocketAccessToken = *i.PocketAccessToken
+	}
+
+	if i.PocketConsumerKey != nil {
+		integration.PocketConsumerKey = *i.PocketConsumerKey
+	}
+
+	if i.TelegramBotEnabled != nil {
+		integration.TelegramBotEnabled = *i.TelegramBotEnabled
+	}
+
+	if i.TelegramBotToken != nil {
+		integration.TelegramBotToken = *i.TelegramBotToken
+	}
+
+	if i.TelegramBotChatID != nil {
+		integration.TelegramBotChatID = *i.TelegramBotChatID
+	}
+
+	if i.LinkdingEnabled != nil {
+		integration.LinkdingEnabled = *i.LinkdingEnabled
+	}
+
+	if i.LinkdingURL != nil {
+		integration.LinkdingURL = *i.LinkdingURL
+	}
+
+	if i.LinkdingAPIKey != nil {
+		integration.LinkdingAPIKey = *i.LinkdingAPIKey
+	}
+
+	if i.MatrixBotEnabled != nil {
+		integration.MatrixBotEnabled = *i.MatrixBotEnabled
+	}
+
+	if i.MatrixBotUser != nil {
+		integration.MatrixBotUser = *i.MatrixBotUser
+	}
+
+	if i.MatrixBotPassword != nil {
+		integration.MatrixBotPassword = *i.MatrixBotPassword
+	}
+
+	if i.MatrixBotURL != nil {
+		integration.MatrixBotURL = *i.MatrixBotURL
+	}
+
+	if i.MatrixBotChatID != nil {
+		integration.MatrixBotChatID = *i.MatrixBotChatID
+	}
+
+	if i.FeverPassword != nil {
+		integration.FeverToken = ""
+		if *i.FeverPassword != "" {
+			integration.FeverToken = fmt.Sprintf("%x", md5.Sum([]byte(integration.FeverUsername+":"+*i.FeverPassword)))
+		}
+	}
 }
@@ -0,0 +1,37 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package model // import "miniflux.app/model"
+
+import "fmt"
+
+// Label represents a user-defined label that can be attached to entries.
+type Label struct {
+	ID         int64  `json:"id"`
+	UserID     int64  `json:"user_id"`
+	Title      string `json:"title"`
+	EntryCount int    `json:"-"`
+}
+
+func (l *Label) String() string {
+	return fmt.Sprintf("ID=%d, UserID=%d, Title=%s", l.ID, l.UserID, l.Title)
+}
+
+// LabelRequest represents the request to create or update a label.
+type LabelRequest struct {
+	Title string `json:"title"`
+}
+
+// Patch updates label fields.
+func (lr *LabelRequest) Patch(label *Label) {
+	label.Title = lr.Title
+}
+
+// Labels represents a list of labels.
+type Labels []*Label
+
+// EntryLabelsRequest represents the request to replace the labels of an entry.
+type EntryLabelsRequest struct {
+	Labels []string `json:"labels"`
+}
@@ -0,0 +1,77 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package model // import "miniflux.app/model"
+
+import "time"
+
+// Database maintenance operations.
+const (
+	DatabaseVacuum  = "vacuum"
+	DatabaseAnalyze = "analyze"
+	DatabaseReindex = "reindex"
+)
+
+// StorageStatistics represents the disk usage of the database.
+type StorageStatistics struct {
+	DatabaseSize int64
+	Tables       []*TableStatistics
+	Indexes      []*IndexStatistics
+	Users        []*UserStorageStatistics
+	Feeds        []*FeedStorageStatistics
+}
+
+// TableStatistics represents the size of a database table.
+type TableStatistics struct {
+	Name      string
+	RowCount  int64
+	TableSize int64
+	IndexSize int64
+}
+
+// IndexStatistics represents the size of a database index.
+type IndexStatistics struct {
+	TableName string
+	Name      string
+	Size      int64
+}
+
+// UserStorageStatistics represents the rows and the content stored for a user.
+type UserStorageStatistics struct {
+	UserID            int64
+	Username          string
+	FeedCount         int64
+	EntryCount        int64
+	RemovedEntryCount int64
+	ContentSize       int64
+}
+
+// FeedStorageStatistics represents the rows and the content stored for a feed.
+type FeedStorageStatistics struct {
+	FeedID            int64
+	UserID            int64
+	Username          string
+	Title             string
+	EntryCount        int64
+	RemovedEntryCount int64
+	ContentSize       int64
+}
+
+// DatabaseMaintenanceStatus represents the maintenance operation running or the last one.
+type DatabaseMaintenanceStatus struct {
+	Operation  string
+	Running    bool
+	StartedAt  time.Time
+	FinishedAt time.Time
+	ErrorMsg   string
+}
+
+// IsValidDatabaseOperation returns true if the maintenance operation is supported.
+func IsValidDatabaseOperation(operation string) bool {
+	switch operation {
+	case DatabaseVacuum, DatabaseAnalyze, DatabaseReindex:
+		return true
+	}
+	return false
+}
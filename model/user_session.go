@@ -12,13 +12,15 @@ import (
 )
 
 // UserSession represents a user session in the system.
+// The token is the session cookie value and is never serialized.
 type UserSession struct {
-	ID        int64
-	UserID    int64
-	Token     string
-	CreatedAt time.Time
-	UserAgent string
-	IP        string
+	ID         int64      `json:"id"`
+	UserID     int64      `json:"user_id"`
+	Token      string     `json:"-"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	UserAgent  string     `json:"user_agent"`
+	IP         string     `json:"ip"`
 }
 
 func (u *UserSession) String() string {
@@ -28,6 +30,10 @@ func (u *UserSession) String() string {
 // UseTimezone converts creation date to the given timezone.
 func (u *UserSession) UseTimezone(tz string) {
 	u.CreatedAt = timezone.Convert(tz, u.CreatedAt)
+	if u.LastUsedAt != nil {
+		lastUsedAt := timezone.Convert(tz, *u.LastUsedAt)
+		u.LastUsedAt = &lastUsedAt
+	}
 }
 
 // UserSessions represents a list of sessions.
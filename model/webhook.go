@@ -0,0 +1,179 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package model // import "miniflux.app/model"
+
+import (
+	"time"
+
+	"miniflux.app/crypto"
+)
+
+// Webhook events.
+const (
+	WebhookEventEntryCreated = "entry_created"
+	WebhookEventEntryRead    = "entry_read"
+	WebhookEventEntryStarred = "entry_starred"
+	WebhookEventFeedError    = "feed_error"
+)
+
+// Webhook delivery statuses.
+const (
+	WebhookDeliveryStatusPending = "pending"
+	WebhookDeliveryStatusSuccess = "success"
+	WebhookDeliveryStatusFailed  = "failed"
+)
+
+// WebhookEvents returns the list of events that can be sent to a webhook.
+func WebhookEvents() []string {
+	return []string{
+		WebhookEventEntryCreated,
+		WebhookEventEntryRead,
+		WebhookEventEntryStarred,
+		WebhookEventFeedError,
+	}
+}
+
+// IsValidWebhookEvent returns true if the event is supported.
+func IsValidWebhookEvent(event string) bool {
+	for _, supportedEvent := range WebhookEvents() {
+		if event == supportedEvent {
+			return true
+		}
+	}
+	return false
+}
+
+// Webhook represents a user endpoint notified when events occur.
+type Webhook struct {
+	ID        int64
+	UserID    int64
+	URL       string
+	Secret    string
+	Events    []string
+	CreatedAt time.Time
+}
+
+// NewWebhook initializes a new Webhook, a random secret is generated if none is provided.
+func NewWebhook(userID int64, url, secret string, events []string) *Webhook {
+	if secret == "" {
+		secret = crypto.GenerateRandomStringHex(32)
+	}
+
+	return &Webhook{
+		UserID: userID,
+		URL:    url,
+		Secret: secret,
+		Events: events,
+	}
+}
+
+// HasEvent returns true if the webhook is subscribed to the given event.
+func (w *Webhook) HasEvent(event string) bool {
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Webhooks represents a list of webhooks.
+type Webhooks []*Webhook
+
+// WebhookDelivery represents an event queued for a webhook.
+type WebhookDelivery struct {
+	ID             int64
+	WebhookID      int64
+	Event          string
+	Payload        string
+	Status         string
+	Attempts       int
+	ResponseStatus int
+	ErrorMsg       string
+	NextAttemptAt  time.Time
+	LastAttemptAt  *time.Time
+	CreatedAt      time.Time
+	Webhook        *Webhook
+}
+
+// WebhookDeliveries represents a list of webhook deliveries.
+type WebhookDeliveries []*WebhookDelivery
+
+// WebhookEvent represents the JSON payload sent to webhooks.
+type WebhookEvent struct {
+	EventType string          `json:"event_type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Feed      *WebhookFeed    `json:"feed,omitempty"`
+	Entries   []*WebhookEntry `json:"entries,omitempty"`
+}
+
+// WebhookFeed represents a feed in webhook payloads.
+type WebhookFeed struct {
+	ID                int64     `json:"id"`
+	UserID            int64     `json:"user_id"`
+	FeedURL           string    `json:"feed_url"`
+	SiteURL           string    `json:"site_url"`
+	Title             string    `json:"title"`
+	CheckedAt         time.Time `json:"checked_at"`
+	ParsingErrorMsg   string    `json:"parsing_error_message,omitempty"`
+	ParsingErrorCount int       `json:"parsing_error_count,omitempty"`
+}
+
+// NewWebhookFeed returns the webhook representation of a feed.
+func NewWebhookFeed(feed *Feed) *WebhookFeed {
+	return &WebhookFeed{
+		ID:                feed.ID,
+		UserID:            feed.UserID,
+		FeedURL:           feed.FeedURL,
+		SiteURL:           feed.SiteURL,
+		Title:             feed.Title,
+		CheckedAt:         feed.CheckedAt,
+		ParsingErrorMsg:   feed.ParsingErrorMsg,
+		ParsingErrorCount: feed.ParsingErrorCount,
+	}
+}
+
+// WebhookEntry represents an entry in webhook payloads.
+// The content is only sent for new entries.
+type WebhookEntry struct {
+	ID          int64     `json:"id"`
+	UserID      int64     `json:"user_id"`
+	FeedID      int64     `json:"feed_id"`
+	Status      string    `json:"status"`
+	Hash        string    `json:"hash"`
+	Title       string    `json:"title"`
+	URL         string    `json:"url"`
+	CommentsURL string    `json:"comments_url"`
+	Date        time.Time `json:"published_at"`
+	CreatedAt   time.Time `json:"created_at"`
+	ChangedAt   time.Time `json:"changed_at"`
+	Content     string    `json:"content,omitempty"`
+	Author      string    `json:"author"`
+	Starred     bool      `json:"starred"`
+	ReadingTime int       `json:"reading_time"`
+	Tags        []string  `json:"tags"`
+}
+
+// NewWebhookEntry returns the webhook representation of an entry.
+func NewWebhookEntry(entry *Entry) *WebhookEntry {
+	return &WebhookEntry{
+		ID:          entry.ID,
+		UserID:      entry.UserID,
+		FeedID:      entry.FeedID,
+		Status:      entry.Status,
+		Hash:        entry.Hash,
+		Title:       entry.Title,
+		URL:         entry.URL,
+		CommentsURL: entry.CommentsURL,
+		Date:        entry.Date,
+		CreatedAt:   entry.CreatedAt,
+		ChangedAt:   entry.ChangedAt,
+		Content:     entry.Content,
+		Author:      entry.Author,
+		Starred:     entry.Starred,
+		ReadingTime: entry.ReadingTime,
+		Tags:        entry.Tags,
+	}
+}
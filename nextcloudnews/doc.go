@@ -0,0 +1,8 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+/*
+Package nextcloudnews implements Nextcloud News API endpoints (versions 1.2 and 1.3).
+*/
+package nextcloudnews // import "miniflux.app/nextcloudnews"
@@ -0,0 +1,740 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package nextcloudnews // import "miniflux.app/nextcloudnews"
+
+import (
+	json_parser "encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"miniflux.app/crypto"
+	"miniflux.app/http/request"
+	"miniflux.app/http/response/json"
+	"miniflux.app/logger"
+	"miniflux.app/model"
+	"miniflux.app/proxy"
+	feedHandler "miniflux.app/reader/handler"
+	"miniflux.app/storage"
+
+	"github.com/gorilla/mux"
+)
+
+// Clients enable some features according to the version of the News app.
+const newsAppVersion = "18.0.0"
+
+// Types of the item queries.
+const (
+	itemTypeFeed = iota
+	itemTypeFolder
+	itemTypeStarred
+	itemTypeAll
+)
+
+type handler struct {
+	store  *storage.Storage
+	router *mux.Router
+}
+
+// Serve handles Nextcloud News API calls.
+// Clients append the API path to the server URL, with or without the front controller.
+func Serve(router *mux.Router, store *storage.Storage) {
+	handler := &handler{store, router}
+	middleware := newMiddleware(store)
+
+	for _, prefix := range []string{"/index.php/apps/news/api", "/apps/news/api"} {
+		router.HandleFunc(prefix, handler.apiLevels).Methods(http.MethodGet)
+
+		for _, apiLevel := range []string{"v1-2", "v1-3"} {
+			// Version 1.2 uses PUT to change the state of the items, version 1.3 uses POST.
+			sr := router.PathPrefix(prefix + "/" + apiLevel).Subrouter()
+			sr.Use(middleware.basicAuth)
+			sr.HandleFunc("/version", handler.version).Methods(http.MethodGet)
+			sr.HandleFunc("/status", handler.status).Methods(http.MethodGet)
+			sr.HandleFunc("/user", handler.user).Methods(http.MethodGet)
+			sr.HandleFunc("/folders", handler.folders).Methods(http.MethodGet)
+			sr.HandleFunc("/folders", handler.createFolder).Methods(http.MethodPost)
+			sr.HandleFunc("/folders/{folderID:[0-9]+}", handler.renameFolder).Methods(http.MethodPut)
+			sr.HandleFunc("/folders/{folderID:[0-9]+}", handler.removeFolder).Methods(http.MethodDelete)
+			sr.HandleFunc("/folders/{folderID:[0-9]+}/read", handler.markFolderAsRead).Methods(http.MethodPut, http.MethodPost)
+			sr.HandleFunc("/feeds", handler.feeds).Methods(http.MethodGet)
+			sr.HandleFunc("/feeds", handler.createFeed).Methods(http.MethodPost)
+			sr.HandleFunc("/feeds/{feedID:[0-9]+}", handler.removeFeed).Methods(http.MethodDelete)
+			sr.HandleFunc("/feeds/{feedID:[0-9]+}/move", handler.moveFeed).Methods(http.MethodPut, http.MethodPost)
+			sr.HandleFunc("/feeds/{feedID:[0-9]+}/rename", handler.renameFeed).Methods(http.MethodPut, http.MethodPost)
+			sr.HandleFunc("/feeds/{feedID:[0-9]+}/read", handler.markFeedAsRead).Methods(http.MethodPut, http.MethodPost)
+			sr.HandleFunc("/items", handler.items).Methods(http.MethodGet)
+			sr.HandleFunc("/items/updated", handler.updatedItems).Methods(http.MethodGet)
+			sr.HandleFunc("/items/read", handler.markAllAsRead).Methods(http.MethodPut, http.MethodPost)
+			sr.HandleFunc("/items/read/multiple", handler.markItems(model.EntryStatusRead)).Methods(http.MethodPut, http.MethodPost)
+			sr.HandleFunc("/items/unread/multiple", handler.markItems(model.EntryStatusUnread)).Methods(http.MethodPut, http.MethodPost)
+			sr.HandleFunc("/items/star/multiple", handler.starItems(true)).Methods(http.MethodPut, http.MethodPost)
+			sr.HandleFunc("/items/unstar/multiple", handler.starItems(false)).Methods(http.MethodPut, http.MethodPost)
+			sr.HandleFunc("/items/{itemID:[0-9]+}/read", handler.markItem(model.EntryStatusRead)).Methods(http.MethodPut, http.MethodPost)
+			sr.HandleFunc("/items/{itemID:[0-9]+}/unread", handler.markItem(model.EntryStatusUnread)).Methods(http.MethodPut, http.MethodPost)
+			sr.HandleFunc("/items/{itemID:[0-9]+}/star", handler.starItem(true)).Methods(http.MethodPut, http.MethodPost)
+			sr.HandleFunc("/items/{itemID:[0-9]+}/unstar", handler.starItem(false)).Methods(http.MethodPut, http.MethodPost)
+			sr.HandleFunc("/items/{feedID:[0-9]+}/{guidHash}/star", handler.starItem(true)).Methods(http.MethodPut, http.MethodPost)
+			sr.HandleFunc("/items/{feedID:[0-9]+}/{guidHash}/unstar", handler.starItem(false)).Methods(http.MethodPut, http.MethodPost)
+		}
+	}
+}
+
+func (h *handler) apiLevels(w http.ResponseWriter, r *http.Request) {
+	json.OK(w, r, apiLevelsResponse{APILevels: []string{"v1-2", "v1-3"}})
+}
+
+func (h *handler) version(w http.ResponseWriter, r *http.Request) {
+	json.OK(w, r, versionResponse{Version: newsAppVersion})
+}
+
+func (h *handler) status(w http.ResponseWriter, r *http.Request) {
+	json.OK(w, r, statusResponse{Version: newsAppVersion})
+}
+
+func (h *handler) user(w http.ResponseWriter, r *http.Request) {
+	user, err := h.store.UserByID(request.UserID(r))
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	integration, err := h.store.Integration(user.ID)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	result := userResponse{UserID: integration.NextcloudNewsUsername, DisplayName: user.Username}
+	if user.LastLoginAt != nil {
+		result.LastLoginTimestamp = user.LastLoginAt.Unix()
+	}
+
+	json.OK(w, r, result)
+}
+
+func (h *handler) folders(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	logger.Debug("[NextcloudNews] Fetching folders for user #%d", userID)
+
+	categories, err := h.store.Categories(userID)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	result := foldersResponse{Folders: make([]folder, 0, len(categories))}
+	for _, category := range categories {
+		result.Folders = append(result.Folders, folder{ID: category.ID, Name: category.Title})
+	}
+
+	json.OK(w, r, result)
+}
+
+func (h *handler) createFolder(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+
+	var folderRequest struct {
+		Name string `json:"name"`
+	}
+	if err := json_parser.NewDecoder(r.Body).Decode(&folderRequest); err != nil {
+		json.BadRequest(w, r, err)
+		return
+	}
+
+	if folderRequest.Name == "" {
+		UnprocessableEntity(w, r, errors.New("The folder name is required"))
+		return
+	}
+
+	if h.store.CategoryTitleExists(userID, folderRequest.Name) {
+		Conflict(w, r, errors.New("This folder already exists"))
+		return
+	}
+
+	category, err := h.store.CreateCategory(userID, &model.CategoryRequest{Title: folderRequest.Name})
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.OK(w, r, foldersResponse{Folders: []folder{{ID: category.ID, Name: category.Title}}})
+}
+
+func (h *handler) renameFolder(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+
+	category, err := h.store.Category(userID, request.RouteInt64Param(r, "folderID"))
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	if category == nil {
+		json.NotFound(w, r)
+		return
+	}
+
+	var folderRequest struct {
+		Name string `json:"name"`
+	}
+	if err := json_parser.NewDecoder(r.Body).Decode(&folderRequest); err != nil {
+		json.BadRequest(w, r, err)
+		return
+	}
+
+	if folderRequest.Name == "" {
+		UnprocessableEntity(w, r, errors.New("The folder name is required"))
+		return
+	}
+
+	if h.store.AnotherCategoryExists(userID, category.ID, folderRequest.Name) {
+		Conflict(w, r, errors.New("This folder already exists"))
+		return
+	}
+
+	category.Title = folderRequest.Name
+	if err := h.store.UpdateCategory(category); err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	OK(w, r)
+}
+
+func (h *handler) removeFolder(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	categoryID := request.RouteInt64Param(r, "folderID")
+
+	if !h.store.CategoryIDExists(userID, categoryID) {
+		json.NotFound(w, r)
+		return
+	}
+
+	if err := h.store.RemoveCategory(userID, categoryID); err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	OK(w, r)
+}
+
+func (h *handler) markFolderAsRead(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	categoryID := request.RouteInt64Param(r, "folderID")
+
+	if !h.store.CategoryIDExists(userID, categoryID) {
+		json.NotFound(w, r)
+		return
+	}
+
+	builder := h.store.NewEntryQueryBuilder(userID)
+	builder.WithCategoryID(categoryID)
+	h.markAsRead(w, r, builder)
+}
+
+func (h *handler) feeds(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	logger.Debug("[NextcloudNews] Fetching feeds for user #%d", userID)
+
+	feeds, err := h.store.FeedsWithCounters(userID)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	builder := h.store.NewEntryQueryBuilder(userID)
+	builder.WithoutStatus(model.EntryStatusRemoved)
+	builder.WithStarred(true)
+	starredCount, err := builder.CountEntries()
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	newestItemID, err := h.newestItemID(userID)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	result := feedsResponse{Feeds: make([]feed, 0, len(feeds)), StarredCount: starredCount, NewestItemID: newestItemID}
+	for _, f := range feeds {
+		result.Feeds = append(result.Feeds, newFeed(f))
+	}
+
+	json.OK(w, r, result)
+}
+
+func (h *handler) createFeed(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+
+	var feedRequest struct {
+		URL      string `json:"url"`
+		FolderID int64  `json:"folderId"`
+	}
+	if err := json_parser.NewDecoder(r.Body).Decode(&feedRequest); err != nil {
+		json.BadRequest(w, r, err)
+		return
+	}
+
+	if feedRequest.URL == "" {
+		UnprocessableEntity(w, r, errors.New("The feed URL is required"))
+		return
+	}
+
+	if h.store.FeedURLExists(userID, feedRequest.URL) {
+		Conflict(w, r, errors.New("This feed already exists"))
+		return
+	}
+
+	// The root folder of the News app does not exist, the feed is added to the first category.
+	categoryID := feedRequest.FolderID
+	if categoryID == 0 {
+		category, err := h.store.FirstCategory(userID)
+		if err != nil {
+			json.ServerError(w, r, err)
+			return
+		}
+		categoryID = category.ID
+	} else if !h.store.CategoryIDExists(userID, categoryID) {
+		UnprocessableEntity(w, r, errors.New("This folder does not exist"))
+		return
+	}
+
+	created, err := feedHandler.CreateFeed(h.store, userID, &model.FeedCreationRequest{FeedURL: feedRequest.URL, CategoryID: categoryID})
+	if err != nil {
+		UnprocessableEntity(w, r, err)
+		return
+	}
+
+	newestItemID, err := h.newestItemID(userID)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.OK(w, r, feedsResponse{Feeds: []feed{newFeed(created)}, NewestItemID: newestItemID})
+}
+
+func (h *handler) removeFeed(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	feedID := request.RouteInt64Param(r, "feedID")
+
+	if !h.store.FeedExists(userID, feedID) {
+		json.NotFound(w, r)
+		return
+	}
+
+	if err := h.store.RemoveFeed(userID, feedID); err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	OK(w, r)
+}
+
+func (h *handler) moveFeed(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+
+	f, err := h.store.FeedByID(userID, request.RouteInt64Param(r, "feedID"))
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	if f == nil {
+		json.NotFound(w, r)
+		return
+	}
+
+	var moveRequest struct {
+		FolderID int64 `json:"folderId"`
+	}
+	if err := json_parser.NewDecoder(r.Body).Decode(&moveRequest); err != nil {
+		json.BadRequest(w, r, err)
+		return
+	}
+
+	category, err := h.store.Category(userID, moveRequest.FolderID)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	if category == nil {
+		UnprocessableEntity(w, r, errors.New("This folder does not exist"))
+		return
+	}
+
+	f.Category = category
+	if err := h.store.UpdateFeed(f); err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	OK(w, r)
+}
+
+func (h *handler) renameFeed(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+
+	f, err := h.store.FeedByID(userID, request.RouteInt64Param(r, "feedID"))
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	if f == nil {
+		json.NotFound(w, r)
+		return
+	}
+
+	var renameRequest struct {
+		FeedTitle string `json:"feedTitle"`
+	}
+	if err := json_parser.NewDecoder(r.Body).Decode(&renameRequest); err != nil {
+		json.BadRequest(w, r, err)
+		return
+	}
+
+	if renameRequest.FeedTitle == "" {
+		UnprocessableEntity(w, r, errors.New("The feed title is required"))
+		return
+	}
+
+	f.Title = renameRequest.FeedTitle
+	if err := h.store.UpdateFeed(f); err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	OK(w, r)
+}
+
+func (h *handler) markFeedAsRead(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	feedID := request.RouteInt64Param(r, "feedID")
+
+	if !h.store.FeedExists(userID, feedID) {
+		json.NotFound(w, r)
+		return
+	}
+
+	builder := h.store.NewEntryQueryBuilder(userID)
+	builder.WithFeedID(feedID)
+	h.markAsRead(w, r, builder)
+}
+
+func (h *handler) markAllAsRead(w http.ResponseWriter, r *http.Request) {
+	h.markAsRead(w, r, h.store.NewEntryQueryBuilder(request.UserID(r)))
+}
+
+// markAsRead marks as read the unread entries matched by the builder up to the newest item known by the client,
+// the items received after the last synchronization stay unread.
+func (h *handler) markAsRead(w http.ResponseWriter, r *http.Request, builder *storage.EntryQueryBuilder) {
+	var markRequest struct {
+		NewestItemID int64 `json:"newestItemId"`
+	}
+	if err := json_parser.NewDecoder(r.Body).Decode(&markRequest); err != nil {
+		json.BadRequest(w, r, err)
+		return
+	}
+
+	if markRequest.NewestItemID <= 0 {
+		UnprocessableEntity(w, r, errors.New("The newest item ID is required"))
+		return
+	}
+
+	builder.WithStatus(model.EntryStatusUnread)
+	builder.BeforeEntryID(markRequest.NewestItemID + 1)
+	entryIDs, err := builder.GetEntryIDs()
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	if len(entryIDs) > 0 {
+		if err := h.store.SetEntriesStatus(request.UserID(r), entryIDs, model.EntryStatusRead); err != nil {
+			json.ServerError(w, r, err)
+			return
+		}
+	}
+
+	OK(w, r)
+}
+
+func (h *handler) items(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	builder := h.store.NewEntryQueryBuilder(userID)
+	if err := withItemType(builder, r); err != nil {
+		UnprocessableEntity(w, r, err)
+		return
+	}
+
+	if request.QueryStringParam(r, "getRead", "true") == "false" {
+		builder.WithStatus(model.EntryStatusUnread)
+	}
+
+	// The offset is the ID of the last item received by the client, the next batch starts after it.
+	offset := request.QueryInt64Param(r, "offset", 0)
+	if request.QueryStringParam(r, "oldestFirst", "false") == "true" {
+		builder.AfterEntryID(offset)
+		builder.WithDirection("asc")
+	} else {
+		builder.BeforeEntryID(offset)
+		builder.WithDirection("desc")
+	}
+
+	builder.WithOrder("e.id")
+	builder.WithLimit(request.QueryIntParam(r, "batchSize", -1))
+	h.sendItems(w, r, builder)
+}
+
+func (h *handler) updatedItems(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	lastModified := request.QueryInt64Param(r, "lastModified", 0)
+	if lastModified <= 0 {
+		UnprocessableEntity(w, r, errors.New("The lastModified parameter is required"))
+		return
+	}
+
+	builder := h.store.NewEntryQueryBuilder(userID)
+	if err := withItemType(builder, r); err != nil {
+		UnprocessableEntity(w, r, err)
+		return
+	}
+
+	builder.ChangedSince(lastModifiedTime(lastModified))
+	builder.WithOrder("e.id")
+	builder.WithDirection("desc")
+	h.sendItems(w, r, builder)
+}
+
+// lastModifiedTime converts the lastModified parameter to a date.
+// Recent clients send microseconds like the News app stores them, older clients send seconds.
+func lastModifiedTime(lastModified int64) time.Time {
+	if lastModified > 1e12 {
+		return time.UnixMicro(lastModified)
+	}
+	return time.Unix(lastModified, 0)
+}
+
+// withItemType filters the entries according to the type and the id parameters of the query.
+func withItemType(builder *storage.EntryQueryBuilder, r *http.Request) error {
+	builder.WithoutStatus(model.EntryStatusRemoved)
+
+	id := request.QueryInt64Param(r, "id", 0)
+	switch request.QueryIntParam(r, "type", itemTypeAll) {
+	case itemTypeFeed:
+		builder.WithFeedID(id)
+	case itemTypeFolder:
+		builder.WithCategoryID(id)
+	case itemTypeStarred:
+		builder.WithStarred(true)
+	case itemTypeAll:
+	default:
+		return errors.New("Unsupported item type")
+	}
+	return nil
+}
+
+func (h *handler) sendItems(w http.ResponseWriter, r *http.Request, builder *storage.EntryQueryBuilder) {
+	entries, err := builder.GetEntries()
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	result := itemsResponse{Items: make([]item, 0, len(entries))}
+	for _, entry := range entries {
+		result.Items = append(result.Items, h.newItem(r, entry))
+	}
+
+	json.OK(w, r, result)
+}
+
+func (h *handler) markItem(status string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := request.UserID(r)
+		entryID := request.RouteInt64Param(r, "itemID")
+
+		if !h.store.EntryExists(userID, entryID) {
+			json.NotFound(w, r)
+			return
+		}
+
+		if err := h.store.SetEntriesStatus(userID, []int64{entryID}, status); err != nil {
+			json.ServerError(w, r, err)
+			return
+		}
+
+		OK(w, r)
+	}
+}
+
+func (h *handler) markItems(status string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := request.UserID(r)
+		entryIDs, err := h.itemIDs(r)
+		if err != nil {
+			json.BadRequest(w, r, err)
+			return
+		}
+
+		if len(entryIDs) > 0 {
+			if err := h.store.SetEntriesStatus(userID, entryIDs, status); err != nil {
+				json.ServerError(w, r, err)
+				return
+			}
+		}
+
+		OK(w, r)
+	}
+}
+
+// starItem changes the starred flag of an item identified by its ID (version 1.3) or by its feed and its hash (version 1.2).
+func (h *handler) starItem(starred bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := request.UserID(r)
+
+		var entryID int64
+		if guidHash := request.RouteStringParam(r, "guidHash"); guidHash != "" {
+			entryID = h.store.EntryIDByHash(userID, request.RouteInt64Param(r, "feedID"), guidHash)
+		} else if itemID := request.RouteInt64Param(r, "itemID"); h.store.EntryExists(userID, itemID) {
+			entryID = itemID
+		}
+
+		if entryID == 0 {
+			json.NotFound(w, r)
+			return
+		}
+
+		if err := h.store.SetEntriesBookmarkedState(userID, []int64{entryID}, starred); err != nil {
+			json.ServerError(w, r, err)
+			return
+		}
+
+		OK(w, r)
+	}
+}
+
+func (h *handler) starItems(starred bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := request.UserID(r)
+		entryIDs, err := h.itemIDs(r)
+		if err != nil {
+			json.BadRequest(w, r, err)
+			return
+		}
+
+		if len(entryIDs) > 0 {
+			if err := h.store.SetEntriesBookmarkedState(userID, entryIDs, starred); err != nil {
+				json.ServerError(w, r, err)
+				return
+			}
+		}
+
+		OK(w, r)
+	}
+}
+
+// itemIDs decodes the items of the requests changing several items at once.
+// Version 1.3 sends the IDs in "itemIds", version 1.2 sends the IDs in "items",
+// or the feeds and the hashes of the items to star.
+func (h *handler) itemIDs(r *http.Request) ([]int64, error) {
+	var itemsRequest struct {
+		ItemIDs []int64                `json:"itemIds"`
+		Items   json_parser.RawMessage `json:"items"`
+	}
+	if err := json_parser.NewDecoder(r.Body).Decode(&itemsRequest); err != nil {
+		return nil, err
+	}
+
+	if len(itemsRequest.Items) == 0 {
+		return itemsRequest.ItemIDs, nil
+	}
+
+	var itemIDs []int64
+	if err := json_parser.Unmarshal(itemsRequest.Items, &itemIDs); err == nil {
+		return append(itemIDs, itemsRequest.ItemIDs...), nil
+	}
+
+	var itemHashes []struct {
+		FeedID   int64  `json:"feedId"`
+		GUIDHash string `json:"guidHash"`
+	}
+	if err := json_parser.Unmarshal(itemsRequest.Items, &itemHashes); err != nil {
+		return nil, errors.New("Invalid list of items")
+	}
+
+	userID := request.UserID(r)
+	for _, itemHash := range itemHashes {
+		if entryID := h.store.EntryIDByHash(userID, itemHash.FeedID, itemHash.GUIDHash); entryID > 0 {
+			itemIDs = append(itemIDs, entryID)
+		}
+	}
+	return itemIDs, nil
+}
+
+// newestItemID returns the ID of the most recent entry of the user, clients send it back to mark items as read.
+func (h *handler) newestItemID(userID int64) (*int64, error) {
+	builder := h.store.NewEntryQueryBuilder(userID)
+	builder.WithoutStatus(model.EntryStatusRemoved)
+	builder.WithOrder("e.id")
+	builder.WithDirection("desc")
+	builder.WithLimit(1)
+
+	entryIDs, err := builder.GetEntryIDs()
+	if err != nil || len(entryIDs) == 0 {
+		return nil, err
+	}
+	return &entryIDs[0], nil
+}
+
+func newFeed(f *model.Feed) feed {
+	result := feed{
+		ID:               f.ID,
+		URL:              f.FeedURL,
+		Title:            f.Title,
+		FolderID:         f.Category.ID,
+		UnreadCount:      f.UnreadCount,
+		Link:             f.SiteURL,
+		UpdateErrorCount: f.ParsingErrorCount,
+		LastUpdateError:  f.ParsingErrorMsg,
+	}
+
+	// The creation date of the feeds is not stored, the first check is the closest approximation.
+	if !f.CheckedAt.IsZero() {
+		result.Added = f.CheckedAt.Unix()
+	}
+	return result
+}
+
+func (h *handler) newItem(r *http.Request, entry *model.Entry) item {
+	result := item{
+		ID:           entry.ID,
+		GUID:         entry.URL,
+		GUIDHash:     entry.Hash,
+		URL:          entry.URL,
+		Title:        entry.Title,
+		Author:       entry.Author,
+		PubDate:      entry.Date.Unix(),
+		UpdatedDate:  entry.Date.Unix(),
+		Body:         proxy.AbsoluteProxyRewriter(h.router, r.Host, entry.Content),
+		FeedID:       entry.FeedID,
+		Unread:       entry.Status == model.EntryStatusUnread,
+		Starred:      entry.Starred,
+		LastModified: entry.ChangedAt.Unix(),
+		Fingerprint:  entry.Hash,
+		ContentHash:  crypto.Hash(entry.Content),
+	}
+
+	if len(entry.Enclosures) > 0 {
+		result.EnclosureLink = &entry.Enclosures[0].URL
+		result.EnclosureMime = &entry.Enclosures[0].MimeType
+	}
+	return result
+}
@@ -0,0 +1,290 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package nextcloudnews // import "miniflux.app/nextcloudnews"
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"miniflux.app/model"
+	"miniflux.app/storage"
+	"miniflux.app/storage/storagetest"
+
+	"github.com/gorilla/mux"
+)
+
+const apiPrefix = "/index.php/apps/news/api/v1-3"
+
+type testClient struct {
+	t        *testing.T
+	server   *httptest.Server
+	username string
+	password string
+}
+
+// newTestClient creates a user with a feed of three entries published one hour apart,
+// and enables the Nextcloud News integration with the credentials of the client.
+func newTestClient(t *testing.T) (*testClient, *storage.Storage, *model.Feed, model.Entries) {
+	t.Helper()
+
+	store := storagetest.NewStorage(t)
+	user := storagetest.CreateUser(t, store, false)
+
+	integration, err := store.Integration(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	integration.NextcloudNewsEnabled = true
+	integration.NextcloudNewsUsername = "news"
+	integration.NextcloudNewsPassword = "secret"
+	if err := store.UpdateIntegration(integration); err != nil {
+		t.Fatal(err)
+	}
+
+	feed := storagetest.CreateFeed(t, store, user)
+
+	now := time.Now().Truncate(time.Second)
+	newEntries := model.Entries{
+		{Hash: "hash1", Title: "Entry 1", URL: "https://example.org/1", Date: now.Add(-2 * time.Hour), Content: "<p>1</p>"},
+		{Hash: "hash2", Title: "Entry 2", URL: "https://example.org/2", Date: now.Add(-time.Hour), Content: "<p>2</p>"},
+		{Hash: "hash3", Title: "Entry 3", URL: "https://example.org/3", Date: now, Content: "<p>3</p>"},
+	}
+	entries := storagetest.RefreshEntries(t, store, feed, newEntries, false)
+
+	router := mux.NewRouter()
+	Serve(router, store)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	return &testClient{t: t, server: server, username: "news", password: "secret"}, store, feed, entries
+}
+
+func (c *testClient) send(method, path, body string) (int, []byte) {
+	c.t.Helper()
+
+	request, err := http.NewRequest(method, c.server.URL+path, strings.NewReader(body))
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.SetBasicAuth(c.username, c.password)
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	defer response.Body.Close()
+
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	return response.StatusCode, data
+}
+
+func (c *testClient) do(method, path, body string, into interface{}) {
+	c.t.Helper()
+
+	status, data := c.send(method, apiPrefix+path, body)
+	if status != http.StatusOK {
+		c.t.Fatalf(`Unexpected status code for %s %s: got %d: %s`, method, path, status, data)
+	}
+
+	if into != nil {
+		if err := json.Unmarshal(data, into); err != nil {
+			c.t.Fatalf(`Unable to decode the response of %s %s: %v: %s`, method, path, err, data)
+		}
+	}
+}
+
+func (c *testClient) items(query string) []item {
+	c.t.Helper()
+
+	var response itemsResponse
+	c.do(http.MethodGet, "/items"+query, "", &response)
+	return response.Items
+}
+
+func TestAuthentication(t *testing.T) {
+	client, store, feed, _ := newTestClient(t)
+
+	status, data := client.send(http.MethodGet, "/apps/news/api", "")
+	if status != http.StatusOK || !strings.Contains(string(data), `"v1-3"`) {
+		t.Fatalf(`Unexpected API levels: got %d: %s`, status, data)
+	}
+
+	client.do(http.MethodGet, "/version", "", nil)
+
+	client.password = "invalid"
+	if status, _ := client.send(http.MethodGet, apiPrefix+"/version", ""); status != http.StatusUnauthorized {
+		t.Errorf(`Invalid credentials should be rejected: got %d`, status)
+	}
+
+	client.password = "secret"
+	integration, err := store.Integration(feed.UserID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	integration.NextcloudNewsEnabled = false
+	if err := store.UpdateIntegration(integration); err != nil {
+		t.Fatal(err)
+	}
+
+	if status, _ := client.send(http.MethodGet, apiPrefix+"/version", ""); status != http.StatusUnauthorized {
+		t.Errorf(`A disabled integration should reject the credentials: got %d`, status)
+	}
+}
+
+func TestFoldersAndFeeds(t *testing.T) {
+	client, _, feed, entries := newTestClient(t)
+
+	var folders foldersResponse
+	client.do(http.MethodGet, "/folders", "", &folders)
+	if len(folders.Folders) != 1 || folders.Folders[0].ID != feed.Category.ID {
+		t.Fatalf(`Unexpected folders: %+v`, folders.Folders)
+	}
+
+	var created foldersResponse
+	client.do(http.MethodPost, "/folders", `{"name":"News"}`, &created)
+	if len(created.Folders) != 1 || created.Folders[0].Name != "News" {
+		t.Fatalf(`Unexpected created folder: %+v`, created.Folders)
+	}
+
+	if status, _ := client.send(http.MethodPost, apiPrefix+"/folders", `{"name":"News"}`); status != http.StatusConflict {
+		t.Errorf(`A duplicate folder should be rejected: got %d`, status)
+	}
+
+	if status, _ := client.send(http.MethodPost, apiPrefix+"/folders", `{"name":""}`); status != http.StatusUnprocessableEntity {
+		t.Errorf(`An empty folder name should be rejected: got %d`, status)
+	}
+
+	client.do(http.MethodPost, fmt.Sprintf("/feeds/%d/move", feed.ID), fmt.Sprintf(`{"folderId":%d}`, created.Folders[0].ID), nil)
+	client.do(http.MethodPost, fmt.Sprintf("/feeds/%d/rename", feed.ID), `{"feedTitle":"Renamed"}`, nil)
+
+	var feeds feedsResponse
+	client.do(http.MethodGet, "/feeds", "", &feeds)
+	if len(feeds.Feeds) != 1 {
+		t.Fatalf(`Unexpected feeds: %+v`, feeds.Feeds)
+	}
+	if f := feeds.Feeds[0]; f.FolderID != created.Folders[0].ID || f.Title != "Renamed" || f.UnreadCount != 3 {
+		t.Errorf(`Unexpected feed: %+v`, f)
+	}
+	if feeds.NewestItemID == nil || *feeds.NewestItemID != entries[2].ID {
+		t.Errorf(`Unexpected newest item ID: %v`, feeds.NewestItemID)
+	}
+
+	if status, _ := client.send(http.MethodPost, apiPrefix+"/feeds", `{"url":"https://example.org/feed.xml"}`); status != http.StatusConflict {
+		t.Errorf(`A duplicate feed should be rejected: got %d`, status)
+	}
+
+	client.do(http.MethodDelete, fmt.Sprintf("/folders/%d", created.Folders[0].ID), "", nil)
+	client.do(http.MethodGet, "/feeds", "", &feeds)
+	if len(feeds.Feeds) != 0 {
+		t.Errorf(`The feeds of a removed folder should be removed: %+v`, feeds.Feeds)
+	}
+}
+
+func TestItems(t *testing.T) {
+	client, _, feed, entries := newTestClient(t)
+
+	items := client.items("?batchSize=2&type=3")
+	if len(items) != 2 || items[0].ID != entries[2].ID || items[1].ID != entries[1].ID {
+		t.Fatalf(`Unexpected first batch: %+v`, items)
+	}
+
+	items = client.items(fmt.Sprintf("?batchSize=2&type=3&offset=%d", items[1].ID))
+	if len(items) != 1 || items[0].ID != entries[0].ID {
+		t.Fatalf(`Unexpected second batch: %+v`, items)
+	}
+
+	items = client.items(fmt.Sprintf("?batchSize=-1&type=0&id=%d&oldestFirst=true", feed.ID))
+	if len(items) != 3 || items[0].ID != entries[0].ID {
+		t.Fatalf(`Unexpected items in ascending order: %+v`, items)
+	}
+	if items[0].GUIDHash != "hash1" || items[0].FeedID != feed.ID || !items[0].Unread || items[0].Body != "<p>1</p>" {
+		t.Errorf(`Unexpected item: %+v`, items[0])
+	}
+
+	client.do(http.MethodPost, "/items/read/multiple", fmt.Sprintf(`{"itemIds":[%d]}`, entries[0].ID), nil)
+	client.do(http.MethodPost, fmt.Sprintf("/items/%d/star", entries[1].ID), "", nil)
+
+	if items := client.items("?type=3&getRead=false"); len(items) != 2 {
+		t.Errorf(`Unexpected unread items: %+v`, items)
+	}
+
+	if items := client.items("?type=2"); len(items) != 1 || items[0].ID != entries[1].ID || !items[0].Starred {
+		t.Errorf(`Unexpected starred items: %+v`, items)
+	}
+
+	// Version 1.2 identifies the items to star by their feed and their hash.
+	client.do(http.MethodPut, "/items/unstar/multiple", fmt.Sprintf(`{"items":[{"feedId":%d,"guidHash":"hash2"}]}`, feed.ID), nil)
+	client.do(http.MethodPut, fmt.Sprintf("/items/%d/hash3/star", feed.ID), "", nil)
+
+	if items := client.items("?type=2"); len(items) != 1 || items[0].ID != entries[2].ID {
+		t.Errorf(`Unexpected starred items after the changes by hash: %+v`, items)
+	}
+
+	if status, _ := client.send(http.MethodPost, apiPrefix+"/items/999999/read", ""); status != http.StatusNotFound {
+		t.Errorf(`An unknown item should not be found: got %d`, status)
+	}
+}
+
+func TestUpdatedItems(t *testing.T) {
+	client, _, _, entries := newTestClient(t)
+
+	items := client.items("?type=3")
+	lastModified := items[0].LastModified
+	for _, i := range items {
+		if i.LastModified > lastModified {
+			lastModified = i.LastModified
+		}
+	}
+
+	// The modification dates have a precision of one second, the next change must be in a later second.
+	time.Sleep(time.Until(time.Unix(lastModified+1, 0)))
+	client.do(http.MethodPost, fmt.Sprintf("/items/%d/read", entries[1].ID), "", nil)
+
+	var response itemsResponse
+	client.do(http.MethodGet, fmt.Sprintf("/items/updated?type=3&lastModified=%d", lastModified+1), "", &response)
+	if len(response.Items) != 1 || response.Items[0].ID != entries[1].ID || response.Items[0].Unread {
+		t.Fatalf(`Unexpected updated items: %+v`, response.Items)
+	}
+
+	client.do(http.MethodGet, fmt.Sprintf("/items/updated?type=3&lastModified=%d", (lastModified+1)*1000000), "", &response)
+	if len(response.Items) != 1 {
+		t.Errorf(`The lastModified parameter should accept microseconds: %+v`, response.Items)
+	}
+
+	if status, _ := client.send(http.MethodGet, apiPrefix+"/items/updated?type=3", ""); status != http.StatusUnprocessableEntity {
+		t.Errorf(`A missing lastModified parameter should be rejected: got %d`, status)
+	}
+}
+
+func TestMarkAsRead(t *testing.T) {
+	client, _, feed, entries := newTestClient(t)
+
+	// The items received after the newest item known by the client stay unread.
+	client.do(http.MethodPut, fmt.Sprintf("/feeds/%d/read", feed.ID), fmt.Sprintf(`{"newestItemId":%d}`, entries[0].ID), nil)
+	if items := client.items("?type=3&getRead=false"); len(items) != 2 {
+		t.Errorf(`Unexpected unread items after marking the feed as read: %+v`, items)
+	}
+
+	client.do(http.MethodPut, fmt.Sprintf("/folders/%d/read", feed.Category.ID), fmt.Sprintf(`{"newestItemId":%d}`, entries[1].ID), nil)
+	if items := client.items("?type=3&getRead=false"); len(items) != 1 || items[0].ID != entries[2].ID {
+		t.Errorf(`Unexpected unread items after marking the folder as read: %+v`, items)
+	}
+
+	client.do(http.MethodPost, "/items/unread/multiple", fmt.Sprintf(`{"itemIds":[%d,%d]}`, entries[0].ID, entries[1].ID), nil)
+	client.do(http.MethodPost, "/items/read", fmt.Sprintf(`{"newestItemId":%d}`, entries[2].ID), nil)
+	if items := client.items("?type=3&getRead=false"); len(items) != 0 {
+		t.Errorf(`All the items should be read: %+v`, items)
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package nextcloudnews // import "miniflux.app/nextcloudnews"
+
+import (
+	"context"
+	"net/http"
+
+	"miniflux.app/http/request"
+	"miniflux.app/logger"
+	"miniflux.app/storage"
+)
+
+type middleware struct {
+	store *storage.Storage
+}
+
+func newMiddleware(s *storage.Storage) *middleware {
+	return &middleware{s}
+}
+
+// basicAuth authenticates the requests with the Nextcloud News credentials defined on the integrations page.
+func (m *middleware) basicAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clientIP := request.ClientIP(r)
+		username, password, authOK := r.BasicAuth()
+		if !authOK {
+			logger.Info("[NextcloudNews] [ClientIP=%s] No credentials provided", clientIP)
+			Unauthorized(w, r)
+			return
+		}
+
+		userID, err := m.store.NextcloudNewsUserCheckPassword(username, password)
+		if err != nil {
+			logger.Error("[NextcloudNews] [ClientIP=%s] %v", clientIP, err)
+			Unauthorized(w, r)
+			return
+		}
+
+		user, err := m.store.UserByID(userID)
+		if err != nil {
+			logger.Error("[NextcloudNews] [ClientIP=%s] %v", clientIP, err)
+			Unauthorized(w, r)
+			return
+		}
+
+		if user == nil {
+			logger.Info("[NextcloudNews] [ClientIP=%s] No user found with the userID: %d", clientIP, userID)
+			Unauthorized(w, r)
+			return
+		}
+
+		logger.Info("[NextcloudNews] [ClientIP=%s] User #%d is authenticated with user agent %q", clientIP, user.ID, r.UserAgent())
+		m.store.SetLastLogin(user.ID)
+
+		ctx := r.Context()
+		ctx = context.WithValue(ctx, request.UserIDContextKey, user.ID)
+		ctx = context.WithValue(ctx, request.UserTimezoneContextKey, user.Timezone)
+		ctx = context.WithValue(ctx, request.IsAdminUserContextKey, user.IsAdmin)
+		ctx = context.WithValue(ctx, request.IsAuthenticatedContextKey, true)
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
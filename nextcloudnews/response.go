@@ -0,0 +1,141 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package nextcloudnews // import "miniflux.app/nextcloudnews"
+
+import (
+	json_parser "encoding/json"
+	"net/http"
+
+	"miniflux.app/http/response"
+	"miniflux.app/logger"
+)
+
+type apiLevelsResponse struct {
+	APILevels []string `json:"apiLevels"`
+}
+
+type versionResponse struct {
+	Version string `json:"version"`
+}
+
+type statusWarnings struct {
+	ImproperlyConfiguredCron bool `json:"improperlyConfiguredCron"`
+	IncorrectDBCharset       bool `json:"incorrectDbCharset"`
+}
+
+type statusResponse struct {
+	Version  string         `json:"version"`
+	Warnings statusWarnings `json:"warnings"`
+}
+
+type userResponse struct {
+	UserID             string  `json:"userId"`
+	DisplayName        string  `json:"displayName"`
+	LastLoginTimestamp int64   `json:"lastLoginTimestamp"`
+	Avatar             *string `json:"avatar"`
+}
+
+type folder struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type foldersResponse struct {
+	Folders []folder `json:"folders"`
+}
+
+type feed struct {
+	ID               int64   `json:"id"`
+	URL              string  `json:"url"`
+	Title            string  `json:"title"`
+	FaviconLink      *string `json:"faviconLink"`
+	Added            int64   `json:"added"`
+	FolderID         int64   `json:"folderId"`
+	UnreadCount      int     `json:"unreadCount"`
+	Ordering         int     `json:"ordering"`
+	Link             string  `json:"link"`
+	Pinned           bool    `json:"pinned"`
+	UpdateErrorCount int     `json:"updateErrorCount"`
+	LastUpdateError  string  `json:"lastUpdateError"`
+}
+
+type feedsResponse struct {
+	Feeds        []feed `json:"feeds"`
+	StarredCount int    `json:"starredCount"`
+	NewestItemID *int64 `json:"newestItemId,omitempty"`
+}
+
+type item struct {
+	ID               int64   `json:"id"`
+	GUID             string  `json:"guid"`
+	GUIDHash         string  `json:"guidHash"`
+	URL              string  `json:"url"`
+	Title            string  `json:"title"`
+	Author           string  `json:"author"`
+	PubDate          int64   `json:"pubDate"`
+	UpdatedDate      int64   `json:"updatedDate"`
+	Body             string  `json:"body"`
+	EnclosureMime    *string `json:"enclosureMime"`
+	EnclosureLink    *string `json:"enclosureLink"`
+	MediaThumbnail   *string `json:"mediaThumbnail"`
+	MediaDescription *string `json:"mediaDescription"`
+	FeedID           int64   `json:"feedId"`
+	Unread           bool    `json:"unread"`
+	Starred          bool    `json:"starred"`
+	RTL              bool    `json:"rtl"`
+	LastModified     int64   `json:"lastModified"`
+	Fingerprint      string  `json:"fingerprint"`
+	ContentHash      string  `json:"contentHash"`
+}
+
+type itemsResponse struct {
+	Items []item `json:"items"`
+}
+
+// OK sends an empty list, the News app answers this way to the requests without result.
+func OK(w http.ResponseWriter, r *http.Request) {
+	sendJSON(w, r, http.StatusOK, []string{})
+}
+
+// Unauthorized sends a not authorized error to the client.
+func Unauthorized(w http.ResponseWriter, r *http.Request) {
+	logger.Error("[HTTP:Unauthorized] %s", r.URL)
+
+	builder := response.New(w, r)
+	builder.WithStatus(http.StatusUnauthorized)
+	builder.WithHeader("Content-Type", "application/json")
+	builder.WithHeader("WWW-Authenticate", `Basic realm="Miniflux"`)
+	builder.WithBody(`{"message":"Unauthorized"}`)
+	builder.Write()
+}
+
+// Conflict sends a conflict error to the client, it is used when a folder or a feed already exists.
+func Conflict(w http.ResponseWriter, r *http.Request, err error) {
+	logger.Error("[HTTP:Conflict] %s => %v", r.URL, err)
+	sendJSON(w, r, http.StatusConflict, errorResponse{Message: err.Error()})
+}
+
+// UnprocessableEntity sends a validation error to the client.
+func UnprocessableEntity(w http.ResponseWriter, r *http.Request, err error) {
+	logger.Error("[HTTP:Unprocessable Entity] %s => %v", r.URL, err)
+	sendJSON(w, r, http.StatusUnprocessableEntity, errorResponse{Message: err.Error()})
+}
+
+type errorResponse struct {
+	Message string `json:"message"`
+}
+
+func sendJSON(w http.ResponseWriter, r *http.Request, status int, body interface{}) {
+	data, err := json_parser.Marshal(body)
+	if err != nil {
+		logger.Error("[NextcloudNews] Unable to marshal the response: %v", err)
+	}
+
+	builder := response.New(w, r)
+	builder.WithStatus(status)
+	builder.WithHeader("Content-Type", "application/json")
+	builder.WithBody(data)
+	builder.Write()
+}
@@ -103,6 +103,7 @@ func ProcessFeedEntries(store *storage.Storage, feed *model.Feed, user *model.Us
 			}
 		}
 
+		entry.Language = detectLanguage(entry.Title, entry.Content)
 		updateEntryReadingTime(store, feed, entry, entryIsNew, user)
 		filteredEntries = append(filteredEntries, entry)
 	}
@@ -175,6 +176,7 @@ func ProcessEntryWebPage(feed *model.Feed, entry *model.Entry, user *model.User)
 
 	rewrite.Rewriter(url, entry, entry.Feed.RewriteRules)
 	entry.Content = sanitizer.Sanitize(url, entry.Content)
+	entry.Language = detectLanguage(entry.Title, entry.Content)
 
 	return nil
 }
@@ -299,3 +301,12 @@ func calculateReadingTime(content string, user *model.User) int {
 
 	return timeToReadInt
 }
+
+// detectLanguage returns the ISO 639-1 code of the entry language, or an empty string when undetermined.
+func detectLanguage(title, content string) string {
+	languageInfo := getlang.FromString(title + " " + sanitizer.StripTags(content))
+	if languageInfo.LanguageCode() == "und" {
+		return ""
+	}
+	return languageInfo.LanguageCode()
+}
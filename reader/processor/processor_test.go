@@ -77,3 +77,23 @@ func TestParseISO8601(t *testing.T) {
 		}
 	}
 }
+
+func TestDetectLanguage(t *testing.T) {
+	var scenarios = []struct {
+		title    string
+		content  string
+		expected string
+	}{
+		{"", "", ""},
+		{"Breaking news", "<p>The quick brown fox jumps over the lazy dog while the farmer is watching the field.</p>", "en"},
+		{"Nachrichten", "<p>Die Bundesregierung hat heute neue Maßnahmen für die Wirtschaft und die Bürger beschlossen.</p>", "de"},
+		{"Actualités", "<p>Le gouvernement a annoncé aujourd'hui de nouvelles mesures pour les entreprises et les citoyens.</p>", "fr"},
+	}
+
+	for _, tc := range scenarios {
+		result := detectLanguage(tc.title, tc.content)
+		if tc.expected != result {
+			t.Errorf(`Unexpected language, got %q instead of %q for %q`, result, tc.expected, tc.title)
+		}
+	}
+}
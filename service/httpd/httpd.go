@@ -19,7 +19,9 @@ import (
 	"miniflux.app/googlereader"
 	"miniflux.app/http/request"
 	"miniflux.app/logger"
+	"miniflux.app/nextcloudnews"
 	"miniflux.app/storage"
+	"miniflux.app/ttrss"
 	"miniflux.app/ui"
 	"miniflux.app/version"
 	"miniflux.app/worker"
@@ -184,6 +186,8 @@ func setupHandler(store *storage.Storage, pool *worker.Pool) *mux.Router {
 
 	fever.Serve(router, store)
 	googlereader.Serve(router, store)
+	nextcloudnews.Serve(router, store)
+	ttrss.Serve(router, store)
 	api.Serve(router, store, pool)
 	ui.Serve(router, store, pool)
 
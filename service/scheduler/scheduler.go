@@ -5,9 +5,11 @@
 package scheduler // import "miniflux.app/service/scheduler"
 
 import (
+	"sync"
 	"time"
 
 	"miniflux.app/config"
+	"miniflux.app/integration/webhook"
 	"miniflux.app/logger"
 	"miniflux.app/metric"
 	"miniflux.app/model"
@@ -15,16 +17,29 @@ import (
 	"miniflux.app/worker"
 )
 
+const (
+	// webhookFrequency is the interval between two runs of the webhook delivery scheduler.
+	webhookFrequency = 30 * time.Second
+
+	// webhookBatchSize is the maximum number of deliveries sent at each run.
+	webhookBatchSize = 100
+
+	// webhookClaimDuration is the time the deliveries of a run are reserved to this instance,
+	// it is longer than a whole batch sent to a single slow endpoint.
+	webhookClaimDuration = 30 * time.Minute
+
+	// webhookMaxConcurrentEndpoints is the number of endpoints receiving deliveries at the same time.
+	webhookMaxConcurrentEndpoints = 10
+
+	// webhookDeliveriesRetentionDays is the number of days the delivery history is kept.
+	webhookDeliveriesRetentionDays = 7
+)
+
 // Serve starts the internal scheduler.
 func Serve(store *storage.Storage, pool *worker.Pool) {
 	logger.Info(`Starting scheduler...`)
 
-	go feedScheduler(
-		store,
-		pool,
-		config.Opts.PollingFrequency(),
-		config.Opts.BatchSize(),
-	)
+	go feedScheduler(store, pool, config.Opts.BatchSize())
 
 	go cleanupScheduler(
 		store,
@@ -34,10 +49,17 @@ func Serve(store *storage.Storage, pool *worker.Pool) {
 		config.Opts.CleanupArchiveBatchSize(),
 		config.Opts.CleanupRemoveSessionsDays(),
 	)
+
+	go webhookScheduler(store, webhookFrequency, webhookBatchSize)
 }
 
-func feedScheduler(store *storage.Storage, pool *worker.Pool, frequency, batchSize int) {
-	for range time.Tick(time.Duration(frequency) * time.Minute) {
+func feedScheduler(store *storage.Storage, pool *worker.Pool, batchSize int) {
+	// The polling frequency is read at each run because it can be changed by reloading the configuration.
+	lastRun := time.Now()
+	for {
+		time.Sleep(time.Until(lastRun.Add(time.Duration(config.Opts.PollingFrequency()) * time.Minute)))
+		lastRun = time.Now()
+
 		jobs, err := store.NewBatch(batchSize)
 		if err != nil {
 			logger.Error("[Scheduler:Feed] %v", err)
@@ -50,30 +72,128 @@ func feedScheduler(store *storage.Storage, pool *worker.Pool, frequency, batchSi
 
 func cleanupScheduler(store *storage.Storage, frequency, archiveReadDays, archiveUnreadDays, archiveBatchSize, sessionsDays int) {
 	for range time.Tick(time.Duration(frequency) * time.Hour) {
-		nbSessions := store.CleanOldSessions(sessionsDays)
-		nbUserSessions := store.CleanOldUserSessions(sessionsDays)
-		logger.Info("[Scheduler:Cleanup] Cleaned %d sessions and %d user sessions", nbSessions, nbUserSessions)
+		runCleanupTasks(store, archiveReadDays, archiveUnreadDays, archiveBatchSize, sessionsDays)
+	}
+}
 
-		startTime := time.Now()
-		if rowsAffected, err := store.ArchiveEntries(model.EntryStatusRead, archiveReadDays, archiveBatchSize); err != nil {
-			logger.Error("[Scheduler:ArchiveReadEntries] %v", err)
-		} else {
-			logger.Info("[Scheduler:ArchiveReadEntries] %d entries changed", rowsAffected)
+// RunCleanupTasks removes the old sessions and webhook deliveries, and archives the old entries once.
+func RunCleanupTasks(store *storage.Storage) {
+	runCleanupTasks(
+		store,
+		config.Opts.CleanupArchiveReadDays(),
+		config.Opts.CleanupArchiveUnreadDays(),
+		config.Opts.CleanupArchiveBatchSize(),
+		config.Opts.CleanupRemoveSessionsDays(),
+	)
+}
 
-			if config.Opts.HasMetricsCollector() {
-				metric.ArchiveEntriesDuration.WithLabelValues(model.EntryStatusRead).Observe(time.Since(startTime).Seconds())
-			}
+func runCleanupTasks(store *storage.Storage, archiveReadDays, archiveUnreadDays, archiveBatchSize, sessionsDays int) {
+	nbSessions := store.CleanOldSessions(sessionsDays)
+	nbUserSessions := store.CleanOldUserSessions(sessionsDays)
+	logger.Info("[Scheduler:Cleanup] Cleaned %d sessions and %d user sessions", nbSessions, nbUserSessions)
+
+	if nbDeliveries, err := store.CleanOldWebhookDeliveries(webhookDeliveriesRetentionDays); err != nil {
+		logger.Error("[Scheduler:Cleanup] %v", err)
+	} else {
+		logger.Info("[Scheduler:Cleanup] Cleaned %d webhook deliveries", nbDeliveries)
+	}
+
+	startTime := time.Now()
+	if rowsAffected, err := store.ArchiveEntries(model.EntryStatusRead, archiveReadDays, archiveBatchSize); err != nil {
+		logger.Error("[Scheduler:ArchiveReadEntries] %v", err)
+	} else {
+		logger.Info("[Scheduler:ArchiveReadEntries] %d entries changed", rowsAffected)
+
+		if config.Opts.HasMetricsCollector() {
+			metric.ArchiveEntriesDuration.WithLabelValues(model.EntryStatusRead).Observe(time.Since(startTime).Seconds())
 		}
+	}
 
-		startTime = time.Now()
-		if rowsAffected, err := store.ArchiveEntries(model.EntryStatusUnread, archiveUnreadDays, archiveBatchSize); err != nil {
-			logger.Error("[Scheduler:ArchiveUnreadEntries] %v", err)
-		} else {
-			logger.Info("[Scheduler:ArchiveUnreadEntries] %d entries changed", rowsAffected)
+	startTime = time.Now()
+	if rowsAffected, err := store.ArchiveEntries(model.EntryStatusUnread, archiveUnreadDays, archiveBatchSize); err != nil {
+		logger.Error("[Scheduler:ArchiveUnreadEntries] %v", err)
+	} else {
+		logger.Info("[Scheduler:ArchiveUnreadEntries] %d entries changed", rowsAffected)
 
-			if config.Opts.HasMetricsCollector() {
-				metric.ArchiveEntriesDuration.WithLabelValues(model.EntryStatusUnread).Observe(time.Since(startTime).Seconds())
-			}
+		if config.Opts.HasMetricsCollector() {
+			metric.ArchiveEntriesDuration.WithLabelValues(model.EntryStatusUnread).Observe(time.Since(startTime).Seconds())
+		}
+	}
+}
+
+func webhookScheduler(store *storage.Storage, frequency time.Duration, batchSize int) {
+	for range time.Tick(frequency) {
+		deliverPendingWebhooks(store, batchSize)
+	}
+}
+
+// DeliverPendingWebhooks sends the webhook deliveries that are due once.
+func DeliverPendingWebhooks(store *storage.Storage) {
+	deliverPendingWebhooks(store, webhookBatchSize)
+}
+
+func deliverPendingWebhooks(store *storage.Storage, batchSize int) {
+	deliveries, err := store.ClaimWebhookDeliveries(batchSize, webhookClaimDuration)
+	if err != nil {
+		logger.Error("[Scheduler:Webhook] %v", err)
+		return
+	}
+
+	// Each endpoint receives its deliveries one at a time and in order, a slow endpoint only delays its own deliveries.
+	var endpoints []string
+	deliveriesByEndpoint := make(map[string]model.WebhookDeliveries)
+	for _, delivery := range deliveries {
+		endpoint := delivery.Webhook.URL
+		if _, found := deliveriesByEndpoint[endpoint]; !found {
+			endpoints = append(endpoints, endpoint)
 		}
+		deliveriesByEndpoint[endpoint] = append(deliveriesByEndpoint[endpoint], delivery)
+	}
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, webhookMaxConcurrentEndpoints)
+	for _, endpoint := range endpoints {
+		wg.Add(1)
+		semaphore <- struct{}{}
+
+		go func(deliveries model.WebhookDeliveries) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+
+			for _, delivery := range deliveries {
+				deliverWebhook(store, delivery)
+			}
+		}(deliveriesByEndpoint[endpoint])
+	}
+	wg.Wait()
+}
+
+func deliverWebhook(store *storage.Storage, delivery *model.WebhookDelivery) {
+	client := webhook.NewClient(delivery.Webhook.URL, delivery.Webhook.Secret)
+	statusCode, err := client.Send(delivery.ID, delivery.Event, delivery.Payload)
+
+	now := time.Now()
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+	delivery.ResponseStatus = statusCode
+
+	switch {
+	case err == nil:
+		delivery.Status = model.WebhookDeliveryStatusSuccess
+		delivery.ErrorMsg = ""
+	case delivery.Attempts >= webhook.MaxAttempts:
+		delivery.Status = model.WebhookDeliveryStatusFailed
+		delivery.ErrorMsg = webhook.ErrorMessage(statusCode)
+		logger.Error("[Scheduler:Webhook] Delivery #%d to %s failed after %d attempts: %v", delivery.ID, delivery.Webhook.URL, delivery.Attempts, err)
+	default:
+		delivery.ErrorMsg = webhook.ErrorMessage(statusCode)
+		delivery.NextAttemptAt = now.Add(webhook.RetryDelay(delivery.Attempts))
+		logger.Debug("[Scheduler:Webhook] Delivery #%d to %s failed, retrying at %v: %v", delivery.ID, delivery.Webhook.URL, delivery.NextAttemptAt, err)
+	}
+
+	if err := store.UpdateWebhookDelivery(delivery); err != nil {
+		logger.Error("[Scheduler:Webhook] %v", err)
 	}
 }
@@ -0,0 +1,227 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package storage // import "miniflux.app/storage"
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"miniflux.app/model"
+)
+
+// AnnotationQuery filters the annotations returned by Annotations.
+type AnnotationQuery struct {
+	EntryID     int64
+	SearchQuery string
+	Language    string
+}
+
+// CreateAnnotation adds a highlight or a note to an entry.
+func (s *Storage) CreateAnnotation(userID, entryID int64, request *model.AnnotationRequest) (*model.Annotation, error) {
+	var annotation model.Annotation
+
+	args := []interface{}{
+		userID,
+		entryID,
+		request.Quote,
+		request.StartOffset,
+		request.EndOffset,
+		request.Note,
+	}
+
+	var searchColumn, searchValue string
+	if !s.isSQLite() {
+		searchColumn = ", document_vectors"
+		searchValue = ", " + annotationVectors("$3", "$6", 7)
+		args = append(args, s.textSearchConfig(s.UserLanguage(userID)))
+	}
+
+	query := `
+		INSERT INTO entry_annotations
+			(user_id, entry_id, quote, start_offset, end_offset, note` + searchColumn + `)
+		VALUES
+			($1, $2, $3, $4, $5, $6` + searchValue + `)
+		RETURNING
+			id, user_id, entry_id, quote, start_offset, end_offset, note, created_at, changed_at
+	`
+	err := s.db.QueryRow(query, args...).Scan(
+		&annotation.ID,
+		&annotation.UserID,
+		&annotation.EntryID,
+		&annotation.Quote,
+		&annotation.StartOffset,
+		&annotation.EndOffset,
+		&annotation.Note,
+		&annotation.CreatedAt,
+		&annotation.ChangedAt,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf(`store: unable to create annotation for entry #%d: %v`, entryID, err)
+	}
+
+	s.touchUser(userID)
+	return &annotation, nil
+}
+
+// Annotation returns an annotation from the database.
+func (s *Storage) Annotation(userID, annotationID int64) (*model.Annotation, error) {
+	var annotation model.Annotation
+
+	query := `
+		SELECT
+			id, user_id, entry_id, quote, start_offset, end_offset, note, created_at, changed_at
+		FROM
+			entry_annotations
+		WHERE
+			user_id=$1 AND id=$2
+	`
+	err := s.db.QueryRow(query, userID, annotationID).Scan(
+		&annotation.ID,
+		&annotation.UserID,
+		&annotation.EntryID,
+		&annotation.Quote,
+		&annotation.StartOffset,
+		&annotation.EndOffset,
+		&annotation.Note,
+		&annotation.CreatedAt,
+		&annotation.ChangedAt,
+	)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf(`store: unable to fetch annotation: %v`, err)
+	default:
+		return &annotation, nil
+	}
+}
+
+// EntryAnnotations returns the annotations of an entry ordered by position.
+func (s *Storage) EntryAnnotations(userID, entryID int64) (model.Annotations, error) {
+	return s.Annotations(userID, &AnnotationQuery{EntryID: entryID})
+}
+
+// Annotations returns the annotations of a user matching the given query.
+func (s *Storage) Annotations(userID int64, annotationQuery *AnnotationQuery) (model.Annotations, error) {
+	conditions := []string{"a.user_id = $1"}
+	args := []interface{}{userID}
+
+	if annotationQuery.EntryID > 0 {
+		conditions = append(conditions, fmt.Sprintf("a.entry_id = $%d", len(args)+1))
+		args = append(args, annotationQuery.EntryID)
+	}
+
+	if annotationQuery.SearchQuery != "" {
+		conditions = append(conditions, s.annotationSearchCondition(len(args)+1, len(args)+2))
+		args = append(args, s.searchArguments(annotationQuery.SearchQuery, annotationQuery.Language)...)
+	}
+
+	query := `
+		SELECT
+			a.id,
+			a.user_id,
+			a.entry_id,
+			a.quote,
+			a.start_offset,
+			a.end_offset,
+			a.note,
+			a.created_at,
+			a.changed_at,
+			e.title,
+			e.url
+		FROM
+			entry_annotations a
+		JOIN
+			entries e ON e.id = a.entry_id
+		WHERE
+			%s
+		ORDER BY
+			e.published_at DESC, a.entry_id DESC, a.start_offset ASC, a.id ASC
+	`
+	rows, err := s.db.Query(fmt.Sprintf(query, strings.Join(conditions, " AND ")), args...)
+	if err != nil {
+		return nil, fmt.Errorf(`store: unable to fetch annotations: %v`, err)
+	}
+	defer rows.Close()
+
+	annotations := make(model.Annotations, 0)
+	for rows.Next() {
+		var annotation model.Annotation
+		err := rows.Scan(
+			&annotation.ID,
+			&annotation.UserID,
+			&annotation.EntryID,
+			&annotation.Quote,
+			&annotation.StartOffset,
+			&annotation.EndOffset,
+			&annotation.Note,
+			&annotation.CreatedAt,
+			&annotation.ChangedAt,
+			&annotation.EntryTitle,
+			&annotation.EntryURL,
+		)
+		if err != nil {
+			return nil, fmt.Errorf(`store: unable to fetch annotation row: %v`, err)
+		}
+
+		annotations = append(annotations, &annotation)
+	}
+
+	return annotations, nil
+}
+
+// UpdateAnnotation updates the note of an annotation.
+func (s *Storage) UpdateAnnotation(annotation *model.Annotation) error {
+	args := []interface{}{annotation.Note, annotation.ID, annotation.UserID}
+
+	var searchAssignment string
+	if !s.isSQLite() {
+		searchAssignment = ", document_vectors=" + annotationVectors("quote", "$1", 4)
+		args = append(args, s.textSearchConfig(s.UserLanguage(annotation.UserID)))
+	}
+
+	query := `UPDATE entry_annotations SET note=$1, changed_at=now()` + searchAssignment + ` WHERE id=$2 AND user_id=$3 RETURNING changed_at`
+	err := s.db.QueryRow(query, args...).Scan(&annotation.ChangedAt)
+	if err != nil {
+		return fmt.Errorf(`store: unable to update annotation #%d: %v`, annotation.ID, err)
+	}
+
+	s.touchUser(annotation.UserID)
+	return nil
+}
+
+// RemoveAnnotation deletes an annotation.
+func (s *Storage) RemoveAnnotation(userID, annotationID int64) error {
+	result, err := s.db.Exec(`DELETE FROM entry_annotations WHERE id=$1 AND user_id=$2`, annotationID, userID)
+	if err != nil {
+		return fmt.Errorf(`store: unable to remove this annotation: %v`, err)
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf(`store: unable to remove this annotation: %v`, err)
+	}
+
+	if count == 0 {
+		return errors.New(`store: no annotation has been removed`)
+	}
+
+	s.touchUser(userID)
+	return nil
+}
+
+// reindexAnnotations rebuilds the search vectors of the annotations of a user with a new language.
+func (s *Storage) reindexAnnotations(userID int64, language string) error {
+	query := `UPDATE entry_annotations SET document_vectors=` + annotationVectors("quote", "note", 1) + ` WHERE user_id=$2`
+	if _, err := s.db.Exec(query, s.textSearchConfig(language), userID); err != nil {
+		return fmt.Errorf(`store: unable to reindex the annotations of user #%d: %v`, userID, err)
+	}
+
+	return nil
+}
@@ -5,6 +5,7 @@
 package storage // import "miniflux.app/storage"
 
 import (
+	"database/sql"
 	"fmt"
 
 	"miniflux.app/model"
@@ -18,6 +19,14 @@ func (s *Storage) APIKeyExists(userID int64, description string) bool {
 	return result
 }
 
+// APIKeyIDExists checks if the API key belongs to the given user.
+func (s *Storage) APIKeyIDExists(userID, keyID int64) bool {
+	var result bool
+	query := `SELECT true FROM api_keys WHERE user_id=$1 AND id=$2`
+	s.db.QueryRow(query, userID, keyID).Scan(&result)
+	return result
+}
+
 // SetAPIKeyUsedTimestamp updates the last used date of an API Key.
 func (s *Storage) SetAPIKeyUsedTimestamp(userID int64, token string) error {
 	query := `UPDATE api_keys SET last_used_at=now() WHERE user_id=$1 and token=$2`
@@ -33,7 +42,7 @@ func (s *Storage) SetAPIKeyUsedTimestamp(userID int64, token string) error {
 func (s *Storage) APIKeys(userID int64) (model.APIKeys, error) {
 	query := `
 		SELECT
-			id, user_id, token, description, last_used_at, created_at
+			id, user_id, token, description, scopes, category_ids, last_used_at, created_at
 		FROM
 			api_keys
 		WHERE
@@ -54,6 +63,8 @@ func (s *Storage) APIKeys(userID int64) (model.APIKeys, error) {
 			&apiKey.UserID,
 			&apiKey.Token,
 			&apiKey.Description,
+			s.scanArray(&apiKey.Scopes),
+			s.scanArray(&apiKey.CategoryIDs),
 			&apiKey.LastUsedAt,
 			&apiKey.CreatedAt,
 		); err != nil {
@@ -66,13 +77,46 @@ func (s *Storage) APIKeys(userID int64) (model.APIKeys, error) {
 	return apiKeys, nil
 }
 
+// APIKeyByToken returns the API key with the given token, nil is returned when the token does not exist.
+func (s *Storage) APIKeyByToken(token string) (*model.APIKey, error) {
+	query := `
+		SELECT
+			id, user_id, token, description, scopes, category_ids, last_used_at, created_at
+		FROM
+			api_keys
+		WHERE
+			token=$1
+	`
+
+	var apiKey model.APIKey
+	err := s.db.QueryRow(query, token).Scan(
+		&apiKey.ID,
+		&apiKey.UserID,
+		&apiKey.Token,
+		&apiKey.Description,
+		s.scanArray(&apiKey.Scopes),
+		s.scanArray(&apiKey.CategoryIDs),
+		&apiKey.LastUsedAt,
+		&apiKey.CreatedAt,
+	)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf(`store: unable to fetch API Key: %v`, err)
+	}
+
+	return &apiKey, nil
+}
+
 // CreateAPIKey inserts a new API key.
 func (s *Storage) CreateAPIKey(apiKey *model.APIKey) error {
 	query := `
 		INSERT INTO api_keys
-			(user_id, token, description)
+			(user_id, token, description, scopes, category_ids)
 		VALUES
-			($1, $2, $3)
+			($1, $2, $3, $4, $5)
 		RETURNING
 			id, created_at
 	`
@@ -81,6 +125,8 @@ func (s *Storage) CreateAPIKey(apiKey *model.APIKey) error {
 		apiKey.UserID,
 		apiKey.Token,
 		apiKey.Description,
+		s.array(apiKey.Scopes),
+		s.array(apiKey.CategoryIDs),
 	).Scan(
 		&apiKey.ID,
 		&apiKey.CreatedAt,
@@ -9,7 +9,6 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/lib/pq"
 	"miniflux.app/model"
 )
 
@@ -37,6 +36,14 @@ func (s *Storage) CategoryIDExists(userID, categoryID int64) bool {
 	return result
 }
 
+// CategoryUserID returns the owner of the given category, or 0 if the category does not exist.
+func (s *Storage) CategoryUserID(categoryID int64) int64 {
+	var userID int64
+	query := `SELECT user_id FROM categories WHERE id=$1`
+	s.db.QueryRow(query, categoryID).Scan(&userID)
+	return userID
+}
+
 // Category returns a category from the database.
 func (s *Storage) Category(userID, categoryID int64) (*model.Category, error) {
 	var category model.Category
@@ -171,28 +178,32 @@ func (s *Storage) CreateCategory(userID int64, request *model.CategoryRequest) (
 
 	query := `
 		INSERT INTO categories
-			(user_id, title)
+			(user_id, title, hide_globally)
 		VALUES
-			($1, $2)
+			($1, $2, $3)
 		RETURNING
 			id,
 			user_id,
-			title
+			title,
+			hide_globally
 	`
 	err := s.db.QueryRow(
 		query,
 		userID,
 		request.Title,
+		request.HideGlobally != "",
 	).Scan(
 		&category.ID,
 		&category.UserID,
 		&category.Title,
+		&category.HideGlobally,
 	)
 
 	if err != nil {
 		return nil, fmt.Errorf(`store: unable to create category %q: %v`, request.Title, err)
 	}
 
+	s.touchUser(userID)
 	return &category, nil
 }
 
@@ -211,6 +222,7 @@ func (s *Storage) UpdateCategory(category *model.Category) error {
 		return fmt.Errorf(`store: unable to update category: %v`, err)
 	}
 
+	s.touchUser(category.UserID)
 	return nil
 }
 
@@ -231,6 +243,7 @@ func (s *Storage) RemoveCategory(userID, categoryID int64) error {
 		return errors.New(`store: no category has been removed`)
 	}
 
+	s.touchUser(userID)
 	return nil
 }
 
@@ -242,9 +255,9 @@ func (s *Storage) RemoveAndReplaceCategoriesByName(userid int64, titles []string
 		return errors.New("unable to begin transaction")
 	}
 
-	titleParam := pq.Array(titles)
+	titleParam := s.array(titles)
 	var count int
-	query := "SELECT count(*) FROM categories WHERE user_id = $1 and title != ANY($2)"
+	query := "SELECT count(*) FROM categories WHERE user_id = $1 AND NOT " + s.inArray("title", 2)
 	err = tx.QueryRow(query, userid, titleParam).Scan(&count)
 	if err != nil {
 		tx.Rollback()
@@ -256,7 +269,7 @@ func (s *Storage) RemoveAndReplaceCategoriesByName(userid int64, titles []string
 	}
 
 	query = `
-		WITH d_cats AS (SELECT id FROM categories WHERE user_id = $1 AND title = ANY($2)) 
+		WITH d_cats AS (SELECT id FROM categories WHERE user_id = $1 AND ` + s.inArray("title", 2) + `) 
 		UPDATE feeds 
 		 SET category_id = 
 		  (SELECT id 
@@ -272,12 +285,13 @@ func (s *Storage) RemoveAndReplaceCategoriesByName(userid int64, titles []string
 		return fmt.Errorf("unable to replace categories: %v", err)
 	}
 
-	query = "DELETE FROM categories WHERE user_id = $1 AND title = ANY($2)"
+	query = "DELETE FROM categories WHERE user_id = $1 AND " + s.inArray("title", 2)
 	_, err = tx.Exec(query, userid, titleParam)
 	if err != nil {
 		tx.Rollback()
 		return fmt.Errorf("unable to delete categories: %v", err)
 	}
 	tx.Commit()
+	s.touchUser(userid)
 	return nil
 }
@@ -30,7 +30,7 @@ func NewCertificateCache(storage *Storage) *CertificateCache {
 // Get returns a certificate data for the specified key.
 // If there's no such key, Get returns ErrCacheMiss.
 func (c *CertificateCache) Get(ctx context.Context, key string) ([]byte, error) {
-	query := `SELECT data::bytea FROM acme_cache WHERE key = $1`
+	query := `SELECT data FROM acme_cache WHERE key = $1`
 	var data []byte
 	err := c.storage.db.QueryRowContext(ctx, query, key).Scan(&data)
 	if err == sql.ErrNoRows {
@@ -42,8 +42,8 @@ func (c *CertificateCache) Get(ctx context.Context, key string) ([]byte, error)
 
 // Put stores the data in the cache under the specified key.
 func (c *CertificateCache) Put(ctx context.Context, key string, data []byte) error {
-	query := `INSERT INTO acme_cache (key, data, updated_at) VALUES($1, $2::bytea, now())
-	          ON CONFLICT (key) DO UPDATE SET data = $2::bytea, updated_at = now()`
+	query := `INSERT INTO acme_cache (key, data, updated_at) VALUES($1, $2, now())
+	          ON CONFLICT (key) DO UPDATE SET data = $2, updated_at = now()`
 	_, err := c.storage.db.ExecContext(ctx, query, key, data)
 	if err != nil {
 		return err
@@ -0,0 +1,136 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package storage // import "miniflux.app/storage"
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/lib/pq"
+
+	"miniflux.app/database"
+)
+
+// The helpers below generate the SQL fragments that differ between PostgreSQL and SQLite.
+// Arrays are native with PostgreSQL and stored as JSON with SQLite.
+
+func (s *Storage) isSQLite() bool {
+	return s.driver == database.SQLite
+}
+
+// inArray returns a condition matching when the expression is one of the values of the array placeholder.
+func (s *Storage) inArray(expr string, arg int) string {
+	if s.isSQLite() {
+		return fmt.Sprintf("%s IN (SELECT value FROM json_each($%d))", expr, arg)
+	}
+	return fmt.Sprintf("%s = ANY($%d)", expr, arg)
+}
+
+// arrayContains returns a condition matching when the array column contains the value of the placeholder.
+func (s *Storage) arrayContains(column string, arg int) string {
+	if s.isSQLite() {
+		return fmt.Sprintf("EXISTS (SELECT 1 FROM json_each(%s) WHERE value = $%d)", column, arg)
+	}
+	return fmt.Sprintf("$%d = ANY(%s)", arg, column)
+}
+
+// arrayTable returns a table expression with one row per value of the array placeholder, in a column named "value".
+func (s *Storage) arrayTable(arg int, elementType string) string {
+	if s.isSQLite() {
+		return fmt.Sprintf("json_each($%d)", arg)
+	}
+	return fmt.Sprintf("unnest($%d::%s[]) AS value", arg, elementType)
+}
+
+// arraySubquery returns an expression aggregating the column returned by the subquery into an array.
+func (s *Storage) arraySubquery(column, subquery string) string {
+	if s.isSQLite() {
+		return fmt.Sprintf("(SELECT json_group_array(%s) FROM (%s))", column, subquery)
+	}
+	return fmt.Sprintf("array(%s)", subquery)
+}
+
+// array converts a slice to a query argument.
+func (s *Storage) array(values interface{}) interface{} {
+	if s.isSQLite() {
+		return jsonArray{values}
+	}
+	return pq.Array(values)
+}
+
+// scanArray returns a scanner for an array column.
+func (s *Storage) scanArray(dest interface{}) sql.Scanner {
+	if s.isSQLite() {
+		return jsonArray{dest}
+	}
+	return pq.Array(dest)
+}
+
+// daysAgo returns an expression for the current time minus the given number of days.
+func (s *Storage) daysAgo(days int) string {
+	if s.isSQLite() {
+		return fmt.Sprintf("strftime('%%Y-%%m-%%d %%H:%%M:%%f', 'now', '-%d days')", days)
+	}
+	return fmt.Sprintf("now() - interval '%d days'", days)
+}
+
+// atTimeZone converts a timestamp column to the given timezone.
+// SQLite timestamps are returned in UTC and converted by the application.
+func (s *Storage) atTimeZone(column, timezone string) string {
+	if s.isSQLite() {
+		return column
+	}
+	return fmt.Sprintf("%s at time zone %s", column, timezone)
+}
+
+// unixMicroseconds converts a timestamp expression to the number of microseconds since the Unix epoch.
+// SQLite only keeps the milliseconds of the timestamp.
+func (s *Storage) unixMicroseconds(expr string) string {
+	if s.isSQLite() {
+		return fmt.Sprintf("CAST(round((julianday(%s) - 2440587.5) * 86400000) AS INTEGER) * 1000", expr)
+	}
+	return fmt.Sprintf("CAST(extract(epoch FROM %s) * 1000000 AS bigint)", expr)
+}
+
+// octetLength returns an expression for the size in bytes of a text column, NULL values count as zero.
+func (s *Storage) octetLength(column string) string {
+	if s.isSQLite() {
+		return fmt.Sprintf("coalesce(length(cast(%s AS blob)), 0)", column)
+	}
+	return fmt.Sprintf("coalesce(octet_length(%s), 0)", column)
+}
+
+// jsonArray stores a slice as a JSON array.
+type jsonArray struct {
+	values interface{}
+}
+
+// Value implements driver.Valuer.
+func (a jsonArray) Value() (driver.Value, error) {
+	if v := reflect.ValueOf(a.values); v.Kind() == reflect.Slice && v.IsNil() {
+		return "[]", nil
+	}
+
+	data, err := json.Marshal(a.values)
+	return string(data), err
+}
+
+// Scan implements sql.Scanner.
+func (a jsonArray) Scan(src interface{}) error {
+	switch data := src.(type) {
+	case nil:
+		return nil
+	case string:
+		return json.Unmarshal([]byte(data), a.values)
+	case []byte:
+		return json.Unmarshal(data, a.values)
+	default:
+		return errors.New("store: unable to scan array")
+	}
+}
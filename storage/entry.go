@@ -13,8 +13,6 @@ import (
 	"miniflux.app/crypto"
 	"miniflux.app/logger"
 	"miniflux.app/model"
-
-	"github.com/lib/pq"
 )
 
 // CountAllEntries returns the number of entries for each status in the database.
@@ -76,35 +74,49 @@ func (s *Storage) UpdateEntryContent(entry *model.Entry) error {
 		UPDATE
 			entries
 		SET
-			content=$1, reading_time=$2
+			content=$1, reading_time=$2, language=$3
 		WHERE
-			id=$3 AND user_id=$4
+			id=$4 AND user_id=$5
 	`
-	_, err = tx.Exec(query, entry.Content, entry.ReadingTime, entry.ID, entry.UserID)
+	_, err = tx.Exec(query, entry.Content, entry.ReadingTime, entry.Language, entry.ID, entry.UserID)
 	if err != nil {
 		tx.Rollback()
 		return fmt.Errorf(`store: unable to update content of entry #%d: %v`, entry.ID, err)
 	}
 
-	query = `
-		UPDATE
-			entries
-		SET
-			document_vectors = setweight(to_tsvector(left(coalesce(title, ''), 500000)), 'A') || setweight(to_tsvector(left(coalesce(content, ''), 500000)), 'B')
-		WHERE
-			id=$1 AND user_id=$2
-	`
-	_, err = tx.Exec(query, entry.ID, entry.UserID)
-	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf(`store: unable to update content of entry #%d: %v`, entry.ID, err)
+	if !s.isSQLite() {
+		query = `
+			UPDATE
+				entries
+			SET
+				search_config = $1::regconfig,
+				document_vectors = ` + documentVectors("title", "content", 1) + `
+			WHERE
+				id=$2 AND user_id=$3
+		`
+		_, err = tx.Exec(query, s.textSearchConfig(entry.Language), entry.ID, entry.UserID)
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf(`store: unable to update content of entry #%d: %v`, entry.ID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
 	}
 
-	return tx.Commit()
+	s.touchUser(entry.UserID)
+	return nil
 }
 
 // createEntry add a new entry.
 func (s *Storage) createEntry(tx *sql.Tx, entry *model.Entry) error {
+	var searchColumn, searchValue string
+	if !s.isSQLite() {
+		searchColumn = ", search_config, document_vectors"
+		searchValue = ", $13::regconfig, " + documentVectors("$1", "$6", 13)
+	}
+
 	query := `
 		INSERT INTO entries
 			(
@@ -119,8 +131,8 @@ func (s *Storage) createEntry(tx *sql.Tx, entry *model.Entry) error {
 				feed_id,
 				reading_time,
 				changed_at,
-				document_vectors,
-				tags
+				tags,
+				language` + searchColumn + `
 			)
 		VALUES
 			(
@@ -135,11 +147,11 @@ func (s *Storage) createEntry(tx *sql.Tx, entry *model.Entry) error {
 				$9,
 				$10,
 				now(),
-				setweight(to_tsvector(left(coalesce($1, ''), 500000)), 'A') || setweight(to_tsvector(left(coalesce($6, ''), 500000)), 'B'),
-				$11
+				$11,
+				$12` + searchValue + `
 			)
 		RETURNING
-			id, status
+			id, status, created_at, changed_at
 	`
 	err := tx.QueryRow(
 		query,
@@ -153,8 +165,10 @@ func (s *Storage) createEntry(tx *sql.Tx, entry *model.Entry) error {
 		entry.UserID,
 		entry.FeedID,
 		entry.ReadingTime,
-		pq.Array(removeDuplicates(entry.Tags)),
-	).Scan(&entry.ID, &entry.Status)
+		s.array(removeDuplicates(entry.Tags)),
+		entry.Language,
+		s.textSearchConfig(entry.Language),
+	).Scan(&entry.ID, &entry.Status, &entry.CreatedAt, &entry.ChangedAt)
 
 	if err != nil {
 		return fmt.Errorf(`store: unable to create entry %q (feed #%d): %v`, entry.URL, entry.FeedID, err)
@@ -175,8 +189,25 @@ func (s *Storage) createEntry(tx *sql.Tx, entry *model.Entry) error {
 // updateEntry updates an entry when a feed is refreshed.
 // Note: we do not update the published date because some feeds do not contains any date,
 // it default to time.Now() which could change the order of items on the history page.
+// The removed entries are not updated, their content may have been purged and they have no visible revisions.
 func (s *Storage) updateEntry(tx *sql.Tx, entry *model.Entry) error {
+	var searchAssignment string
+	if !s.isSQLite() {
+		searchAssignment = ", search_config = $12::regconfig, document_vectors = " + documentVectors("$1", "$4", 12)
+	}
+
+	// The previous values are returned by the same statement to create the revision.
+	// The statements of a query see the rows as they were before the update on PostgreSQL,
+	// SQLite needs the materialization of the common table expression used by the condition.
+	previous := "previous AS"
+	if s.isSQLite() {
+		previous = "previous AS MATERIALIZED"
+	}
+
 	query := `
+		WITH ` + previous + ` (
+			SELECT id, title, content FROM entries WHERE user_id=$7 AND feed_id=$8 AND hash=$9 AND status <> $13
+		)
 		UPDATE
 			entries
 		SET
@@ -186,13 +217,16 @@ func (s *Storage) updateEntry(tx *sql.Tx, entry *model.Entry) error {
 			content=$4,
 			author=$5,
 			reading_time=$6,
-			document_vectors = setweight(to_tsvector(left(coalesce($1, ''), 500000)), 'A') || setweight(to_tsvector(left(coalesce($4, ''), 500000)), 'B'),
-			tags=$10
+			tags=$10,
+			language=$11` + searchAssignment + `
 		WHERE
-			user_id=$7 AND feed_id=$8 AND hash=$9
+			id=(SELECT id FROM previous)
 		RETURNING
-			id
+			id,
+			(SELECT title FROM previous),
+			(SELECT coalesce(content, '') FROM previous)
 	`
+	var previousTitle, previousContent string
 	err := tx.QueryRow(
 		query,
 		entry.Title,
@@ -204,13 +238,25 @@ func (s *Storage) updateEntry(tx *sql.Tx, entry *model.Entry) error {
 		entry.UserID,
 		entry.FeedID,
 		entry.Hash,
-		pq.Array(removeDuplicates(entry.Tags)),
-	).Scan(&entry.ID)
-
-	if err != nil {
+		s.array(removeDuplicates(entry.Tags)),
+		entry.Language,
+		s.textSearchConfig(entry.Language),
+		model.EntryStatusRemoved,
+	).Scan(&entry.ID, &previousTitle, &previousContent)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return nil
+	case err != nil:
 		return fmt.Errorf(`store: unable to update entry %q: %v`, entry.URL, err)
 	}
 
+	if previousTitle != entry.Title || previousContent != entry.Content {
+		if err := s.createEntryRevision(tx, entry.ID, previousTitle, previousContent); err != nil {
+			return err
+		}
+	}
+
 	for _, enclosure := range entry.Enclosures {
 		enclosure.UserID = entry.UserID
 		enclosure.EntryID = entry.ID
@@ -253,9 +299,9 @@ func (s *Storage) cleanupEntries(feedID int64, entryHashes []string) error {
 		WHERE
 			feed_id=$1
 		AND
-			id IN (SELECT id FROM entries WHERE feed_id=$2 AND status=$3 AND NOT (hash=ANY($4)))
+			id IN (SELECT id FROM entries WHERE feed_id=$2 AND status=$3 AND NOT ` + s.inArray("hash", 4) + `)
 	`
-	if _, err := s.db.Exec(query, feedID, feedID, model.EntryStatusRemoved, pq.Array(entryHashes)); err != nil {
+	if _, err := s.db.Exec(query, feedID, feedID, model.EntryStatusRemoved, s.array(entryHashes)); err != nil {
 		return fmt.Errorf(`store: unable to cleanup entries: %v`, err)
 	}
 
@@ -265,6 +311,7 @@ func (s *Storage) cleanupEntries(feedID int64, entryHashes []string) error {
 // RefreshFeedEntries updates feed entries while refreshing a feed.
 func (s *Storage) RefreshFeedEntries(userID, feedID int64, entries model.Entries, updateExistingEntries bool) (err error) {
 	var entryHashes []string
+	var newEntries model.Entries
 
 	for _, entry := range entries {
 		entry.UserID = userID
@@ -281,6 +328,7 @@ func (s *Storage) RefreshFeedEntries(userID, feedID int64, entries model.Entries
 			}
 		} else {
 			err = s.createEntry(tx, entry)
+			newEntries = append(newEntries, entry)
 		}
 
 		if err != nil {
@@ -295,6 +343,13 @@ func (s *Storage) RefreshFeedEntries(userID, feedID int64, entries model.Entries
 		entryHashes = append(entryHashes, entry.Hash)
 	}
 
+	if len(newEntries) > 0 || (updateExistingEntries && len(entryHashes) > 0) {
+		s.touchUser(userID)
+	}
+
+	s.fireNewEntriesWebhookEvent(userID, feedID, newEntries)
+	publishNewEntries(userID, feedID, newEntries)
+
 	go func() {
 		if err := s.cleanupEntries(feedID, entryHashes); err != nil {
 			logger.Error(`store: feed #%d: %v`, feedID, err)
@@ -316,38 +371,54 @@ func (s *Storage) ArchiveEntries(status string, days, limit int) (int64, error)
 		SET
 			status='removed'
 		WHERE
-			id=ANY(SELECT id FROM entries WHERE status=$1 AND starred is false AND share_code='' AND created_at < now () - '%d days'::interval ORDER BY created_at ASC LIMIT %d)
+			id IN (SELECT id FROM entries WHERE status=$1 AND starred is false AND share_code='' AND NOT EXISTS (SELECT 1 FROM entry_labels el WHERE el.entry_id=entries.id) AND NOT EXISTS (SELECT 1 FROM entry_annotations ea WHERE ea.entry_id=entries.id) AND created_at < %s ORDER BY created_at ASC LIMIT %d)
+		RETURNING
+			user_id
 	`
 
-	result, err := s.db.Exec(fmt.Sprintf(query, days, limit), status)
+	rows, err := s.db.Query(fmt.Sprintf(query, s.daysAgo(days), limit), status)
 	if err != nil {
 		return 0, fmt.Errorf(`store: unable to archive %s entries: %v`, status, err)
 	}
 
-	count, err := result.RowsAffected()
+	count, userIDs, err := userIDsReturned(rows)
 	if err != nil {
-		return 0, fmt.Errorf(`store: unable to get the number of rows affected: %v`, err)
+		return 0, fmt.Errorf(`store: unable to archive %s entries: %v`, status, err)
 	}
 
+	s.touchUsers(userIDs)
 	return count, nil
 }
 
 // SetEntriesStatus update the status of the given list of entries.
 func (s *Storage) SetEntriesStatus(userID int64, entryIDs []int64, status string) error {
-	query := `UPDATE entries SET status=$1, changed_at=now() WHERE user_id=$2 AND id=ANY($3)`
-	result, err := s.db.Exec(query, status, userID, pq.Array(entryIDs))
+	// Only the entries with a different status are updated, the events are not sent twice for the same change.
+	query := `UPDATE entries SET status=$1, changed_at=now() WHERE user_id=$2 AND status <> $1 AND ` + s.inArray("id", 3) + ` RETURNING id`
+	changedEntryIDs, err := s.updateEntriesReturningIDs(query, status, userID, s.array(entryIDs))
 	if err != nil {
 		return fmt.Errorf(`store: unable to update entries statuses %v: %v`, entryIDs, err)
 	}
 
-	count, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf(`store: unable to update these entries %v: %v`, entryIDs, err)
+	if len(changedEntryIDs) == 0 {
+		var count int
+		query = `SELECT count(*) FROM entries WHERE user_id=$1 AND ` + s.inArray("id", 2)
+		if err := s.db.QueryRow(query, userID, s.array(entryIDs)).Scan(&count); err != nil {
+			return fmt.Errorf(`store: unable to count entries %v: %v`, entryIDs, err)
+		}
+
+		if count == 0 {
+			return errors.New(`store: nothing has been updated`)
+		}
+
+		return nil
 	}
 
-	if count == 0 {
-		return errors.New(`store: nothing has been updated`)
+	s.touchUser(userID)
+
+	if status == model.EntryStatusRead {
+		s.fireEntriesWebhookEvent(userID, model.WebhookEventEntryRead, changedEntryIDs)
 	}
+	publishEntriesStatus(userID, changedEntryIDs, status)
 
 	return nil
 }
@@ -363,11 +434,11 @@ func (s *Storage) SetEntriesStatusCount(userID int64, entryIDs []int64, status s
 		    JOIN feeds f ON (f.id = e.feed_id)
 		    JOIN categories c ON (c.id = f.category_id)
 		WHERE e.user_id = $1
-			AND e.id = ANY($2)
+			AND ` + s.inArray("e.id", 2) + `
 			AND NOT f.hide_globally
 			AND NOT c.hide_globally
 	`
-	row := s.db.QueryRow(query, userID, pq.Array(entryIDs))
+	row := s.db.QueryRow(query, userID, s.array(entryIDs))
 	visible := 0
 	if err := row.Scan(&visible); err != nil {
 		return 0, fmt.Errorf(`store: unable to query entries visibility %v: %v`, entryIDs, err)
@@ -378,8 +449,8 @@ func (s *Storage) SetEntriesStatusCount(userID int64, entryIDs []int64, status s
 
 // SetEntriesBookmarked update the bookmarked state for the given list of entries.
 func (s *Storage) SetEntriesBookmarkedState(userID int64, entryIDs []int64, starred bool) error {
-	query := `UPDATE entries SET starred=$1, changed_at=now() WHERE user_id=$2 AND id=ANY($3)`
-	result, err := s.db.Exec(query, starred, userID, pq.Array(entryIDs))
+	query := `UPDATE entries SET starred=$1, changed_at=now() WHERE user_id=$2 AND ` + s.inArray("id", 3)
+	result, err := s.db.Exec(query, starred, userID, s.array(entryIDs))
 	if err != nil {
 		return fmt.Errorf(`store: unable to update the bookmarked state %v: %v`, entryIDs, err)
 	}
@@ -393,25 +464,34 @@ func (s *Storage) SetEntriesBookmarkedState(userID int64, entryIDs []int64, star
 		return errors.New(`store: nothing has been updated`)
 	}
 
+	s.touchUser(userID)
+
+	if starred {
+		s.fireEntriesWebhookEvent(userID, model.WebhookEventEntryStarred, entryIDs)
+	}
+	publishEntriesStarred(userID, entryIDs, starred)
+
 	return nil
 }
 
 // ToggleBookmark toggles entry bookmark value.
 func (s *Storage) ToggleBookmark(userID int64, entryID int64) error {
-	query := `UPDATE entries SET starred = NOT starred, changed_at=now() WHERE user_id=$1 AND id=$2`
-	result, err := s.db.Exec(query, userID, entryID)
-	if err != nil {
+	query := `UPDATE entries SET starred = NOT starred, changed_at=now() WHERE user_id=$1 AND id=$2 RETURNING starred`
+	var starred bool
+	err := s.db.QueryRow(query, userID, entryID).Scan(&starred)
+	switch {
+	case err == sql.ErrNoRows:
+		return errors.New(`store: nothing has been updated`)
+	case err != nil:
 		return fmt.Errorf(`store: unable to toggle bookmark flag for entry #%d: %v`, entryID, err)
 	}
 
-	count, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf(`store: unable to toggle bookmark flag for entry #%d: %v`, entryID, err)
-	}
+	s.touchUser(userID)
 
-	if count == 0 {
-		return errors.New(`store: nothing has been updated`)
+	if starred {
+		s.fireEntriesWebhookEvent(userID, model.WebhookEventEntryStarred, []int64{entryID})
 	}
+	publishEntriesStarred(userID, []int64{entryID}, starred)
 
 	return nil
 }
@@ -426,25 +506,32 @@ func (s *Storage) FlushHistory(userID int64) error {
 			changed_at=now()
 		WHERE
 			user_id=$2 AND status=$3 AND starred is false AND share_code=''
+		AND
+			NOT EXISTS (SELECT 1 FROM entry_labels el WHERE el.entry_id=entries.id)
+		AND
+			NOT EXISTS (SELECT 1 FROM entry_annotations ea WHERE ea.entry_id=entries.id)
 	`
 	_, err := s.db.Exec(query, model.EntryStatusRemoved, userID, model.EntryStatusRead)
 	if err != nil {
 		return fmt.Errorf(`store: unable to flush history: %v`, err)
 	}
 
+	s.touchUser(userID)
 	return nil
 }
 
 // MarkAllAsRead updates all user entries to the read status.
 func (s *Storage) MarkAllAsRead(userID int64) error {
-	query := `UPDATE entries SET status=$1, changed_at=now() WHERE user_id=$2 AND status=$3`
-	result, err := s.db.Exec(query, model.EntryStatusRead, userID, model.EntryStatusUnread)
+	query := `UPDATE entries SET status=$1, changed_at=now() WHERE user_id=$2 AND status=$3 RETURNING id`
+	entryIDs, err := s.updateEntriesReturningIDs(query, model.EntryStatusRead, userID, model.EntryStatusUnread)
 	if err != nil {
 		return fmt.Errorf(`store: unable to mark all entries as read: %v`, err)
 	}
 
-	count, _ := result.RowsAffected()
-	logger.Debug("[Storage:MarkAllAsRead] %d items marked as read", count)
+	logger.Debug("[Storage:MarkAllAsRead] %d items marked as read", len(entryIDs))
+	s.touchUser(userID)
+	s.fireEntriesWebhookEvent(userID, model.WebhookEventEntryRead, entryIDs)
+	publishEntriesStatus(userID, entryIDs, model.EntryStatusRead)
 
 	return nil
 }
@@ -459,14 +546,18 @@ func (s *Storage) MarkFeedAsRead(userID, feedID int64, before time.Time) error {
 			changed_at=now()
 		WHERE
 			user_id=$2 AND feed_id=$3 AND status=$4 AND published_at < $5
+		RETURNING
+			id
 	`
-	result, err := s.db.Exec(query, model.EntryStatusRead, userID, feedID, model.EntryStatusUnread, before)
+	entryIDs, err := s.updateEntriesReturningIDs(query, model.EntryStatusRead, userID, feedID, model.EntryStatusUnread, before)
 	if err != nil {
 		return fmt.Errorf(`store: unable to mark feed entries as read: %v`, err)
 	}
 
-	count, _ := result.RowsAffected()
-	logger.Debug("[Storage:MarkFeedAsRead] %d items marked as read", count)
+	logger.Debug("[Storage:MarkFeedAsRead] %d items marked as read", len(entryIDs))
+	s.touchUser(userID)
+	s.fireEntriesWebhookEvent(userID, model.WebhookEventEntryRead, entryIDs)
+	publishEntriesStatus(userID, entryIDs, model.EntryStatusRead)
 
 	return nil
 }
@@ -487,18 +578,75 @@ func (s *Storage) MarkCategoryAsRead(userID, categoryID int64, before time.Time)
 			published_at < $4
 		AND
 			feed_id IN (SELECT id FROM feeds WHERE user_id=$2 AND category_id=$5)
+		RETURNING
+			id
 	`
-	result, err := s.db.Exec(query, model.EntryStatusRead, userID, model.EntryStatusUnread, before, categoryID)
+	entryIDs, err := s.updateEntriesReturningIDs(query, model.EntryStatusRead, userID, model.EntryStatusUnread, before, categoryID)
 	if err != nil {
 		return fmt.Errorf(`store: unable to mark category entries as read: %v`, err)
 	}
 
-	count, _ := result.RowsAffected()
-	logger.Debug("[Storage:MarkCategoryAsRead] %d items marked as read", count)
+	logger.Debug("[Storage:MarkCategoryAsRead] %d items marked as read", len(entryIDs))
+	s.touchUser(userID)
+	s.fireEntriesWebhookEvent(userID, model.WebhookEventEntryRead, entryIDs)
+	publishEntriesStatus(userID, entryIDs, model.EntryStatusRead)
 
 	return nil
 }
 
+// updateEntriesReturningIDs executes an update query returning the ID of the modified entries.
+func (s *Storage) updateEntriesReturningIDs(query string, args ...interface{}) ([]int64, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entryIDs []int64
+	for rows.Next() {
+		var entryID int64
+		if err := rows.Scan(&entryID); err != nil {
+			return nil, err
+		}
+		entryIDs = append(entryIDs, entryID)
+	}
+
+	return entryIDs, rows.Err()
+}
+
+// EntriesContent returns the most recent entries published between the two dates,
+// only the ID, the URL, the publication date and the content are fetched.
+func (s *Storage) EntriesContent(userID int64, from, to time.Time, limit int) (model.Entries, error) {
+	query := `
+		SELECT
+			id, url, published_at, coalesce(content, '')
+		FROM
+			entries
+		WHERE
+			user_id=$1 AND status <> $2 AND published_at > $3 AND published_at < $4
+		ORDER BY published_at DESC, id DESC
+		LIMIT $5
+	`
+	rows, err := s.db.Query(query, userID, model.EntryStatusRemoved, from, to, limit)
+	if err != nil {
+		return nil, fmt.Errorf(`store: unable to fetch entries content: %v`, err)
+	}
+	defer rows.Close()
+
+	entries := make(model.Entries, 0)
+	for rows.Next() {
+		var entry model.Entry
+		if err := rows.Scan(&entry.ID, &entry.URL, &entry.Date, &entry.Content); err != nil {
+			return nil, fmt.Errorf(`store: unable to fetch entry content row: %v`, err)
+		}
+
+		entry.UserID = userID
+		entries = append(entries, &entry)
+	}
+
+	return entries, nil
+}
+
 // EntryURLExists returns true if an entry with this URL already exists.
 func (s *Storage) EntryURLExists(feedID int64, entryURL string) bool {
 	var result bool
@@ -507,6 +655,30 @@ func (s *Storage) EntryURLExists(feedID int64, entryURL string) bool {
 	return result
 }
 
+// EntryExists returns true if the entry belongs to the user and has not been removed.
+func (s *Storage) EntryExists(userID, entryID int64) bool {
+	var result bool
+	query := `SELECT true FROM entries WHERE user_id=$1 AND id=$2 AND status <> 'removed'`
+	s.db.QueryRow(query, userID, entryID).Scan(&result)
+	return result
+}
+
+// EntryCategoryID returns the category of the entry feed, zero is returned when the entry does not exist.
+func (s *Storage) EntryCategoryID(userID, entryID int64) int64 {
+	var categoryID int64
+	query := `SELECT f.category_id FROM entries e INNER JOIN feeds f ON f.id=e.feed_id WHERE e.user_id=$1 AND e.id=$2`
+	s.db.QueryRow(query, userID, entryID).Scan(&categoryID)
+	return categoryID
+}
+
+// EntryIDByHash returns the ID of the feed entry having the given hash, zero is returned when the entry does not exist.
+func (s *Storage) EntryIDByHash(userID, feedID int64, hash string) int64 {
+	var entryID int64
+	query := `SELECT id FROM entries WHERE user_id=$1 AND feed_id=$2 AND hash=$3 AND status <> 'removed'`
+	s.db.QueryRow(query, userID, feedID, hash).Scan(&entryID)
+	return entryID
+}
+
 // EntryShareCode returns the share code of the provided entry.
 // It generates a new one if not already defined.
 func (s *Storage) EntryShareCode(userID int64, entryID int64) (shareCode string, err error) {
@@ -526,6 +698,8 @@ func (s *Storage) EntryShareCode(userID int64, entryID int64) (shareCode string,
 			err = fmt.Errorf(`store: unable to set share code for entry #%d: %v`, entryID, err)
 			return
 		}
+
+		s.touchUser(userID)
 	}
 
 	return
@@ -537,7 +711,10 @@ func (s *Storage) UnshareEntry(userID int64, entryID int64) (err error) {
 	_, err = s.db.Exec(query, userID, entryID)
 	if err != nil {
 		err = fmt.Errorf(`store: unable to remove share code for entry #%d: %v`, entryID, err)
+		return
 	}
+
+	s.touchUser(userID)
 	return
 }
 
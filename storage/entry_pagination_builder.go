@@ -25,10 +25,11 @@ type EntryPaginationBuilder struct {
 }
 
 // WithSearchQuery adds full-text search query to the condition.
-func (e *EntryPaginationBuilder) WithSearchQuery(query string) {
+func (e *EntryPaginationBuilder) WithSearchQuery(query, language string) {
 	if query != "" {
-		e.conditions = append(e.conditions, fmt.Sprintf("e.document_vectors @@ plainto_tsquery($%d)", len(e.args)+1))
-		e.args = append(e.args, query)
+		nArgs := len(e.args) + 1
+		e.conditions = append(e.conditions, e.store.entrySearchCondition(nArgs, nArgs+1))
+		e.args = append(e.args, e.store.searchArguments(query, language)...)
 	}
 }
 
@@ -37,6 +38,14 @@ func (e *EntryPaginationBuilder) WithStarred() {
 	e.conditions = append(e.conditions, "e.starred is true")
 }
 
+// WithLabelID adds a user label to the condition.
+func (e *EntryPaginationBuilder) WithLabelID(labelID int64) {
+	if labelID != 0 {
+		e.conditions = append(e.conditions, fmt.Sprintf("EXISTS (SELECT 1 FROM entry_labels el WHERE el.entry_id = e.id AND el.label_id = $%d)", len(e.args)+1))
+		e.args = append(e.args, labelID)
+	}
+}
+
 // WithFeedID adds feed_id to the condition.
 func (e *EntryPaginationBuilder) WithFeedID(feedID int64) {
 	if feedID != 0 {
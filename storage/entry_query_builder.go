@@ -10,32 +10,37 @@ import (
 	"strings"
 	"time"
 
-	"github.com/lib/pq"
-
 	"miniflux.app/model"
 	"miniflux.app/timezone"
 )
 
 // EntryQueryBuilder builds a SQL query to fetch entries.
 type EntryQueryBuilder struct {
-	store      *Storage
-	args       []interface{}
-	conditions []string
-	order      string
-	direction  string
-	limit      int
-	offset     int
+	store           *Storage
+	args            []interface{}
+	conditions      []string
+	order           string
+	direction       string
+	limit           int
+	offset          int
+	searchQuery     string
+	searchArg       int
+	keyset          bool
+	cursor          *model.EntryCursor
+	withAnnotations bool
 }
 
 // WithSearchQuery adds full-text search query to the condition.
-func (e *EntryQueryBuilder) WithSearchQuery(query string) *EntryQueryBuilder {
+// The entries are searched with the configuration of their language, the annotations with the given language.
+func (e *EntryQueryBuilder) WithSearchQuery(query, language string) *EntryQueryBuilder {
 	if query != "" {
 		nArgs := len(e.args) + 1
-		e.conditions = append(e.conditions, fmt.Sprintf("e.document_vectors @@ plainto_tsquery($%d)", nArgs))
-		e.args = append(e.args, query)
+		e.conditions = append(e.conditions, e.store.entrySearchCondition(nArgs, nArgs+1))
+		e.args = append(e.args, e.store.searchArguments(query, language)...)
+		e.searchQuery = query
+		e.searchArg = nArgs
 
-		// 0.0000001 = 0.1 / (seconds_in_a_day)
-		e.WithOrder(fmt.Sprintf("ts_rank(document_vectors, plainto_tsquery($%d)) - extract (epoch from now() - published_at)::float * 0.0000001", nArgs))
+		e.WithOrder(e.store.entrySearchRanking(nArgs, nArgs+1))
 		e.WithDirection("DESC")
 	}
 	return e
@@ -65,6 +70,13 @@ func (e *EntryQueryBuilder) AfterDate(date time.Time) *EntryQueryBuilder {
 	return e
 }
 
+// ChangedSince adds a condition >= changed_at, it returns the entries modified since the given date.
+func (e *EntryQueryBuilder) ChangedSince(date time.Time) *EntryQueryBuilder {
+	e.conditions = append(e.conditions, fmt.Sprintf("e.changed_at >= $%d", len(e.args)+1))
+	e.args = append(e.args, date)
+	return e
+}
+
 // BeforeEntryID adds a condition < entryID.
 func (e *EntryQueryBuilder) BeforeEntryID(entryID int64) *EntryQueryBuilder {
 	if entryID != 0 {
@@ -85,8 +97,15 @@ func (e *EntryQueryBuilder) AfterEntryID(entryID int64) *EntryQueryBuilder {
 
 // WithEntryIDs filter by entry IDs.
 func (e *EntryQueryBuilder) WithEntryIDs(entryIDs []int64) *EntryQueryBuilder {
-	e.conditions = append(e.conditions, fmt.Sprintf("e.id = ANY($%d)", len(e.args)+1))
-	e.args = append(e.args, pq.Int64Array(entryIDs))
+	e.conditions = append(e.conditions, e.store.inArray("e.id", len(e.args)+1))
+	e.args = append(e.args, e.store.array(entryIDs))
+	return e
+}
+
+// WithURLs filter by entry URLs.
+func (e *EntryQueryBuilder) WithURLs(urls []string) *EntryQueryBuilder {
+	e.conditions = append(e.conditions, e.store.inArray("e.url", len(e.args)+1))
+	e.args = append(e.args, e.store.array(urls))
 	return e
 }
 
@@ -117,6 +136,15 @@ func (e *EntryQueryBuilder) WithCategoryID(categoryID int64) *EntryQueryBuilder
 	return e
 }
 
+// WithCategoryIDs filter by a list of categories, no filter is applied when the list is empty.
+func (e *EntryQueryBuilder) WithCategoryIDs(categoryIDs []int64) *EntryQueryBuilder {
+	if len(categoryIDs) > 0 {
+		e.conditions = append(e.conditions, e.store.inArray("f.category_id", len(e.args)+1))
+		e.args = append(e.args, e.store.array(categoryIDs))
+	}
+	return e
+}
+
 // WithStatus filter by entry status.
 func (e *EntryQueryBuilder) WithStatus(status string) *EntryQueryBuilder {
 	if status != "" {
@@ -129,8 +157,8 @@ func (e *EntryQueryBuilder) WithStatus(status string) *EntryQueryBuilder {
 // WithStatuses filter by a list of entry statuses.
 func (e *EntryQueryBuilder) WithStatuses(statuses []string) *EntryQueryBuilder {
 	if len(statuses) > 0 {
-		e.conditions = append(e.conditions, fmt.Sprintf("e.status = ANY($%d)", len(e.args)+1))
-		e.args = append(e.args, pq.StringArray(statuses))
+		e.conditions = append(e.conditions, e.store.inArray("e.status", len(e.args)+1))
+		e.args = append(e.args, e.store.array(statuses))
 	}
 	return e
 }
@@ -139,13 +167,22 @@ func (e *EntryQueryBuilder) WithStatuses(statuses []string) *EntryQueryBuilder {
 func (e *EntryQueryBuilder) WithTags(tags []string) *EntryQueryBuilder {
 	if len(tags) > 0 {
 		for _, cat := range tags {
-			e.conditions = append(e.conditions, fmt.Sprintf("$%d = ANY(e.tags)", len(e.args)+1))
+			e.conditions = append(e.conditions, e.store.arrayContains("e.tags", len(e.args)+1))
 			e.args = append(e.args, cat)
 		}
 	}
 	return e
 }
 
+// WithLabelID filter by user label ID.
+func (e *EntryQueryBuilder) WithLabelID(labelID int64) *EntryQueryBuilder {
+	if labelID > 0 {
+		e.conditions = append(e.conditions, fmt.Sprintf("EXISTS (SELECT 1 FROM entry_labels el WHERE el.entry_id = e.id AND el.label_id = $%d)", len(e.args)+1))
+		e.args = append(e.args, labelID)
+	}
+	return e
+}
+
 // WithoutStatus set the entry status that should not be returned.
 func (e *EntryQueryBuilder) WithoutStatus(status string) *EntryQueryBuilder {
 	if status != "" {
@@ -180,6 +217,14 @@ func (e *EntryQueryBuilder) WithDirection(direction string) *EntryQueryBuilder {
 	return e
 }
 
+// WithCursor sorts entries by publication date and ID, and returns the entries located after the cursor.
+// The cursor is optional for the first page, it is ignored when counting entries.
+func (e *EntryQueryBuilder) WithCursor(cursor *model.EntryCursor) *EntryQueryBuilder {
+	e.keyset = true
+	e.cursor = cursor
+	return e
+}
+
 // WithLimit set the limit.
 func (e *EntryQueryBuilder) WithLimit(limit int) *EntryQueryBuilder {
 	if limit > 0 {
@@ -202,6 +247,12 @@ func (e *EntryQueryBuilder) WithGloballyVisible() *EntryQueryBuilder {
 	return e
 }
 
+// WithAnnotations loads the annotations of the entry returned by GetEntry.
+func (e *EntryQueryBuilder) WithAnnotations() *EntryQueryBuilder {
+	e.withAnnotations = true
+	return e
+}
+
 // CountEntries count the number of entries that match the condition.
 func (e *EntryQueryBuilder) CountEntries() (count int, err error) {
 	query := `
@@ -238,30 +289,43 @@ func (e *EntryQueryBuilder) GetEntry() (*model.Entry, error) {
 		return nil, err
 	}
 
+	if e.withAnnotations {
+		entries[0].Annotations, err = e.store.EntryAnnotations(entries[0].UserID, entries[0].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return entries[0], nil
 }
 
 // GetEntries returns a list of entries that match the condition.
 func (e *EntryQueryBuilder) GetEntries() (model.Entries, error) {
+	// The aliases are required by SQLite: the feeds and categories tables have columns with the same names,
+	// and an unqualified sort column like "title" or "status" would be ambiguous otherwise.
 	query := `
 		SELECT
-			e.id,
+			e.id as id,
 			e.user_id,
 			e.feed_id,
 			e.hash,
-			e.published_at at time zone u.timezone,
-			e.title,
+			%s,
+			e.title as title,
 			e.url,
 			e.comments_url,
 			e.author,
 			e.share_code,
 			e.content,
-			e.status,
+			e.status as status,
 			e.starred,
 			e.reading_time,
-			e.created_at,
-			e.changed_at,
+			e.created_at as created_at,
+			e.changed_at as changed_at,
 			e.tags,
+			e.language,
+			e.revision_count,
+			%s as labels,
+			%s as snippet,
 			f.title as feed_title,
 			f.feed_url,
 			f.site_url,
@@ -287,11 +351,18 @@ func (e *EntryQueryBuilder) GetEntries() (model.Entries, error) {
 		WHERE %s %s
 	`
 
-	condition := e.buildCondition()
+	condition, args := e.buildCursorCondition()
 	sorting := e.buildSorting()
-	query = fmt.Sprintf(query, condition, sorting)
-
-	rows, err := e.store.db.Query(query, e.args...)
+	query = fmt.Sprintf(
+		query,
+		e.store.atTimeZone("e.published_at", "u.timezone"),
+		e.store.arraySubquery("title", "SELECT l.title FROM entry_labels el JOIN labels l ON l.id = el.label_id WHERE el.entry_id = e.id ORDER BY l.title"),
+		e.buildSnippet(),
+		condition,
+		sorting,
+	)
+
+	rows, err := e.store.db.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("unable to get entries: %v", err)
 	}
@@ -324,7 +395,11 @@ func (e *EntryQueryBuilder) GetEntries() (model.Entries, error) {
 			&entry.ReadingTime,
 			&entry.CreatedAt,
 			&entry.ChangedAt,
-			pq.Array(&entry.Tags),
+			e.store.scanArray(&entry.Tags),
+			&entry.Language,
+			&entry.RevisionCount,
+			e.store.scanArray(&entry.Labels),
+			&entry.Snippet,
 			&entry.Feed.Title,
 			&entry.Feed.FeedURL,
 			&entry.Feed.SiteURL,
@@ -356,6 +431,8 @@ func (e *EntryQueryBuilder) GetEntries() (model.Entries, error) {
 		entry.ChangedAt = timezone.Convert(tz, entry.ChangedAt)
 		entry.Feed.CheckedAt = timezone.Convert(tz, entry.Feed.CheckedAt)
 
+		entry.Snippet = formatSearchSnippet(entry.Snippet)
+
 		entry.Feed.ID = entry.FeedID
 		entry.Feed.UserID = entry.UserID
 		entry.Feed.Icon.FeedID = entry.FeedID
@@ -370,10 +447,10 @@ func (e *EntryQueryBuilder) GetEntries() (model.Entries, error) {
 func (e *EntryQueryBuilder) GetEntryIDs() ([]int64, error) {
 	query := `SELECT e.id FROM entries e LEFT JOIN feeds f ON f.id=e.feed_id WHERE %s %s`
 
-	condition := e.buildCondition()
+	condition, args := e.buildCursorCondition()
 	query = fmt.Sprintf(query, condition, e.buildSorting())
 
-	rows, err := e.store.db.Query(query, e.args...)
+	rows, err := e.store.db.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("unable to get entries: %v", err)
 	}
@@ -398,15 +475,44 @@ func (e *EntryQueryBuilder) buildCondition() string {
 	return strings.Join(e.conditions, " AND ")
 }
 
+// buildCursorCondition returns the condition and the arguments used to fetch the entries located after the cursor.
+func (e *EntryQueryBuilder) buildCursorCondition() (string, []interface{}) {
+	condition := e.buildCondition()
+	if e.cursor == nil {
+		return condition, e.args
+	}
+
+	operator := ">"
+	if strings.EqualFold(e.direction, "desc") {
+		operator = "<"
+	}
+
+	condition += fmt.Sprintf(" AND (e.published_at, e.id) %s ($%d, $%d)", operator, len(e.args)+1, len(e.args)+2)
+	args := append(e.args[:len(e.args):len(e.args)], e.cursor.PublishedAt, e.cursor.EntryID)
+	return condition, args
+}
+
+func (e *EntryQueryBuilder) buildSnippet() string {
+	if e.searchQuery == "" {
+		return "''"
+	}
+
+	return e.store.entrySearchSnippet(e.searchArg, e.searchArg+1)
+}
+
 func (e *EntryQueryBuilder) buildSorting() string {
 	var parts []string
 
-	if e.order != "" {
-		parts = append(parts, fmt.Sprintf(`ORDER BY %s`, e.order))
-	}
+	if e.keyset {
+		parts = append(parts, fmt.Sprintf(`ORDER BY e.published_at %[1]s, e.id %[1]s`, e.direction))
+	} else {
+		if e.order != "" {
+			parts = append(parts, fmt.Sprintf(`ORDER BY %s`, e.order))
+		}
 
-	if e.direction != "" {
-		parts = append(parts, e.direction)
+		if e.direction != "" {
+			parts = append(parts, e.direction)
+		}
 	}
 
 	if e.limit > 0 {
@@ -0,0 +1,98 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package storage // import "miniflux.app/storage"
+
+import (
+	"database/sql"
+	"fmt"
+
+	"miniflux.app/config"
+	"miniflux.app/model"
+)
+
+// createEntryRevision keeps the previous version of an updated entry
+// and marks the entry as unread again if the feed asks for it.
+func (s *Storage) createEntryRevision(tx *sql.Tx, entryID int64, title, content string) error {
+	query := `
+		UPDATE
+			entries
+		SET
+			status=$2,
+			changed_at=now()
+		WHERE
+			id=$1 AND status=$3 AND EXISTS (
+				SELECT 1 FROM feeds f WHERE f.id=entries.feed_id AND f.mark_updated_entries_unread is true
+			)
+	`
+	if _, err := tx.Exec(query, entryID, model.EntryStatusUnread, model.EntryStatusRead); err != nil {
+		return fmt.Errorf(`store: unable to mark updated entry #%d as unread: %v`, entryID, err)
+	}
+
+	limit := config.Opts.EntryRevisionsLimit()
+	if limit <= 0 {
+		return nil
+	}
+
+	query = `INSERT INTO entry_revisions (entry_id, title, content) VALUES ($1, $2, $3)`
+	if _, err := tx.Exec(query, entryID, title, content); err != nil {
+		return fmt.Errorf(`store: unable to create revision for entry #%d: %v`, entryID, err)
+	}
+
+	query = `
+		DELETE FROM
+			entry_revisions
+		WHERE
+			entry_id=$1 AND id NOT IN (
+				SELECT id FROM entry_revisions WHERE entry_id=$1 ORDER BY id DESC LIMIT $2
+			)
+	`
+	if _, err := tx.Exec(query, entryID, limit); err != nil {
+		return fmt.Errorf(`store: unable to remove old revisions of entry #%d: %v`, entryID, err)
+	}
+
+	query = `UPDATE entries SET revision_count=revision_count+1 WHERE id=$1`
+	if _, err := tx.Exec(query, entryID); err != nil {
+		return fmt.Errorf(`store: unable to update revision count of entry #%d: %v`, entryID, err)
+	}
+
+	return nil
+}
+
+// EntryRevisions returns the previous versions of an entry, the most recent first.
+func (s *Storage) EntryRevisions(userID, entryID int64) (model.EntryRevisions, error) {
+	query := `
+		SELECT
+			r.id,
+			r.entry_id,
+			r.title,
+			r.content,
+			r.created_at
+		FROM
+			entry_revisions r
+		JOIN
+			entries e ON e.id=r.entry_id
+		WHERE
+			e.user_id=$1 AND r.entry_id=$2
+		ORDER BY
+			r.id DESC
+	`
+	rows, err := s.db.Query(query, userID, entryID)
+	if err != nil {
+		return nil, fmt.Errorf(`store: unable to fetch revisions of entry #%d: %v`, entryID, err)
+	}
+	defer rows.Close()
+
+	revisions := make(model.EntryRevisions, 0)
+	for rows.Next() {
+		var revision model.EntryRevision
+		if err := rows.Scan(&revision.ID, &revision.EntryID, &revision.Title, &revision.Content, &revision.CreatedAt); err != nil {
+			return nil, fmt.Errorf(`store: unable to fetch revision row: %v`, err)
+		}
+
+		revisions = append(revisions, &revision)
+	}
+
+	return revisions, nil
+}
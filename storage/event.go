@@ -0,0 +1,63 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package storage // import "miniflux.app/storage"
+
+import (
+	"miniflux.app/broker"
+	"miniflux.app/model"
+)
+
+// publishNewEntries notifies the event stream of the entries created during a feed refresh.
+func publishNewEntries(userID, feedID int64, newEntries model.Entries) {
+	if len(newEntries) == 0 || !broker.HasSubscribers(userID) {
+		return
+	}
+
+	entries := make([]*broker.Entry, 0, len(newEntries))
+	for _, entry := range newEntries {
+		entries = append(entries, broker.NewEntry(entry))
+	}
+
+	broker.Publish(&broker.Event{
+		UserID: userID,
+		Type:   broker.EventNewEntries,
+		Data:   &broker.NewEntriesData{FeedID: feedID, Entries: entries},
+	})
+}
+
+// publishEntriesStatus notifies the event stream of a status change.
+func publishEntriesStatus(userID int64, entryIDs []int64, status string) {
+	if len(entryIDs) == 0 {
+		return
+	}
+
+	broker.Publish(&broker.Event{
+		UserID: userID,
+		Type:   broker.EventEntriesStatus,
+		Data:   &broker.EntriesStatusData{EntryIDs: entryIDs, Status: status},
+	})
+}
+
+// publishEntriesStarred notifies the event stream of a bookmark change.
+func publishEntriesStarred(userID int64, entryIDs []int64, starred bool) {
+	broker.Publish(&broker.Event{
+		UserID: userID,
+		Type:   broker.EventEntriesStarred,
+		Data:   &broker.EntriesStarredData{EntryIDs: entryIDs, Starred: starred},
+	})
+}
+
+// publishFeedError notifies the event stream of a feed refresh failure.
+func publishFeedError(feed *model.Feed) {
+	broker.Publish(&broker.Event{
+		UserID: feed.UserID,
+		Type:   broker.EventFeedError,
+		Data: &broker.FeedErrorData{
+			FeedID:            feed.ID,
+			ParsingErrorMsg:   feed.ParsingErrorMsg,
+			ParsingErrorCount: feed.ParsingErrorCount,
+		},
+	})
+}
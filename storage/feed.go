@@ -42,6 +42,14 @@ func (s *Storage) FeedExists(userID, feedID int64) bool {
 	return result
 }
 
+// FeedCategoryID returns the category of the feed, zero is returned when the feed does not exist.
+func (s *Storage) FeedCategoryID(userID, feedID int64) int64 {
+	var categoryID int64
+	query := `SELECT category_id FROM feeds WHERE user_id=$1 AND id=$2`
+	s.db.QueryRow(query, userID, feedID).Scan(&categoryID)
+	return categoryID
+}
+
 // FeedURLExists checks if feed URL already exists.
 func (s *Storage) FeedURLExists(userID int64, feedURL string) bool {
 	var result bool
@@ -186,11 +194,11 @@ func (s *Storage) WeeklyFeedEntryCount(userID, feedID int64) (int, error) {
 		WHERE
 			entries.user_id=$1 AND 
 			entries.feed_id=$2 AND 
-			entries.published_at BETWEEN (now() - interval '1 week') AND now();
+			entries.published_at BETWEEN (%s) AND now();
 	`
 
 	var weeklyCount int
-	err := s.db.QueryRow(query, userID, feedID).Scan(&weeklyCount)
+	err := s.db.QueryRow(fmt.Sprintf(query, s.daysAgo(7)), userID, feedID).Scan(&weeklyCount)
 
 	switch {
 	case errors.Is(err, sql.ErrNoRows):
@@ -300,6 +308,7 @@ func (s *Storage) CreateFeed(feed *model.Feed) error {
 		}
 	}
 
+	s.touchUser(feed.UserID)
 	return nil
 }
 
@@ -333,9 +342,10 @@ func (s *Storage) UpdateFeed(feed *model.Feed) (err error) {
 			allow_self_signed_certificates=$22,
 			fetch_via_proxy=$23,
 			hide_globally=$24,
-			url_rewrite_rules=$25
+			url_rewrite_rules=$25,
+			mark_updated_entries_unread=$26
 		WHERE
-			id=$26 AND user_id=$27
+			id=$27 AND user_id=$28
 	`
 	_, err = s.db.Exec(query,
 		feed.FeedURL,
@@ -363,6 +373,7 @@ func (s *Storage) UpdateFeed(feed *model.Feed) (err error) {
 		feed.FetchViaProxy,
 		feed.HideGlobally,
 		feed.UrlRewriteRules,
+		feed.MarkUpdatedEntriesUnread,
 		feed.ID,
 		feed.UserID,
 	)
@@ -371,6 +382,7 @@ func (s *Storage) UpdateFeed(feed *model.Feed) (err error) {
 		return fmt.Errorf(`store: unable to update feed #%d (%s): %v`, feed.ID, feed.FeedURL, err)
 	}
 
+	s.touchUser(feed.UserID)
 	return nil
 }
 
@@ -400,6 +412,14 @@ func (s *Storage) UpdateFeedError(feed *model.Feed) (err error) {
 		return fmt.Errorf(`store: unable to update feed error #%d (%s): %v`, feed.ID, feed.FeedURL, err)
 	}
 
+	s.touchUser(feed.UserID)
+
+	// The events are sent for the first error only, not at each refresh of a failing feed.
+	if feed.ParsingErrorCount == 1 {
+		s.fireFeedErrorWebhookEvent(feed)
+		publishFeedError(feed)
+	}
+
 	return nil
 }
 
@@ -429,11 +449,33 @@ func (s *Storage) RemoveFeed(userID, feedID int64) error {
 		return fmt.Errorf(`store: unable to delete feed #%d: %v`, feedID, err)
 	}
 
+	s.touchUser(userID)
 	return nil
 }
 
 // ResetFeedErrors removes all feed errors.
 func (s *Storage) ResetFeedErrors() error {
-	_, err := s.db.Exec(`UPDATE feeds SET parsing_error_count=0, parsing_error_msg=''`)
-	return err
+	query := `
+		UPDATE
+			feeds
+		SET
+			parsing_error_count=0,
+			parsing_error_msg=''
+		WHERE
+			parsing_error_count <> 0 OR parsing_error_msg <> ''
+		RETURNING
+			user_id
+	`
+	rows, err := s.db.Query(query)
+	if err != nil {
+		return err
+	}
+
+	_, userIDs, err := userIDsReturned(rows)
+	if err != nil {
+		return err
+	}
+
+	s.touchUsers(userIDs)
+	return nil
 }
@@ -150,7 +150,7 @@ func (f *FeedQueryBuilder) GetFeeds() (model.Feeds, error) {
 			f.etag_header,
 			f.last_modified_header,
 			f.user_id,
-			f.checked_at at time zone u.timezone,
+			%s,
 			f.parsing_error_count,
 			f.parsing_error_msg,
 			f.scraper_rules,
@@ -168,6 +168,7 @@ func (f *FeedQueryBuilder) GetFeeds() (model.Feeds, error) {
 			f.fetch_via_proxy,
 			f.disabled,
 			f.hide_globally,
+			f.mark_updated_entries_unread,
 			f.category_id,
 			c.title as category_title,
 			c.hide_globally as category_hidden,
@@ -185,7 +186,7 @@ func (f *FeedQueryBuilder) GetFeeds() (model.Feeds, error) {
 		%s
 	`
 
-	query = fmt.Sprintf(query, f.buildCondition(), f.buildSorting())
+	query = fmt.Sprintf(query, f.store.atTimeZone("f.checked_at", "u.timezone"), f.buildCondition(), f.buildSorting())
 
 	rows, err := f.store.db.Query(query, f.args...)
 	if err != nil {
@@ -231,6 +232,7 @@ func (f *FeedQueryBuilder) GetFeeds() (model.Feeds, error) {
 			&feed.FetchViaProxy,
 			&feed.Disabled,
 			&feed.HideGlobally,
+			&feed.MarkUpdatedEntriesUnread,
 			&feed.Category.ID,
 			&feed.Category.Title,
 			&feed.Category.HideGlobally,
@@ -113,6 +113,7 @@ func (s *Storage) CreateFeedIcon(feedID int64, icon *model.Icon) error {
 		return fmt.Errorf(`store: unable to create feed icon: %v`, err)
 	}
 
+	s.touchFeedUser(feedID)
 	return nil
 }
 
@@ -9,6 +9,7 @@ import (
 	"fmt"
 
 	"golang.org/x/crypto/bcrypt"
+	"miniflux.app/crypto"
 	"miniflux.app/model"
 )
 
@@ -28,6 +29,22 @@ func (s *Storage) HasDuplicateGoogleReaderUsername(userID int64, googleReaderUse
 	return result
 }
 
+// HasDuplicateNextcloudNewsUsername checks if another user have the same Nextcloud News username.
+func (s *Storage) HasDuplicateNextcloudNewsUsername(userID int64, nextcloudNewsUsername string) bool {
+	query := `SELECT true FROM integrations WHERE user_id != $1 AND nextcloud_news_username=$2`
+	var result bool
+	s.db.QueryRow(query, userID, nextcloudNewsUsername).Scan(&result)
+	return result
+}
+
+// HasDuplicateTTRSSUsername checks if another user have the same Tiny Tiny RSS username.
+func (s *Storage) HasDuplicateTTRSSUsername(userID int64, ttrssUsername string) bool {
+	query := `SELECT true FROM integrations WHERE user_id != $1 AND ttrss_username=$2`
+	var result bool
+	s.db.QueryRow(query, userID, ttrssUsername).Scan(&result)
+	return result
+}
+
 // UserByFeverToken returns a user by using the Fever API token.
 func (s *Storage) UserByFeverToken(token string) (*model.User, error) {
 	query := `
@@ -38,7 +55,7 @@ func (s *Storage) UserByFeverToken(token string) (*model.User, error) {
 		LEFT JOIN
 			integrations ON integrations.user_id=users.id
 		WHERE
-			integrations.fever_enabled='t' AND lower(integrations.fever_token)=lower($1)
+			integrations.fever_enabled is true AND lower(integrations.fever_token)=lower($1)
 	`
 
 	var user model.User
@@ -63,7 +80,7 @@ func (s *Storage) GoogleReaderUserCheckPassword(username, password string) error
 		FROM
 			integrations
 		WHERE
-			integrations.googlereader_enabled='t' AND integrations.googlereader_username=$1
+			integrations.googlereader_enabled is true AND integrations.googlereader_username=$1
 	`
 
 	err := s.db.QueryRow(query, username).Scan(&hash)
@@ -93,7 +110,7 @@ func (s *Storage) GoogleReaderUserGetIntegration(username string) (*model.Integr
 		FROM
 			integrations
 		WHERE
-			integrations.googlereader_enabled='t' AND integrations.googlereader_username=$1
+			integrations.googlereader_enabled is true AND integrations.googlereader_username=$1
 	`
 
 	err := s.db.QueryRow(query, username).Scan(&integration.UserID, &integration.GoogleReaderEnabled, &integration.GoogleReaderUsername, &integration.GoogleReaderPassword)
@@ -106,6 +123,119 @@ func (s *Storage) GoogleReaderUserGetIntegration(username string) (*model.Integr
 	return &integration, nil
 }
 
+// NextcloudNewsUserCheckPassword validates the Nextcloud News credentials and returns the ID of the user.
+func (s *Storage) NextcloudNewsUserCheckPassword(username, password string) (int64, error) {
+	var userID int64
+	var hash string
+
+	query := `
+		SELECT
+			user_id, nextcloud_news_password
+		FROM
+			integrations
+		WHERE
+			integrations.nextcloud_news_enabled is true AND integrations.nextcloud_news_username=$1
+	`
+
+	err := s.db.QueryRow(query, username).Scan(&userID, &hash)
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf(`store: unable to find this user: %s`, username)
+	} else if err != nil {
+		return 0, fmt.Errorf(`store: unable to fetch user: %v`, err)
+	}
+
+	if hash == "" {
+		return 0, fmt.Errorf(`store: no Nextcloud News password defined for "%s"`, username)
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)); err != nil {
+		return 0, fmt.Errorf(`store: invalid password for "%s" (%v)`, username, err)
+	}
+
+	return userID, nil
+}
+
+// TTRSSUserPasswordHash returns the ID of the user and the hash of the Tiny Tiny RSS password.
+func (s *Storage) TTRSSUserPasswordHash(username string) (int64, string, error) {
+	var userID int64
+	var hash string
+
+	query := `
+		SELECT
+			user_id, ttrss_password
+		FROM
+			integrations
+		WHERE
+			integrations.ttrss_enabled is true AND integrations.ttrss_username=$1
+	`
+
+	err := s.db.QueryRow(query, username).Scan(&userID, &hash)
+	if err == sql.ErrNoRows {
+		return 0, "", fmt.Errorf(`store: unable to find this user: %s`, username)
+	} else if err != nil {
+		return 0, "", fmt.Errorf(`store: unable to fetch user: %v`, err)
+	}
+
+	if hash == "" {
+		return 0, "", fmt.Errorf(`store: no Tiny Tiny RSS password defined for "%s"`, username)
+	}
+
+	return userID, hash, nil
+}
+
+// TTRSSUserSessionKey returns the ID of the user and the key signing its Tiny Tiny RSS sessions.
+// The key changes with the password and when the sessions are revoked.
+func (s *Storage) TTRSSUserSessionKey(username string) (int64, string, error) {
+	var userID int64
+	var hash, nonce string
+
+	query := `
+		SELECT
+			user_id, ttrss_password, coalesce(ttrss_session_nonce, '')
+		FROM
+			integrations
+		WHERE
+			integrations.ttrss_enabled is true AND integrations.ttrss_username=$1
+	`
+
+	err := s.db.QueryRow(query, username).Scan(&userID, &hash, &nonce)
+	if err == sql.ErrNoRows {
+		return 0, "", fmt.Errorf(`store: unable to find this user: %s`, username)
+	} else if err != nil {
+		return 0, "", fmt.Errorf(`store: unable to fetch user: %v`, err)
+	}
+
+	if hash == "" {
+		return 0, "", fmt.Errorf(`store: no Tiny Tiny RSS password defined for "%s"`, username)
+	}
+
+	return userID, hash + nonce, nil
+}
+
+// RevokeTTRSSSessions invalidates all the Tiny Tiny RSS sessions of the user.
+func (s *Storage) RevokeTTRSSSessions(userID int64) error {
+	query := `UPDATE integrations SET ttrss_session_nonce=$1 WHERE user_id=$2`
+	if _, err := s.db.Exec(query, crypto.GenerateRandomStringHex(16), userID); err != nil {
+		return fmt.Errorf(`store: unable to revoke Tiny Tiny RSS sessions: %v`, err)
+	}
+
+	return nil
+}
+
+// TTRSSUserCheckPassword validates the Tiny Tiny RSS credentials and returns the ID of the user.
+func (s *Storage) TTRSSUserCheckPassword(username, password string) (int64, error) {
+	userID, hash, err := s.TTRSSUserPasswordHash(username)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)); err != nil {
+		return 0, fmt.Errorf(`store: invalid password for "%s" (%v)`, username, err)
+	}
+
+	return userID, nil
+}
+
 // Integration returns user integration settings.
 func (s *Storage) Integration(userID int64) (*model.Integration, error) {
 	query := `
@@ -123,7 +253,10 @@ func (s *Storage) Integration(userID int64) (*model.Integration, error) {
 			fever_token,
 			googlereader_enabled,
 			googlereader_username,
-			googlereader_password,
+			nextcloud_news_enabled,
+			nextcloud_news_username,
+			ttrss_enabled,
+			ttrss_username,
 			wallabag_enabled,
 			wallabag_only_url,
 			wallabag_url,
@@ -172,7 +305,10 @@ func (s *Storage) Integration(userID int64) (*model.Integration, error) {
 		&integration.FeverToken,
 		&integration.GoogleReaderEnabled,
 		&integration.GoogleReaderUsername,
-		&integration.GoogleReaderPassword,
+		&integration.NextcloudNewsEnabled,
+		&integration.NextcloudNewsUsername,
+		&integration.TTRSSEnabled,
+		&integration.TTRSSUsername,
 		&integration.WallabagEnabled,
 		&integration.WallabagOnlyURL,
 		&integration.WallabagURL,
@@ -213,14 +349,9 @@ func (s *Storage) Integration(userID int64) (*model.Integration, error) {
 }
 
 // UpdateIntegration saves user integration settings.
+// The API passwords are hashed, they are left unchanged when empty.
 func (s *Storage) UpdateIntegration(integration *model.Integration) error {
-	var err error
-	if integration.GoogleReaderPassword != "" {
-		integration.GoogleReaderPassword, err = hashPassword(integration.GoogleReaderPassword)
-		if err != nil {
-			return err
-		}
-		query := `
+	query := `
 		UPDATE
 			integrations
 		SET
@@ -249,170 +380,110 @@ func (s *Storage) UpdateIntegration(integration *model.Integration) error {
 			pocket_consumer_key=$23,
 			googlereader_enabled=$24,
 			googlereader_username=$25,
-			googlereader_password=$26,
-			telegram_bot_enabled=$27,
-			telegram_bot_token=$28,
-			telegram_bot_chat_id=$29,
-			espial_enabled=$30,
-			espial_url=$31,
-			espial_api_key=$32,
-			espial_tags=$33,
-			linkding_enabled=$34,
-			linkding_url=$35,
-			linkding_api_key=$36,
-			matrix_bot_enabled=$37,
-			matrix_bot_user=$38,
-			matrix_bot_password=$39,
-			matrix_bot_url=$40,
-			matrix_bot_chat_id=$41
+			telegram_bot_enabled=$26,
+			telegram_bot_token=$27,
+			telegram_bot_chat_id=$28,
+			espial_enabled=$29,
+			espial_url=$30,
+			espial_api_key=$31,
+			espial_tags=$32,
+			linkding_enabled=$33,
+			linkding_url=$34,
+			linkding_api_key=$35,
+			matrix_bot_enabled=$36,
+			matrix_bot_user=$37,
+			matrix_bot_password=$38,
+			matrix_bot_url=$39,
+			matrix_bot_chat_id=$40,
+			nextcloud_news_enabled=$41,
+			nextcloud_news_username=$42,
+			ttrss_enabled=$43,
+			ttrss_username=$44
 		WHERE
-			user_id=$42
-	`
-		_, err = s.db.Exec(
-			query,
-			integration.PinboardEnabled,
-			integration.PinboardToken,
-			integration.PinboardTags,
-			integration.PinboardMarkAsUnread,
-			integration.InstapaperEnabled,
-			integration.InstapaperUsername,
-			integration.InstapaperPassword,
-			integration.FeverEnabled,
-			integration.FeverUsername,
-			integration.FeverToken,
-			integration.WallabagEnabled,
-			integration.WallabagOnlyURL,
-			integration.WallabagURL,
-			integration.WallabagClientID,
-			integration.WallabagClientSecret,
-			integration.WallabagUsername,
-			integration.WallabagPassword,
-			integration.NunuxKeeperEnabled,
-			integration.NunuxKeeperURL,
-			integration.NunuxKeeperAPIKey,
-			integration.PocketEnabled,
-			integration.PocketAccessToken,
-			integration.PocketConsumerKey,
-			integration.GoogleReaderEnabled,
-			integration.GoogleReaderUsername,
-			integration.GoogleReaderPassword,
-			integration.TelegramBotEnabled,
-			integration.TelegramBotToken,
-			integration.TelegramBotChatID,
-			integration.EspialEnabled,
-			integration.EspialURL,
-			integration.EspialAPIKey,
-			integration.EspialTags,
-			integration.LinkdingEnabled,
-			integration.LinkdingURL,
-			integration.LinkdingAPIKey,
-			integration.MatrixBotEnabled,
-			integration.MatrixBotUser,
-			integration.MatrixBotPassword,
-			integration.MatrixBotURL,
-			integration.MatrixBotChatID,
-			integration.UserID,
-		)
-	} else {
-		query := `
-		UPDATE
-			integrations
-		SET
-		pinboard_enabled=$1,
-		pinboard_token=$2,
-		pinboard_tags=$3,
-		pinboard_mark_as_unread=$4,
-		instapaper_enabled=$5,
-		instapaper_username=$6,
-		instapaper_password=$7,
-		fever_enabled=$8,
-		fever_username=$9,
-		fever_token=$10,
-		wallabag_enabled=$11,
-		wallabag_only_url=$12,
-		wallabag_url=$13,
-		wallabag_client_id=$14,
-		wallabag_client_secret=$15,
-		wallabag_username=$16,
-		wallabag_password=$17,
-		nunux_keeper_enabled=$18,
-		nunux_keeper_url=$19,
-		nunux_keeper_api_key=$20,
-		pocket_enabled=$21,
-		pocket_access_token=$22,
-		pocket_consumer_key=$23,
-		googlereader_enabled=$24,
-		googlereader_username=$25,
-		googlereader_password=$26,
-		telegram_bot_enabled=$27,
-		telegram_bot_token=$28,
-		telegram_bot_chat_id=$29,
-		espial_enabled=$30,
-		espial_url=$31,
-		espial_api_key=$32,
-		espial_tags=$33,
-		linkding_enabled=$34,
-		linkding_url=$35,
-		linkding_api_key=$36,
-		matrix_bot_enabled=$37,
-		matrix_bot_user=$38,
-		matrix_bot_password=$39,
-		matrix_bot_url=$40,
-		matrix_bot_chat_id=$41
-	WHERE
-		user_id=$42
+			user_id=$45
 	`
-		_, err = s.db.Exec(
-			query,
-			integration.PinboardEnabled,
-			integration.PinboardToken,
-			integration.PinboardTags,
-			integration.PinboardMarkAsUnread,
-			integration.InstapaperEnabled,
-			integration.InstapaperUsername,
-			integration.InstapaperPassword,
-			integration.FeverEnabled,
-			integration.FeverUsername,
-			integration.FeverToken,
-			integration.WallabagEnabled,
-			integration.WallabagOnlyURL,
-			integration.WallabagURL,
-			integration.WallabagClientID,
-			integration.WallabagClientSecret,
-			integration.WallabagUsername,
-			integration.WallabagPassword,
-			integration.NunuxKeeperEnabled,
-			integration.NunuxKeeperURL,
-			integration.NunuxKeeperAPIKey,
-			integration.PocketEnabled,
-			integration.PocketAccessToken,
-			integration.PocketConsumerKey,
-			integration.GoogleReaderEnabled,
-			integration.GoogleReaderUsername,
-			integration.GoogleReaderPassword,
-			integration.TelegramBotEnabled,
-			integration.TelegramBotToken,
-			integration.TelegramBotChatID,
-			integration.EspialEnabled,
-			integration.EspialURL,
-			integration.EspialAPIKey,
-			integration.EspialTags,
-			integration.LinkdingEnabled,
-			integration.LinkdingURL,
-			integration.LinkdingAPIKey,
-			integration.MatrixBotEnabled,
-			integration.MatrixBotUser,
-			integration.MatrixBotPassword,
-			integration.MatrixBotURL,
-			integration.MatrixBotChatID,
-			integration.UserID,
-		)
-	}
+	_, err := s.db.Exec(
+		query,
+		integration.PinboardEnabled,
+		integration.PinboardToken,
+		integration.PinboardTags,
+		integration.PinboardMarkAsUnread,
+		integration.InstapaperEnabled,
+		integration.InstapaperUsername,
+		integration.InstapaperPassword,
+		integration.FeverEnabled,
+		integration.FeverUsername,
+		integration.FeverToken,
+		integration.WallabagEnabled,
+		integration.WallabagOnlyURL,
+		integration.WallabagURL,
+		integration.WallabagClientID,
+		integration.WallabagClientSecret,
+		integration.WallabagUsername,
+		integration.WallabagPassword,
+		integration.NunuxKeeperEnabled,
+		integration.NunuxKeeperURL,
+		integration.NunuxKeeperAPIKey,
+		integration.PocketEnabled,
+		integration.PocketAccessToken,
+		integration.PocketConsumerKey,
+		integration.GoogleReaderEnabled,
+		integration.GoogleReaderUsername,
+		integration.TelegramBotEnabled,
+		integration.TelegramBotToken,
+		integration.TelegramBotChatID,
+		integration.EspialEnabled,
+		integration.EspialURL,
+		integration.EspialAPIKey,
+		integration.EspialTags,
+		integration.LinkdingEnabled,
+		integration.LinkdingURL,
+		integration.LinkdingAPIKey,
+		integration.MatrixBotEnabled,
+		integration.MatrixBotUser,
+		integration.MatrixBotPassword,
+		integration.MatrixBotURL,
+		integration.MatrixBotChatID,
+		integration.NextcloudNewsEnabled,
+		integration.NextcloudNewsUsername,
+		integration.TTRSSEnabled,
+		integration.TTRSSUsername,
+		integration.UserID,
+	)
 
 	if err != nil {
 		return fmt.Errorf(`store: unable to update integration row: %v`, err)
 	}
 
+	if err := s.updateAPIPassword(integration.UserID, "googlereader_password", &integration.GoogleReaderPassword); err != nil {
+		return err
+	}
+
+	if err := s.updateAPIPassword(integration.UserID, "nextcloud_news_password", &integration.NextcloudNewsPassword); err != nil {
+		return err
+	}
+
+	return s.updateAPIPassword(integration.UserID, "ttrss_password", &integration.TTRSSPassword)
+}
+
+// updateAPIPassword saves the hash of a new API password, the password is left unchanged when empty.
+// The hash is never loaded in the integration settings, it is kept when the API is disabled.
+func (s *Storage) updateAPIPassword(userID int64, column string, password *string) error {
+	if *password == "" {
+		return nil
+	}
+
+	hash, err := hashPassword(*password)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`UPDATE integrations SET %s=$1 WHERE user_id=$2`, column)
+	if _, err := s.db.Exec(query, hash, userID); err != nil {
+		return fmt.Errorf(`store: unable to update the API password: %v`, err)
+	}
+
+	*password = ""
 	return nil
 }
 
@@ -426,7 +497,7 @@ func (s *Storage) HasSaveEntry(userID int64) (result bool) {
 		WHERE
 			user_id=$1
 		AND
-			(pinboard_enabled='t' OR instapaper_enabled='t' OR wallabag_enabled='t' OR nunux_keeper_enabled='t' OR espial_enabled='t' OR pocket_enabled='t' OR linkding_enabled='t')
+			(pinboard_enabled is true OR instapaper_enabled is true OR wallabag_enabled is true OR nunux_keeper_enabled is true OR espial_enabled is true OR pocket_enabled is true OR linkding_enabled is true)
 	`
 	if err := s.db.QueryRow(query, userID).Scan(&result); err != nil {
 		result = false
@@ -62,6 +62,22 @@ func (s *Storage) NewCategoryBatch(userID int64, categoryID int64, batchSize int
 	return s.fetchBatchRows(fmt.Sprintf(query, batchSize), userID, categoryID)
 }
 
+// NewAllFeedsBatch returns a job for each enabled feed of all users.
+func (s *Storage) NewAllFeedsBatch() (jobs model.JobList, err error) {
+	// The schedule and the error counter are ignored to force a refresh.
+	query := `
+		SELECT
+			id,
+			user_id
+		FROM
+			feeds
+		WHERE
+			disabled is false
+		ORDER BY next_check_at ASC
+	`
+	return s.fetchBatchRows(query)
+}
+
 func (s *Storage) fetchBatchRows(query string, args ...interface{}) (jobs model.JobList, err error) {
 	rows, err := s.db.Query(query, args...)
 	if err != nil {
@@ -0,0 +1,340 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package storage // import "miniflux.app/storage"
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"miniflux.app/model"
+)
+
+// AnotherLabelExists checks if another label exists with the same title.
+func (s *Storage) AnotherLabelExists(userID, labelID int64, title string) bool {
+	var result bool
+	query := `SELECT true FROM labels WHERE user_id=$1 AND id != $2 AND lower(title)=lower($3) LIMIT 1`
+	s.db.QueryRow(query, userID, labelID, title).Scan(&result)
+	return result
+}
+
+// LabelTitleExists checks if the given label exists into the database.
+func (s *Storage) LabelTitleExists(userID int64, title string) bool {
+	var result bool
+	query := `SELECT true FROM labels WHERE user_id=$1 AND lower(title)=lower($2) LIMIT 1`
+	s.db.QueryRow(query, userID, title).Scan(&result)
+	return result
+}
+
+// LabelIDExists checks if the given label exists into the database.
+func (s *Storage) LabelIDExists(userID, labelID int64) bool {
+	var result bool
+	query := `SELECT true FROM labels WHERE user_id=$1 AND id=$2`
+	s.db.QueryRow(query, userID, labelID).Scan(&result)
+	return result
+}
+
+// Label returns a label from the database.
+func (s *Storage) Label(userID, labelID int64) (*model.Label, error) {
+	var label model.Label
+
+	query := `SELECT id, user_id, title FROM labels WHERE user_id=$1 AND id=$2`
+	err := s.db.QueryRow(query, userID, labelID).Scan(&label.ID, &label.UserID, &label.Title)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf(`store: unable to fetch label: %v`, err)
+	default:
+		return &label, nil
+	}
+}
+
+// LabelByTitle finds a label by the title.
+func (s *Storage) LabelByTitle(userID int64, title string) (*model.Label, error) {
+	var label model.Label
+
+	query := `SELECT id, user_id, title FROM labels WHERE user_id=$1 AND lower(title)=lower($2)`
+	err := s.db.QueryRow(query, userID, title).Scan(&label.ID, &label.UserID, &label.Title)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf(`store: unable to fetch label: %v`, err)
+	default:
+		return &label, nil
+	}
+}
+
+// Labels returns all labels that belongs to the given user.
+func (s *Storage) Labels(userID int64) (model.Labels, error) {
+	query := `SELECT id, user_id, title FROM labels WHERE user_id=$1 ORDER BY title ASC`
+	rows, err := s.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf(`store: unable to fetch labels: %v`, err)
+	}
+	defer rows.Close()
+
+	labels := make(model.Labels, 0)
+	for rows.Next() {
+		var label model.Label
+		if err := rows.Scan(&label.ID, &label.UserID, &label.Title); err != nil {
+			return nil, fmt.Errorf(`store: unable to fetch label row: %v`, err)
+		}
+
+		labels = append(labels, &label)
+	}
+
+	return labels, nil
+}
+
+// LabelsWithEntryCount returns all labels with the number of visible entries.
+func (s *Storage) LabelsWithEntryCount(userID int64) (model.Labels, error) {
+	query := `
+		SELECT
+			l.id,
+			l.user_id,
+			l.title,
+			(SELECT count(*)
+			   FROM entry_labels el
+			     JOIN entries e ON (e.id = el.entry_id)
+			   WHERE el.label_id = l.id AND e.status <> 'removed') AS count
+		FROM labels l
+		WHERE
+			l.user_id=$1
+		ORDER BY
+			l.title ASC
+	`
+	rows, err := s.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf(`store: unable to fetch labels: %v`, err)
+	}
+	defer rows.Close()
+
+	labels := make(model.Labels, 0)
+	for rows.Next() {
+		var label model.Label
+		if err := rows.Scan(&label.ID, &label.UserID, &label.Title, &label.EntryCount); err != nil {
+			return nil, fmt.Errorf(`store: unable to fetch label row: %v`, err)
+		}
+
+		labels = append(labels, &label)
+	}
+
+	return labels, nil
+}
+
+// CreateLabel creates a new label.
+func (s *Storage) CreateLabel(userID int64, request *model.LabelRequest) (*model.Label, error) {
+	var label model.Label
+
+	query := `
+		INSERT INTO labels
+			(user_id, title)
+		VALUES
+			($1, $2)
+		RETURNING
+			id,
+			user_id,
+			title
+	`
+	err := s.db.QueryRow(
+		query,
+		userID,
+		request.Title,
+	).Scan(
+		&label.ID,
+		&label.UserID,
+		&label.Title,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf(`store: unable to create label %q: %v`, request.Title, err)
+	}
+
+	return &label, nil
+}
+
+// UpdateLabel updates an existing label.
+func (s *Storage) UpdateLabel(label *model.Label) error {
+	query := `UPDATE labels SET title=$1 WHERE id=$2 AND user_id=$3`
+	_, err := s.db.Exec(query, label.Title, label.ID, label.UserID)
+	if err != nil {
+		return fmt.Errorf(`store: unable to update label: %v`, err)
+	}
+
+	s.touchUser(label.UserID)
+	return nil
+}
+
+// RemoveLabel deletes a label, entries are left untouched.
+func (s *Storage) RemoveLabel(userID, labelID int64) error {
+	query := `DELETE FROM labels WHERE id = $1 AND user_id = $2`
+	result, err := s.db.Exec(query, labelID, userID)
+	if err != nil {
+		return fmt.Errorf(`store: unable to remove this label: %v`, err)
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf(`store: unable to remove this label: %v`, err)
+	}
+
+	if count == 0 {
+		return errors.New(`store: no label has been removed`)
+	}
+
+	s.touchUser(userID)
+	return nil
+}
+
+// SetEntryLabels replaces the labels attached to an entry, missing labels are created.
+func (s *Storage) SetEntryLabels(userID, entryID int64, titles []string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf(`store: unable to start transaction: %v`, err)
+	}
+
+	var exists bool
+	tx.QueryRow(`SELECT true FROM entries WHERE user_id=$1 AND id=$2`, userID, entryID).Scan(&exists)
+	if !exists {
+		tx.Rollback()
+		return fmt.Errorf(`store: entry #%d not found`, entryID)
+	}
+
+	labelIDs := make([]int64, 0, len(titles))
+	for _, title := range normalizeLabelTitles(titles) {
+		labelID, err := s.labelIDByTitle(tx, userID, title)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		labelIDs = append(labelIDs, labelID)
+	}
+
+	query := `DELETE FROM entry_labels WHERE entry_id=$1 AND NOT ` + s.inArray("label_id", 2)
+	if _, err := tx.Exec(query, entryID, s.array(labelIDs)); err != nil {
+		tx.Rollback()
+		return fmt.Errorf(`store: unable to update labels of entry #%d: %v`, entryID, err)
+	}
+
+	query = `INSERT INTO entry_labels (entry_id, label_id) SELECT $1, value FROM ` + s.arrayTable(2, "bigint") + ` WHERE true ON CONFLICT DO NOTHING`
+	if _, err := tx.Exec(query, entryID, s.array(labelIDs)); err != nil {
+		tx.Rollback()
+		return fmt.Errorf(`store: unable to update labels of entry #%d: %v`, entryID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf(`store: unable to commit transaction: %v`, err)
+	}
+
+	s.touchUser(userID)
+	return nil
+}
+
+// AddEntriesLabel attaches a label to the given list of entries, the label is created if necessary.
+func (s *Storage) AddEntriesLabel(userID int64, entryIDs []int64, title string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf(`store: unable to start transaction: %v`, err)
+	}
+
+	labelID, err := s.labelIDByTitle(tx, userID, strings.TrimSpace(title))
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	query := `
+		INSERT INTO entry_labels
+			(entry_id, label_id)
+		SELECT
+			id, $1
+		FROM
+			entries
+		WHERE
+			user_id=$2 AND ` + s.inArray("id", 3) + `
+		ON CONFLICT DO NOTHING
+	`
+	if _, err := tx.Exec(query, labelID, userID, s.array(entryIDs)); err != nil {
+		tx.Rollback()
+		return fmt.Errorf(`store: unable to add label %q to entries %v: %v`, title, entryIDs, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf(`store: unable to commit transaction: %v`, err)
+	}
+
+	s.touchUser(userID)
+	return nil
+}
+
+// RemoveEntriesLabel detaches a label from the given list of entries.
+func (s *Storage) RemoveEntriesLabel(userID int64, entryIDs []int64, title string) error {
+	query := `
+		DELETE FROM
+			entry_labels
+		WHERE
+			label_id IN (SELECT id FROM labels WHERE user_id=$1 AND lower(title)=lower($2))
+		AND
+			` + s.inArray("entry_id", 3) + `
+	`
+	if _, err := s.db.Exec(query, userID, strings.TrimSpace(title), s.array(entryIDs)); err != nil {
+		return fmt.Errorf(`store: unable to remove label %q from entries %v: %v`, title, entryIDs, err)
+	}
+
+	s.touchUser(userID)
+	return nil
+}
+
+func (s *Storage) labelIDByTitle(tx *sql.Tx, userID int64, title string) (int64, error) {
+	if title == "" {
+		return 0, errors.New(`store: empty label title`)
+	}
+
+	// The label is not created when a category has the same title, they share the same Google Reader stream ID.
+	query := `
+		INSERT INTO labels
+			(user_id, title)
+		SELECT
+			$1, $2
+		WHERE
+			NOT EXISTS (SELECT 1 FROM categories WHERE user_id=$1 AND lower(title)=lower($2))
+		ON CONFLICT (user_id, lower(title)) DO NOTHING
+	`
+	if _, err := tx.Exec(query, userID, title); err != nil {
+		return 0, fmt.Errorf(`store: unable to create label %q: %v`, title, err)
+	}
+
+	var labelID int64
+	query = `SELECT id FROM labels WHERE user_id=$1 AND lower(title)=lower($2)`
+	err := tx.QueryRow(query, userID, title).Scan(&labelID)
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf(`store: unable to create label %q, a category has the same title`, title)
+	} else if err != nil {
+		return 0, fmt.Errorf(`store: unable to fetch label %q: %v`, title, err)
+	}
+
+	return labelID, nil
+}
+
+// normalizeLabelTitles trims label titles and removes empty and duplicate values (case-insensitive).
+func normalizeLabelTitles(titles []string) []string {
+	seen := make(map[string]bool)
+	result := make([]string, 0, len(titles))
+	for _, title := range titles {
+		title = strings.TrimSpace(title)
+		key := strings.ToLower(title)
+		if title == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, title)
+	}
+	return result
+}
@@ -63,6 +63,16 @@ func (s *Storage) UpdateAppSessionField(sessionID, field string, value interface
 		WHERE
 			id=$2
 	`
+	if s.isSQLite() {
+		query = `
+			UPDATE
+				sessions
+			SET
+				data = json_set(data, '$.%s', CAST($1 AS TEXT))
+			WHERE
+				id=$2
+		`
+	}
 	_, err := s.db.Exec(fmt.Sprintf(query, field), value, sessionID)
 	if err != nil {
 		return fmt.Errorf(`store: unable to update session field: %v`, err)
@@ -112,9 +122,9 @@ func (s *Storage) CleanOldSessions(days int) int64 {
 		DELETE FROM
 			sessions
 		WHERE
-			id IN (SELECT id FROM sessions WHERE created_at < now() - interval '%d days')
+			id IN (SELECT id FROM sessions WHERE created_at < %s)
 	`
-	result, err := s.db.Exec(fmt.Sprintf(query, days))
+	result, err := s.db.Exec(fmt.Sprintf(query, s.daysAgo(days)))
 	if err != nil {
 		return 0
 	}
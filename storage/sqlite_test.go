@@ -0,0 +1,873 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package storage // import "miniflux.app/storage"
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"miniflux.app/config"
+	"miniflux.app/database"
+	"miniflux.app/model"
+)
+
+func newSQLiteStorage(t *testing.T) *Storage {
+	t.Helper()
+
+	var err error
+	config.Opts, err = config.NewParser().ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatalf(`Parsing failure: %v`, err)
+	}
+
+	db, err := database.NewConnectionPool("sqlite://"+filepath.Join(t.TempDir(), "miniflux.db"), 1, 5, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := database.Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := database.IsSchemaUpToDate(db); err != nil {
+		t.Fatal(err)
+	}
+
+	return NewStorage(db)
+}
+
+func createSQLiteFeed(t *testing.T, store *Storage) (*model.User, *model.Feed) {
+	t.Helper()
+
+	user, err := store.CreateUser(&model.UserCreationRequest{Username: "Admin", Password: "test123", IsAdmin: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	category, err := store.FirstCategory(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	feed := &model.Feed{
+		UserID:   user.ID,
+		Category: category,
+		FeedURL:  "https://example.org/feed.xml",
+		SiteURL:  "https://example.org/",
+		Title:    "Example",
+	}
+	if err := store.CreateFeed(feed); err != nil {
+		t.Fatal(err)
+	}
+
+	entries := model.Entries{
+		{
+			Hash:     "1",
+			Title:    "Gardening in spring",
+			URL:      "https://example.org/1",
+			Date:     time.Now().Add(-time.Hour),
+			Content:  "<p>Plant <b>tomatoes</b> after the last frost.</p>",
+			Tags:     []string{"garden", "spring", "garden"},
+			Language: "en",
+		},
+		{
+			Hash:     "2",
+			Title:    "Cooking pasta",
+			URL:      "https://example.org/2",
+			Date:     time.Now(),
+			Content:  "<p>Boil the water.</p>",
+			Language: "en",
+		},
+	}
+	if err := store.RefreshFeedEntries(user.ID, feed.ID, entries, false); err != nil {
+		t.Fatal(err)
+	}
+
+	return user, feed
+}
+
+func TestSQLiteUsers(t *testing.T) {
+	store := newSQLiteStorage(t)
+
+	if version := store.DatabaseVersion(); !strings.HasPrefix(version, "SQLite") {
+		t.Fatalf(`Unexpected database version: %q`, version)
+	}
+
+	user, err := store.CreateUser(&model.UserCreationRequest{Username: "Admin", Password: "test123", IsAdmin: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if user.Username != "admin" || !user.IsAdmin || user.Theme != "light_serif" || !user.KeyboardShortcuts {
+		t.Fatalf(`Unexpected user: %+v`, user)
+	}
+
+	if err := store.CheckPassword("admin", "test123"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.SetLastLogin(user.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	user, err = store.UserByUsername("admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if user.LastLoginAt == nil || time.Since(*user.LastLoginAt) > time.Minute {
+		t.Fatalf(`Unexpected last login: %v`, user.LastLoginAt)
+	}
+
+	user.Timezone = "Europe/Paris"
+	if err := store.UpdateUser(user); err != nil {
+		t.Fatal(err)
+	}
+
+	users, err := store.Users()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(users) != 1 || users[0].Timezone != "Europe/Paris" {
+		t.Fatalf(`Unexpected users: %+v`, users)
+	}
+
+	timezones, err := store.Timezones()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, found := timezones["UTC"]; !found {
+		t.Fatalf(`UTC is missing from the timezones`)
+	}
+}
+
+func TestSQLiteSessions(t *testing.T) {
+	store := newSQLiteStorage(t)
+
+	session, err := store.CreateAppSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.UpdateAppSessionField(session.ID, "language", "fr_FR"); err != nil {
+		t.Fatal(err)
+	}
+
+	session, err = store.AppSession(session.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if session.Data.Language != "fr_FR" || session.Data.CSRF == "" {
+		t.Fatalf(`Unexpected session data: %+v`, session.Data)
+	}
+
+	if n := store.CleanOldSessions(1); n != 0 {
+		t.Fatalf(`Recent sessions should not be removed, got %d`, n)
+	}
+
+	if _, err := store.CreateUser(&model.UserCreationRequest{Username: "admin", Password: "test123"}); err != nil {
+		t.Fatal(err)
+	}
+
+	token, userID, err := store.CreateUserSessionFromUsername("admin", "Go", "127.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	userSession, err := store.UserSessionByToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if userSession == nil || userSession.UserID != userID || time.Since(userSession.CreatedAt) > time.Minute {
+		t.Fatalf(`Unexpected user session: %+v`, userSession)
+	}
+
+	if userSession.LastUsedAt == nil || time.Since(*userSession.LastUsedAt) > time.Minute {
+		t.Fatalf(`The creation of a session should set the last used date: %+v`, userSession)
+	}
+
+	if err := store.SetUserSessionUsedTimestamp(userSession.ID); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSQLiteFeedsAndEntries(t *testing.T) {
+	store := newSQLiteStorage(t)
+	user, feed := createSQLiteFeed(t, store)
+
+	feeds, err := store.FeedsWithCounters(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(feeds) != 1 || feeds[0].UnreadCount != 2 || feeds[0].CheckedAt.IsZero() {
+		t.Fatalf(`Unexpected feeds: %+v`, feeds)
+	}
+
+	count, err := store.WeeklyFeedEntryCount(user.ID, feed.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if count != 2 {
+		t.Fatalf(`Unexpected weekly entry count: got %d instead of 2`, count)
+	}
+
+	entries, err := store.NewEntryQueryBuilder(user.ID).WithTags([]string{"garden"}).GetEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 1 || entries[0].Hash != "1" || len(entries[0].Tags) != 2 || entries[0].Date.Location().String() != "UTC" {
+		t.Fatalf(`Unexpected entries: %+v`, entries)
+	}
+
+	entryIDs, err := store.NewEntryQueryBuilder(user.ID).WithOrder("published_at").WithDirection("ASC").GetEntryIDs()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entryIDs) != 2 {
+		t.Fatalf(`Unexpected entry IDs: %v`, entryIDs)
+	}
+
+	for _, order := range []string{"id", "title", "status", "created_at", "changed_at"} {
+		if _, err := store.NewEntryQueryBuilder(user.ID).WithOrder(order).GetEntries(); err != nil {
+			t.Fatalf(`Unable to sort the entries by %q: %v`, order, err)
+		}
+	}
+
+	entries, err = store.EntriesContent(user.ID, time.Now().AddDate(0, 0, -7), time.Now().Add(time.Minute), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 1 || entries[0].ID != entryIDs[1] || entries[0].URL == "" || entries[0].Date.IsZero() {
+		t.Fatalf(`Only the most recent entry should be returned: %+v`, entries)
+	}
+
+	if err := store.SetEntriesStatus(user.ID, entryIDs, model.EntryStatusRead); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.SetEntriesBookmarkedState(user.ID, entryIDs[:1], true); err != nil {
+		t.Fatal(err)
+	}
+
+	count, err = store.NewEntryQueryBuilder(user.ID).
+		WithEntryIDs(entryIDs).
+		WithStatuses([]string{model.EntryStatusRead, model.EntryStatusUnread}).
+		WithStarred(true).
+		CountEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if count != 1 {
+		t.Fatalf(`Unexpected number of starred entries: got %d instead of 1`, count)
+	}
+
+	archived, err := store.ArchiveEntries(model.EntryStatusRead, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if archived != 1 {
+		t.Fatalf(`Only the entry which is not starred should be archived, got %d`, archived)
+	}
+}
+
+func TestSQLiteSearch(t *testing.T) {
+	store := newSQLiteStorage(t)
+	user, _ := createSQLiteFeed(t, store)
+
+	entries, err := store.NewEntryQueryBuilder(user.ID).WithSearchQuery("tomatoes", "en").GetEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 1 || entries[0].Hash != "1" {
+		t.Fatalf(`Unexpected search results: %+v`, entries)
+	}
+
+	if !strings.Contains(entries[0].Snippet, "<mark>tomatoes</mark>") || strings.Contains(entries[0].Snippet, "<b>") {
+		t.Fatalf(`Unexpected snippet: %q`, entries[0].Snippet)
+	}
+
+	entries[0].Content = "<p>Boil the potatoes.</p>"
+	if err := store.UpdateEntryContent(entries[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	for query, expected := range map[string]int{"tomatoes": 0, "potatoes": 1, `boil "the`: 2, "pasta": 1, "gardening spring": 1} {
+		count, err := store.NewEntryQueryBuilder(user.ID).WithSearchQuery(query, "en").CountEntries()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if count != expected {
+			t.Errorf(`Unexpected number of results for %q: got %d instead of %d`, query, count, expected)
+		}
+	}
+
+	annotation, err := store.CreateAnnotation(user.ID, entries[0].ID, &model.AnnotationRequest{Quote: "Boil", Note: "Salted water"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	annotations, err := store.Annotations(user.ID, &AnnotationQuery{SearchQuery: "salted"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(annotations) != 1 || annotations[0].ID != annotation.ID {
+		t.Fatalf(`Unexpected annotations: %+v`, annotations)
+	}
+
+	count, err := store.NewEntryQueryBuilder(user.ID).WithSearchQuery("salted", "en").CountEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if count != 1 {
+		t.Fatalf(`Entries with a matching annotation should be returned, got %d`, count)
+	}
+
+	entry, err := store.NewEntryQueryBuilder(user.ID).WithEntryID(entries[0].ID).GetEntry()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entry.Annotations) != 0 {
+		t.Fatalf(`The annotations should only be loaded when requested: %+v`, entry.Annotations)
+	}
+
+	entry, err = store.NewEntryQueryBuilder(user.ID).WithEntryID(entries[0].ID).WithAnnotations().GetEntry()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entry.Annotations) != 1 || entry.Annotations[0].ID != annotation.ID {
+		t.Fatalf(`Unexpected annotations: %+v`, entry.Annotations)
+	}
+}
+
+func TestSQLiteLabelsAndRevisions(t *testing.T) {
+	store := newSQLiteStorage(t)
+	user, feed := createSQLiteFeed(t, store)
+
+	entryIDs, err := store.NewEntryQueryBuilder(user.ID).WithOrder("published_at").WithDirection("ASC").GetEntryIDs()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.SetEntryLabels(user.ID, entryIDs[0], []string{"To Read", "Favorite"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.SetEntryLabels(user.ID, entryIDs[0], []string{"Favorite", "Later"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.AddEntriesLabel(user.ID, entryIDs, "Later"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.RemoveEntriesLabel(user.ID, entryIDs[1:], "Favorite"); err != nil {
+		t.Fatal(err)
+	}
+
+	entry, err := store.NewEntryQueryBuilder(user.ID).WithEntryID(entryIDs[0]).GetEntry()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Join(entry.Labels, ",") != "Favorite,Later" {
+		t.Fatalf(`Unexpected labels: %v`, entry.Labels)
+	}
+
+	category, err := store.FirstCategory(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.AddEntriesLabel(user.ID, entryIDs, strings.ToUpper(category.Title)); err == nil {
+		t.Fatal(`Creating a label with the title of a category should fail`)
+	}
+
+	updated := model.Entries{{Hash: "1", Title: "Gardening in summer", URL: "https://example.org/1", Content: "<p>Water daily.</p>"}}
+	if err := store.RefreshFeedEntries(user.ID, feed.ID, updated, true); err != nil {
+		t.Fatal(err)
+	}
+
+	revisions, err := store.EntryRevisions(user.ID, entryIDs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(revisions) != 1 || revisions[0].Title != "Gardening in spring" {
+		t.Fatalf(`Unexpected revisions: %+v`, revisions)
+	}
+
+	if err := store.SetEntriesStatus(user.ID, entryIDs[1:], model.EntryStatusRemoved); err != nil {
+		t.Fatal(err)
+	}
+
+	updated = model.Entries{{Hash: "2", Title: "Cooking rice", URL: "https://example.org/2", Content: "<p>Rinse first.</p>"}}
+	if err := store.RefreshFeedEntries(user.ID, feed.ID, updated, true); err != nil {
+		t.Fatal(err)
+	}
+
+	var revisionCount int
+	if err := store.db.QueryRow(`SELECT count(*) FROM entry_revisions WHERE entry_id=$1`, entryIDs[1]).Scan(&revisionCount); err != nil {
+		t.Fatal(err)
+	}
+
+	if revisionCount != 0 {
+		t.Fatalf(`No revision should be stored for a removed entry, got %d`, revisionCount)
+	}
+}
+
+func TestSQLiteCategoriesAndCertificates(t *testing.T) {
+	store := newSQLiteStorage(t)
+	user, feed := createSQLiteFeed(t, store)
+
+	category, err := store.CreateCategory(user.ID, &model.CategoryRequest{Title: "News", HideGlobally: "on"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !category.HideGlobally {
+		t.Fatal(`The category should be hidden globally`)
+	}
+
+	feed.Category = category
+	if err := store.UpdateFeed(feed); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.RemoveAndReplaceCategoriesByName(user.ID, []string{"All", "News"}); err == nil {
+		t.Fatal(`At least one category should remain`)
+	}
+
+	if err := store.RemoveAndReplaceCategoriesByName(user.ID, []string{"News"}); err != nil {
+		t.Fatal(err)
+	}
+
+	feed, err = store.FeedByID(user.ID, feed.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if feed.Category.Title != "All" {
+		t.Fatalf(`The feed should be moved to the remaining category, got %q`, feed.Category.Title)
+	}
+
+	cache := NewCertificateCache(store)
+	if err := cache.Put(context.Background(), "key", []byte{0, 1, 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := cache.Get(context.Background(), "key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(data) != 3 || data[2] != 2 {
+		t.Fatalf(`Unexpected certificate data: %v`, data)
+	}
+}
+
+func TestSQLiteBatches(t *testing.T) {
+	store := newSQLiteStorage(t)
+	user, feed := createSQLiteFeed(t, store)
+
+	feed.Disabled = true
+	feed.ID = 0
+	feed.FeedURL = "https://example.org/disabled.xml"
+	if err := store.CreateFeed(feed); err != nil {
+		t.Fatal(err)
+	}
+
+	jobs, err := store.NewAllFeedsBatch()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(jobs) != 1 || jobs[0].UserID != user.ID {
+		t.Fatalf(`Only the enabled feed should be returned: %+v`, jobs)
+	}
+
+	if userID := store.CategoryUserID(feed.Category.ID); userID != user.ID {
+		t.Fatalf(`Unexpected category owner: %d`, userID)
+	}
+
+	if userID := store.CategoryUserID(12345); userID != 0 {
+		t.Fatalf(`An unknown category should not have an owner: %d`, userID)
+	}
+}
+
+func TestSQLiteMaintenanceOnlyTouchesChangedUsers(t *testing.T) {
+	store := newSQLiteStorage(t)
+	user, feed := createSQLiteFeed(t, store)
+
+	changedAt, err := store.UserChangedAt(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	if err := store.ResetFeedErrors(); err != nil {
+		t.Fatal(err)
+	}
+
+	if count, err := store.ArchiveEntries(model.EntryStatusRead, 1, 100); err != nil || count != 0 {
+		t.Fatalf(`No entry should be archived: count=%d err=%v`, count, err)
+	}
+
+	if unchanged, err := store.UserChangedAt(user.ID); err != nil || !unchanged.Equal(changedAt) {
+		t.Fatalf(`The user should not be touched when nothing changed: %v != %v, err=%v`, unchanged, changedAt, err)
+	}
+
+	feed.WithError("Timeout")
+	if err := store.UpdateFeedError(feed); err != nil {
+		t.Fatal(err)
+	}
+
+	changedAt, err = store.UserChangedAt(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	if err := store.ResetFeedErrors(); err != nil {
+		t.Fatal(err)
+	}
+
+	if touched, err := store.UserChangedAt(user.ID); err != nil || !touched.After(changedAt) {
+		t.Fatalf(`The user should be touched when the feed errors are reset: %v, err=%v`, touched, err)
+	}
+}
+
+func TestFTSQuery(t *testing.T) {
+	if query := ftsQuery(` Hello  "world" `); query != `"Hello" """world"""` {
+		t.Fatalf(`Unexpected FTS query: %q`, query)
+	}
+}
+
+func TestSQLiteEntryCursor(t *testing.T) {
+	store := newSQLiteStorage(t)
+	user, _ := createSQLiteFeed(t, store)
+
+	for _, direction := range []string{"asc", "desc"} {
+		var cursor *model.EntryCursor
+		var titles []string
+
+		for i := 0; i < 3; i++ {
+			builder := store.NewEntryQueryBuilder(user.ID)
+			builder.WithOrder(model.DefaultSortingOrder)
+			builder.WithDirection(direction)
+			builder.WithCursor(cursor)
+			builder.WithLimit(1)
+
+			entries, err := builder.GetEntries()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(entries) == 0 {
+				break
+			}
+
+			titles = append(titles, entries[0].Title)
+			cursor = model.NewEntryCursor(entries[0])
+		}
+
+		if len(titles) != 2 || titles[0] == titles[1] {
+			t.Fatalf(`Unexpected pages with direction %q: %v`, direction, titles)
+		}
+
+		if (direction == "asc") != (titles[0] == "Gardening in spring") {
+			t.Fatalf(`Unexpected order with direction %q: %v`, direction, titles)
+		}
+	}
+}
+
+func TestSQLiteStorageStatistics(t *testing.T) {
+	store := newSQLiteStorage(t)
+	user, feed := createSQLiteFeed(t, store)
+
+	entryIDs, err := store.NewEntryQueryBuilder(user.ID).WithOrder("published_at").WithDirection("ASC").GetEntryIDs()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.SetEntriesStatus(user.ID, entryIDs[:1], model.EntryStatusRemoved); err != nil {
+		t.Fatal(err)
+	}
+
+	statistics, err := store.StorageStatistics(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if statistics.DatabaseSize == 0 || len(statistics.Tables) == 0 || len(statistics.Indexes) == 0 {
+		t.Fatalf(`Unexpected database statistics: %+v`, statistics)
+	}
+
+	if len(statistics.Users) != 1 || statistics.Users[0].FeedCount != 1 || statistics.Users[0].EntryCount != 2 || statistics.Users[0].RemovedEntryCount != 1 {
+		t.Fatalf(`Unexpected user statistics: %+v`, statistics.Users)
+	}
+
+	if len(statistics.Feeds) != 1 || statistics.Feeds[0].FeedID != feed.ID || statistics.Feeds[0].ContentSize == 0 {
+		t.Fatalf(`Unexpected feed statistics: %+v`, statistics.Feeds)
+	}
+
+	for _, table := range statistics.Tables {
+		if table.Name == "entries" && table.RowCount != 2 {
+			t.Fatalf(`Unexpected entries table statistics: %+v`, table)
+		}
+	}
+
+	count, err := store.PurgeRemovedEntries(feed.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if count != 1 {
+		t.Fatalf(`Unexpected number of purged entries: got %d instead of 1`, count)
+	}
+
+	feeds, err := store.FeedStorageStatistics(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if feeds[0].EntryCount != 2 || feeds[0].ContentSize >= statistics.Feeds[0].ContentSize {
+		t.Fatalf(`The content of the removed entries should be deleted: %+v`, feeds[0])
+	}
+
+	refreshed := model.Entries{{Hash: "1", Title: "Gardening in spring", URL: "https://example.org/1", Content: "<p>Plant seeds.</p>"}}
+	if err := store.RefreshFeedEntries(user.ID, feed.ID, refreshed, true); err != nil {
+		t.Fatal(err)
+	}
+
+	var content string
+	var revisionCount int
+	if err := store.db.QueryRow(`SELECT content, revision_count FROM entries WHERE id=$1`, entryIDs[0]).Scan(&content, &revisionCount); err != nil {
+		t.Fatal(err)
+	}
+
+	if content != "" || revisionCount != 0 {
+		t.Fatalf(`A purged entry should not be updated by a refresh: content=%q revisions=%d`, content, revisionCount)
+	}
+
+	for _, operation := range []string{model.DatabaseVacuum, model.DatabaseAnalyze, model.DatabaseReindex} {
+		if err := store.RunDatabaseMaintenance(operation); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := store.RunDatabaseMaintenance("drop"); err == nil {
+		t.Fatal(`Unknown operations should be rejected`)
+	}
+}
+
+func TestSQLiteWebhooks(t *testing.T) {
+	store := newSQLiteStorage(t)
+	user, feed := createSQLiteFeed(t, store)
+
+	webhook := model.NewWebhook(user.ID, "https://example.org/webhook", "", model.WebhookEvents())
+	if err := store.CreateWebhook(webhook); err != nil {
+		t.Fatal(err)
+	}
+
+	if webhook.Secret == "" {
+		t.Fatal(`A secret should be generated`)
+	}
+
+	feedErrorWebhook := model.NewWebhook(user.ID, "https://example.org/errors", "secret", []string{model.WebhookEventFeedError})
+	if err := store.CreateWebhook(feedErrorWebhook); err != nil {
+		t.Fatal(err)
+	}
+
+	webhooks, err := store.Webhooks(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(webhooks) != 2 || len(webhooks[0].Events) != 4 || !webhooks[1].HasEvent(model.WebhookEventFeedError) || webhooks[1].HasEvent(model.WebhookEventEntryRead) {
+		t.Fatalf(`Unexpected webhooks: %+v`, webhooks)
+	}
+
+	newEntries := model.Entries{
+		{Hash: "1", Title: "Gardening in spring", URL: "https://example.org/1", Date: time.Now()},
+		{Hash: "3", Title: "Baking bread", URL: "https://example.org/3", Date: time.Now(), Content: "<p>Knead the dough.</p>"},
+	}
+	if err := store.RefreshFeedEntries(user.ID, feed.ID, newEntries, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.ToggleBookmark(user.ID, newEntries[1].ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.SetEntriesStatus(user.ID, []int64{newEntries[1].ID}, model.EntryStatusRead); err != nil {
+		t.Fatal(err)
+	}
+
+	// The entries already read are not sent again.
+	if err := store.SetEntriesStatus(user.ID, []int64{newEntries[1].ID}, model.EntryStatusRead); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.SetEntriesStatus(user.ID, []int64{123456789}, model.EntryStatusRead); err == nil {
+		t.Fatal(`Unknown entries should not be updated`)
+	}
+
+	if err := store.MarkFeedAsRead(user.ID, feed.ID, time.Now().Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	feed.ParsingErrorCount = 1
+	feed.ParsingErrorMsg = "unable to parse feed"
+	if err := store.UpdateFeedError(feed); err != nil {
+		t.Fatal(err)
+	}
+
+	// The event is sent for the first error only.
+	feed.ParsingErrorCount = 2
+	if err := store.UpdateFeedError(feed); err != nil {
+		t.Fatal(err)
+	}
+
+	deliveries, err := store.ClaimWebhookDeliveries(10, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if claimedDeliveries, err := store.ClaimWebhookDeliveries(10, time.Minute); err != nil || len(claimedDeliveries) != 0 {
+		t.Fatalf(`The claimed deliveries should not be returned again: %d (%v)`, len(claimedDeliveries), err)
+	}
+
+	expectedEvents := []string{
+		model.WebhookEventEntryCreated,
+		model.WebhookEventEntryStarred,
+		model.WebhookEventEntryRead,
+		model.WebhookEventEntryRead,
+		model.WebhookEventFeedError,
+		model.WebhookEventFeedError,
+	}
+	if len(deliveries) != len(expectedEvents) {
+		t.Fatalf(`Unexpected number of deliveries: got %d instead of %d`, len(deliveries), len(expectedEvents))
+	}
+
+	for i, delivery := range deliveries {
+		if delivery.Event != expectedEvents[i] {
+			t.Fatalf(`Unexpected event for delivery #%d: got %q instead of %q`, i, delivery.Event, expectedEvents[i])
+		}
+	}
+
+	var event model.WebhookEvent
+	if err := json.Unmarshal([]byte(deliveries[0].Payload), &event); err != nil {
+		t.Fatal(err)
+	}
+
+	if event.Feed == nil || event.Feed.ID != feed.ID || len(event.Entries) != 1 || event.Entries[0].Title != "Baking bread" || event.Entries[0].Content == "" {
+		t.Fatalf(`Unexpected entry_created payload: %s`, deliveries[0].Payload)
+	}
+
+	event = model.WebhookEvent{}
+	if err := json.Unmarshal([]byte(deliveries[2].Payload), &event); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(event.Entries) != 1 || event.Entries[0].Status != model.EntryStatusRead || event.Entries[0].Content != "" {
+		t.Fatalf(`Unexpected entry_read payload: %s`, deliveries[2].Payload)
+	}
+
+	event = model.WebhookEvent{}
+	if err := json.Unmarshal([]byte(deliveries[3].Payload), &event); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(event.Entries) != 2 {
+		t.Fatalf(`The entries already read should not be sent again: %s`, deliveries[3].Payload)
+	}
+
+	if deliveries[5].Webhook.URL != feedErrorWebhook.URL || deliveries[5].Webhook.Secret != "secret" {
+		t.Fatalf(`Unexpected webhook for delivery: %+v`, deliveries[5].Webhook)
+	}
+
+	now := time.Now()
+	deliveries[0].Status = model.WebhookDeliveryStatusSuccess
+	deliveries[0].Attempts = 1
+	deliveries[0].ResponseStatus = 200
+	deliveries[0].LastAttemptAt = &now
+
+	deliveries[1].Attempts = 1
+	deliveries[1].ResponseStatus = 500
+	deliveries[1].ErrorMsg = "internal server error"
+	deliveries[1].LastAttemptAt = &now
+	deliveries[1].NextAttemptAt = now.Add(-time.Second)
+
+	for _, delivery := range deliveries[:2] {
+		if err := store.UpdateWebhookDelivery(delivery); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pendingDeliveries, err := store.ClaimWebhookDeliveries(10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pendingDeliveries) != 1 || pendingDeliveries[0].ID != deliveries[1].ID {
+		t.Fatalf(`Only the delivery to retry should be due: %+v`, pendingDeliveries)
+	}
+
+	history, err := store.WebhookDeliveries(user.ID, webhook.ID, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(history) != 5 || history[4].Status != model.WebhookDeliveryStatusSuccess || history[3].ErrorMsg != "internal server error" || history[3].LastAttemptAt == nil {
+		t.Fatalf(`Unexpected delivery history: %+v`, history)
+	}
+
+	if count, err := store.CleanOldWebhookDeliveries(0); err != nil || count != 1 {
+		t.Fatalf(`Only the delivered events should be cleaned: count=%d err=%v`, count, err)
+	}
+
+	if err := store.RemoveWebhook(user.ID, webhook.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if store.WebhookIDExists(user.ID, webhook.ID) {
+		t.Fatal(`The webhook should be removed`)
+	}
+
+	history, err = store.WebhookDeliveries(user.ID, feedErrorWebhook.ID, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(history) != 1 {
+		t.Fatalf(`The deliveries of the other webhook should be kept: %d`, len(history))
+	}
+
+	if removedDeliveries, err := store.webhookDeliveriesByIDs([]int64{deliveries[2].ID}); err != nil || len(removedDeliveries) != 0 {
+		t.Fatalf(`The deliveries of the removed webhook should be removed: %d (%v)`, len(removedDeliveries), err)
+	}
+}
@@ -7,23 +7,35 @@ package storage // import "miniflux.app/storage"
 import (
 	"context"
 	"database/sql"
+	"sync"
 	"time"
+
+	"miniflux.app/database"
 )
 
 // Storage handles all operations related to the database.
 type Storage struct {
-	db *sql.DB
+	db     *sql.DB
+	driver string
+
+	textSearchConfigsOnce      sync.Once
+	availableTextSearchConfigs map[string]bool
 }
 
 // NewStorage returns a new Storage.
 func NewStorage(db *sql.DB) *Storage {
-	return &Storage{db}
+	return &Storage{db: db, driver: database.DriverName(db)}
 }
 
 // DatabaseVersion returns the version of the database which is in use.
 func (s *Storage) DatabaseVersion() string {
+	query := `SELECT current_setting('server_version')`
+	if s.isSQLite() {
+		query = `SELECT 'SQLite ' || sqlite_version()`
+	}
+
 	var dbVersion string
-	err := s.db.QueryRow(`SELECT current_setting('server_version')`).Scan(&dbVersion)
+	err := s.db.QueryRow(query).Scan(&dbVersion)
 	if err != nil {
 		return err.Error()
 	}
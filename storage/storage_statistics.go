@@ -0,0 +1,367 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package storage // import "miniflux.app/storage"
+
+import (
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"miniflux.app/model"
+)
+
+// StorageStatistics returns the disk usage of the database, the users and the feeds using the most space.
+func (s *Storage) StorageStatistics(feedLimit int) (*model.StorageStatistics, error) {
+	statistics := &model.StorageStatistics{}
+
+	query := `SELECT pg_database_size(current_database())`
+	if s.isSQLite() {
+		query = `SELECT page_count * page_size FROM pragma_page_count(), pragma_page_size()`
+	}
+
+	if err := s.db.QueryRow(query).Scan(&statistics.DatabaseSize); err != nil {
+		return nil, fmt.Errorf(`store: unable to fetch database size: %v`, err)
+	}
+
+	var err error
+	if s.isSQLite() {
+		statistics.Tables, statistics.Indexes, err = s.sqliteTableStatistics()
+	} else {
+		statistics.Tables, statistics.Indexes, err = s.postgresTableStatistics()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if statistics.Users, err = s.UserStorageStatistics(); err != nil {
+		return nil, err
+	}
+
+	if statistics.Feeds, err = s.FeedStorageStatistics(feedLimit); err != nil {
+		return nil, err
+	}
+
+	return statistics, nil
+}
+
+// UserStorageStatistics returns the number of rows and the size of the content stored for each user.
+func (s *Storage) UserStorageStatistics() ([]*model.UserStorageStatistics, error) {
+	query := `
+		SELECT
+			u.id,
+			u.username,
+			(SELECT count(*) FROM feeds f WHERE f.user_id=u.id),
+			coalesce(e.entry_count, 0),
+			coalesce(e.removed_count, 0),
+			coalesce(e.content_size, 0)
+		FROM
+			users u
+		LEFT JOIN
+			(
+				SELECT
+					user_id,
+					count(*) AS entry_count,
+					sum(CASE WHEN status=$1 THEN 1 ELSE 0 END) AS removed_count,
+					sum(` + s.octetLength("title") + ` + ` + s.octetLength("content") + `) AS content_size
+				FROM
+					entries
+				GROUP BY
+					user_id
+			) e ON e.user_id=u.id
+		ORDER BY
+			6 DESC, u.username ASC
+	`
+	rows, err := s.db.Query(query, model.EntryStatusRemoved)
+	if err != nil {
+		return nil, fmt.Errorf(`store: unable to fetch user storage statistics: %v`, err)
+	}
+	defer rows.Close()
+
+	var users []*model.UserStorageStatistics
+	for rows.Next() {
+		var user model.UserStorageStatistics
+		if err := rows.Scan(
+			&user.UserID,
+			&user.Username,
+			&user.FeedCount,
+			&user.EntryCount,
+			&user.RemovedEntryCount,
+			&user.ContentSize,
+		); err != nil {
+			return nil, fmt.Errorf(`store: unable to fetch user storage statistics row: %v`, err)
+		}
+
+		users = append(users, &user)
+	}
+
+	return users, nil
+}
+
+// FeedStorageStatistics returns the feeds with the largest content, a limit of zero returns all feeds.
+func (s *Storage) FeedStorageStatistics(limit int) ([]*model.FeedStorageStatistics, error) {
+	query := `
+		SELECT
+			f.id,
+			f.user_id,
+			u.username,
+			f.title,
+			coalesce(e.entry_count, 0),
+			coalesce(e.removed_count, 0),
+			coalesce(e.content_size, 0)
+		FROM
+			feeds f
+		JOIN
+			users u ON u.id=f.user_id
+		LEFT JOIN
+			(
+				SELECT
+					feed_id,
+					count(*) AS entry_count,
+					sum(CASE WHEN status=$1 THEN 1 ELSE 0 END) AS removed_count,
+					sum(` + s.octetLength("title") + ` + ` + s.octetLength("content") + `) AS content_size
+				FROM
+					entries
+				GROUP BY
+					feed_id
+			) e ON e.feed_id=f.id
+		ORDER BY
+			7 DESC, f.id ASC
+	`
+	args := []interface{}{model.EntryStatusRemoved}
+	if limit > 0 {
+		query += ` LIMIT $2`
+		args = append(args, limit)
+	}
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf(`store: unable to fetch feed storage statistics: %v`, err)
+	}
+	defer rows.Close()
+
+	var feeds []*model.FeedStorageStatistics
+	for rows.Next() {
+		var feed model.FeedStorageStatistics
+		if err := rows.Scan(
+			&feed.FeedID,
+			&feed.UserID,
+			&feed.Username,
+			&feed.Title,
+			&feed.EntryCount,
+			&feed.RemovedEntryCount,
+			&feed.ContentSize,
+		); err != nil {
+			return nil, fmt.Errorf(`store: unable to fetch feed storage statistics row: %v`, err)
+		}
+
+		feeds = append(feeds, &feed)
+	}
+
+	return feeds, nil
+}
+
+// postgresTableStatistics returns the size of the tables and indexes of the current schema.
+// Row counts are estimated from the planner statistics to avoid scanning large tables.
+func (s *Storage) postgresTableStatistics() ([]*model.TableStatistics, []*model.IndexStatistics, error) {
+	rows, err := s.db.Query(`
+		SELECT
+			c.relname,
+			greatest(c.reltuples, 0)::bigint,
+			pg_table_size(c.oid),
+			pg_indexes_size(c.oid)
+		FROM
+			pg_class c
+		JOIN
+			pg_namespace n ON n.oid=c.relnamespace
+		WHERE
+			c.relkind='r' AND n.nspname=current_schema()
+		ORDER BY
+			pg_total_relation_size(c.oid) DESC
+	`)
+	if err != nil {
+		return nil, nil, fmt.Errorf(`store: unable to fetch table statistics: %v`, err)
+	}
+	defer rows.Close()
+
+	var tables []*model.TableStatistics
+	for rows.Next() {
+		var table model.TableStatistics
+		if err := rows.Scan(&table.Name, &table.RowCount, &table.TableSize, &table.IndexSize); err != nil {
+			return nil, nil, fmt.Errorf(`store: unable to fetch table statistics row: %v`, err)
+		}
+		tables = append(tables, &table)
+	}
+
+	rows, err = s.db.Query(`
+		SELECT
+			t.relname,
+			i.relname,
+			pg_relation_size(i.oid)
+		FROM
+			pg_index x
+		JOIN
+			pg_class i ON i.oid=x.indexrelid
+		JOIN
+			pg_class t ON t.oid=x.indrelid
+		JOIN
+			pg_namespace n ON n.oid=t.relnamespace
+		WHERE
+			n.nspname=current_schema()
+		ORDER BY
+			3 DESC, 2 ASC
+	`)
+	if err != nil {
+		return nil, nil, fmt.Errorf(`store: unable to fetch index statistics: %v`, err)
+	}
+	defer rows.Close()
+
+	var indexes []*model.IndexStatistics
+	for rows.Next() {
+		var index model.IndexStatistics
+		if err := rows.Scan(&index.TableName, &index.Name, &index.Size); err != nil {
+			return nil, nil, fmt.Errorf(`store: unable to fetch index statistics row: %v`, err)
+		}
+		indexes = append(indexes, &index)
+	}
+
+	return tables, indexes, nil
+}
+
+// sqliteTableStatistics returns the size of the tables and indexes using the dbstat virtual table.
+// When SQLite is compiled without dbstat, only the row counts are reported.
+func (s *Storage) sqliteTableStatistics() ([]*model.TableStatistics, []*model.IndexStatistics, error) {
+	query := `
+		SELECT
+			m.type,
+			m.name,
+			m.tbl_name,
+			%s
+		FROM
+			sqlite_master m
+		%s
+		WHERE
+			m.type IN ('table', 'index')
+		GROUP BY
+			m.type, m.name, m.tbl_name
+		ORDER BY
+			4 DESC, 2 ASC
+	`
+	rows, err := s.db.Query(fmt.Sprintf(query, `coalesce(sum(d.pgsize), 0)`, `LEFT JOIN dbstat d ON d.name=m.name`))
+	if err != nil {
+		rows, err = s.db.Query(fmt.Sprintf(query, `0`, ``))
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf(`store: unable to fetch table statistics: %v`, err)
+	}
+	defer rows.Close()
+
+	var tables []*model.TableStatistics
+	var indexes []*model.IndexStatistics
+	for rows.Next() {
+		var objectType, name, tableName string
+		var size int64
+		if err := rows.Scan(&objectType, &name, &tableName, &size); err != nil {
+			return nil, nil, fmt.Errorf(`store: unable to fetch table statistics row: %v`, err)
+		}
+
+		if objectType == "index" {
+			indexes = append(indexes, &model.IndexStatistics{TableName: tableName, Name: name, Size: size})
+		} else {
+			tables = append(tables, &model.TableStatistics{Name: name, TableSize: size})
+		}
+	}
+	rows.Close()
+
+	for _, table := range tables {
+		query := `SELECT count(*) FROM ` + pq.QuoteIdentifier(table.Name)
+		if err := s.db.QueryRow(query).Scan(&table.RowCount); err != nil {
+			return nil, nil, fmt.Errorf(`store: unable to count rows of table %q: %v`, table.Name, err)
+		}
+
+		for _, index := range indexes {
+			if index.TableName == table.Name {
+				table.IndexSize += index.Size
+			}
+		}
+	}
+
+	return tables, indexes, nil
+}
+
+// RunDatabaseMaintenance runs a maintenance operation on the whole database.
+func (s *Storage) RunDatabaseMaintenance(operation string) error {
+	var queries []string
+
+	switch operation {
+	case model.DatabaseVacuum:
+		queries = []string{`VACUUM`}
+	case model.DatabaseAnalyze:
+		queries = []string{`ANALYZE`}
+	case model.DatabaseReindex:
+		if s.isSQLite() {
+			queries = []string{`REINDEX`, `INSERT INTO entries_fts (entries_fts) VALUES ('optimize')`}
+		} else {
+			var schema string
+			if err := s.db.QueryRow(`SELECT current_schema()`).Scan(&schema); err != nil {
+				return fmt.Errorf(`store: unable to fetch current schema: %v`, err)
+			}
+			queries = []string{`REINDEX SCHEMA ` + pq.QuoteIdentifier(schema)}
+		}
+	default:
+		return fmt.Errorf(`store: unknown database maintenance operation %q`, operation)
+	}
+
+	for _, query := range queries {
+		if _, err := s.db.Exec(query); err != nil {
+			return fmt.Errorf(`store: unable to run %s: %v`, operation, err)
+		}
+	}
+
+	return nil
+}
+
+// PurgeRemovedEntries deletes the content, the enclosures and the revisions of the removed entries of a feed.
+// The rows are kept, otherwise the entries still present in the feed would be imported again as unread.
+func (s *Storage) PurgeRemovedEntries(feedID int64) (int64, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf(`store: unable to start transaction: %v`, err)
+	}
+
+	removedEntries := `SELECT id FROM entries WHERE feed_id=$1 AND status=$2`
+	for _, table := range []string{"enclosures", "entry_revisions"} {
+		query := `DELETE FROM ` + table + ` WHERE entry_id IN (` + removedEntries + `)`
+		if _, err := tx.Exec(query, feedID, model.EntryStatusRemoved); err != nil {
+			tx.Rollback()
+			return 0, fmt.Errorf(`store: unable to purge %s of feed #%d: %v`, table, feedID, err)
+		}
+	}
+
+	var searchAssignment string
+	if !s.isSQLite() {
+		searchAssignment = ", document_vectors=NULL"
+	}
+
+	result, err := tx.Exec(
+		`UPDATE entries SET content='', revision_count=0`+searchAssignment+` WHERE feed_id=$1 AND status=$2 AND content <> ''`,
+		feedID,
+		model.EntryStatusRemoved,
+	)
+	if err != nil {
+		tx.Rollback()
+		return 0, fmt.Errorf(`store: unable to purge removed entries of feed #%d: %v`, feedID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf(`store: unable to commit transaction: %v`, err)
+	}
+
+	count, _ := result.RowsAffected()
+	if count > 0 {
+		s.touchFeedUser(feedID)
+	}
+
+	return count, nil
+}
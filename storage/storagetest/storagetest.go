@@ -0,0 +1,92 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+// Package storagetest provides SQLite fixtures for the tests of the packages using the storage.
+package storagetest // import "miniflux.app/storage/storagetest"
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"miniflux.app/config"
+	"miniflux.app/database"
+	"miniflux.app/model"
+	"miniflux.app/storage"
+)
+
+// NewStorage returns a storage using a migrated SQLite database, the database is removed at the end of the test.
+// The configuration is loaded from the environment variables.
+func NewStorage(t *testing.T) *storage.Storage {
+	t.Helper()
+
+	var err error
+	config.Opts, err = config.NewParser().ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatalf(`Parsing failure: %v`, err)
+	}
+
+	db, err := database.NewConnectionPool("sqlite://"+filepath.Join(t.TempDir(), "miniflux.db"), 1, 5, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := database.Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+
+	return storage.NewStorage(db)
+}
+
+// CreateUser creates the user "admin" with the password "test123".
+func CreateUser(t *testing.T, store *storage.Storage, isAdmin bool) *model.User {
+	t.Helper()
+
+	user, err := store.CreateUser(&model.UserCreationRequest{Username: "admin", Password: "test123", IsAdmin: isAdmin})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return user
+}
+
+// CreateFeed creates the feed "https://example.org/feed.xml" in the first category of the user.
+func CreateFeed(t *testing.T, store *storage.Storage, user *model.User) *model.Feed {
+	t.Helper()
+
+	category, err := store.FirstCategory(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	feed := &model.Feed{
+		UserID:   user.ID,
+		Category: category,
+		FeedURL:  "https://example.org/feed.xml",
+		SiteURL:  "https://example.org/",
+		Title:    "Example",
+	}
+	if err := store.CreateFeed(feed); err != nil {
+		t.Fatal(err)
+	}
+
+	return feed
+}
+
+// RefreshEntries stores the entries of the feed and returns all the entries of the user ordered by ID.
+func RefreshEntries(t *testing.T, store *storage.Storage, feed *model.Feed, entries model.Entries, updateExistingEntries bool) model.Entries {
+	t.Helper()
+
+	if err := store.RefreshFeedEntries(feed.UserID, feed.ID, entries, updateExistingEntries); err != nil {
+		t.Fatal(err)
+	}
+
+	storedEntries, err := store.NewEntryQueryBuilder(feed.UserID).WithOrder("e.id").WithDirection("asc").GetEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return storedEntries
+}
@@ -0,0 +1,193 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package storage // import "miniflux.app/storage"
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"miniflux.app/database"
+	"miniflux.app/logger"
+)
+
+// searchSnippetOptions are the ts_headline() options used to generate search result snippets.
+const searchSnippetOptions = "StartSel=<mark>, StopSel=</mark>, MaxWords=35, MinWords=15, MaxFragments=2, FragmentDelimiter=\" … \""
+
+// searchArguments returns the two arguments used by the search placeholders:
+// the text search configuration with PostgreSQL or the FTS5 query with SQLite, followed by the search query.
+func (s *Storage) searchArguments(query, language string) []interface{} {
+	if s.isSQLite() {
+		return []interface{}{ftsQuery(query), query}
+	}
+	return []interface{}{s.textSearchConfig(language), query}
+}
+
+// entrySearchCondition matches entries whose content or annotations contain the search query.
+// The placeholders refer to the arguments returned by searchArguments.
+//
+// With PostgreSQL, the query is parsed with the configuration used to index each entry.
+// There is one condition per configuration, otherwise the index of the search vectors would not be used.
+func (s *Storage) entrySearchCondition(configArg, queryArg int) string {
+	if s.isSQLite() {
+		return fmt.Sprintf(
+			`(e.id IN (SELECT rowid FROM entries_fts WHERE entries_fts MATCH $%[1]d) OR EXISTS (
+				SELECT 1 FROM entry_annotations a
+				WHERE a.entry_id = e.id AND %[2]s
+			))`,
+			configArg,
+			s.annotationSearchCondition(configArg, queryArg),
+		)
+	}
+
+	var conditions []string
+	for _, config := range s.installedTextSearchConfigs() {
+		conditions = append(conditions, fmt.Sprintf(
+			"(e.search_config = %[1]s AND e.document_vectors @@ plainto_tsquery(%[1]s, $%[2]d))",
+			pq.QuoteLiteral(config),
+			queryArg,
+		))
+	}
+
+	return fmt.Sprintf(
+		`(%[1]s OR EXISTS (
+			SELECT 1 FROM entry_annotations a
+			WHERE a.entry_id = e.id AND %[2]s
+		))`,
+		strings.Join(conditions, " OR "),
+		s.annotationSearchCondition(configArg, queryArg),
+	)
+}
+
+// annotationSearchCondition matches annotations whose quote or note contain the search query.
+func (s *Storage) annotationSearchCondition(configArg, queryArg int) string {
+	if s.isSQLite() {
+		return fmt.Sprintf("instr(lower(a.quote || ' ' || a.note), lower($%d)) > 0", queryArg)
+	}
+
+	return fmt.Sprintf(
+		"a.document_vectors @@ plainto_tsquery($%[1]d::regconfig, $%[2]d)",
+		configArg,
+		queryArg,
+	)
+}
+
+// entrySearchRanking returns the sorting expression of search results, recent entries rank higher.
+func (s *Storage) entrySearchRanking(configArg, queryArg int) string {
+	if s.isSQLite() {
+		// 0.00864 = 0.0000001 * (seconds_in_a_day), the same penalty per day as with PostgreSQL
+		return fmt.Sprintf(
+			"coalesce((SELECT -bm25(entries_fts, 10.0, 1.0) FROM entries_fts WHERE entries_fts MATCH $%d AND rowid = e.id), 0) - (julianday('now') - julianday(e.published_at)) * 0.00864",
+			configArg,
+		)
+	}
+
+	// 0.0000001 = 0.1 / (seconds_in_a_day)
+	return fmt.Sprintf(
+		"ts_rank(e.document_vectors, plainto_tsquery(e.search_config, $%d)) - extract (epoch from now() - e.published_at)::float * 0.0000001",
+		queryArg,
+	)
+}
+
+// entrySearchSnippet returns the expression generating the search result snippet with highlighted terms.
+func (s *Storage) entrySearchSnippet(configArg, queryArg int) string {
+	if s.isSQLite() {
+		return fmt.Sprintf(
+			`coalesce((SELECT snippet(entries_fts, 1, '<mark>', '</mark>', ' … ', 30) FROM entries_fts WHERE entries_fts MATCH $%d AND rowid = e.id), '')`,
+			configArg,
+		)
+	}
+
+	return fmt.Sprintf(
+		`ts_headline(e.search_config, regexp_replace(coalesce(e.content, ''), '<[^>]*>', ' ', 'g'), plainto_tsquery(e.search_config, $%[1]d), '%[2]s')`,
+		queryArg,
+		searchSnippetOptions,
+	)
+}
+
+// documentVectors returns the PostgreSQL expression indexing the title and the content of an entry.
+// SQLite entries are indexed by triggers.
+func documentVectors(title, content string, configArg int) string {
+	return fmt.Sprintf(
+		"setweight(to_tsvector($%[3]d::regconfig, left(coalesce(%[1]s, ''), 500000)), 'A') || setweight(to_tsvector($%[3]d::regconfig, left(coalesce(%[2]s, ''), 500000)), 'B')",
+		title,
+		content,
+		configArg,
+	)
+}
+
+// annotationVectors returns the PostgreSQL expression indexing the quote and the note of an annotation.
+func annotationVectors(quote, note string, configArg int) string {
+	return fmt.Sprintf("to_tsvector($%[3]d::regconfig, %[1]s || ' ' || %[2]s)", quote, note, configArg)
+}
+
+// ftsQuery converts a search query to a FTS5 query matching all the words, like plainto_tsquery().
+func ftsQuery(query string) string {
+	words := strings.Fields(query)
+	for i, word := range words {
+		words[i] = `"` + strings.ReplaceAll(word, `"`, `""`) + `"`
+	}
+	return strings.Join(words, " ")
+}
+
+// textSearchConfig returns the Postgres text search configuration for an entry language ("de")
+// or a user locale ("de_DE").
+func (s *Storage) textSearchConfig(language string) string {
+	config := database.TextSearchConfig(language)
+	if s.isSQLite() {
+		return config
+	}
+
+	s.textSearchConfigsOnce.Do(s.loadTextSearchConfigs)
+	if !s.availableTextSearchConfigs[config] {
+		return database.DefaultTextSearchConfig
+	}
+
+	return config
+}
+
+// installedTextSearchConfigs returns the configurations that can be used to index the entries.
+func (s *Storage) installedTextSearchConfigs() []string {
+	s.textSearchConfigsOnce.Do(s.loadTextSearchConfigs)
+
+	var configs []string
+	for _, config := range database.TextSearchConfigs() {
+		if s.availableTextSearchConfigs[config] {
+			configs = append(configs, config)
+		}
+	}
+	return configs
+}
+
+// loadTextSearchConfigs fetches the text search configurations installed in the database.
+func (s *Storage) loadTextSearchConfigs() {
+	installed, err := database.InstalledTextSearchConfigs(s.db)
+	if err != nil {
+		logger.Error(`store: unable to fetch text search configurations: %v`, err)
+		s.availableTextSearchConfigs = map[string]bool{database.DefaultTextSearchConfig: true}
+		return
+	}
+	s.availableTextSearchConfigs = installed
+
+	for _, config := range database.TextSearchConfigs() {
+		if !installed[config] {
+			logger.Info(`[Storage] The text search configuration %q is not available, %q is used instead`, config, database.DefaultTextSearchConfig)
+		}
+	}
+}
+
+// formatSearchSnippet escapes the text generated by ts_headline() while keeping the highlighted terms.
+func formatSearchSnippet(headline string) string {
+	if headline == "" {
+		return ""
+	}
+
+	text := strings.NewReplacer("<mark>", "\x02", "</mark>", "\x03").Replace(headline)
+	text = html.EscapeString(html.UnescapeString(text))
+	text = strings.NewReplacer("\x02", "<mark>", "\x03", "</mark>").Replace(text)
+	return strings.Join(strings.Fields(text), " ")
+}
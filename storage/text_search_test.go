@@ -0,0 +1,55 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package storage // import "miniflux.app/storage"
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTextSearchConfig(t *testing.T) {
+	store := &Storage{driver: "postgres"}
+	store.textSearchConfigsOnce.Do(func() {
+		store.availableTextSearchConfigs = map[string]bool{"simple": true, "english": true, "german": true}
+	})
+
+	scenarios := map[string]string{
+		"":      "simple",
+		"en":    "english",
+		"de_DE": "german",
+		"DE-at": "german",
+		"hy":    "simple",
+		"xx":    "simple",
+	}
+
+	for language, expected := range scenarios {
+		if config := store.textSearchConfig(language); config != expected {
+			t.Errorf(`Unexpected configuration for %q: got %q instead of %q`, language, config, expected)
+		}
+	}
+}
+
+func TestPostgresEntrySearchCondition(t *testing.T) {
+	store := &Storage{driver: "postgres"}
+	store.textSearchConfigsOnce.Do(func() {
+		store.availableTextSearchConfigs = map[string]bool{"simple": true, "english": true, "german": true}
+	})
+
+	condition := store.entrySearchCondition(1, 2)
+	for _, expected := range []string{
+		"(e.search_config = 'english' AND e.document_vectors @@ plainto_tsquery('english', $2))",
+		"(e.search_config = 'german' AND e.document_vectors @@ plainto_tsquery('german', $2))",
+		"(e.search_config = 'simple' AND e.document_vectors @@ plainto_tsquery('simple', $2))",
+		"a.document_vectors @@ plainto_tsquery($1::regconfig, $2)",
+	} {
+		if !strings.Contains(condition, expected) {
+			t.Errorf(`The condition should contain %q: %s`, expected, condition)
+		}
+	}
+
+	if strings.Contains(condition, "french") {
+		t.Errorf(`The configurations that are not installed should be skipped: %s`, condition)
+	}
+}
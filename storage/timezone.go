@@ -6,11 +6,26 @@ package storage // import "miniflux.app/storage"
 
 import (
 	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
 	"strings"
+	"time"
 )
 
+// zoneinfoDirectories are the locations of the timezone database used with SQLite.
+var zoneinfoDirectories = []string{
+	"/usr/share/zoneinfo",
+	"/usr/share/lib/zoneinfo",
+	"/usr/lib/locale/TZ",
+}
+
 // Timezones returns all timezones supported by the database.
 func (s *Storage) Timezones() (map[string]string, error) {
+	if s.isSQLite() {
+		return systemTimezones(), nil
+	}
+
 	timezones := make(map[string]string)
 	rows, err := s.db.Query(`SELECT name FROM pg_timezone_names() ORDER BY name ASC`)
 	if err != nil {
@@ -31,3 +46,37 @@ func (s *Storage) Timezones() (map[string]string, error) {
 
 	return timezones, nil
 }
+
+// systemTimezones returns the timezones of the system timezone database that can be loaded by the application.
+func systemTimezones() map[string]string {
+	timezones := map[string]string{"UTC": "UTC"}
+
+	directories := zoneinfoDirectories
+	if directory := os.Getenv("ZONEINFO"); directory != "" {
+		directories = append([]string{directory}, directories...)
+	}
+
+	for _, directory := range directories {
+		filepath.WalkDir(directory, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() {
+				return nil
+			}
+
+			name, _ := filepath.Rel(directory, path)
+			if name[0] < 'A' || name[0] > 'Z' || strings.HasPrefix(name, "posix") || strings.HasPrefix(name, "SystemV") || strings.Contains(name, ".") {
+				return nil
+			}
+
+			if _, err := time.LoadLocation(name); err == nil {
+				timezones[name] = name
+			}
+			return nil
+		})
+
+		if len(timezones) > 1 {
+			break
+		}
+	}
+
+	return timezones
+}
@@ -0,0 +1,89 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package storage // import "miniflux.app/storage"
+
+import (
+	"fmt"
+	"time"
+
+	"miniflux.app/model"
+)
+
+// FeedUnreadCounters returns the number of unread entries and the date of the newest unread entry of each feed.
+func (s *Storage) FeedUnreadCounters(userID int64) ([]*model.UnreadCounter, error) {
+	query := `
+		SELECT
+			f.id, f.title, count(*), %s
+		FROM
+			entries e
+		JOIN
+			feeds f ON f.id=e.feed_id
+		WHERE
+			e.user_id=$1 AND e.status=$2
+		GROUP BY
+			f.id, f.title
+	`
+	return s.fetchUnreadCounters(fmt.Sprintf(query, s.unixMicroseconds("max(e.published_at)")), userID)
+}
+
+// CategoryUnreadCounters returns the number of unread entries and the date of the newest unread entry of each category.
+func (s *Storage) CategoryUnreadCounters(userID int64) ([]*model.UnreadCounter, error) {
+	query := `
+		SELECT
+			c.id, c.title, count(*), %s
+		FROM
+			entries e
+		JOIN
+			feeds f ON f.id=e.feed_id
+		JOIN
+			categories c ON c.id=f.category_id
+		WHERE
+			e.user_id=$1 AND e.status=$2
+		GROUP BY
+			c.id, c.title
+	`
+	return s.fetchUnreadCounters(fmt.Sprintf(query, s.unixMicroseconds("max(e.published_at)")), userID)
+}
+
+// LabelUnreadCounters returns the number of unread entries and the date of the newest unread entry of each label.
+func (s *Storage) LabelUnreadCounters(userID int64) ([]*model.UnreadCounter, error) {
+	query := `
+		SELECT
+			l.id, l.title, count(*), %s
+		FROM
+			entries e
+		JOIN
+			entry_labels el ON el.entry_id=e.id
+		JOIN
+			labels l ON l.id=el.label_id
+		WHERE
+			e.user_id=$1 AND e.status=$2
+		GROUP BY
+			l.id, l.title
+	`
+	return s.fetchUnreadCounters(fmt.Sprintf(query, s.unixMicroseconds("max(e.published_at)")), userID)
+}
+
+func (s *Storage) fetchUnreadCounters(query string, userID int64) ([]*model.UnreadCounter, error) {
+	rows, err := s.db.Query(query, userID, model.EntryStatusUnread)
+	if err != nil {
+		return nil, fmt.Errorf(`store: unable to fetch unread counters: %v`, err)
+	}
+	defer rows.Close()
+
+	counters := make([]*model.UnreadCounter, 0)
+	for rows.Next() {
+		var counter model.UnreadCounter
+		var newestEntryDate int64
+		if err := rows.Scan(&counter.ID, &counter.Title, &counter.Count, &newestEntryDate); err != nil {
+			return nil, fmt.Errorf(`store: unable to fetch unread counter row: %v`, err)
+		}
+
+		counter.NewestEntryDate = time.UnixMicro(newestEntryDate).UTC()
+		counters = append(counters, &counter)
+	}
+
+	return counters, nil
+}
@@ -155,6 +155,9 @@ func (s *Storage) CreateUser(userCreationRequest *model.UserCreationRequest) (*m
 
 // UpdateUser updates a user.
 func (s *Storage) UpdateUser(user *model.User) error {
+	// The annotations are indexed with the language of the user.
+	languageChanged := !s.isSQLite() && s.UserLanguage(user.ID) != user.Language
+
 	if user.Password != "" {
 		hashedPassword, err := hashPassword(user.Password)
 		if err != nil {
@@ -183,7 +186,8 @@ func (s *Storage) UpdateUser(user *model.User) error {
 				default_reading_speed=$18,
 				cjk_reading_speed=$19,
 				default_home_page=$20,
-				categories_sorting_order=$21
+				categories_sorting_order=$21,
+				changed_at=now()
 			WHERE
 				id=$22
 		`
@@ -238,7 +242,8 @@ func (s *Storage) UpdateUser(user *model.User) error {
 				default_reading_speed=$17,
 				cjk_reading_speed=$18,
 				default_home_page=$19,
-				categories_sorting_order=$20
+				categories_sorting_order=$20,
+				changed_at=now()
 			WHERE
 				id=$21
 		`
@@ -273,6 +278,10 @@ func (s *Storage) UpdateUser(user *model.User) error {
 		}
 	}
 
+	if languageChanged {
+		return s.reindexAnnotations(user.ID, user.Language)
+	}
+
 	return nil
 }
 
@@ -0,0 +1,74 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package storage // import "miniflux.app/storage"
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"miniflux.app/logger"
+)
+
+// UserChangedAt returns the last time the feeds, categories or entries of the user have been modified.
+// The API uses this timestamp to answer conditional requests without loading the data.
+func (s *Storage) UserChangedAt(userID int64) (time.Time, error) {
+	var changedAt time.Time
+	err := s.db.QueryRow(`SELECT changed_at FROM users WHERE id=$1`, userID).Scan(&changedAt)
+	if err != nil {
+		return changedAt, fmt.Errorf(`store: unable to fetch the last change of user #%d: %v`, userID, err)
+	}
+
+	return changedAt, nil
+}
+
+// touchUser records a modification of the data of the user.
+// It is called after the changes are committed to not hold a lock on the user row during long transactions.
+func (s *Storage) touchUser(userID int64) {
+	if _, err := s.db.Exec(`UPDATE users SET changed_at=now() WHERE id=$1`, userID); err != nil {
+		logger.Error(`store: unable to update the last change of user #%d: %v`, userID, err)
+	}
+}
+
+// touchFeedUser records a modification of the data of the user who owns the feed.
+func (s *Storage) touchFeedUser(feedID int64) {
+	query := `UPDATE users SET changed_at=now() WHERE id=(SELECT user_id FROM feeds WHERE id=$1)`
+	if _, err := s.db.Exec(query, feedID); err != nil {
+		logger.Error(`store: unable to update the last change of the owner of feed #%d: %v`, feedID, err)
+	}
+}
+
+// touchUsers records a modification of the data of the given users, it is used by maintenance tasks.
+func (s *Storage) touchUsers(userIDs []int64) {
+	if len(userIDs) == 0 {
+		return
+	}
+
+	query := `UPDATE users SET changed_at=now() WHERE ` + s.inArray("id", 1)
+	if _, err := s.db.Exec(query, s.array(userIDs)); err != nil {
+		logger.Error(`store: unable to update the last change of users: %v`, err)
+	}
+}
+
+// userIDsReturned reads the user IDs returned by a modification query, each user is only returned once.
+func userIDsReturned(rows *sql.Rows) (count int64, userIDs []int64, err error) {
+	defer rows.Close()
+
+	seen := make(map[int64]bool)
+	for rows.Next() {
+		var userID int64
+		if err := rows.Scan(&userID); err != nil {
+			return 0, nil, err
+		}
+
+		count++
+		if !seen[userID] {
+			seen[userID] = true
+			userIDs = append(userIDs, userID)
+		}
+	}
+
+	return count, userIDs, rows.Err()
+}
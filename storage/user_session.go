@@ -20,6 +20,7 @@ func (s *Storage) UserSessions(userID int64) (model.UserSessions, error) {
 			user_id,
 			token,
 			created_at,
+			last_used_at,
 			user_agent,
 			ip
 		FROM
@@ -41,6 +42,7 @@ func (s *Storage) UserSessions(userID int64) (model.UserSessions, error) {
 			&session.UserID,
 			&session.Token,
 			&session.CreatedAt,
+			&session.LastUsedAt,
 			&session.UserAgent,
 			&session.IP,
 		)
@@ -71,7 +73,7 @@ func (s *Storage) CreateUserSessionFromUsername(username, userAgent, ip string)
 	}
 
 	_, err = tx.Exec(
-		`INSERT INTO user_sessions (token, user_id, user_agent, ip) VALUES ($1, $2, $3, $4)`,
+		`INSERT INTO user_sessions (token, user_id, user_agent, ip, last_used_at) VALUES ($1, $2, $3, $4, now())`,
 		token,
 		userID,
 		userAgent,
@@ -99,8 +101,9 @@ func (s *Storage) UserSessionByToken(token string) (*model.UserSession, error) {
 			user_id,
 			token,
 			created_at,
+			last_used_at,
 			user_agent,
-			ip 
+			ip
 		FROM
 			user_sessions
 		WHERE
@@ -111,6 +114,7 @@ func (s *Storage) UserSessionByToken(token string) (*model.UserSession, error) {
 		&session.UserID,
 		&session.Token,
 		&session.CreatedAt,
+		&session.LastUsedAt,
 		&session.UserAgent,
 		&session.IP,
 	)
@@ -125,6 +129,16 @@ func (s *Storage) UserSessionByToken(token string) (*model.UserSession, error) {
 	}
 }
 
+// SetUserSessionUsedTimestamp updates the last used date of a user session.
+func (s *Storage) SetUserSessionUsedTimestamp(sessionID int64) error {
+	query := `UPDATE user_sessions SET last_used_at=now() WHERE id=$1`
+	if _, err := s.db.Exec(query, sessionID); err != nil {
+		return fmt.Errorf(`store: unable to update last used date for user session: %v`, err)
+	}
+
+	return nil
+}
+
 // RemoveUserSessionByToken remove a session by using the token.
 func (s *Storage) RemoveUserSessionByToken(userID int64, token string) error {
 	query := `DELETE FROM user_sessions WHERE user_id=$1 AND token=$2`
@@ -165,15 +179,33 @@ func (s *Storage) RemoveUserSessionByID(userID, sessionID int64) error {
 	return nil
 }
 
+// UserSessionIDExists checks if the session belongs to the given user.
+func (s *Storage) UserSessionIDExists(userID, sessionID int64) bool {
+	var result bool
+	query := `SELECT true FROM user_sessions WHERE user_id=$1 AND id=$2`
+	s.db.QueryRow(query, userID, sessionID).Scan(&result)
+	return result
+}
+
+// RemoveUserSessions removes all sessions of the given user.
+func (s *Storage) RemoveUserSessions(userID int64) error {
+	query := `DELETE FROM user_sessions WHERE user_id=$1`
+	if _, err := s.db.Exec(query, userID); err != nil {
+		return fmt.Errorf(`store: unable to remove user sessions: %v`, err)
+	}
+
+	return nil
+}
+
 // CleanOldUserSessions removes user sessions older than specified days.
 func (s *Storage) CleanOldUserSessions(days int) int64 {
 	query := `
 		DELETE FROM
 			user_sessions
 		WHERE
-			id IN (SELECT id FROM user_sessions WHERE created_at < now() - interval '%d days')
+			id IN (SELECT id FROM user_sessions WHERE created_at < %s)
 	`
-	result, err := s.db.Exec(fmt.Sprintf(query, days))
+	result, err := s.db.Exec(fmt.Sprintf(query, s.daysAgo(days)))
 	if err != nil {
 		return 0
 	}
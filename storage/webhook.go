@@ -0,0 +1,520 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package storage // import "miniflux.app/storage"
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"miniflux.app/logger"
+	"miniflux.app/model"
+)
+
+// webhookEventMaxEntries is the maximum number of entries sent in a single webhook delivery.
+const webhookEventMaxEntries = 100
+
+// WebhookIDExists checks if the webhook belongs to the given user.
+func (s *Storage) WebhookIDExists(userID, webhookID int64) bool {
+	var result bool
+	query := `SELECT true FROM webhooks WHERE user_id=$1 AND id=$2`
+	s.db.QueryRow(query, userID, webhookID).Scan(&result)
+	return result
+}
+
+// Webhooks returns all webhooks that belongs to the given user.
+func (s *Storage) Webhooks(userID int64) (model.Webhooks, error) {
+	query := `
+		SELECT
+			id, user_id, url, secret, events, created_at
+		FROM
+			webhooks
+		WHERE
+			user_id=$1
+		ORDER BY id ASC
+	`
+	rows, err := s.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf(`store: unable to fetch webhooks: %v`, err)
+	}
+	defer rows.Close()
+
+	webhooks := make(model.Webhooks, 0)
+	for rows.Next() {
+		var webhook model.Webhook
+		if err := rows.Scan(
+			&webhook.ID,
+			&webhook.UserID,
+			&webhook.URL,
+			&webhook.Secret,
+			s.scanArray(&webhook.Events),
+			&webhook.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf(`store: unable to fetch webhook row: %v`, err)
+		}
+
+		webhooks = append(webhooks, &webhook)
+	}
+
+	return webhooks, nil
+}
+
+// CreateWebhook inserts a new webhook.
+func (s *Storage) CreateWebhook(webhook *model.Webhook) error {
+	query := `
+		INSERT INTO webhooks
+			(user_id, url, secret, events)
+		VALUES
+			($1, $2, $3, $4)
+		RETURNING
+			id, created_at
+	`
+	err := s.db.QueryRow(
+		query,
+		webhook.UserID,
+		webhook.URL,
+		webhook.Secret,
+		s.array(webhook.Events),
+	).Scan(
+		&webhook.ID,
+		&webhook.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf(`store: unable to create webhook: %v`, err)
+	}
+
+	return nil
+}
+
+// RemoveWebhook deletes a webhook and its deliveries.
+func (s *Storage) RemoveWebhook(userID, webhookID int64) error {
+	query := `DELETE FROM webhooks WHERE id = $1 AND user_id = $2`
+	_, err := s.db.Exec(query, webhookID, userID)
+	if err != nil {
+		return fmt.Errorf(`store: unable to remove this webhook: %v`, err)
+	}
+
+	return nil
+}
+
+// WebhookDeliveries returns the last deliveries of a webhook.
+func (s *Storage) WebhookDeliveries(userID, webhookID int64, limit int) (model.WebhookDeliveries, error) {
+	query := `
+		SELECT
+			d.id,
+			d.webhook_id,
+			d.event,
+			d.status,
+			d.attempts,
+			d.response_status,
+			d.error_msg,
+			d.next_attempt_at,
+			d.last_attempt_at,
+			d.created_at
+		FROM
+			webhook_deliveries d
+		JOIN
+			webhooks w ON w.id=d.webhook_id
+		WHERE
+			w.user_id=$1 AND d.webhook_id=$2
+		ORDER BY d.id DESC
+		LIMIT $3
+	`
+	rows, err := s.db.Query(query, userID, webhookID, limit)
+	if err != nil {
+		return nil, fmt.Errorf(`store: unable to fetch webhook deliveries: %v`, err)
+	}
+	defer rows.Close()
+
+	deliveries := make(model.WebhookDeliveries, 0)
+	for rows.Next() {
+		var delivery model.WebhookDelivery
+		if err := rows.Scan(
+			&delivery.ID,
+			&delivery.WebhookID,
+			&delivery.Event,
+			&delivery.Status,
+			&delivery.Attempts,
+			&delivery.ResponseStatus,
+			&delivery.ErrorMsg,
+			&delivery.NextAttemptAt,
+			&delivery.LastAttemptAt,
+			&delivery.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf(`store: unable to fetch webhook delivery row: %v`, err)
+		}
+
+		deliveries = append(deliveries, &delivery)
+	}
+
+	return deliveries, nil
+}
+
+// ClaimWebhookDeliveries returns the deliveries that are due, with their webhook.
+// The deliveries are postponed for the claim duration to not be sent by another instance in the meantime,
+// they are sent again after that if the result of the attempt is never saved.
+func (s *Storage) ClaimWebhookDeliveries(limit int, claimDuration time.Duration) (model.WebhookDeliveries, error) {
+	lockClause := ""
+	if !s.isSQLite() {
+		lockClause = "FOR UPDATE SKIP LOCKED"
+	}
+
+	query := `
+		UPDATE
+			webhook_deliveries
+		SET
+			next_attempt_at=$1
+		WHERE
+			id IN (
+				SELECT
+					id
+				FROM
+					webhook_deliveries
+				WHERE
+					status=$2 AND next_attempt_at <= now()
+				ORDER BY id ASC
+				LIMIT $3
+				` + lockClause + `
+			)
+		RETURNING
+			id
+	`
+	rows, err := s.db.Query(query, time.Now().Add(claimDuration), model.WebhookDeliveryStatusPending, limit)
+	if err != nil {
+		return nil, fmt.Errorf(`store: unable to claim pending webhook deliveries: %v`, err)
+	}
+	defer rows.Close()
+
+	var deliveryIDs []int64
+	for rows.Next() {
+		var deliveryID int64
+		if err := rows.Scan(&deliveryID); err != nil {
+			return nil, fmt.Errorf(`store: unable to claim pending webhook delivery: %v`, err)
+		}
+		deliveryIDs = append(deliveryIDs, deliveryID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf(`store: unable to claim pending webhook deliveries: %v`, err)
+	}
+
+	if len(deliveryIDs) == 0 {
+		return model.WebhookDeliveries{}, nil
+	}
+
+	return s.webhookDeliveriesByIDs(deliveryIDs)
+}
+
+// webhookDeliveriesByIDs returns the given deliveries with their webhook.
+func (s *Storage) webhookDeliveriesByIDs(deliveryIDs []int64) (model.WebhookDeliveries, error) {
+	query := `
+		SELECT
+			d.id,
+			d.webhook_id,
+			d.event,
+			d.payload,
+			d.status,
+			d.attempts,
+			d.next_attempt_at,
+			d.created_at,
+			w.user_id,
+			w.url,
+			w.secret
+		FROM
+			webhook_deliveries d
+		JOIN
+			webhooks w ON w.id=d.webhook_id
+		WHERE
+			` + s.inArray("d.id", 1) + `
+		ORDER BY d.id ASC
+	`
+	rows, err := s.db.Query(query, s.array(deliveryIDs))
+	if err != nil {
+		return nil, fmt.Errorf(`store: unable to fetch webhook deliveries: %v`, err)
+	}
+	defer rows.Close()
+
+	deliveries := make(model.WebhookDeliveries, 0)
+	for rows.Next() {
+		var delivery model.WebhookDelivery
+		delivery.Webhook = &model.Webhook{}
+
+		if err := rows.Scan(
+			&delivery.ID,
+			&delivery.WebhookID,
+			&delivery.Event,
+			&delivery.Payload,
+			&delivery.Status,
+			&delivery.Attempts,
+			&delivery.NextAttemptAt,
+			&delivery.CreatedAt,
+			&delivery.Webhook.UserID,
+			&delivery.Webhook.URL,
+			&delivery.Webhook.Secret,
+		); err != nil {
+			return nil, fmt.Errorf(`store: unable to fetch webhook delivery row: %v`, err)
+		}
+
+		delivery.Webhook.ID = delivery.WebhookID
+		deliveries = append(deliveries, &delivery)
+	}
+
+	return deliveries, nil
+}
+
+// UpdateWebhookDelivery saves the result of a delivery attempt.
+func (s *Storage) UpdateWebhookDelivery(delivery *model.WebhookDelivery) error {
+	query := `
+		UPDATE
+			webhook_deliveries
+		SET
+			status=$1,
+			attempts=$2,
+			response_status=$3,
+			error_msg=$4,
+			next_attempt_at=$5,
+			last_attempt_at=$6
+		WHERE
+			id=$7
+	`
+	_, err := s.db.Exec(query,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.ResponseStatus,
+		delivery.ErrorMsg,
+		delivery.NextAttemptAt,
+		delivery.LastAttemptAt,
+		delivery.ID,
+	)
+	if err != nil {
+		return fmt.Errorf(`store: unable to update webhook delivery #%d: %v`, delivery.ID, err)
+	}
+
+	return nil
+}
+
+// CleanOldWebhookDeliveries removes the deliveries that are no longer pending and older than the given number of days.
+func (s *Storage) CleanOldWebhookDeliveries(days int) (int64, error) {
+	query := fmt.Sprintf(`DELETE FROM webhook_deliveries WHERE status <> $1 AND created_at < %s`, s.daysAgo(days))
+	result, err := s.db.Exec(query, model.WebhookDeliveryStatusPending)
+	if err != nil {
+		return 0, fmt.Errorf(`store: unable to clean old webhook deliveries: %v`, err)
+	}
+
+	count, _ := result.RowsAffected()
+	return count, nil
+}
+
+// webhookIDs returns the webhooks of the user subscribed to the given event.
+func (s *Storage) webhookIDs(userID int64, event string) ([]int64, error) {
+	query := `SELECT id FROM webhooks WHERE user_id=$1 AND ` + s.arrayContains("events", 2)
+	rows, err := s.db.Query(query, userID, event)
+	if err != nil {
+		return nil, fmt.Errorf(`store: unable to fetch webhooks for event %q: %v`, event, err)
+	}
+	defer rows.Close()
+
+	var webhookIDs []int64
+	for rows.Next() {
+		var webhookID int64
+		if err := rows.Scan(&webhookID); err != nil {
+			return nil, fmt.Errorf(`store: unable to fetch webhook row: %v`, err)
+		}
+		webhookIDs = append(webhookIDs, webhookID)
+	}
+
+	return webhookIDs, nil
+}
+
+// enqueueWebhookEvent queues a delivery of the event for each of the given webhooks.
+func (s *Storage) enqueueWebhookEvent(userID int64, webhookIDs []int64, event *model.WebhookEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf(`store: unable to serialize webhook event %q: %v`, event.EventType, err)
+	}
+
+	query := `INSERT INTO webhook_deliveries (webhook_id, event, payload) VALUES ($1, $2, $3)`
+	for _, webhookID := range webhookIDs {
+		if _, err := s.db.Exec(query, webhookID, event.EventType, string(payload)); err != nil {
+			return fmt.Errorf(`store: unable to queue webhook event %q for user #%d: %v`, event.EventType, userID, err)
+		}
+	}
+
+	return nil
+}
+
+// enqueueEntriesWebhookEvent queues the event for the given entries, in batches of webhookEventMaxEntries.
+func (s *Storage) enqueueEntriesWebhookEvent(userID int64, webhookIDs []int64, eventType string, feed *model.WebhookFeed, entries []*model.WebhookEntry) error {
+	for start := 0; start < len(entries); start += webhookEventMaxEntries {
+		end := start + webhookEventMaxEntries
+		if end > len(entries) {
+			end = len(entries)
+		}
+
+		event := &model.WebhookEvent{
+			EventType: eventType,
+			CreatedAt: time.Now(),
+			Feed:      feed,
+			Entries:   entries[start:end],
+		}
+
+		if err := s.enqueueWebhookEvent(userID, webhookIDs, event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fireEntriesWebhookEvent queues the event for the given entry IDs.
+// Errors are logged because webhooks must not prevent the status of entries to be changed.
+func (s *Storage) fireEntriesWebhookEvent(userID int64, eventType string, entryIDs []int64) {
+	if len(entryIDs) == 0 {
+		return
+	}
+
+	webhookIDs, err := s.webhookIDs(userID, eventType)
+	if err != nil {
+		logger.Error(`[Storage:Webhook] %v`, err)
+		return
+	}
+
+	if len(webhookIDs) == 0 {
+		return
+	}
+
+	entries, err := s.webhookEntries(userID, entryIDs)
+	if err != nil {
+		logger.Error(`[Storage:Webhook] %v`, err)
+		return
+	}
+
+	if err := s.enqueueEntriesWebhookEvent(userID, webhookIDs, eventType, nil, entries); err != nil {
+		logger.Error(`[Storage:Webhook] %v`, err)
+	}
+}
+
+// fireNewEntriesWebhookEvent queues the event for the entries created during a feed refresh.
+func (s *Storage) fireNewEntriesWebhookEvent(userID, feedID int64, newEntries model.Entries) {
+	if len(newEntries) == 0 {
+		return
+	}
+
+	webhookIDs, err := s.webhookIDs(userID, model.WebhookEventEntryCreated)
+	if err != nil {
+		logger.Error(`[Storage:Webhook] %v`, err)
+		return
+	}
+
+	if len(webhookIDs) == 0 {
+		return
+	}
+
+	var feed model.WebhookFeed
+	query := `SELECT id, user_id, feed_url, site_url, title, checked_at FROM feeds WHERE user_id=$1 AND id=$2`
+	if err := s.db.QueryRow(query, userID, feedID).Scan(
+		&feed.ID,
+		&feed.UserID,
+		&feed.FeedURL,
+		&feed.SiteURL,
+		&feed.Title,
+		&feed.CheckedAt,
+	); err != nil {
+		logger.Error(`[Storage:Webhook] unable to fetch feed #%d: %v`, feedID, err)
+		return
+	}
+
+	entries := make([]*model.WebhookEntry, 0, len(newEntries))
+	for _, entry := range newEntries {
+		entries = append(entries, model.NewWebhookEntry(entry))
+	}
+
+	if err := s.enqueueEntriesWebhookEvent(userID, webhookIDs, model.WebhookEventEntryCreated, &feed, entries); err != nil {
+		logger.Error(`[Storage:Webhook] %v`, err)
+	}
+}
+
+// fireFeedErrorWebhookEvent queues the event for a feed that failed to refresh.
+func (s *Storage) fireFeedErrorWebhookEvent(feed *model.Feed) {
+	webhookIDs, err := s.webhookIDs(feed.UserID, model.WebhookEventFeedError)
+	if err != nil {
+		logger.Error(`[Storage:Webhook] %v`, err)
+		return
+	}
+
+	if len(webhookIDs) == 0 {
+		return
+	}
+
+	event := &model.WebhookEvent{
+		EventType: model.WebhookEventFeedError,
+		CreatedAt: time.Now(),
+		Feed:      model.NewWebhookFeed(feed),
+	}
+
+	if err := s.enqueueWebhookEvent(feed.UserID, webhookIDs, event); err != nil {
+		logger.Error(`[Storage:Webhook] %v`, err)
+	}
+}
+
+// webhookEntries returns the webhook representation of the given entries, without their content.
+func (s *Storage) webhookEntries(userID int64, entryIDs []int64) ([]*model.WebhookEntry, error) {
+	query := `
+		SELECT
+			id,
+			user_id,
+			feed_id,
+			status,
+			hash,
+			title,
+			url,
+			comments_url,
+			published_at,
+			created_at,
+			changed_at,
+			author,
+			starred,
+			reading_time,
+			tags
+		FROM
+			entries
+		WHERE
+			user_id=$1 AND ` + s.inArray("id", 2) + `
+		ORDER BY id ASC
+	`
+	rows, err := s.db.Query(query, userID, s.array(entryIDs))
+	if err != nil {
+		return nil, fmt.Errorf(`store: unable to fetch webhook entries: %v`, err)
+	}
+	defer rows.Close()
+
+	entries := make([]*model.WebhookEntry, 0, len(entryIDs))
+	for rows.Next() {
+		var entry model.WebhookEntry
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.UserID,
+			&entry.FeedID,
+			&entry.Status,
+			&entry.Hash,
+			&entry.Title,
+			&entry.URL,
+			&entry.CommentsURL,
+			&entry.Date,
+			&entry.CreatedAt,
+			&entry.ChangedAt,
+			&entry.Author,
+			&entry.Starred,
+			&entry.ReadingTime,
+			s.scanArray(&entry.Tags),
+		); err != nil {
+			return nil, fmt.Errorf(`store: unable to fetch webhook entry row: %v`, err)
+		}
+
+		entries = append(entries, &entry)
+	}
+
+	return entries, nil
+}
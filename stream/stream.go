@@ -0,0 +1,128 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package stream // import "miniflux.app/stream"
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"miniflux.app/broker"
+	"miniflux.app/logger"
+	"miniflux.app/model"
+	"miniflux.app/storage"
+)
+
+// EventCounters is sent when the stream is opened and after the entries of the user have changed.
+const EventCounters = "counters"
+
+const (
+	keepAliveInterval = 30 * time.Second
+
+	// countersDelay groups the counters updates of consecutive events.
+	countersDelay = time.Second
+
+	// retryDelay is the reconnection delay sent to the client, in milliseconds.
+	retryDelay = 5000
+)
+
+// Counters represents the data of the counters event.
+type Counters struct {
+	model.FeedCounters
+	UnreadCount    int `json:"unread_count"`
+	ErrorFeedCount int `json:"error_feed_count"`
+}
+
+// Serve streams the events of the user until the client disconnects.
+//
+// Only the events published in this process are sent, see the broker package.
+func Serve(w http.ResponseWriter, r *http.Request, store *storage.Storage, userID int64) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	// The stream is open for a long time, the write timeout of the server would close it.
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		logger.Debug("[Stream] Unable to remove the write deadline: %v", err)
+	}
+
+	events, unsubscribe := broker.Subscribe(userID)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", retryDelay)
+	if err := sendCounters(w, store, userID); err != nil {
+		logger.Error("[Stream] %v", err)
+		return
+	}
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	countersTimer := time.NewTimer(countersDelay)
+	countersTimer.Stop()
+	defer countersTimer.Stop()
+	countersPending := false
+
+	for {
+		var err error
+
+		select {
+		case <-r.Context().Done():
+			return
+		case event := <-events:
+			err = send(w, event.Type, event.Data)
+			if event.Type != broker.EventEntriesStarred && !countersPending {
+				countersPending = true
+				countersTimer.Reset(countersDelay)
+			}
+		case <-countersTimer.C:
+			countersPending = false
+			err = sendCounters(w, store, userID)
+		case <-keepAlive.C:
+			_, err = io.WriteString(w, ": keep-alive\n\n")
+		}
+
+		if err != nil {
+			logger.Debug("[Stream] Closing the event stream of user #%d: %v", userID, err)
+			return
+		}
+
+		flusher.Flush()
+	}
+}
+
+func sendCounters(w io.Writer, store *storage.Storage, userID int64) error {
+	feedCounters, err := store.FetchCounters(userID)
+	if err != nil {
+		return err
+	}
+
+	return send(w, EventCounters, &Counters{
+		FeedCounters:   feedCounters,
+		UnreadCount:    store.CountUnreadEntries(userID),
+		ErrorFeedCount: store.CountUserFeedsWithErrors(userID),
+	})
+}
+
+func send(w io.Writer, eventType string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("stream: unable to serialize %q event: %v", eventType, err)
+	}
+
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", eventType, payload)
+	return err
+}
@@ -0,0 +1,127 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+//go:build integration
+// +build integration
+
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	miniflux "miniflux.app/client"
+)
+
+func TestCreateAnnotation(t *testing.T) {
+	client := createClient(t)
+	createFeed(t, client)
+
+	result, err := client.Entries(&miniflux.Filter{Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entryID := result.Entries[0].ID
+	annotation, err := client.CreateAnnotation(entryID, &miniflux.AnnotationRequest{
+		Quote:       "some highlighted text",
+		StartOffset: 10,
+		EndOffset:   31,
+		Note:        "My note about zeppelins",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if annotation.ID == 0 || annotation.EntryID != entryID {
+		t.Fatalf(`Invalid annotation, got %+v`, annotation)
+	}
+
+	entry, err := client.Entry(entryID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entry.Annotations) != 1 || entry.Annotations[0].Note != "My note about zeppelins" {
+		t.Fatalf(`The entry should have one annotation, got %v`, entry.Annotations)
+	}
+
+	annotations, err := client.Annotations("zeppelins")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(annotations) != 1 {
+		t.Fatalf(`The search should return one annotation, got %d`, len(annotations))
+	}
+
+	entries, err := client.Entries(&miniflux.Filter{Search: "zeppelins"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if entries.Total != 1 || entries.Entries[0].ID != entryID {
+		t.Fatalf(`The entry search should include annotations, got %d entries`, entries.Total)
+	}
+
+	markdown, err := client.ExportAnnotations("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(markdown), "> some highlighted text\n\nMy note about zeppelins\n") {
+		t.Fatalf(`Unexpected Markdown export: %q`, markdown)
+	}
+}
+
+func TestCannotCreateEmptyAnnotation(t *testing.T) {
+	client := createClient(t)
+	createFeed(t, client)
+
+	result, err := client.Entries(&miniflux.Filter{Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.CreateAnnotation(result.Entries[0].ID, &miniflux.AnnotationRequest{}); err == nil {
+		t.Fatal(`Empty annotations should not be allowed`)
+	}
+}
+
+func TestUpdateAndDeleteAnnotation(t *testing.T) {
+	client := createClient(t)
+	createFeed(t, client)
+
+	result, err := client.Entries(&miniflux.Filter{Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	annotation, err := client.CreateAnnotation(result.Entries[0].ID, &miniflux.AnnotationRequest{Note: "First version"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	annotation, err = client.UpdateAnnotation(annotation.ID, "Second version")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if annotation.Note != "Second version" {
+		t.Fatalf(`Invalid note, got %q`, annotation.Note)
+	}
+
+	if err := client.DeleteAnnotation(annotation.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	annotations, err := client.EntryAnnotations(result.Entries[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(annotations) != 0 {
+		t.Fatalf(`The annotation should have been removed, got %d annotations`, len(annotations))
+	}
+}
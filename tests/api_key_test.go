@@ -0,0 +1,193 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+//go:build integration
+// +build integration
+
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	miniflux "miniflux.app/client"
+)
+
+func TestCreateAndRevokeAPIKey(t *testing.T) {
+	client := createClient(t)
+
+	apiKey, err := client.CreateAPIKey("Provisioning")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if apiKey.ID == 0 || apiKey.Token == "" || apiKey.Description != "Provisioning" {
+		t.Fatalf(`Invalid API key: %+v`, apiKey)
+	}
+
+	if _, err := miniflux.New(testBaseURL, apiKey.Token).Me(); err != nil {
+		t.Fatalf(`The new API key should be usable: %v`, err)
+	}
+
+	apiKeys, err := client.APIKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(apiKeys) != 1 || apiKeys[0].Token != "" || apiKeys[0].LastUsedAt == nil {
+		t.Fatalf(`Unexpected API keys: %+v`, apiKeys)
+	}
+
+	if _, err := client.CreateAPIKey("Provisioning"); err == nil {
+		t.Fatal(`Duplicate API key descriptions should be rejected`)
+	}
+
+	if _, err := client.CreateAPIKey(""); err == nil {
+		t.Fatal(`Empty API key descriptions should be rejected`)
+	}
+
+	if err := client.DeleteAPIKey(apiKey.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := miniflux.New(testBaseURL, apiKey.Token).Me(); err == nil {
+		t.Fatal(`A revoked API key should not be usable`)
+	}
+
+	if err := client.DeleteAPIKey(apiKey.ID); !errors.Is(err, miniflux.ErrNotFound) {
+		t.Fatalf(`Removing an unknown API key should return a not found error, got %v`, err)
+	}
+}
+
+func TestManageAPIKeysOfAnotherUser(t *testing.T) {
+	client := createClient(t)
+	user, err := client.Me()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	adminClient := miniflux.New(testBaseURL, testAdminUsername, testAdminPassword)
+	apiKey, err := adminClient.CreateUserAPIKey(user.ID, "Offboarding")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if apiKey.UserID != user.ID {
+		t.Fatalf(`The API key should belong to user #%d, got #%d`, user.ID, apiKey.UserID)
+	}
+
+	apiKeys, err := adminClient.UserAPIKeys(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(apiKeys) != 1 || apiKeys[0].ID != apiKey.ID {
+		t.Fatalf(`Unexpected API keys: %+v`, apiKeys)
+	}
+
+	if err := adminClient.DeleteUserAPIKey(user.ID, apiKey.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	admin, err := adminClient.Me()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.UserAPIKeys(admin.ID); !errors.Is(err, miniflux.ErrForbidden) {
+		t.Fatalf(`Standard users should not access the API keys of other users, got %v`, err)
+	}
+}
+
+func TestReadOnlyAPIKey(t *testing.T) {
+	client := createClient(t)
+
+	apiKey, err := client.CreateRestrictedAPIKey(&miniflux.APIKeyCreationRequest{
+		Description: "Dashboard",
+		Scopes:      []string{miniflux.APIKeyScopeReadOnly},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(apiKey.Scopes) != 1 || apiKey.Scopes[0] != miniflux.APIKeyScopeReadOnly {
+		t.Fatalf(`Unexpected API key scopes: %+v`, apiKey)
+	}
+
+	readOnlyClient := miniflux.New(testBaseURL, apiKey.Token)
+	if _, err := readOnlyClient.Me(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := readOnlyClient.Categories(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := readOnlyClient.CreateCategory("Read-only"); !errors.Is(err, miniflux.ErrForbidden) {
+		t.Fatalf(`A read-only key should not create categories, got %v`, err)
+	}
+
+	if _, err := readOnlyClient.APIKeys(); !errors.Is(err, miniflux.ErrForbidden) {
+		t.Fatalf(`A restricted key should not manage API keys, got %v`, err)
+	}
+
+	if _, err := client.CreateRestrictedAPIKey(&miniflux.APIKeyCreationRequest{
+		Description: "Invalid",
+		Scopes:      []string{miniflux.APIKeyScopeReadOnly, miniflux.APIKeyScopeFeedsAdmin},
+	}); err == nil {
+		t.Fatal(`A read-only key should not have other scopes`)
+	}
+
+	if _, err := client.CreateRestrictedAPIKey(&miniflux.APIKeyCreationRequest{
+		Description: "Invalid",
+		Scopes:      []string{"everything"},
+	}); err == nil {
+		t.Fatal(`Unknown scopes should be rejected`)
+	}
+}
+
+func TestAPIKeyRestrictedToCategories(t *testing.T) {
+	client := createClient(t)
+
+	allowedCategory, err := client.CreateCategory("Dashboard")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherCategory, err := client.CreateCategory("Private")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	apiKey, err := client.CreateRestrictedAPIKey(&miniflux.APIKeyCreationRequest{
+		Description: "Dashboard",
+		Scopes:      []string{miniflux.APIKeyScopeFeedsAdmin},
+		CategoryIDs: []int64{allowedCategory.ID},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restrictedClient := miniflux.New(testBaseURL, apiKey.Token)
+	categories, err := restrictedClient.Categories()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(categories) != 1 || categories[0].ID != allowedCategory.ID {
+		t.Fatalf(`Unexpected categories: %+v`, categories)
+	}
+
+	if _, err := restrictedClient.UpdateCategory(allowedCategory.ID, "Wall"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := restrictedClient.UpdateCategory(otherCategory.ID, "Public"); !errors.Is(err, miniflux.ErrForbidden) {
+		t.Fatalf(`Categories that are not allowed should not be updated, got %v`, err)
+	}
+
+	if _, err := restrictedClient.CategoryFeeds(otherCategory.ID); !errors.Is(err, miniflux.ErrForbidden) {
+		t.Fatalf(`Categories that are not allowed should not be visible, got %v`, err)
+	}
+}
@@ -8,6 +8,7 @@
 package tests
 
 import (
+	"errors"
 	"testing"
 
 	miniflux "miniflux.app/client"
@@ -84,11 +85,33 @@ func TestUpdateCategory(t *testing.T) {
 	}
 }
 
+func TestCategoryHiddenGlobally(t *testing.T) {
+	client := createClient(t)
+
+	category, err := client.CreateCategoryWithOptions(&miniflux.CategoryRequest{Title: "Hidden", HideGlobally: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !category.HideGlobally {
+		t.Fatalf(`The category should be hidden globally`)
+	}
+
+	category, err = client.UpdateCategoryWithOptions(category.ID, &miniflux.CategoryRequest{Title: "Visible"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if category.HideGlobally || category.Title != "Visible" {
+		t.Fatalf(`Invalid category, got "%+v"`, category)
+	}
+}
+
 func TestUpdateInexistingCategory(t *testing.T) {
 	client := createClient(t)
 
 	_, err := client.UpdateCategory(4200000, "Test")
-	if err != miniflux.ErrNotFound {
+	if !errors.Is(err, miniflux.ErrNotFound) {
 		t.Errorf(`Updating an inexisting category should returns a 404 instead of %v`, err)
 	}
 }
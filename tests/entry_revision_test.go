@@ -0,0 +1,99 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+//go:build integration
+// +build integration
+
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	miniflux "miniflux.app/client"
+)
+
+func TestGetEntryRevisionsWithoutChanges(t *testing.T) {
+	client := createClient(t)
+	createFeed(t, client)
+
+	result, err := client.Entries(&miniflux.Filter{Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entry := result.Entries[0]
+	if entry.RevisionCount != 0 {
+		t.Fatalf(`A new entry should not have revisions, got %d`, entry.RevisionCount)
+	}
+
+	revisions, err := client.EntryRevisions(entry.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(revisions) != 0 {
+		t.Fatalf(`A new entry should not have revisions, got %d`, len(revisions))
+	}
+
+	if _, err := client.EntryRevisionDiff(entry.ID, 123456789); !errors.Is(err, miniflux.ErrNotFound) {
+		t.Fatalf(`Fetching an unknown revision should return a not found error, got %v`, err)
+	}
+}
+
+func TestGetEntryRevisionsWithInvalidEntry(t *testing.T) {
+	client := createClient(t)
+
+	if _, err := client.EntryRevisions(123456789); !errors.Is(err, miniflux.ErrNotFound) {
+		t.Fatalf(`Fetching revisions of an unknown entry should return a not found error, got %v`, err)
+	}
+}
+
+func TestGetEntryRevisionsWithRemovedEntry(t *testing.T) {
+	client := createClient(t)
+	createFeed(t, client)
+
+	result, err := client.Entries(&miniflux.Filter{Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entry := result.Entries[0]
+	if err := client.UpdateEntries([]int64{entry.ID}, miniflux.EntryStatusRemoved); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.EntryRevisions(entry.ID); !errors.Is(err, miniflux.ErrNotFound) {
+		t.Fatalf(`Fetching revisions of a removed entry should return a not found error, got %v`, err)
+	}
+
+	if _, err := client.EntryRevisionDiff(entry.ID, 1); !errors.Is(err, miniflux.ErrNotFound) {
+		t.Fatalf(`Fetching a revision diff of a removed entry should return a not found error, got %v`, err)
+	}
+}
+
+func TestUpdateFeedMarkUpdatedEntriesUnread(t *testing.T) {
+	client := createClient(t)
+	feed, _ := createFeed(t, client)
+
+	markUpdatedEntriesUnread := true
+	updatedFeed, err := client.UpdateFeed(feed.ID, &miniflux.FeedModificationRequest{MarkUpdatedEntriesUnread: &markUpdatedEntriesUnread})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if updatedFeed.MarkUpdatedEntriesUnread != markUpdatedEntriesUnread {
+		t.Fatalf(`Wrong mark_updated_entries_unread value, got "%v" instead of "%v"`, updatedFeed.MarkUpdatedEntriesUnread, markUpdatedEntriesUnread)
+	}
+
+	markUpdatedEntriesUnread = false
+	updatedFeed, err = client.UpdateFeed(feed.ID, &miniflux.FeedModificationRequest{MarkUpdatedEntriesUnread: &markUpdatedEntriesUnread})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if updatedFeed.MarkUpdatedEntriesUnread != markUpdatedEntriesUnread {
+		t.Fatalf(`Wrong mark_updated_entries_unread value, got "%v" instead of "%v"`, updatedFeed.MarkUpdatedEntriesUnread, markUpdatedEntriesUnread)
+	}
+}
@@ -8,6 +8,7 @@
 package tests
 
 import (
+	"strings"
 	"testing"
 
 	miniflux "miniflux.app/client"
@@ -285,6 +286,10 @@ func TestSearchEntries(t *testing.T) {
 	if results.Total != 1 {
 		t.Fatalf(`We should have only one entry instead of %d`, results.Total)
 	}
+
+	if !strings.Contains(results.Entries[0].Snippet, "<mark>") {
+		t.Fatalf(`The search snippet should highlight matches, got %q`, results.Entries[0].Snippet)
+	}
 }
 
 func TestInvalidFilters(t *testing.T) {
@@ -451,3 +456,46 @@ func TestHistoryOrder(t *testing.T) {
 		t.Fatal("The entry that we just read should be at the top of the history")
 	}
 }
+
+func TestEntriesIterator(t *testing.T) {
+	client := createClient(t)
+	createFeed(t, client)
+
+	result, err := client.Entries(&miniflux.Filter{Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.NextCursor == "" {
+		t.Fatal(`The first page should have a next cursor`)
+	}
+
+	seen := make(map[int64]bool)
+	entries := client.EntriesIterator(&miniflux.Filter{Limit: 2, Direction: "desc"})
+	for entries.Next() {
+		if seen[entries.Entry().ID] {
+			t.Fatalf(`Entry #%d returned twice`, entries.Entry().ID)
+		}
+		seen[entries.Entry().ID] = true
+	}
+
+	if err := entries.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(seen) != result.Total {
+		t.Fatalf(`Invalid number of entries, got %d instead of %d`, len(seen), result.Total)
+	}
+}
+
+func TestEntriesWithInvalidCursor(t *testing.T) {
+	client := createClient(t)
+
+	if _, err := client.Entries(&miniflux.Filter{Cursor: "invalid!"}); err == nil {
+		t.Fatal(`An invalid cursor should be rejected`)
+	}
+
+	if _, err := client.Entries(&miniflux.Filter{Cursor: "MTIzOjQy", Search: "miniflux"}); err == nil {
+		t.Fatal(`A cursor combined with a search query should be rejected`)
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+//go:build integration
+// +build integration
+
+package tests
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	miniflux "miniflux.app/client"
+)
+
+func TestEventStreamSendsCountersOnConnect(t *testing.T) {
+	username := getRandomUsername()
+	admin := miniflux.New(testBaseURL, testAdminUsername, testAdminPassword)
+	if _, err := admin.CreateUser(username, testStandardPassword, false); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, testBaseURL+"v1/events", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	request.SetBasicAuth(username, testStandardPassword)
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf(`Unexpected status code: %d`, response.StatusCode)
+	}
+
+	if contentType := response.Header.Get("Content-Type"); contentType != "text/event-stream" {
+		t.Fatalf(`Unexpected content type: %q`, contentType)
+	}
+
+	var eventType, data string
+	scanner := bufio.NewScanner(response.Body)
+	for scanner.Scan() && data == "" {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			eventType = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+
+	if eventType != "counters" {
+		t.Fatalf(`Unexpected first event: %q`, eventType)
+	}
+
+	var counters struct {
+		Reads       map[string]int `json:"reads"`
+		Unreads     map[string]int `json:"unreads"`
+		UnreadCount *int           `json:"unread_count"`
+	}
+	if err := json.Unmarshal([]byte(data), &counters); err != nil {
+		t.Fatal(err)
+	}
+
+	if counters.UnreadCount == nil || *counters.UnreadCount != 0 {
+		t.Fatalf(`Unexpected counters: %s`, data)
+	}
+}
+
+func TestEventStreamRequiresAuthentication(t *testing.T) {
+	response, err := http.Get(testBaseURL + "v1/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusUnauthorized {
+		t.Fatalf(`Unexpected status code: %d`, response.StatusCode)
+	}
+}
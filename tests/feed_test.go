@@ -775,6 +775,29 @@ func TestGetFeedIcon(t *testing.T) {
 	}
 }
 
+func TestGetIconByID(t *testing.T) {
+	client := createClient(t)
+	feed, _ := createFeed(t, client)
+
+	feed, err := client.Feed(feed.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if feed.Icon == nil || feed.Icon.IconID == 0 {
+		t.Fatalf(`The feed should have an icon: %+v`, feed.Icon)
+	}
+
+	icon, err := client.Icon(feed.Icon.IconID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if icon.ID != feed.Icon.IconID || icon.MimeType != "image/x-icon" {
+		t.Fatalf(`Invalid icon, got "%v"`, icon)
+	}
+}
+
 func TestGetFeedIconNotFound(t *testing.T) {
 	client := createClient(t)
 	if _, err := client.FeedIcon(42); err == nil {
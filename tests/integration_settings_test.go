@@ -0,0 +1,188 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+//go:build integration
+// +build integration
+
+package tests
+
+import (
+	"crypto/md5"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	miniflux "miniflux.app/client"
+)
+
+func TestGetIntegrations(t *testing.T) {
+	client := createClient(t)
+
+	integration, err := client.Integrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if integration.FeverEnabled || integration.PinboardTags != "miniflux" {
+		t.Fatalf(`Unexpected default integration settings: %+v`, integration)
+	}
+}
+
+func TestUpdateIntegrations(t *testing.T) {
+	client := createClient(t)
+	username := getRandomUsername()
+	enabled := true
+	password := "fever-secret"
+	wallabagURL := "https://wallabag.example.org"
+
+	integration, err := client.UpdateIntegrations(&miniflux.IntegrationModificationRequest{
+		FeverEnabled:  &enabled,
+		FeverUsername: &username,
+		FeverPassword: &password,
+		WallabagURL:   &wallabagURL,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !integration.FeverEnabled || integration.FeverUsername != username || integration.WallabagURL != wallabagURL {
+		t.Fatalf(`Unexpected integration settings: %+v`, integration)
+	}
+
+	// The Fever API accepts the token derived from the new password.
+	apiKey := fmt.Sprintf("%x", md5.Sum([]byte(username+":"+password)))
+	response, err := http.PostForm(testBaseURL+"fever/?api", url.Values{"api_key": {apiKey}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+
+	body, _ := io.ReadAll(response.Body)
+	if !strings.Contains(string(body), `"auth":1`) {
+		t.Fatalf(`The Fever authentication should succeed, got %s`, body)
+	}
+
+	// Fields which are not sent are left untouched.
+	tags := "api"
+	integration, err = client.UpdateIntegrations(&miniflux.IntegrationModificationRequest{PinboardTags: &tags})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !integration.FeverEnabled || integration.PinboardTags != tags {
+		t.Fatalf(`Unexpected integration settings: %+v`, integration)
+	}
+}
+
+func TestUpdateIntegrationsWithInvalidValues(t *testing.T) {
+	client := createClient(t)
+	username := getRandomUsername()
+
+	if _, err := client.UpdateIntegrations(&miniflux.IntegrationModificationRequest{FeverUsername: &username}); err != nil {
+		t.Fatal(err)
+	}
+
+	otherClient := createClient(t)
+	if _, err := otherClient.UpdateIntegrations(&miniflux.IntegrationModificationRequest{FeverUsername: &username}); err == nil {
+		t.Fatal(`Duplicate Fever usernames should be rejected`)
+	}
+
+	invalidURL := "not a url"
+	if _, err := client.UpdateIntegrations(&miniflux.IntegrationModificationRequest{LinkdingURL: &invalidURL}); err == nil {
+		t.Fatal(`Invalid URLs should be rejected`)
+	}
+}
+
+func TestUpdateIntegrationsKeepsGoogleReaderPassword(t *testing.T) {
+	client := createClient(t)
+	username := getRandomUsername()
+	enabled := true
+	password := "googlereader-secret"
+
+	if _, err := client.UpdateIntegrations(&miniflux.IntegrationModificationRequest{
+		GoogleReaderEnabled:  &enabled,
+		GoogleReaderUsername: &username,
+		GoogleReaderPassword: &password,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	login := func() int {
+		response, err := http.PostForm(testBaseURL+"accounts/ClientLogin", url.Values{"Email": {username}, "Passwd": {password}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer response.Body.Close()
+		return response.StatusCode
+	}
+
+	if status := login(); status != http.StatusOK {
+		t.Fatalf(`The Google Reader login should succeed, got %d`, status)
+	}
+
+	tags := "x"
+	if _, err := client.UpdateIntegrations(&miniflux.IntegrationModificationRequest{PinboardTags: &tags}); err != nil {
+		t.Fatal(err)
+	}
+
+	if status := login(); status != http.StatusOK {
+		t.Fatalf(`The Google Reader password should be kept after a partial update, got %d`, status)
+	}
+
+	disabled := false
+	if _, err := client.UpdateIntegrations(&miniflux.IntegrationModificationRequest{GoogleReaderEnabled: &disabled}); err != nil {
+		t.Fatal(err)
+	}
+
+	if status := login(); status == http.StatusOK {
+		t.Fatal(`The Google Reader login should fail when the API is disabled`)
+	}
+
+	if _, err := client.UpdateIntegrations(&miniflux.IntegrationModificationRequest{GoogleReaderEnabled: &enabled}); err != nil {
+		t.Fatal(err)
+	}
+
+	if status := login(); status != http.StatusOK {
+		t.Fatalf(`The Google Reader password should be kept when the API is disabled, got %d`, status)
+	}
+}
+
+func TestUpdateFeverUsernameRequiresPassword(t *testing.T) {
+	client := createClient(t)
+	username := getRandomUsername()
+	enabled := true
+	password := "fever-secret"
+
+	if _, err := client.UpdateIntegrations(&miniflux.IntegrationModificationRequest{
+		FeverEnabled:  &enabled,
+		FeverUsername: &username,
+		FeverPassword: &password,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	newUsername := getRandomUsername()
+	if _, err := client.UpdateIntegrations(&miniflux.IntegrationModificationRequest{FeverUsername: &newUsername}); err == nil {
+		t.Fatal(`The Fever username should not change without the password`)
+	}
+
+	if _, err := client.UpdateIntegrations(&miniflux.IntegrationModificationRequest{FeverUsername: &newUsername, FeverPassword: &password}); err != nil {
+		t.Fatal(err)
+	}
+
+	apiKey := fmt.Sprintf("%x", md5.Sum([]byte(newUsername+":"+password)))
+	response, err := http.PostForm(testBaseURL+"fever/?api", url.Values{"api_key": {apiKey}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+
+	body, _ := io.ReadAll(response.Body)
+	if !strings.Contains(string(body), `"auth":1`) {
+		t.Fatalf(`The Fever token should be computed with the new username, got %s`, body)
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+//go:build integration
+// +build integration
+
+package tests
+
+import (
+	"testing"
+
+	miniflux "miniflux.app/client"
+)
+
+func TestCreateLabel(t *testing.T) {
+	labelName := "My label"
+	client := createClient(t)
+	label, err := client.CreateLabel(labelName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if label.ID == 0 {
+		t.Fatalf(`Invalid labelID, got "%v"`, label.ID)
+	}
+
+	if label.Title != labelName {
+		t.Fatalf(`Invalid title, got "%v" instead of "%v"`, label.Title, labelName)
+	}
+}
+
+func TestCannotCreateDuplicatedLabel(t *testing.T) {
+	client := createClient(t)
+
+	if _, err := client.CreateLabel("My label"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.CreateLabel("my LABEL"); err == nil {
+		t.Fatal(`Duplicated labels should not be allowed`)
+	}
+}
+
+func TestUpdateAndDeleteLabel(t *testing.T) {
+	client := createClient(t)
+	label, err := client.CreateLabel("My label")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	label, err = client.UpdateLabel(label.ID, "Updated label")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if label.Title != "Updated label" {
+		t.Fatalf(`Invalid title, got %q`, label.Title)
+	}
+
+	if err := client.DeleteLabel(label.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	labels, err := client.Labels()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(labels) != 0 {
+		t.Fatalf(`Invalid number of labels, got %d`, len(labels))
+	}
+}
+
+func TestUpdateEntryLabels(t *testing.T) {
+	client := createClient(t)
+	createFeed(t, client)
+
+	result, err := client.Entries(&miniflux.Filter{Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entryID := result.Entries[0].ID
+	if err := client.UpdateEntryLabels(entryID, []string{"To read", "Go", " go "}); err != nil {
+		t.Fatal(err)
+	}
+
+	entry, err := client.Entry(entryID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entry.Labels) != 2 || entry.Labels[0] != "Go" || entry.Labels[1] != "To read" {
+		t.Fatalf(`Invalid entry labels, got %v`, entry.Labels)
+	}
+
+	labels, err := client.Labels()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(labels) != 2 {
+		t.Fatalf(`Invalid number of labels, got %d`, len(labels))
+	}
+
+	labelEntries, err := client.LabelEntries(labels[0].ID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if labelEntries.Total != 1 || labelEntries.Entries[0].ID != entryID {
+		t.Fatalf(`Invalid label entries, got %d entries`, labelEntries.Total)
+	}
+
+	if err := client.UpdateEntryLabels(entryID, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	filteredEntries, err := client.Entries(&miniflux.Filter{LabelID: labels[0].ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if filteredEntries.Total != 0 {
+		t.Fatalf(`Labels should have been removed, got %d entries`, filteredEntries.Total)
+	}
+}
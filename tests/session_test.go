@@ -0,0 +1,66 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+//go:build integration
+// +build integration
+
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	miniflux "miniflux.app/client"
+)
+
+func TestGetSessions(t *testing.T) {
+	client := createClient(t)
+
+	sessions, err := client.Sessions()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sessions) != 0 {
+		t.Fatalf(`API calls should not create web sessions: %+v`, sessions)
+	}
+
+	if err := client.DeleteSessions(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.DeleteSession(123456789); !errors.Is(err, miniflux.ErrNotFound) {
+		t.Fatalf(`Removing an unknown session should return a not found error, got %v`, err)
+	}
+}
+
+func TestManageSessionsOfAnotherUser(t *testing.T) {
+	client := createClient(t)
+	user, err := client.Me()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	adminClient := miniflux.New(testBaseURL, testAdminUsername, testAdminPassword)
+	if _, err := adminClient.UserSessions(user.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := adminClient.DeleteUserSessions(user.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := adminClient.UserSessions(123456789); !errors.Is(err, miniflux.ErrNotFound) {
+		t.Fatalf(`Unknown users should return a not found error, got %v`, err)
+	}
+
+	admin, err := adminClient.Me()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.UserSessions(admin.ID); !errors.Is(err, miniflux.ErrForbidden) {
+		t.Fatalf(`Standard users should not access the sessions of other users, got %v`, err)
+	}
+}
@@ -8,6 +8,7 @@
 package tests
 
 import (
+	"errors"
 	"testing"
 
 	miniflux "miniflux.app/client"
@@ -48,7 +49,7 @@ func TestDiscoverSubscriptionsWithInvalidURL(t *testing.T) {
 func TestDiscoverSubscriptionsWithNoSubscription(t *testing.T) {
 	client := createClient(t)
 	_, err := client.Discover(testBaseURL)
-	if err != miniflux.ErrNotFound {
+	if !errors.Is(err, miniflux.ErrNotFound) {
 		t.Fatal(`A 404 should be returned when there is no subscription`)
 	}
 }
@@ -8,6 +8,7 @@
 package tests
 
 import (
+	"errors"
 	"testing"
 
 	miniflux "miniflux.app/client"
@@ -20,7 +21,7 @@ func TestWithWrongCredentials(t *testing.T) {
 		t.Fatal(`Using bad credentials should raise an error`)
 	}
 
-	if err != miniflux.ErrNotAuthorized {
+	if !errors.Is(err, miniflux.ErrNotAuthorized) {
 		t.Fatal(`A "Not Authorized" error should be raised`)
 	}
 }
@@ -538,7 +539,7 @@ func TestCannotListUsersAsNonAdmin(t *testing.T) {
 		t.Fatal(`Standard users should not be able to list any users`)
 	}
 
-	if err != miniflux.ErrForbidden {
+	if !errors.Is(err, miniflux.ErrForbidden) {
 		t.Fatal(`A "Forbidden" error should be raised`)
 	}
 }
@@ -557,7 +558,7 @@ func TestCannotGetUserAsNonAdmin(t *testing.T) {
 		t.Fatal(`Standard users should not be able to get any users`)
 	}
 
-	if err != miniflux.ErrForbidden {
+	if !errors.Is(err, miniflux.ErrForbidden) {
 		t.Fatal(`A "Forbidden" error should be raised`)
 	}
 }
@@ -600,7 +601,7 @@ func TestCannotUpdateUserAsNonAdmin(t *testing.T) {
 		t.Fatal(`Standard users should not be able to update other users`)
 	}
 
-	if err != miniflux.ErrForbidden {
+	if !errors.Is(err, miniflux.ErrForbidden) {
 		t.Fatal(`A "Forbidden" error should be raised`)
 	}
 
@@ -629,7 +630,7 @@ func TestCannotCreateUserAsNonAdmin(t *testing.T) {
 		t.Fatal(`Standard users should not be able to create users`)
 	}
 
-	if err != miniflux.ErrForbidden {
+	if !errors.Is(err, miniflux.ErrForbidden) {
 		t.Fatal(`A "Forbidden" error should be raised`)
 	}
 }
@@ -648,7 +649,7 @@ func TestCannotDeleteUserAsNonAdmin(t *testing.T) {
 		t.Fatal(`Standard users should not be able to remove any users`)
 	}
 
-	if err != miniflux.ErrForbidden {
+	if !errors.Is(err, miniflux.ErrForbidden) {
 		t.Fatal(`A "Forbidden" error should be raised`)
 	}
 }
@@ -710,7 +711,7 @@ func TestCannotMarkUserAsReadAsOtherUser(t *testing.T) {
 	if err == nil {
 		t.Fatalf(`Non-admin users should not be able to mark another user as read`)
 	}
-	if err != miniflux.ErrForbidden {
+	if !errors.Is(err, miniflux.ErrForbidden) {
 		t.Errorf(`A "Forbidden" error should be raised, got %q`, err)
 	}
 }
@@ -0,0 +1,647 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package ttrss // import "miniflux.app/ttrss"
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"miniflux.app/http/request"
+	"miniflux.app/http/response/json"
+	"miniflux.app/logger"
+	"miniflux.app/model"
+	"miniflux.app/proxy"
+	"miniflux.app/reader/sanitizer"
+	"miniflux.app/storage"
+	"miniflux.app/version"
+
+	"github.com/gorilla/mux"
+)
+
+// Clients enable some features according to the API level, level 15 adds the modes of catchupFeed.
+const apiLevel = 15
+
+// Virtual feeds, they are used as feed IDs when is_cat is false.
+const (
+	feedArchived  = 0
+	feedStarred   = -1
+	feedPublished = -2
+	feedFresh     = -3
+	feedAll       = -4
+)
+
+// Special categories, they are used as category IDs by getFeeds and getCategories.
+const (
+	categoryUncategorized  = 0
+	categorySpecial        = -1
+	categoryAllFeeds       = -3
+	categoryAllWithSpecial = -4
+)
+
+const (
+	defaultHeadlinesLimit = 60
+	maxHeadlinesLimit     = 200
+	excerptLength         = 100
+	freshArticleMaxAge    = 24 * time.Hour
+)
+
+type handler struct {
+	store  *storage.Storage
+	router *mux.Router
+}
+
+// Serve handles Tiny Tiny RSS API calls.
+// Clients are configured with the URL of the installation and append /api/ to it.
+func Serve(router *mux.Router, store *storage.Storage) {
+	handler := &handler{store, router}
+
+	sr := router.PathPrefix("/tt-rss/api").Subrouter()
+	sr.HandleFunc("/", handler.serve).Methods(http.MethodPost).Name("ttrssEndpoint")
+	sr.HandleFunc("", handler.serve).Methods(http.MethodPost)
+}
+
+// serve dispatches the calls according to the "op" parameter, the names of the operations are case-insensitive.
+func (h *handler) serve(w http.ResponseWriter, r *http.Request) {
+	clientIP := request.ClientIP(r)
+
+	params, err := decodeRequest(r.Body)
+	if err != nil {
+		logger.Error("[TTRSS] [ClientIP=%s] Unable to decode the request: %v", clientIP, err)
+		sendError(w, r, apiRequest{}, errorIncorrectUsage)
+		return
+	}
+
+	op := strings.ToLower(params.stringParam("op"))
+	switch op {
+	case "login":
+		h.login(w, r, params)
+		return
+	case "isloggedin":
+		h.isLoggedIn(w, r, params)
+		return
+	}
+
+	authenticatedRequest, err := authenticate(h.store, r, params.stringParam("sid"))
+	if err != nil {
+		logger.Info("[TTRSS] [ClientIP=%s] %v", clientIP, err)
+		sendError(w, r, params, errorNotLoggedIn)
+		return
+	}
+	r = authenticatedRequest
+
+	switch op {
+	case "logout":
+		h.logout(w, r, params)
+	case "getapilevel":
+		sendContent(w, r, params, levelContent{Level: apiLevel})
+	case "getversion":
+		sendContent(w, r, params, versionContent{Version: version.Version})
+	case "getconfig":
+		sendContent(w, r, params, h.config(request.UserID(r)))
+	case "getunread":
+		sendContent(w, r, params, unreadContent{Unread: strconv.Itoa(h.store.CountUnreadEntries(request.UserID(r)))})
+	case "getcounters":
+		h.getCounters(w, r, params)
+	case "getcategories":
+		h.getCategories(w, r, params)
+	case "getfeeds":
+		h.getFeeds(w, r, params)
+	case "getheadlines":
+		h.getHeadlines(w, r, params)
+	case "getarticle":
+		h.getArticle(w, r, params)
+	case "updatearticle":
+		h.updateArticle(w, r, params)
+	case "catchupfeed":
+		h.catchupFeed(w, r, params)
+	default:
+		logger.Info("[TTRSS] [ClientIP=%s] Unsupported operation: %q", clientIP, op)
+		sendError(w, r, params, errorUnknownMethod)
+	}
+}
+
+func (h *handler) login(w http.ResponseWriter, r *http.Request, params apiRequest) {
+	clientIP := request.ClientIP(r)
+	username := params.stringParam("user")
+
+	userID, err := h.store.TTRSSUserCheckPassword(username, params.stringParam("password"))
+	if err != nil {
+		logger.Error("[TTRSS] [ClientIP=%s] %v", clientIP, err)
+		sendError(w, r, params, errorLogin)
+		return
+	}
+
+	_, key, err := h.store.TTRSSUserSessionKey(username)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	logger.Info("[TTRSS] [ClientIP=%s] User #%d is logged in with user agent %q", clientIP, userID, r.UserAgent())
+	h.store.SetLastLogin(userID)
+
+	sendContent(w, r, params, loginContent{
+		SessionID: newSessionID(username, key, sessionExpirationDate()),
+		APILevel:  apiLevel,
+		Config:    h.config(userID),
+	})
+}
+
+// logout closes all the sessions of the user, the session IDs are not stored to be revoked one by one.
+func (h *handler) logout(w http.ResponseWriter, r *http.Request, params apiRequest) {
+	if err := h.store.RevokeTTRSSSessions(request.UserID(r)); err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	sendContent(w, r, params, statusContent{Status: "OK"})
+}
+
+func (h *handler) isLoggedIn(w http.ResponseWriter, r *http.Request, params apiRequest) {
+	_, err := authenticate(h.store, r, params.stringParam("sid"))
+	sendContent(w, r, params, loggedInContent{Status: err == nil})
+}
+
+// config returns the settings of the installation, the icons of the feeds are not available through this API.
+func (h *handler) config(userID int64) configContent {
+	return configContent{
+		IconsDir:        "feed-icons",
+		IconsURL:        "feed-icons",
+		DaemonIsRunning: true,
+		NumFeeds:        h.store.CountFeeds(userID),
+	}
+}
+
+func (h *handler) getCounters(w http.ResponseWriter, r *http.Request, params apiRequest) {
+	userID := request.UserID(r)
+
+	outputMode := params.stringParam("output_mode")
+	if outputMode == "" {
+		outputMode = "flc"
+	}
+
+	feeds, err := h.store.FeedsWithCounters(userID)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	virtualFeeds, err := h.virtualFeeds(userID)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	unread := h.store.CountUnreadEntries(userID)
+	counters := []counter{
+		{ID: "global-unread", Counter: unread},
+		{ID: "subscribed-feeds", Counter: len(feeds)},
+	}
+
+	for _, virtualFeed := range virtualFeeds {
+		counters = append(counters, counter{ID: virtualFeed.ID, Counter: virtualFeed.Unread})
+	}
+
+	if strings.Contains(outputMode, "f") {
+		for _, f := range feeds {
+			counters = append(counters, counter{ID: f.ID, Counter: f.UnreadCount})
+		}
+	}
+
+	if strings.Contains(outputMode, "c") {
+		categories, err := h.store.CategoriesWithFeedCount(userID)
+		if err != nil {
+			json.ServerError(w, r, err)
+			return
+		}
+
+		for _, c := range categories {
+			counters = append(counters, counter{ID: c.ID, Counter: c.TotalUnread, Kind: "cat"})
+		}
+	}
+
+	sendContent(w, r, params, counters)
+}
+
+func (h *handler) getCategories(w http.ResponseWriter, r *http.Request, params apiRequest) {
+	userID := request.UserID(r)
+	unreadOnly := params.boolParam("unread_only")
+	includeEmpty := params.boolParam("include_empty")
+
+	categories, err := h.store.CategoriesWithFeedCount(userID)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	result := make([]category, 0, len(categories)+1)
+	if unread := h.store.CountUnreadEntries(userID); !unreadOnly || unread > 0 {
+		result = append(result, category{ID: categorySpecial, Title: "Special", Unread: unread})
+	}
+
+	for i, c := range categories {
+		if unreadOnly && c.TotalUnread == 0 {
+			continue
+		}
+
+		if !includeEmpty && c.FeedCount == 0 {
+			continue
+		}
+
+		result = append(result, category{ID: c.ID, Title: c.Title, Unread: c.TotalUnread, OrderID: i})
+	}
+
+	sendContent(w, r, params, result)
+}
+
+func (h *handler) getFeeds(w http.ResponseWriter, r *http.Request, params apiRequest) {
+	userID := request.UserID(r)
+	categoryID := params.intParam("cat_id", categoryUncategorized)
+	unreadOnly := params.boolParam("unread_only")
+
+	var result []feed
+	if categoryID == categorySpecial || categoryID == categoryAllWithSpecial {
+		virtualFeeds, err := h.virtualFeeds(userID)
+		if err != nil {
+			json.ServerError(w, r, err)
+			return
+		}
+		result = append(result, virtualFeeds...)
+	}
+
+	if categoryID > 0 || categoryID == categoryAllFeeds || categoryID == categoryAllWithSpecial {
+		feeds, err := h.store.FeedsWithCounters(userID)
+		if err != nil {
+			json.ServerError(w, r, err)
+			return
+		}
+
+		for i, f := range feeds {
+			if categoryID > 0 && f.Category.ID != categoryID {
+				continue
+			}
+
+			result = append(result, feed{
+				ID:          f.ID,
+				Title:       f.Title,
+				FeedURL:     f.FeedURL,
+				Unread:      f.UnreadCount,
+				CatID:       f.Category.ID,
+				LastUpdated: f.CheckedAt.Unix(),
+				OrderID:     i,
+			})
+		}
+	}
+
+	filtered := make([]feed, 0, len(result))
+	for _, f := range result {
+		if !unreadOnly || f.Unread > 0 {
+			filtered = append(filtered, f)
+		}
+	}
+
+	offset := int(params.intParam("offset", 0))
+	if offset < 0 || offset > len(filtered) {
+		offset = len(filtered)
+	}
+	filtered = filtered[offset:]
+
+	if limit := int(params.intParam("limit", 0)); limit > 0 && limit < len(filtered) {
+		filtered = filtered[:limit]
+	}
+
+	sendContent(w, r, params, filtered)
+}
+
+// virtualFeeds returns the virtual feeds of the special category with their number of unread entries.
+// Published articles have no equivalent and are not listed.
+func (h *handler) virtualFeeds(userID int64) ([]feed, error) {
+	virtualFeeds := []feed{
+		{ID: feedAll, Title: "All articles"},
+		{ID: feedFresh, Title: "Fresh articles"},
+		{ID: feedStarred, Title: "Starred articles"},
+	}
+
+	for i := range virtualFeeds {
+		builder := h.store.NewEntryQueryBuilder(userID)
+		builder.WithStatus(model.EntryStatusUnread)
+		withFeed(builder, virtualFeeds[i].ID, false)
+
+		count, err := builder.CountEntries()
+		if err != nil {
+			return nil, err
+		}
+
+		virtualFeeds[i].Unread = count
+		virtualFeeds[i].CatID = categorySpecial
+	}
+	return virtualFeeds, nil
+}
+
+// withFeed filters the entries of a feed or a category, it returns false when the feed cannot contain any entry.
+func withFeed(builder *storage.EntryQueryBuilder, feedID int64, isCategory bool) bool {
+	switch {
+	case isCategory && feedID > 0:
+		builder.WithCategoryID(feedID)
+	case isCategory:
+		// Miniflux does not have uncategorized feeds, the other special categories contain all the feeds.
+		return feedID != categoryUncategorized
+	case feedID > 0:
+		builder.WithFeedID(feedID)
+	case feedID == feedStarred:
+		builder.WithStarred(true)
+	case feedID == feedFresh:
+		builder.WithStatus(model.EntryStatusUnread)
+		builder.AfterDate(time.Now().Add(-freshArticleMaxAge))
+	case feedID == feedAll:
+	default:
+		// Archived and published articles, and labels.
+		return false
+	}
+	return true
+}
+
+func (h *handler) getHeadlines(w http.ResponseWriter, r *http.Request, params apiRequest) {
+	userID := request.UserID(r)
+	feedID := params.intParam("feed_id", feedArchived)
+	isCategory := params.boolParam("is_cat")
+
+	if _, found := params["feed_id"]; !found {
+		sendError(w, r, params, errorIncorrectUsage)
+		return
+	}
+
+	builder := h.store.NewEntryQueryBuilder(userID)
+	builder.WithoutStatus(model.EntryStatusRemoved)
+	found := withFeed(builder, feedID, isCategory)
+
+	switch params.stringParam("view_mode") {
+	case "unread":
+		builder.WithStatus(model.EntryStatusUnread)
+	case "marked":
+		builder.WithStarred(true)
+	case "published":
+		found = false
+	case "adaptive":
+		// The unread entries are returned when there are some, all the entries otherwise.
+		unreadBuilder := h.store.NewEntryQueryBuilder(userID)
+		unreadBuilder.WithStatus(model.EntryStatusUnread)
+		withFeed(unreadBuilder, feedID, isCategory)
+		count, err := unreadBuilder.CountEntries()
+		if err != nil {
+			json.ServerError(w, r, err)
+			return
+		}
+		if count > 0 {
+			builder.WithStatus(model.EntryStatusUnread)
+		}
+	}
+
+	if sinceID := params.intParam("since_id", 0); sinceID > 0 {
+		builder.AfterEntryID(sinceID)
+	}
+
+	limit := int(params.intParam("limit", defaultHeadlinesLimit))
+	if limit <= 0 || limit > maxHeadlinesLimit {
+		limit = maxHeadlinesLimit
+	}
+
+	direction := "desc"
+	if params.stringParam("order_by") == "date_reverse" {
+		direction = "asc"
+	}
+
+	builder.WithOrder(model.DefaultSortingOrder)
+	builder.WithDirection(direction)
+	builder.WithLimit(limit)
+	builder.WithOffset(int(params.intParam("skip", 0)))
+
+	entries := model.Entries{}
+	if found {
+		var err error
+		if entries, err = builder.GetEntries(); err != nil {
+			json.ServerError(w, r, err)
+			return
+		}
+	}
+
+	headlines := make([]article, 0, len(entries))
+	for _, entry := range entries {
+		headline := h.newArticle(r, entry)
+		if params.boolParam("show_excerpt") {
+			excerpt := sanitizer.TruncateHTML(entry.Content, excerptLength)
+			headline.Excerpt = &excerpt
+		}
+
+		if !params.boolParam("show_content") {
+			headline.Content = nil
+		}
+		headlines = append(headlines, headline)
+	}
+
+	if !params.boolParam("include_header") {
+		sendContent(w, r, params, headlines)
+		return
+	}
+
+	header := headlinesHeader{ID: feedID, IsCat: isCategory}
+	if len(entries) > 0 {
+		header.FirstID = entries[0].ID
+	}
+	sendContent(w, r, params, []interface{}{header, headlines})
+}
+
+func (h *handler) getArticle(w http.ResponseWriter, r *http.Request, params apiRequest) {
+	entryIDs := params.intListParam("article_id")
+	if len(entryIDs) == 0 {
+		sendError(w, r, params, errorIncorrectUsage)
+		return
+	}
+
+	builder := h.store.NewEntryQueryBuilder(request.UserID(r))
+	builder.WithoutStatus(model.EntryStatusRemoved)
+	builder.WithEntryIDs(entryIDs)
+	entries, err := builder.GetEntries()
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	articles := make([]article, 0, len(entries))
+	for _, entry := range entries {
+		a := h.newArticle(r, entry)
+		a.Comments = entry.CommentsURL
+		articles = append(articles, a)
+	}
+
+	sendContent(w, r, params, articles)
+}
+
+// Fields and modes of updateArticle.
+const (
+	fieldStarred = iota
+	fieldPublished
+	fieldUnread
+	fieldNote
+)
+
+const (
+	modeFalse = iota
+	modeTrue
+	modeToggle
+)
+
+// updateArticle changes the starred flag or the status of the articles, the other fields are not supported.
+func (h *handler) updateArticle(w http.ResponseWriter, r *http.Request, params apiRequest) {
+	userID := request.UserID(r)
+	entryIDs := params.intListParam("article_ids")
+	mode := params.intParam("mode", modeFalse)
+	field := params.intParam("field", fieldStarred)
+
+	if len(entryIDs) == 0 || mode < modeFalse || mode > modeToggle {
+		sendError(w, r, params, errorIncorrectUsage)
+		return
+	}
+
+	if field != fieldStarred && field != fieldUnread {
+		sendContent(w, r, params, updateContent{Status: "OK", Updated: 0})
+		return
+	}
+
+	builder := h.store.NewEntryQueryBuilder(userID)
+	builder.WithoutStatus(model.EntryStatusRemoved)
+	builder.WithEntryIDs(entryIDs)
+	entries, err := builder.GetEntries()
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	// The entries are grouped by new value, toggling them can set different values.
+	var enabledIDs, disabledIDs []int64
+	for _, entry := range entries {
+		enabled := mode == modeTrue
+		if mode == modeToggle {
+			if field == fieldStarred {
+				enabled = !entry.Starred
+			} else {
+				enabled = entry.Status != model.EntryStatusUnread
+			}
+		}
+
+		if enabled {
+			enabledIDs = append(enabledIDs, entry.ID)
+		} else {
+			disabledIDs = append(disabledIDs, entry.ID)
+		}
+	}
+
+	for _, change := range []struct {
+		entryIDs []int64
+		enabled  bool
+	}{{enabledIDs, true}, {disabledIDs, false}} {
+		if len(change.entryIDs) == 0 {
+			continue
+		}
+
+		if field == fieldStarred {
+			err = h.store.SetEntriesBookmarkedState(userID, change.entryIDs, change.enabled)
+		} else if change.enabled {
+			err = h.store.SetEntriesStatus(userID, change.entryIDs, model.EntryStatusUnread)
+		} else {
+			err = h.store.SetEntriesStatus(userID, change.entryIDs, model.EntryStatusRead)
+		}
+
+		if err != nil {
+			json.ServerError(w, r, err)
+			return
+		}
+	}
+
+	sendContent(w, r, params, updateContent{Status: "OK", Updated: len(entries)})
+}
+
+// catchupFeed marks the entries of a feed or a category as read, the mode keeps the recent entries unread.
+func (h *handler) catchupFeed(w http.ResponseWriter, r *http.Request, params apiRequest) {
+	userID := request.UserID(r)
+
+	if _, found := params["feed_id"]; !found {
+		sendError(w, r, params, errorIncorrectUsage)
+		return
+	}
+
+	builder := h.store.NewEntryQueryBuilder(userID)
+	builder.WithStatus(model.EntryStatusUnread)
+	if !withFeed(builder, params.intParam("feed_id", feedArchived), params.boolParam("is_cat")) {
+		sendContent(w, r, params, statusContent{Status: "OK"})
+		return
+	}
+
+	switch params.stringParam("mode") {
+	case "", "all":
+	case "1day":
+		builder.BeforeDate(time.Now().AddDate(0, 0, -1))
+	case "1week":
+		builder.BeforeDate(time.Now().AddDate(0, 0, -7))
+	case "2week":
+		builder.BeforeDate(time.Now().AddDate(0, 0, -14))
+	default:
+		sendError(w, r, params, errorIncorrectUsage)
+		return
+	}
+
+	entryIDs, err := builder.GetEntryIDs()
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	if len(entryIDs) > 0 {
+		if err := h.store.SetEntriesStatus(userID, entryIDs, model.EntryStatusRead); err != nil {
+			json.ServerError(w, r, err)
+			return
+		}
+	}
+
+	sendContent(w, r, params, statusContent{Status: "OK"})
+}
+
+func (h *handler) newArticle(r *http.Request, entry *model.Entry) article {
+	content := proxy.AbsoluteProxyRewriter(h.router, r.Host, entry.Content)
+
+	result := article{
+		ID:           entry.ID,
+		GUID:         entry.Hash,
+		Title:        entry.Title,
+		Link:         entry.URL,
+		Unread:       entry.Status == model.EntryStatusUnread,
+		Marked:       entry.Starred,
+		Updated:      entry.Date.Unix(),
+		FeedID:       entry.FeedID,
+		FeedTitle:    entry.Feed.Title,
+		Tags:         entry.Tags,
+		Labels:       []string{},
+		CommentsLink: entry.CommentsURL,
+		Author:       entry.Author,
+		Content:      &content,
+		Attachments:  []attachment{},
+	}
+
+	if result.Tags == nil {
+		result.Tags = []string{}
+	}
+
+	for _, enclosure := range entry.Enclosures {
+		result.Attachments = append(result.Attachments, attachment{
+			ID:          enclosure.ID,
+			ContentURL:  enclosure.URL,
+			ContentType: enclosure.MimeType,
+			PostID:      entry.ID,
+		})
+	}
+	return result
+}
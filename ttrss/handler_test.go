@@ -0,0 +1,340 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package ttrss // import "miniflux.app/ttrss"
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"miniflux.app/model"
+	"miniflux.app/storage"
+	"miniflux.app/storage/storagetest"
+
+	"github.com/gorilla/mux"
+)
+
+type testClient struct {
+	t         *testing.T
+	server    *httptest.Server
+	sessionID string
+}
+
+type testResponse struct {
+	Seq     int64           `json:"seq"`
+	Status  int             `json:"status"`
+	Content json.RawMessage `json:"content"`
+}
+
+// newTestClient creates a user with a feed of three entries published one day apart,
+// and logs in with the Tiny Tiny RSS credentials.
+func newTestClient(t *testing.T) (*testClient, *storage.Storage, *model.Feed, model.Entries) {
+	t.Helper()
+
+	store := storagetest.NewStorage(t)
+	user := storagetest.CreateUser(t, store, false)
+
+	integration, err := store.Integration(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	integration.TTRSSEnabled = true
+	integration.TTRSSUsername = "reader"
+	integration.TTRSSPassword = "secret"
+	if err := store.UpdateIntegration(integration); err != nil {
+		t.Fatal(err)
+	}
+
+	feed := storagetest.CreateFeed(t, store, user)
+
+	now := time.Now().Truncate(time.Second)
+	newEntries := model.Entries{
+		{Hash: "1", Title: "Entry 1", URL: "https://example.org/1", Date: now.AddDate(0, 0, -2), Content: "<p>First entry</p>"},
+		{Hash: "2", Title: "Entry 2", URL: "https://example.org/2", Date: now.AddDate(0, 0, -1).Add(-time.Hour), Content: "<p>Second entry</p>"},
+		{Hash: "3", Title: "Entry 3", URL: "https://example.org/3", Date: now, Content: "<p>Third entry</p>"},
+	}
+	entries := storagetest.RefreshEntries(t, store, feed, newEntries, false)
+
+	router := mux.NewRouter()
+	Serve(router, store)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	client := &testClient{t: t, server: server}
+	var login loginContent
+	client.call(map[string]interface{}{"op": "login", "user": "reader", "password": "secret"}, &login)
+	if login.SessionID == "" || login.APILevel != apiLevel {
+		t.Fatalf(`Unexpected login response: %+v`, login)
+	}
+	client.sessionID = login.SessionID
+
+	return client, store, feed, entries
+}
+
+func (c *testClient) send(params map[string]interface{}) testResponse {
+	c.t.Helper()
+
+	if _, found := params["sid"]; !found && c.sessionID != "" {
+		params["sid"] = c.sessionID
+	}
+
+	body, err := json.Marshal(params)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+
+	response, err := http.Post(c.server.URL+"/tt-rss/api/", "application/json", bytes.NewReader(body))
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	defer response.Body.Close()
+
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		c.t.Fatalf(`Unexpected status code for %v: got %d: %s`, params["op"], response.StatusCode, data)
+	}
+
+	var result testResponse
+	if err := json.Unmarshal(data, &result); err != nil {
+		c.t.Fatalf(`Unable to decode the response of %v: %v: %s`, params["op"], err, data)
+	}
+	return result
+}
+
+func (c *testClient) call(params map[string]interface{}, into interface{}) {
+	c.t.Helper()
+
+	response := c.send(params)
+	if response.Status != statusOK {
+		c.t.Fatalf(`Call %v failed: %s`, params["op"], response.Content)
+	}
+
+	if into != nil {
+		if err := json.Unmarshal(response.Content, into); err != nil {
+			c.t.Fatalf(`Unable to decode the content of %v: %v: %s`, params["op"], err, response.Content)
+		}
+	}
+}
+
+func (c *testClient) headlines(params map[string]interface{}) []article {
+	c.t.Helper()
+
+	params["op"] = "getHeadlines"
+	var headlines []article
+	c.call(params, &headlines)
+	return headlines
+}
+
+func TestLogin(t *testing.T) {
+	client, store, feed, _ := newTestClient(t)
+
+	response := client.send(map[string]interface{}{"op": "login", "user": "reader", "password": "invalid", "seq": 7})
+	if response.Status != statusError || response.Seq != 7 || string(response.Content) != `{"error":"LOGIN_ERROR"}` {
+		t.Errorf(`Invalid credentials should be rejected: %+v`, response)
+	}
+
+	var loggedIn loggedInContent
+	client.call(map[string]interface{}{"op": "isLoggedIn"}, &loggedIn)
+	if !loggedIn.Status {
+		t.Errorf(`The session should be valid`)
+	}
+
+	response = client.send(map[string]interface{}{"op": "getVersion", "sid": "reader/invalid"})
+	if response.Status != statusError || string(response.Content) != `{"error":"NOT_LOGGED_IN"}` {
+		t.Errorf(`An invalid session should be rejected: %+v`, response)
+	}
+
+	response = client.send(map[string]interface{}{"op": "unknownOperation"})
+	if response.Status != statusError || string(response.Content) != `{"error":"UNKNOWN_METHOD"}` {
+		t.Errorf(`An unknown operation should be rejected: %+v`, response)
+	}
+
+	_, key, err := store.TTRSSUserSessionKey("reader")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response = client.send(map[string]interface{}{"op": "getVersion", "sid": newSessionID("reader", key, time.Now().Add(-time.Minute))})
+	if response.Status != statusError || string(response.Content) != `{"error":"NOT_LOGGED_IN"}` {
+		t.Errorf(`An expired session should be rejected: %+v`, response)
+	}
+
+	// Logging out closes the existing sessions.
+	client.call(map[string]interface{}{"op": "logout"}, nil)
+	client.call(map[string]interface{}{"op": "isLoggedIn"}, &loggedIn)
+	if loggedIn.Status {
+		t.Errorf(`The session should be closed after a logout`)
+	}
+
+	var login loginContent
+	client.call(map[string]interface{}{"op": "login", "user": "reader", "password": "secret"}, &login)
+	client.sessionID = login.SessionID
+
+	client.call(map[string]interface{}{"op": "isLoggedIn"}, &loggedIn)
+	if !loggedIn.Status {
+		t.Errorf(`A new session should be valid after a logout`)
+	}
+
+	// Changing the password closes the existing sessions.
+	integration, err := store.Integration(feed.UserID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	integration.TTRSSPassword = "new secret"
+	if err := store.UpdateIntegration(integration); err != nil {
+		t.Fatal(err)
+	}
+
+	client.call(map[string]interface{}{"op": "isLoggedIn"}, &loggedIn)
+	if loggedIn.Status {
+		t.Errorf(`The session should be closed after a password change`)
+	}
+}
+
+func TestCategoriesAndFeeds(t *testing.T) {
+	client, _, subscription, _ := newTestClient(t)
+
+	var categories []category
+	client.call(map[string]interface{}{"op": "getCategories", "unread_only": true}, &categories)
+	if len(categories) != 2 || categories[0].ID != categorySpecial || categories[1].ID != subscription.Category.ID || categories[1].Unread != 3 {
+		t.Fatalf(`Unexpected categories: %+v`, categories)
+	}
+
+	var feeds []feed
+	client.call(map[string]interface{}{"op": "getFeeds", "cat_id": subscription.Category.ID}, &feeds)
+	if len(feeds) != 1 || feeds[0].ID != subscription.ID || feeds[0].Unread != 3 || feeds[0].FeedURL != subscription.FeedURL {
+		t.Fatalf(`Unexpected feeds of the category: %+v`, feeds)
+	}
+
+	client.call(map[string]interface{}{"op": "getFeeds", "cat_id": "-1"}, &feeds)
+	unread := make(map[int64]int)
+	for _, f := range feeds {
+		unread[f.ID] = f.Unread
+	}
+	if len(feeds) != 3 || unread[feedAll] != 3 || unread[feedFresh] != 1 || unread[feedStarred] != 0 {
+		t.Fatalf(`Unexpected virtual feeds: %+v`, feeds)
+	}
+
+	client.call(map[string]interface{}{"op": "getFeeds", "cat_id": categoryAllWithSpecial, "limit": 2, "offset": 2}, &feeds)
+	if len(feeds) != 2 || feeds[0].ID != feedStarred || feeds[1].ID != subscription.ID {
+		t.Fatalf(`Unexpected page of feeds: %+v`, feeds)
+	}
+
+	var counters []counter
+	client.call(map[string]interface{}{"op": "getCounters"}, &counters)
+	found := false
+	for _, c := range counters {
+		if c.Kind == "cat" && fmt.Sprint(c.ID) == fmt.Sprint(subscription.Category.ID) {
+			found = c.Counter == 3
+		}
+	}
+	if !found {
+		t.Errorf(`The counter of the category is missing: %+v`, counters)
+	}
+}
+
+func TestHeadlines(t *testing.T) {
+	client, _, feed, entries := newTestClient(t)
+
+	headlines := client.headlines(map[string]interface{}{"feed_id": feed.ID, "limit": 2})
+	if len(headlines) != 2 || headlines[0].ID != entries[2].ID || headlines[1].ID != entries[1].ID {
+		t.Fatalf(`Unexpected headlines: %+v`, headlines)
+	}
+	if headlines[0].Content != nil || headlines[0].Excerpt != nil || headlines[0].FeedTitle != "Example" || !headlines[0].Unread {
+		t.Errorf(`Unexpected headline: %+v`, headlines[0])
+	}
+
+	headlines = client.headlines(map[string]interface{}{"feed_id": feed.ID, "skip": 2, "show_content": "true", "show_excerpt": 1})
+	if len(headlines) != 1 || headlines[0].ID != entries[0].ID {
+		t.Fatalf(`Unexpected second page: %+v`, headlines)
+	}
+	if headlines[0].Content == nil || *headlines[0].Content != "<p>First entry</p>" || headlines[0].Excerpt == nil || *headlines[0].Excerpt != "First entry" {
+		t.Errorf(`Unexpected content: %+v`, headlines[0])
+	}
+
+	headlines = client.headlines(map[string]interface{}{"feed_id": feed.Category.ID, "is_cat": true, "order_by": "date_reverse", "since_id": entries[0].ID})
+	if len(headlines) != 2 || headlines[0].ID != entries[1].ID {
+		t.Fatalf(`Unexpected headlines of the category: %+v`, headlines)
+	}
+
+	if headlines := client.headlines(map[string]interface{}{"feed_id": feedFresh}); len(headlines) != 1 || headlines[0].ID != entries[2].ID {
+		t.Errorf(`Unexpected fresh headlines: %+v`, headlines)
+	}
+
+	if headlines := client.headlines(map[string]interface{}{"feed_id": feedPublished}); len(headlines) != 0 {
+		t.Errorf(`There are no published headlines: %+v`, headlines)
+	}
+
+	var withHeader []json.RawMessage
+	client.call(map[string]interface{}{"op": "getHeadlines", "feed_id": feedAll, "include_header": true}, &withHeader)
+	var header headlinesHeader
+	if len(withHeader) != 2 || json.Unmarshal(withHeader[0], &header) != nil || header.ID != feedAll || header.FirstID != entries[2].ID {
+		t.Errorf(`Unexpected header: %s`, withHeader)
+	}
+
+	var articles []article
+	client.call(map[string]interface{}{"op": "getArticle", "article_id": fmt.Sprintf("%d,%d", entries[0].ID, entries[1].ID)}, &articles)
+	if len(articles) != 2 || articles[0].Content == nil {
+		t.Errorf(`Unexpected articles: %+v`, articles)
+	}
+}
+
+func TestUpdateArticle(t *testing.T) {
+	client, _, feed, entries := newTestClient(t)
+
+	var result updateContent
+	client.call(map[string]interface{}{"op": "updateArticle", "article_ids": fmt.Sprintf("%d,%d", entries[0].ID, entries[1].ID), "mode": 1, "field": fieldStarred}, &result)
+	if result.Status != "OK" || result.Updated != 2 {
+		t.Errorf(`Unexpected result: %+v`, result)
+	}
+
+	client.call(map[string]interface{}{"op": "updateArticle", "article_ids": fmt.Sprintf("%d,%d", entries[1].ID, entries[2].ID), "mode": 2, "field": fieldStarred}, nil)
+	headlines := client.headlines(map[string]interface{}{"feed_id": feedStarred, "order_by": "date_reverse"})
+	if len(headlines) != 2 || headlines[0].ID != entries[0].ID || headlines[1].ID != entries[2].ID {
+		t.Errorf(`Unexpected starred headlines after the toggle: %+v`, headlines)
+	}
+
+	client.call(map[string]interface{}{"op": "updateArticle", "article_ids": fmt.Sprint(entries[0].ID), "mode": 0, "field": fieldUnread}, nil)
+	headlines = client.headlines(map[string]interface{}{"feed_id": feed.ID, "view_mode": "unread"})
+	if len(headlines) != 2 {
+		t.Errorf(`Unexpected unread headlines: %+v`, headlines)
+	}
+
+	client.call(map[string]interface{}{"op": "updateArticle", "article_ids": fmt.Sprint(entries[0].ID), "mode": 1, "field": fieldPublished}, &result)
+	if result.Updated != 0 {
+		t.Errorf(`Published articles are not supported: %+v`, result)
+	}
+}
+
+func TestCatchupFeed(t *testing.T) {
+	client, _, feed, _ := newTestClient(t)
+
+	// Only the entries older than one day are marked as read.
+	client.call(map[string]interface{}{"op": "catchupFeed", "feed_id": feed.ID, "mode": "1day"}, nil)
+	if headlines := client.headlines(map[string]interface{}{"feed_id": feedAll, "view_mode": "unread"}); len(headlines) != 1 {
+		t.Errorf(`Unexpected unread headlines: %+v`, headlines)
+	}
+
+	client.call(map[string]interface{}{"op": "catchupFeed", "feed_id": feed.Category.ID, "is_cat": true}, nil)
+	var unread unreadContent
+	client.call(map[string]interface{}{"op": "getUnread"}, &unread)
+	if unread.Unread != "0" {
+		t.Errorf(`All the entries should be read: %+v`, unread)
+	}
+
+	// The adaptive mode returns all the entries when none is unread.
+	if headlines := client.headlines(map[string]interface{}{"feed_id": feedAll, "view_mode": "adaptive"}); len(headlines) != 3 {
+		t.Errorf(`Unexpected adaptive headlines: %+v`, headlines)
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package ttrss // import "miniflux.app/ttrss"
+
+import (
+	json_parser "encoding/json"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// apiRequest holds the parameters of an API call.
+// Clients are not consistent about the types, numbers and booleans are often sent as strings.
+type apiRequest map[string]interface{}
+
+func decodeRequest(body io.Reader) (apiRequest, error) {
+	decoder := json_parser.NewDecoder(body)
+	decoder.UseNumber()
+
+	params := make(apiRequest)
+	if err := decoder.Decode(&params); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+func (a apiRequest) stringParam(name string) string {
+	switch value := a[name].(type) {
+	case string:
+		return value
+	case json_parser.Number:
+		return value.String()
+	case bool:
+		return strconv.FormatBool(value)
+	default:
+		return ""
+	}
+}
+
+func (a apiRequest) intParam(name string, defaultValue int64) int64 {
+	value, err := strconv.ParseInt(strings.TrimSpace(a.stringParam(name)), 10, 64)
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+func (a apiRequest) boolParam(name string) bool {
+	switch strings.ToLower(a.stringParam(name)) {
+	case "true", "t", "1":
+		return true
+	default:
+		return false
+	}
+}
+
+// intListParam returns the IDs given as a comma-separated list, a JSON array or a single number.
+func (a apiRequest) intListParam(name string) []int64 {
+	var values []string
+	if list, ok := a[name].([]interface{}); ok {
+		for _, item := range list {
+			values = append(values, apiRequest{"item": item}.stringParam("item"))
+		}
+	} else {
+		values = strings.Split(a.stringParam(name), ",")
+	}
+
+	var result []int64
+	for _, value := range values {
+		if id, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64); err == nil {
+			result = append(result, id)
+		}
+	}
+	return result
+}
@@ -0,0 +1,151 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package ttrss // import "miniflux.app/ttrss"
+
+import (
+	"net/http"
+
+	"miniflux.app/http/response/json"
+)
+
+const (
+	statusOK = iota
+	statusError
+)
+
+// Error codes returned in the content of the failed calls.
+const (
+	errorNotLoggedIn    = "NOT_LOGGED_IN"
+	errorLogin          = "LOGIN_ERROR"
+	errorUnknownMethod  = "UNKNOWN_METHOD"
+	errorIncorrectUsage = "INCORRECT_USAGE"
+)
+
+type apiResponse struct {
+	Seq     int64       `json:"seq"`
+	Status  int         `json:"status"`
+	Content interface{} `json:"content"`
+}
+
+type errorContent struct {
+	Error string `json:"error"`
+}
+
+type statusContent struct {
+	Status string `json:"status"`
+}
+
+type updateContent struct {
+	Status  string `json:"status"`
+	Updated int    `json:"updated"`
+}
+
+type loggedInContent struct {
+	Status bool `json:"status"`
+}
+
+type levelContent struct {
+	Level int `json:"level"`
+}
+
+type versionContent struct {
+	Version string `json:"version"`
+}
+
+type unreadContent struct {
+	Unread string `json:"unread"`
+}
+
+type loginContent struct {
+	SessionID string        `json:"session_id"`
+	APILevel  int           `json:"api_level"`
+	Config    configContent `json:"config"`
+}
+
+type configContent struct {
+	IconsDir        string `json:"icons_dir"`
+	IconsURL        string `json:"icons_url"`
+	DaemonIsRunning bool   `json:"daemon_is_running"`
+	NumFeeds        int    `json:"num_feeds"`
+}
+
+type counter struct {
+	ID      interface{} `json:"id"`
+	Counter int         `json:"counter"`
+	Kind    string      `json:"kind,omitempty"`
+}
+
+type category struct {
+	ID      int64  `json:"id"`
+	Title   string `json:"title"`
+	Unread  int    `json:"unread"`
+	OrderID int    `json:"order_id"`
+}
+
+type feed struct {
+	ID          int64  `json:"id"`
+	Title       string `json:"title"`
+	FeedURL     string `json:"feed_url,omitempty"`
+	Unread      int    `json:"unread"`
+	HasIcon     bool   `json:"has_icon"`
+	CatID       int64  `json:"cat_id"`
+	LastUpdated int64  `json:"last_updated"`
+	OrderID     int    `json:"order_id"`
+}
+
+type attachment struct {
+	ID          int64  `json:"id"`
+	ContentURL  string `json:"content_url"`
+	ContentType string `json:"content_type"`
+	PostID      int64  `json:"post_id"`
+	Title       string `json:"title"`
+	Duration    string `json:"duration"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+}
+
+// article is used by getHeadlines and getArticle, the excerpt and the content of the headlines are optional.
+type article struct {
+	ID                       int64        `json:"id"`
+	GUID                     string       `json:"guid"`
+	Title                    string       `json:"title"`
+	Link                     string       `json:"link"`
+	Unread                   bool         `json:"unread"`
+	Marked                   bool         `json:"marked"`
+	Published                bool         `json:"published"`
+	Updated                  int64        `json:"updated"`
+	IsUpdated                bool         `json:"is_updated"`
+	FeedID                   int64        `json:"feed_id"`
+	FeedTitle                string       `json:"feed_title"`
+	Tags                     []string     `json:"tags"`
+	Labels                   []string     `json:"labels"`
+	Comments                 string       `json:"comments,omitempty"`
+	CommentsCount            int          `json:"comments_count"`
+	CommentsLink             string       `json:"comments_link"`
+	AlwaysDisplayAttachments bool         `json:"always_display_attachments"`
+	Author                   string       `json:"author"`
+	Score                    int          `json:"score"`
+	Note                     *string      `json:"note"`
+	Lang                     string       `json:"lang"`
+	Excerpt                  *string      `json:"excerpt,omitempty"`
+	Content                  *string      `json:"content,omitempty"`
+	Attachments              []attachment `json:"attachments"`
+}
+
+type headlinesHeader struct {
+	ID      int64 `json:"id"`
+	FirstID int64 `json:"first_id"`
+	IsCat   bool  `json:"is_cat"`
+}
+
+// sendContent sends the result of a successful call.
+func sendContent(w http.ResponseWriter, r *http.Request, params apiRequest, content interface{}) {
+	json.OK(w, r, apiResponse{Seq: params.intParam("seq", 0), Status: statusOK, Content: content})
+}
+
+// sendError sends the error code of a failed call, the API always answers with a 200 status code.
+func sendError(w http.ResponseWriter, r *http.Request, params apiRequest, code string) {
+	json.OK(w, r, apiResponse{Seq: params.intParam("seq", 0), Status: statusError, Content: errorContent{Error: code}})
+}
@@ -0,0 +1,98 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package ttrss // import "miniflux.app/ttrss"
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"miniflux.app/config"
+	"miniflux.app/http/request"
+	"miniflux.app/storage"
+)
+
+// newSessionID signs the username and the expiration date of the session with the session key of the user.
+// Sessions are not stored, they stay valid until they expire, the user logs out, the password is changed or the API is disabled.
+func newSessionID(username, key string, expiresAt time.Time) string {
+	payload := username + "/" + strconv.FormatInt(expiresAt.Unix(), 10)
+	return payload + "/" + signSession(payload, key)
+}
+
+func signSession(payload, key string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// sessionExpirationDate returns the expiration date of a new session, sessions last as long as the web sessions.
+func sessionExpirationDate() time.Time {
+	return time.Now().AddDate(0, 0, config.Opts.CleanupRemoveSessionsDays())
+}
+
+// parseSessionID returns the username and the expiration date of the session.
+func parseSessionID(sessionID string) (string, time.Time, error) {
+	signatureSeparator := strings.LastIndex(sessionID, "/")
+	if signatureSeparator <= 0 {
+		return "", time.Time{}, fmt.Errorf(`invalid session ID: %q`, sessionID)
+	}
+
+	payload := sessionID[:signatureSeparator]
+	expirationSeparator := strings.LastIndex(payload, "/")
+	if expirationSeparator <= 0 {
+		return "", time.Time{}, fmt.Errorf(`invalid session ID: %q`, sessionID)
+	}
+
+	timestamp, err := strconv.ParseInt(payload[expirationSeparator+1:], 10, 64)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf(`invalid session ID: %q`, sessionID)
+	}
+
+	return payload[:expirationSeparator], time.Unix(timestamp, 0), nil
+}
+
+// authenticate checks the session ID and returns the request with the user in its context.
+func authenticate(store *storage.Storage, r *http.Request, sessionID string) (*http.Request, error) {
+	username, expiresAt, err := parseSessionID(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, key, err := store.TTRSSUserSessionKey(username)
+	if err != nil {
+		return nil, err
+	}
+
+	if !hmac.Equal([]byte(newSessionID(username, key, expiresAt)), []byte(sessionID)) {
+		return nil, fmt.Errorf(`the session of %q is invalid`, username)
+	}
+
+	if time.Now().After(expiresAt) {
+		return nil, fmt.Errorf(`the session of %q has expired`, username)
+	}
+
+	user, err := store.UserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		return nil, fmt.Errorf(`user #%d not found`, userID)
+	}
+
+	ctx := r.Context()
+	ctx = context.WithValue(ctx, request.UserIDContextKey, user.ID)
+	ctx = context.WithValue(ctx, request.UserTimezoneContextKey, user.Timezone)
+	ctx = context.WithValue(ctx, request.IsAdminUserContextKey, user.IsAdmin)
+	ctx = context.WithValue(ctx, request.IsAuthenticatedContextKey, true)
+
+	return r.WithContext(ctx), nil
+}
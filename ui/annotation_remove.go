@@ -0,0 +1,35 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package ui // import "miniflux.app/ui"
+
+import (
+	"net/http"
+
+	"miniflux.app/http/request"
+	"miniflux.app/http/response/json"
+)
+
+func (h *handler) removeAnnotation(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	annotationID := request.RouteInt64Param(r, "annotationID")
+
+	annotation, err := h.store.Annotation(userID, annotationID)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	if annotation == nil {
+		json.NotFound(w, r)
+		return
+	}
+
+	if err := h.store.RemoveAnnotation(userID, annotation.ID); err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.OK(w, r, "OK")
+}
@@ -0,0 +1,95 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package ui // import "miniflux.app/ui"
+
+import (
+	"net/http"
+
+	"miniflux.app/http/request"
+	"miniflux.app/http/response/html"
+	"miniflux.app/http/route"
+	"miniflux.app/logger"
+	"miniflux.app/model"
+	"miniflux.app/storage"
+	"miniflux.app/ui/form"
+	"miniflux.app/ui/session"
+	"miniflux.app/ui/view"
+	"miniflux.app/validator"
+)
+
+func (h *handler) saveAnnotation(w http.ResponseWriter, r *http.Request) {
+	user, err := h.store.UserByID(request.UserID(r))
+	if err != nil {
+		html.ServerError(w, r, err)
+		return
+	}
+
+	entryID := request.RouteInt64Param(r, "entryID")
+
+	builder := h.store.NewEntryQueryBuilder(user.ID)
+	builder.WithEntryID(entryID)
+	builder.WithoutStatus(model.EntryStatusRemoved)
+	builder.WithAnnotations()
+
+	entry, err := builder.GetEntry()
+	if err != nil {
+		html.ServerError(w, r, err)
+		return
+	}
+
+	if entry == nil {
+		html.NotFound(w, r)
+		return
+	}
+
+	annotationForm := form.NewAnnotationForm(r)
+	annotationRequest := annotationForm.Request()
+	if validationErr := validator.ValidateAnnotationCreation(annotationRequest); validationErr != nil {
+		logger.Debug("[UI:SaveAnnotation] Entry #%d: %s", entry.ID, validationErr.String())
+
+		entryPaginationBuilder := storage.NewEntryPaginationBuilder(h.store, user.ID, entry.ID, user.EntryOrder, user.EntryDirection)
+		entryPaginationBuilder.WithFeedID(entry.FeedID)
+		prevEntry, nextEntry, err := entryPaginationBuilder.Entries()
+		if err != nil {
+			html.ServerError(w, r, err)
+			return
+		}
+
+		nextEntryRoute := ""
+		if nextEntry != nil {
+			nextEntryRoute = route.Path(h.router, "feedEntry", "feedID", entry.FeedID, "entryID", nextEntry.ID)
+		}
+
+		prevEntryRoute := ""
+		if prevEntry != nil {
+			prevEntryRoute = route.Path(h.router, "feedEntry", "feedID", entry.FeedID, "entryID", prevEntry.ID)
+		}
+
+		sess := session.New(h.store, request.SessionID(r))
+		view := view.New(h.tpl, r, sess)
+		view.Set("entry", entry)
+		view.Set("prevEntry", prevEntry)
+		view.Set("nextEntry", nextEntry)
+		view.Set("nextEntryRoute", nextEntryRoute)
+		view.Set("prevEntryRoute", prevEntryRoute)
+		view.Set("annotationForm", annotationForm)
+		view.Set("errorMessage", validationErr.TranslationKey)
+		view.Set("menu", "feeds")
+		view.Set("user", user)
+		view.Set("countUnread", h.store.CountUnreadEntries(user.ID))
+		view.Set("countErrorFeeds", h.store.CountUserFeedsWithErrors(user.ID))
+		view.Set("hasSaveEntry", h.store.HasSaveEntry(user.ID))
+
+		html.OK(w, r, view.Render("entry"))
+		return
+	}
+
+	if _, err := h.store.CreateAnnotation(user.ID, entry.ID, annotationRequest); err != nil {
+		html.ServerError(w, r, err)
+		return
+	}
+
+	html.Redirect(w, r, route.Path(h.router, "feedEntry", "feedID", entry.FeedID, "entryID", entry.ID))
+}
@@ -9,6 +9,7 @@ import (
 
 	"miniflux.app/http/request"
 	"miniflux.app/http/response/html"
+	"miniflux.app/model"
 	"miniflux.app/ui/form"
 	"miniflux.app/ui/session"
 	"miniflux.app/ui/view"
@@ -24,7 +25,15 @@ func (h *handler) showCreateAPIKeyPage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	categories, err := h.store.Categories(user.ID)
+	if err != nil {
+		html.ServerError(w, r, err)
+		return
+	}
+
 	view.Set("form", &form.APIKeyForm{})
+	view.Set("scopes", model.APIKeyScopes())
+	view.Set("categories", categories)
 	view.Set("menu", "settings")
 	view.Set("user", user)
 	view.Set("countUnread", h.store.CountUnreadEntries(user.ID))
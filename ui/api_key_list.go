@@ -29,7 +29,19 @@ func (h *handler) showAPIKeysPage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	categories, err := h.store.Categories(user.ID)
+	if err != nil {
+		html.ServerError(w, r, err)
+		return
+	}
+
+	categoryTitles := make(map[int64]string, len(categories))
+	for _, category := range categories {
+		categoryTitles[category.ID] = category.Title
+	}
+
 	view.Set("apiKeys", apiKeys)
+	view.Set("categoryTitles", categoryTitles)
 	view.Set("menu", "settings")
 	view.Set("user", user)
 	view.Set("countUnread", h.store.CountUnreadEntries(user.ID))
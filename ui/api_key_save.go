@@ -15,6 +15,7 @@ import (
 	"miniflux.app/ui/form"
 	"miniflux.app/ui/session"
 	"miniflux.app/ui/view"
+	"miniflux.app/validator"
 )
 
 func (h *handler) saveAPIKey(w http.ResponseWriter, r *http.Request) {
@@ -28,7 +29,15 @@ func (h *handler) saveAPIKey(w http.ResponseWriter, r *http.Request) {
 
 	sess := session.New(h.store, request.SessionID(r))
 	view := view.New(h.tpl, r, sess)
+	categories, err := h.store.Categories(user.ID)
+	if err != nil {
+		html.ServerError(w, r, err)
+		return
+	}
+
 	view.Set("form", apiKeyForm)
+	view.Set("scopes", model.APIKeyScopes())
+	view.Set("categories", categories)
 	view.Set("menu", "settings")
 	view.Set("user", user)
 	view.Set("countUnread", h.store.CountUnreadEntries(user.ID))
@@ -40,13 +49,19 @@ func (h *handler) saveAPIKey(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if h.store.APIKeyExists(user.ID, apiKeyForm.Description) {
-		view.Set("errorMessage", "error.api_key_already_exists")
+	if validationErr := validator.ValidateAPIKeyCreation(h.store, user.ID, apiKeyForm.Request()); validationErr != nil {
+		view.Set("errorMessage", validationErr.TranslationKey)
 		html.OK(w, r, view.Render("create_api_key"))
 		return
 	}
 
 	apiKey := model.NewAPIKey(user.ID, apiKeyForm.Description)
+	if apiKeyForm.Scopes != nil {
+		apiKey.Scopes = apiKeyForm.Scopes
+	}
+	if apiKeyForm.CategoryIDs != nil {
+		apiKey.CategoryIDs = apiKeyForm.CategoryIDs
+	}
 	if err = h.store.CreateAPIKey(apiKey); err != nil {
 		logger.Error("[UI:SaveAPIKey] %v", err)
 		view.Set("errorMessage", "error.unable_to_create_api_key")
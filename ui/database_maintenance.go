@@ -0,0 +1,117 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package ui // import "miniflux.app/ui"
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"miniflux.app/http/request"
+	"miniflux.app/http/response/html"
+	"miniflux.app/http/route"
+	"miniflux.app/locale"
+	"miniflux.app/logger"
+	"miniflux.app/model"
+	"miniflux.app/storage"
+	"miniflux.app/ui/session"
+)
+
+// databaseMaintenance runs one maintenance operation at a time in the background,
+// the operations can take a long time on large databases.
+type databaseMaintenance struct {
+	mu     sync.Mutex
+	status model.DatabaseMaintenanceStatus
+}
+
+// Status returns the operation running or the last one.
+func (d *databaseMaintenance) Status() model.DatabaseMaintenanceStatus {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.status
+}
+
+// Start runs the operation in the background, it returns false if another operation is running.
+func (d *databaseMaintenance) Start(store *storage.Storage, operation string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.status.Running {
+		return false
+	}
+
+	d.status = model.DatabaseMaintenanceStatus{Operation: operation, Running: true, StartedAt: time.Now()}
+
+	go func() {
+		err := store.RunDatabaseMaintenance(operation)
+
+		d.mu.Lock()
+		defer d.mu.Unlock()
+
+		d.status.Running = false
+		d.status.FinishedAt = time.Now()
+		if err != nil {
+			d.status.ErrorMsg = err.Error()
+			logger.Error("[UI:DatabaseMaintenance] %v", err)
+		} else {
+			logger.Info("[UI:DatabaseMaintenance] %s completed in %s", strings.ToUpper(operation), d.status.FinishedAt.Sub(d.status.StartedAt))
+		}
+	}()
+
+	return true
+}
+
+func (h *handler) runDatabaseMaintenance(w http.ResponseWriter, r *http.Request) {
+	user, err := h.store.UserByID(request.UserID(r))
+	if err != nil {
+		html.ServerError(w, r, err)
+		return
+	}
+
+	if !user.IsAdmin {
+		html.Forbidden(w, r)
+		return
+	}
+
+	operation := request.RouteStringParam(r, "operation")
+	if !model.IsValidDatabaseOperation(operation) {
+		html.NotFound(w, r)
+		return
+	}
+
+	sess := session.New(h.store, request.SessionID(r))
+	printer := locale.NewPrinter(request.UserLanguage(r))
+	if h.maintenance.Start(h.store, operation) {
+		sess.NewFlashMessage(printer.Printf("alert.database_maintenance_started", strings.ToUpper(operation)))
+	} else {
+		sess.NewFlashErrorMessage(printer.Printf("alert.database_maintenance_running", strings.ToUpper(h.maintenance.Status().Operation)))
+	}
+	html.Redirect(w, r, route.Path(h.router, "database"))
+}
+
+func (h *handler) purgeRemovedEntries(w http.ResponseWriter, r *http.Request) {
+	user, err := h.store.UserByID(request.UserID(r))
+	if err != nil {
+		html.ServerError(w, r, err)
+		return
+	}
+
+	if !user.IsAdmin {
+		html.Forbidden(w, r)
+		return
+	}
+
+	count, err := h.store.PurgeRemovedEntries(request.RouteInt64Param(r, "feedID"))
+	if err != nil {
+		html.ServerError(w, r, err)
+		return
+	}
+
+	sess := session.New(h.store, request.SessionID(r))
+	printer := locale.NewPrinter(request.UserLanguage(r))
+	sess.NewFlashMessage(printer.Plural("alert.removed_entries_purged", int(count), count))
+	html.Redirect(w, r, route.Path(h.router, "database"))
+}
@@ -30,6 +30,7 @@ func (h *handler) showCategoryEntryPage(w http.ResponseWriter, r *http.Request)
 	builder.WithCategoryID(categoryID)
 	builder.WithEntryID(entryID)
 	builder.WithoutStatus(model.EntryStatusRemoved)
+	builder.WithAnnotations()
 
 	entry, err := builder.GetEntry()
 	if err != nil {
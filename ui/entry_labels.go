@@ -0,0 +1,53 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package ui // import "miniflux.app/ui"
+
+import (
+	"net/http"
+
+	"miniflux.app/http/request"
+	"miniflux.app/http/response/html"
+	"miniflux.app/http/route"
+	"miniflux.app/locale"
+	"miniflux.app/model"
+	"miniflux.app/ui/form"
+	"miniflux.app/ui/session"
+	"miniflux.app/validator"
+)
+
+func (h *handler) updateEntryLabels(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	entryID := request.RouteInt64Param(r, "entryID")
+
+	builder := h.store.NewEntryQueryBuilder(userID)
+	builder.WithEntryID(entryID)
+	builder.WithoutStatus(model.EntryStatusRemoved)
+
+	entry, err := builder.GetEntry()
+	if err != nil {
+		html.ServerError(w, r, err)
+		return
+	}
+
+	if entry == nil {
+		html.NotFound(w, r)
+		return
+	}
+
+	labelsForm := form.NewEntryLabelsForm(r)
+	if validationErr := validator.ValidateEntryLabels(h.store, userID, labelsForm.Labels); validationErr != nil {
+		sess := session.New(h.store, request.SessionID(r))
+		sess.NewFlashErrorMessage(locale.NewPrinter(request.UserLanguage(r)).Printf(validationErr.TranslationKey))
+		html.Redirect(w, r, route.Path(h.router, "feedEntry", "feedID", entry.FeedID, "entryID", entry.ID))
+		return
+	}
+
+	if err := h.store.SetEntryLabels(userID, entry.ID, labelsForm.Labels); err != nil {
+		html.ServerError(w, r, err)
+		return
+	}
+
+	html.Redirect(w, r, route.Path(h.router, "feedEntry", "feedID", entry.FeedID, "entryID", entry.ID))
+}
@@ -27,6 +27,7 @@ func (h *handler) showReadEntryPage(w http.ResponseWriter, r *http.Request) {
 	builder := h.store.NewEntryQueryBuilder(user.ID)
 	builder.WithEntryID(entryID)
 	builder.WithoutStatus(model.EntryStatusRemoved)
+	builder.WithAnnotations()
 
 	entry, err := builder.GetEntry()
 	if err != nil {
@@ -60,6 +60,7 @@ func (h *handler) showEditFeedPage(w http.ResponseWriter, r *http.Request) {
 		FetchViaProxy:               feed.FetchViaProxy,
 		Disabled:                    feed.Disabled,
 		HideGlobally:                feed.HideGlobally,
+		MarkUpdatedEntriesUnread:    feed.MarkUpdatedEntriesUnread,
 		CategoryHidden:              feed.Category.HideGlobally,
 	}
 
@@ -0,0 +1,44 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package form // import "miniflux.app/ui/form"
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"miniflux.app/model"
+)
+
+// AnnotationForm represents the form used to highlight a part of an entry or to add a note.
+type AnnotationForm struct {
+	Quote       string
+	StartOffset int
+	EndOffset   int
+	Note        string
+}
+
+// Request returns the annotation creation request.
+func (a AnnotationForm) Request() *model.AnnotationRequest {
+	return &model.AnnotationRequest{
+		Quote:       a.Quote,
+		StartOffset: a.StartOffset,
+		EndOffset:   a.EndOffset,
+		Note:        a.Note,
+	}
+}
+
+// NewAnnotationForm returns a new AnnotationForm.
+func NewAnnotationForm(r *http.Request) *AnnotationForm {
+	startOffset, _ := strconv.Atoi(r.FormValue("start_offset"))
+	endOffset, _ := strconv.Atoi(r.FormValue("end_offset"))
+
+	return &AnnotationForm{
+		Quote:       r.FormValue("quote"),
+		StartOffset: startOffset,
+		EndOffset:   endOffset,
+		Note:        strings.TrimSpace(r.FormValue("note")),
+	}
+}
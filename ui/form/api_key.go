@@ -6,13 +6,37 @@ package form // import "miniflux.app/ui/form"
 
 import (
 	"net/http"
+	"strconv"
 
 	"miniflux.app/errors"
+	"miniflux.app/model"
 )
 
 // APIKeyForm represents the API Key form.
 type APIKeyForm struct {
 	Description string
+	Scopes      []string
+	CategoryIDs []int64
+}
+
+// HasScope returns true if the scope is selected.
+func (a APIKeyForm) HasScope(scope string) bool {
+	for _, s := range a.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// HasCategory returns true if the category is selected.
+func (a APIKeyForm) HasCategory(categoryID int64) bool {
+	for _, id := range a.CategoryIDs {
+		if id == categoryID {
+			return true
+		}
+	}
+	return false
 }
 
 // Validate makes sure the form values are valid.
@@ -24,9 +48,29 @@ func (a APIKeyForm) Validate() error {
 	return nil
 }
 
+// Request returns the API key creation request built from the form values.
+func (a APIKeyForm) Request() *model.APIKeyCreationRequest {
+	return &model.APIKeyCreationRequest{
+		Description: a.Description,
+		Scopes:      a.Scopes,
+		CategoryIDs: a.CategoryIDs,
+	}
+}
+
 // NewAPIKeyForm returns a new APIKeyForm.
 func NewAPIKeyForm(r *http.Request) *APIKeyForm {
+	r.ParseForm()
+
+	var categoryIDs []int64
+	for _, value := range r.Form["category_ids"] {
+		if categoryID, err := strconv.ParseInt(value, 10, 64); err == nil {
+			categoryIDs = append(categoryIDs, categoryID)
+		}
+	}
+
 	return &APIKeyForm{
 		Description: r.FormValue("description"),
+		Scopes:      r.Form["scopes"],
+		CategoryIDs: categoryIDs,
 	}
 }
@@ -32,6 +32,7 @@ type FeedForm struct {
 	FetchViaProxy               bool
 	Disabled                    bool
 	HideGlobally                bool
+	MarkUpdatedEntriesUnread    bool
 	CategoryHidden              bool // Category has "hide_globally"
 }
 
@@ -58,6 +59,7 @@ func (f FeedForm) Merge(feed *model.Feed) *model.Feed {
 	feed.FetchViaProxy = f.FetchViaProxy
 	feed.Disabled = f.Disabled
 	feed.HideGlobally = f.HideGlobally
+	feed.MarkUpdatedEntriesUnread = f.MarkUpdatedEntriesUnread
 	return feed
 }
 
@@ -87,5 +89,6 @@ func NewFeedForm(r *http.Request) *FeedForm {
 		FetchViaProxy:               r.FormValue("fetch_via_proxy") == "1",
 		Disabled:                    r.FormValue("disabled") == "1",
 		HideGlobally:                r.FormValue("hide_globally") == "1",
+		MarkUpdatedEntriesUnread:    r.FormValue("mark_updated_entries_unread") == "1",
 	}
 }
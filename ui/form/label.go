@@ -0,0 +1,27 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package form // import "miniflux.app/ui/form"
+
+import (
+	"net/http"
+	"strings"
+)
+
+// EntryLabelsForm represents the form used to edit the labels of an entry.
+type EntryLabelsForm struct {
+	Labels []string
+}
+
+// NewEntryLabelsForm returns a new EntryLabelsForm, labels are separated by commas.
+func NewEntryLabelsForm(r *http.Request) *EntryLabelsForm {
+	var labels []string
+	for _, label := range strings.Split(r.FormValue("labels"), ",") {
+		if label = strings.TrimSpace(label); label != "" {
+			labels = append(labels, label)
+		}
+	}
+
+	return &EntryLabelsForm{Labels: labels}
+}
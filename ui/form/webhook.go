@@ -0,0 +1,66 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package form // import "miniflux.app/ui/form"
+
+import (
+	"net/http"
+	"strings"
+
+	"miniflux.app/errors"
+	"miniflux.app/integration/webhook"
+	"miniflux.app/model"
+	"miniflux.app/validator"
+)
+
+// WebhookForm represents the webhook form.
+type WebhookForm struct {
+	URL    string
+	Secret string
+	Events []string
+}
+
+// HasEvent returns true if the event is selected.
+func (w WebhookForm) HasEvent(event string) bool {
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate makes sure the form values are valid.
+func (w WebhookForm) Validate() error {
+	if w.URL == "" || len(w.Events) == 0 {
+		return errors.NewLocalizedError("error.webhook_mandatory_fields")
+	}
+
+	if !validator.IsValidURL(w.URL) || (!strings.HasPrefix(w.URL, "http://") && !strings.HasPrefix(w.URL, "https://")) {
+		return errors.NewLocalizedError("error.invalid_webhook_url")
+	}
+
+	if !webhook.IsPublicURL(w.URL) {
+		return errors.NewLocalizedError("error.private_webhook_url")
+	}
+
+	for _, event := range w.Events {
+		if !model.IsValidWebhookEvent(event) {
+			return errors.NewLocalizedError("error.invalid_webhook_event")
+		}
+	}
+
+	return nil
+}
+
+// NewWebhookForm returns a new WebhookForm.
+func NewWebhookForm(r *http.Request) *WebhookForm {
+	r.ParseForm()
+
+	return &WebhookForm{
+		URL:    strings.TrimSpace(r.FormValue("url")),
+		Secret: strings.TrimSpace(r.FormValue("secret")),
+		Events: r.Form["events"],
+	}
+}
@@ -13,8 +13,9 @@ import (
 )
 
 type handler struct {
-	router *mux.Router
-	store  *storage.Storage
-	tpl    *template.Engine
-	pool   *worker.Pool
+	router      *mux.Router
+	store       *storage.Storage
+	tpl         *template.Engine
+	pool        *worker.Pool
+	maintenance *databaseMaintenance
 }
@@ -29,45 +29,49 @@ func (h *handler) showIntegrationPage(w http.ResponseWriter, r *http.Request) {
 	}
 
 	integrationForm := form.IntegrationForm{
-		PinboardEnabled:      integration.PinboardEnabled,
-		PinboardToken:        integration.PinboardToken,
-		PinboardTags:         integration.PinboardTags,
-		PinboardMarkAsUnread: integration.PinboardMarkAsUnread,
-		InstapaperEnabled:    integration.InstapaperEnabled,
-		InstapaperUsername:   integration.InstapaperUsername,
-		InstapaperPassword:   integration.InstapaperPassword,
-		FeverEnabled:         integration.FeverEnabled,
-		FeverUsername:        integration.FeverUsername,
-		GoogleReaderEnabled:  integration.GoogleReaderEnabled,
-		GoogleReaderUsername: integration.GoogleReaderUsername,
-		WallabagEnabled:      integration.WallabagEnabled,
-		WallabagOnlyURL:      integration.WallabagOnlyURL,
-		WallabagURL:          integration.WallabagURL,
-		WallabagClientID:     integration.WallabagClientID,
-		WallabagClientSecret: integration.WallabagClientSecret,
-		WallabagUsername:     integration.WallabagUsername,
-		WallabagPassword:     integration.WallabagPassword,
-		NunuxKeeperEnabled:   integration.NunuxKeeperEnabled,
-		NunuxKeeperURL:       integration.NunuxKeeperURL,
-		NunuxKeeperAPIKey:    integration.NunuxKeeperAPIKey,
-		EspialEnabled:        integration.EspialEnabled,
-		EspialURL:            integration.EspialURL,
-		EspialAPIKey:         integration.EspialAPIKey,
-		EspialTags:           integration.EspialTags,
-		PocketEnabled:        integration.PocketEnabled,
-		PocketAccessToken:    integration.PocketAccessToken,
-		PocketConsumerKey:    integration.PocketConsumerKey,
-		TelegramBotEnabled:   integration.TelegramBotEnabled,
-		TelegramBotToken:     integration.TelegramBotToken,
-		TelegramBotChatID:    integration.TelegramBotChatID,
-		LinkdingEnabled:      integration.LinkdingEnabled,
-		LinkdingURL:          integration.LinkdingURL,
-		LinkdingAPIKey:       integration.LinkdingAPIKey,
-		MatrixBotEnabled:     integration.MatrixBotEnabled,
-		MatrixBotUser:        integration.MatrixBotUser,
-		MatrixBotPassword:    integration.MatrixBotPassword,
-		MatrixBotURL:         integration.MatrixBotURL,
-		MatrixBotChatID:      integration.MatrixBotChatID,
+		PinboardEnabled:       integration.PinboardEnabled,
+		PinboardToken:         integration.PinboardToken,
+		PinboardTags:          integration.PinboardTags,
+		PinboardMarkAsUnread:  integration.PinboardMarkAsUnread,
+		InstapaperEnabled:     integration.InstapaperEnabled,
+		InstapaperUsername:    integration.InstapaperUsername,
+		InstapaperPassword:    integration.InstapaperPassword,
+		FeverEnabled:          integration.FeverEnabled,
+		FeverUsername:         integration.FeverUsername,
+		GoogleReaderEnabled:   integration.GoogleReaderEnabled,
+		GoogleReaderUsername:  integration.GoogleReaderUsername,
+		NextcloudNewsEnabled:  integration.NextcloudNewsEnabled,
+		NextcloudNewsUsername: integration.NextcloudNewsUsername,
+		TTRSSEnabled:          integration.TTRSSEnabled,
+		TTRSSUsername:         integration.TTRSSUsername,
+		WallabagEnabled:       integration.WallabagEnabled,
+		WallabagOnlyURL:       integration.WallabagOnlyURL,
+		WallabagURL:           integration.WallabagURL,
+		WallabagClientID:      integration.WallabagClientID,
+		WallabagClientSecret:  integration.WallabagClientSecret,
+		WallabagUsername:      integration.WallabagUsername,
+		WallabagPassword:      integration.WallabagPassword,
+		NunuxKeeperEnabled:    integration.NunuxKeeperEnabled,
+		NunuxKeeperURL:        integration.NunuxKeeperURL,
+		NunuxKeeperAPIKey:     integration.NunuxKeeperAPIKey,
+		EspialEnabled:         integration.EspialEnabled,
+		EspialURL:             integration.EspialURL,
+		EspialAPIKey:          integration.EspialAPIKey,
+		EspialTags:            integration.EspialTags,
+		PocketEnabled:         integration.PocketEnabled,
+		PocketAccessToken:     integration.PocketAccessToken,
+		PocketConsumerKey:     integration.PocketConsumerKey,
+		TelegramBotEnabled:    integration.TelegramBotEnabled,
+		TelegramBotToken:      integration.TelegramBotToken,
+		TelegramBotChatID:     integration.TelegramBotChatID,
+		LinkdingEnabled:       integration.LinkdingEnabled,
+		LinkdingURL:           integration.LinkdingURL,
+		LinkdingAPIKey:        integration.LinkdingAPIKey,
+		MatrixBotEnabled:      integration.MatrixBotEnabled,
+		MatrixBotUser:         integration.MatrixBotUser,
+		MatrixBotPassword:     integration.MatrixBotPassword,
+		MatrixBotURL:          integration.MatrixBotURL,
+		MatrixBotChatID:       integration.MatrixBotChatID,
 	}
 
 	sess := session.New(h.store, request.SessionID(r))
@@ -15,6 +15,7 @@ import (
 	"miniflux.app/locale"
 	"miniflux.app/ui/form"
 	"miniflux.app/ui/session"
+	"miniflux.app/validator"
 )
 
 func (h *handler) updateIntegration(w http.ResponseWriter, r *http.Request) {
@@ -35,8 +36,8 @@ func (h *handler) updateIntegration(w http.ResponseWriter, r *http.Request) {
 	integrationForm := form.NewIntegrationForm(r)
 	integrationForm.Merge(integration)
 
-	if integration.FeverUsername != "" && h.store.HasDuplicateFeverUsername(user.ID, integration.FeverUsername) {
-		sess.NewFlashErrorMessage(printer.Printf("error.duplicate_fever_username"))
+	if validationErr := validator.ValidateIntegration(h.store, integration); validationErr != nil {
+		sess.NewFlashErrorMessage(printer.Printf(validationErr.TranslationKey))
 		html.Redirect(w, r, route.Path(h.router, "integrations"))
 		return
 	}
@@ -49,12 +50,6 @@ func (h *handler) updateIntegration(w http.ResponseWriter, r *http.Request) {
 		integration.FeverToken = ""
 	}
 
-	if integration.GoogleReaderUsername != "" && h.store.HasDuplicateGoogleReaderUsername(user.ID, integration.GoogleReaderUsername) {
-		sess.NewFlashErrorMessage(printer.Printf("error.duplicate_googlereader_username"))
-		html.Redirect(w, r, route.Path(h.router, "integrations"))
-		return
-	}
-
 	if integration.GoogleReaderEnabled {
 		if integrationForm.GoogleReaderPassword != "" {
 			integration.GoogleReaderPassword = integrationForm.GoogleReaderPassword
@@ -0,0 +1,71 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package ui // import "miniflux.app/ui"
+
+import (
+	"net/http"
+
+	"miniflux.app/http/request"
+	"miniflux.app/http/response/html"
+	"miniflux.app/http/route"
+	"miniflux.app/model"
+	"miniflux.app/ui/session"
+	"miniflux.app/ui/view"
+)
+
+func (h *handler) showLabelEntriesPage(w http.ResponseWriter, r *http.Request) {
+	user, err := h.store.UserByID(request.UserID(r))
+	if err != nil {
+		html.ServerError(w, r, err)
+		return
+	}
+
+	labelID := request.RouteInt64Param(r, "labelID")
+	label, err := h.store.Label(user.ID, labelID)
+	if err != nil {
+		html.ServerError(w, r, err)
+		return
+	}
+
+	if label == nil {
+		html.NotFound(w, r)
+		return
+	}
+
+	offset := request.QueryIntParam(r, "offset", 0)
+	builder := h.store.NewEntryQueryBuilder(user.ID)
+	builder.WithLabelID(label.ID)
+	builder.WithoutStatus(model.EntryStatusRemoved)
+	builder.WithOrder(user.EntryOrder)
+	builder.WithDirection(user.EntryDirection)
+	builder.WithOffset(offset)
+	builder.WithLimit(user.EntriesPerPage)
+
+	entries, err := builder.GetEntries()
+	if err != nil {
+		html.ServerError(w, r, err)
+		return
+	}
+
+	count, err := builder.CountEntries()
+	if err != nil {
+		html.ServerError(w, r, err)
+		return
+	}
+
+	sess := session.New(h.store, request.SessionID(r))
+	view := view.New(h.tpl, r, sess)
+	view.Set("label", label)
+	view.Set("total", count)
+	view.Set("entries", entries)
+	view.Set("pagination", getPagination(route.Path(h.router, "labelEntries", "labelID", label.ID), count, offset, user.EntriesPerPage))
+	view.Set("menu", "categories")
+	view.Set("user", user)
+	view.Set("countUnread", h.store.CountUnreadEntries(user.ID))
+	view.Set("countErrorFeeds", h.store.CountUserFeedsWithErrors(user.ID))
+	view.Set("hasSaveEntry", h.store.HasSaveEntry(user.ID))
+
+	html.OK(w, r, view.Render("label_entries"))
+}
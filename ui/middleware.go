@@ -8,6 +8,7 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"time"
 
 	"miniflux.app/config"
 	"miniflux.app/http/cookie"
@@ -22,6 +23,9 @@ import (
 	"github.com/gorilla/mux"
 )
 
+// userSessionUsedInterval limits the updates of the last used date of the user sessions.
+const userSessionUsedInterval = time.Minute
+
 type middleware struct {
 	router *mux.Router
 	store  *storage.Storage
@@ -45,6 +49,12 @@ func (m *middleware) handleUserSession(next http.Handler) http.Handler {
 		} else {
 			logger.Debug("[UI:UserSession] %s", session)
 
+			if session.LastUsedAt == nil || time.Since(*session.LastUsedAt) > userSessionUsedInterval {
+				if err := m.store.SetUserSessionUsedTimestamp(session.ID); err != nil {
+					logger.Error("[UI:UserSession] %v", err)
+				}
+			}
+
 			ctx := r.Context()
 			ctx = context.WithValue(ctx, request.UserIDContextKey, session.UserID)
 			ctx = context.WithValue(ctx, request.IsAuthenticatedContextKey, true)
@@ -25,7 +25,7 @@ func (h *handler) showSearchEntriesPage(w http.ResponseWriter, r *http.Request)
 	searchQuery := request.QueryStringParam(r, "q", "")
 	offset := request.QueryIntParam(r, "offset", 0)
 	builder := h.store.NewEntryQueryBuilder(user.ID)
-	builder.WithSearchQuery(searchQuery)
+	builder.WithSearchQuery(searchQuery, user.Language)
 	builder.WithoutStatus(model.EntryStatusRemoved)
 	builder.WithOffset(offset)
 	builder.WithLimit(user.EntriesPerPage)
@@ -531,6 +531,77 @@ function updateUnreadCounterValue(callback) {
     }
 }
 
+// Listen to the server events to keep the counters up to date and announce new entries.
+function handleEventStream() {
+    const streamURL = document.body.dataset.eventStreamUrl;
+    if (!streamURL || !window.EventSource) {
+        return;
+    }
+
+    const eventSource = new EventSource(streamURL);
+    eventSource.addEventListener("counters", (event) => updateCounters(JSON.parse(event.data)));
+    eventSource.addEventListener("new_entries", (event) => showNewEntriesNotice(JSON.parse(event.data).entries.length));
+}
+
+function updateCounters(counters) {
+    updateMenuCounter("unread", "unread-counter", counters.unread_count);
+    updateMenuCounter("feeds", "error-feeds-counter", counters.error_feed_count);
+    updateUnreadCounterValue(() => {
+        return counters.unread_count;
+    });
+
+    document.querySelectorAll(".feed-item[data-feed-id]").forEach((element) => {
+        const unreadCount = counters.unreads[element.dataset.feedId] || 0;
+        const readCount = counters.reads[element.dataset.feedId] || 0;
+
+        element.querySelector(".feed-unread-counter").textContent = unreadCount;
+        element.querySelector(".feed-read-counter").textContent = readCount;
+
+        if (!element.classList.contains("feed-parsing-error")) {
+            element.classList.toggle("feed-has-unread", unreadCount > 0);
+        }
+    });
+}
+
+// Updates the counter displayed next to a menu item, the counter is hidden when the value is zero.
+function updateMenuCounter(page, counterClass, value) {
+    const linkElement = document.querySelector(`header a[data-page=${page}]`);
+    if (!linkElement) {
+        return;
+    }
+
+    let wrapperElement = linkElement.querySelector(`.${counterClass}-wrapper`);
+    if (value <= 0) {
+        if (wrapperElement) {
+            wrapperElement.remove();
+        }
+        return;
+    }
+
+    if (!wrapperElement) {
+        const counterElement = document.createElement("span");
+        counterElement.classList.add(counterClass);
+
+        wrapperElement = document.createElement("span");
+        wrapperElement.classList.add(`${counterClass}-wrapper`);
+        wrapperElement.append("(", counterElement, ")");
+        linkElement.append(" ", wrapperElement);
+    }
+
+    wrapperElement.querySelector(`.${counterClass}`).textContent = value;
+}
+
+function showNewEntriesNotice(count) {
+    const noticeElement = document.getElementById("new-entries-notice");
+    if (!noticeElement || count === 0) {
+        return;
+    }
+
+    const counterElement = noticeElement.querySelector(".new-entries-counter");
+    counterElement.textContent = parseInt(counterElement.textContent, 10) + count;
+    noticeElement.hidden = false;
+}
+
 function isEntry() {
     return document.querySelector("section.entry") !== null;
 }
@@ -618,3 +689,122 @@ function showToast(label, iconElement) {
 function goToAddSubscription() {
     window.location.href = document.body.dataset.addSubscriptionUrl;
 }
+
+// Returns the position of a DOM boundary point in the text content of the root element.
+function getTextOffset(rootElement, node, offset) {
+    if (node.nodeType !== Node.TEXT_NODE) {
+        return -1;
+    }
+
+    let walker = document.createTreeWalker(rootElement, NodeFilter.SHOW_TEXT);
+    let position = 0;
+    while (walker.nextNode()) {
+        if (walker.currentNode === node) {
+            return position + offset;
+        }
+        position += walker.currentNode.length;
+    }
+
+    return -1;
+}
+
+// Copy the text selected in the entry content to the annotation form.
+function handleAnnotationSelection() {
+    let formElement = document.querySelector("form.entry-annotation-form");
+    let contentElement = document.querySelector(".entry-content");
+    if (!formElement || !contentElement) {
+        return;
+    }
+
+    let selection = window.getSelection();
+    if (selection.rangeCount === 0 || selection.isCollapsed) {
+        return;
+    }
+
+    let range = selection.getRangeAt(0);
+    if (!contentElement.contains(range.commonAncestorContainer)) {
+        return;
+    }
+
+    let startOffset = getTextOffset(contentElement, range.startContainer, range.startOffset);
+    let endOffset = getTextOffset(contentElement, range.endContainer, range.endOffset);
+    if (startOffset < 0 || endOffset <= startOffset) {
+        return;
+    }
+
+    let quote = selection.toString();
+    formElement.elements["quote"].value = quote;
+    formElement.elements["start_offset"].value = startOffset;
+    formElement.elements["end_offset"].value = endOffset;
+
+    let quoteElement = formElement.querySelector(".entry-annotation-quote");
+    quoteElement.textContent = quote;
+    quoteElement.hidden = false;
+}
+
+// Wrap the highlighted parts of the entry content with a mark element.
+function highlightAnnotations() {
+    let contentElement = document.querySelector(".entry-content");
+    if (!contentElement) {
+        return;
+    }
+
+    document.querySelectorAll(".entry-annotation[data-start-offset]").forEach((annotationElement) => {
+        let startOffset = parseInt(annotationElement.dataset.startOffset, 10);
+        let endOffset = parseInt(annotationElement.dataset.endOffset, 10);
+        let range = findAnnotationRange(contentElement.textContent, annotationElement.dataset.quote || "", startOffset, endOffset);
+        if (range) {
+            highlightTextRange(contentElement, range[0], range[1]);
+        }
+    });
+}
+
+// Find the position of the quote in the text content, the stored offsets are only
+// a hint because the content of the entry may have changed since the highlight was created.
+function findAnnotationRange(text, quote, startOffset, endOffset) {
+    let normalize = (value) => value.replace(/\s+/g, " ").trim();
+
+    if (quote === "") {
+        return endOffset > startOffset && endOffset <= text.length ? [startOffset, endOffset] : null;
+    }
+
+    if (endOffset > startOffset && normalize(text.substring(startOffset, endOffset)) === normalize(quote)) {
+        return [startOffset, endOffset];
+    }
+
+    // Use the occurrence of the quote that is the closest to the original position.
+    let bestIndex = -1;
+    for (let index = text.indexOf(quote); index !== -1; index = text.indexOf(quote, index + 1)) {
+        if (bestIndex === -1 || Math.abs(index - startOffset) < Math.abs(bestIndex - startOffset)) {
+            bestIndex = index;
+        }
+    }
+
+    return bestIndex === -1 ? null : [bestIndex, bestIndex + quote.length];
+}
+
+function highlightTextRange(rootElement, startOffset, endOffset) {
+    let walker = document.createTreeWalker(rootElement, NodeFilter.SHOW_TEXT);
+    let segments = [];
+    let position = 0;
+
+    while (walker.nextNode()) {
+        let node = walker.currentNode;
+        let nodeStart = position;
+        position += node.length;
+
+        if (position > startOffset && nodeStart < endOffset) {
+            segments.push([node, Math.max(startOffset - nodeStart, 0), Math.min(endOffset - nodeStart, node.length)]);
+        }
+    }
+
+    segments.forEach(([node, from, to]) => {
+        let range = document.createRange();
+        range.setStart(node, from);
+        range.setEnd(node, to);
+
+        let markElement = document.createElement("mark");
+        markElement.className = "entry-highlight";
+        range.surroundContents(markElement);
+    });
+}
@@ -52,6 +52,7 @@ document.addEventListener("DOMContentLoaded", function () {
     onClick("a[data-toggle-bookmark]", (event) => handleBookmark(event.target));
     onClick("a[data-fetch-content-entry]", () => handleFetchOriginalContent());
     onClick("a[data-action=search]", (event) => setFocusToSearchInput(event));
+    onClick("a[data-action=reload]", () => window.location.reload());
     onClick("a[data-action=markPageAsRead]", (event) => handleConfirmationMessage(event.target, () => markPageAsRead()));
     onClick("a[data-toggle-status]", (event) => handleEntryStatus("next", event.target));
 
@@ -69,6 +70,11 @@ document.addEventListener("DOMContentLoaded", function () {
         request.execute();
     }));
 
+    highlightAnnotations();
+    document.addEventListener("selectionchange", () => handleAnnotationSelection());
+
+    handleEventStream();
+
     onClick("a[data-original-link]", (event) => {
         handleEntryStatus("next", event.target, true);
     }, true);
@@ -24,7 +24,7 @@ func Serve(router *mux.Router, store *storage.Storage, pool *worker.Pool) {
 		logger.Fatal(`Unable to parse templates: %v`, err)
 	}
 
-	handler := &handler{router, store, templateEngine, pool}
+	handler := &handler{router, store, templateEngine, pool, &databaseMaintenance{}}
 
 	uiRouter := router.NewRoute().Subrouter()
 	uiRouter.Use(middleware.handleUserSession)
@@ -92,12 +92,22 @@ func Serve(router *mux.Router, store *storage.Storage, pool *worker.Pool) {
 	uiRouter.HandleFunc("/category/{categoryID}/remove", handler.removeCategory).Name("removeCategory").Methods(http.MethodPost)
 	uiRouter.HandleFunc("/category/{categoryID}/mark-all-as-read", handler.markCategoryAsRead).Name("markCategoryAsRead").Methods(http.MethodPost)
 
+	// Label pages.
+	uiRouter.HandleFunc("/labels", handler.showLabelListPage).Name("labels").Methods(http.MethodGet)
+	uiRouter.HandleFunc("/label/{labelID}/entries", handler.showLabelEntriesPage).Name("labelEntries").Methods(http.MethodGet)
+	uiRouter.HandleFunc("/label/{labelID}/remove", handler.removeLabel).Name("removeLabel").Methods(http.MethodPost)
+
 	// Entry pages.
 	uiRouter.HandleFunc("/entry/status", handler.updateEntriesStatus).Name("updateEntriesStatus").Methods(http.MethodPost)
+	uiRouter.HandleFunc("/events", handler.streamEvents).Name("eventStream").Methods(http.MethodGet)
 	uiRouter.HandleFunc("/entry/save/{entryID}", handler.saveEntry).Name("saveEntry").Methods(http.MethodPost)
 	uiRouter.HandleFunc("/entry/download/{entryID}", handler.fetchContent).Name("fetchContent").Methods(http.MethodPost)
 	uiRouter.HandleFunc("/proxy/{encodedDigest}/{encodedURL}", handler.mediaProxy).Name("proxy").Methods(http.MethodGet)
 	uiRouter.HandleFunc("/entry/bookmark/{entryID}", handler.toggleBookmark).Name("toggleBookmark").Methods(http.MethodPost)
+	uiRouter.HandleFunc("/entry/labels/{entryID}", handler.updateEntryLabels).Name("updateEntryLabels").Methods(http.MethodPost)
+	uiRouter.HandleFunc("/entry/revisions/{entryID}", handler.showEntryRevisionsPage).Name("entryRevisions").Methods(http.MethodGet)
+	uiRouter.HandleFunc("/entry/annotations/{entryID}", handler.saveAnnotation).Name("saveAnnotation").Methods(http.MethodPost)
+	uiRouter.HandleFunc("/annotation/{annotationID}/remove", handler.removeAnnotation).Name("removeAnnotation").Methods(http.MethodPost)
 
 	// Share pages.
 	uiRouter.HandleFunc("/entry/share/{entryID}", handler.createSharedEntry).Name("shareEntry").Methods(http.MethodGet)
@@ -113,6 +123,11 @@ func Serve(router *mux.Router, store *storage.Storage, pool *worker.Pool) {
 	uiRouter.HandleFunc("/users/{userID}/update", handler.updateUser).Name("updateUser").Methods(http.MethodPost)
 	uiRouter.HandleFunc("/users/{userID}/remove", handler.removeUser).Name("removeUser").Methods(http.MethodPost)
 
+	// Database pages.
+	uiRouter.HandleFunc("/database", handler.showDatabasePage).Name("database").Methods(http.MethodGet)
+	uiRouter.HandleFunc("/database/{operation}", handler.runDatabaseMaintenance).Name("runDatabaseMaintenance").Methods(http.MethodPost)
+	uiRouter.HandleFunc("/database/feeds/{feedID}/purge", handler.purgeRemovedEntries).Name("purgeRemovedEntries").Methods(http.MethodPost)
+
 	// Settings pages.
 	uiRouter.HandleFunc("/settings", handler.showSettingsPage).Name("settings").Methods(http.MethodGet)
 	uiRouter.HandleFunc("/settings", handler.updateSettings).Name("updateSettings").Methods(http.MethodPost)
@@ -132,6 +147,12 @@ func Serve(router *mux.Router, store *storage.Storage, pool *worker.Pool) {
 	uiRouter.HandleFunc("/keys/create", handler.showCreateAPIKeyPage).Name("createAPIKey").Methods(http.MethodGet)
 	uiRouter.HandleFunc("/keys/save", handler.saveAPIKey).Name("saveAPIKey").Methods(http.MethodPost)
 
+	// Webhook pages.
+	uiRouter.HandleFunc("/webhooks", handler.showWebhooksPage).Name("webhooks").Methods(http.MethodGet)
+	uiRouter.HandleFunc("/webhooks/{webhookID}/remove", handler.removeWebhook).Name("removeWebhook").Methods(http.MethodPost)
+	uiRouter.HandleFunc("/webhooks/create", handler.showCreateWebhookPage).Name("createWebhook").Methods(http.MethodGet)
+	uiRouter.HandleFunc("/webhooks/save", handler.saveWebhook).Name("saveWebhook").Methods(http.MethodPost)
+
 	// OPML pages.
 	uiRouter.HandleFunc("/export", handler.exportFeeds).Name("export").Methods(http.MethodGet)
 	uiRouter.HandleFunc("/import", handler.showImportPage).Name("import").Methods(http.MethodGet)
@@ -0,0 +1,53 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package ui // import "miniflux.app/ui"
+
+import (
+	"net/http"
+
+	"miniflux.app/http/request"
+	"miniflux.app/http/response/html"
+	"miniflux.app/model"
+	"miniflux.app/ui/session"
+	"miniflux.app/ui/view"
+)
+
+// webhookDeliveriesLimit is the number of deliveries displayed for each webhook.
+const webhookDeliveriesLimit = 10
+
+func (h *handler) showWebhooksPage(w http.ResponseWriter, r *http.Request) {
+	sess := session.New(h.store, request.SessionID(r))
+	view := view.New(h.tpl, r, sess)
+
+	user, err := h.store.UserByID(request.UserID(r))
+	if err != nil {
+		html.ServerError(w, r, err)
+		return
+	}
+
+	webhooks, err := h.store.Webhooks(user.ID)
+	if err != nil {
+		html.ServerError(w, r, err)
+		return
+	}
+
+	deliveries := make(map[int64]model.WebhookDeliveries, len(webhooks))
+	for _, webhook := range webhooks {
+		deliveries[webhook.ID], err = h.store.WebhookDeliveries(user.ID, webhook.ID, webhookDeliveriesLimit)
+		if err != nil {
+			html.ServerError(w, r, err)
+			return
+		}
+	}
+
+	view.Set("webhooks", webhooks)
+	view.Set("deliveries", deliveries)
+	view.Set("menu", "settings")
+	view.Set("user", user)
+	view.Set("countUnread", h.store.CountUnreadEntries(user.ID))
+	view.Set("countErrorFeeds", h.store.CountUserFeedsWithErrors(user.ID))
+
+	html.OK(w, r, view.Render("webhooks"))
+}
@@ -0,0 +1,53 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package ui // import "miniflux.app/ui"
+
+import (
+	"net/http"
+
+	"miniflux.app/http/request"
+	"miniflux.app/http/response/html"
+	"miniflux.app/http/route"
+	"miniflux.app/logger"
+	"miniflux.app/model"
+	"miniflux.app/ui/form"
+	"miniflux.app/ui/session"
+	"miniflux.app/ui/view"
+)
+
+func (h *handler) saveWebhook(w http.ResponseWriter, r *http.Request) {
+	user, err := h.store.UserByID(request.UserID(r))
+	if err != nil {
+		html.ServerError(w, r, err)
+		return
+	}
+
+	webhookForm := form.NewWebhookForm(r)
+
+	sess := session.New(h.store, request.SessionID(r))
+	view := view.New(h.tpl, r, sess)
+	view.Set("form", webhookForm)
+	view.Set("events", model.WebhookEvents())
+	view.Set("menu", "settings")
+	view.Set("user", user)
+	view.Set("countUnread", h.store.CountUnreadEntries(user.ID))
+	view.Set("countErrorFeeds", h.store.CountUserFeedsWithErrors(user.ID))
+
+	if err := webhookForm.Validate(); err != nil {
+		view.Set("errorMessage", err.Error())
+		html.OK(w, r, view.Render("create_webhook"))
+		return
+	}
+
+	webhook := model.NewWebhook(user.ID, webhookForm.URL, webhookForm.Secret, webhookForm.Events)
+	if err = h.store.CreateWebhook(webhook); err != nil {
+		logger.Error("[UI:SaveWebhook] %v", err)
+		view.Set("errorMessage", "error.unable_to_create_webhook")
+		html.OK(w, r, view.Render("create_webhook"))
+		return
+	}
+
+	html.Redirect(w, r, route.Path(h.router, "webhooks"))
+}
@@ -0,0 +1,31 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package validator // import "miniflux.app/validator"
+
+import (
+	"miniflux.app/model"
+)
+
+// ValidateAnnotationCreation validates annotation creation.
+func ValidateAnnotationCreation(request *model.AnnotationRequest) *ValidationError {
+	if request.Quote == "" && request.Note == "" {
+		return NewValidationError("error.annotation_empty")
+	}
+
+	if request.StartOffset < 0 || request.EndOffset < request.StartOffset {
+		return NewValidationError("error.annotation_invalid_offsets")
+	}
+
+	return nil
+}
+
+// ValidateAnnotationModification validates annotation modification.
+func ValidateAnnotationModification(annotation *model.Annotation, request *model.AnnotationModificationRequest) *ValidationError {
+	if request.Note != nil && *request.Note == "" && annotation.Quote == "" {
+		return NewValidationError("error.annotation_empty")
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package validator // import "miniflux.app/validator"
+
+import (
+	"testing"
+
+	"miniflux.app/model"
+)
+
+func TestValidateAnnotationCreation(t *testing.T) {
+	scenarios := map[*model.AnnotationRequest]string{
+		{Note: "Note"}: "",
+		{Quote: "Quote", StartOffset: 10, EndOffset: 15}: "",
+		{}: "error.annotation_empty",
+		{Quote: "Quote", StartOffset: -1, EndOffset: 4}: "error.annotation_invalid_offsets",
+		{Quote: "Quote", StartOffset: 10, EndOffset: 5}: "error.annotation_invalid_offsets",
+	}
+
+	for request, expected := range scenarios {
+		err := ValidateAnnotationCreation(request)
+		switch {
+		case expected == "" && err != nil:
+			t.Errorf(`Annotation %+v should be valid, got %q`, request, err.TranslationKey)
+		case expected != "" && (err == nil || err.TranslationKey != expected):
+			t.Errorf(`Annotation %+v should be invalid with %q, got %v`, request, expected, err)
+		}
+	}
+}
+
+func TestValidateAnnotationModification(t *testing.T) {
+	empty := ""
+
+	if err := ValidateAnnotationModification(&model.Annotation{Quote: "Quote"}, &model.AnnotationModificationRequest{Note: &empty}); err != nil {
+		t.Errorf(`Removing the note of a highlight should be allowed`)
+	}
+
+	if err := ValidateAnnotationModification(&model.Annotation{Note: "Note"}, &model.AnnotationModificationRequest{Note: &empty}); err == nil {
+		t.Errorf(`Removing the note of an annotation without highlight should not be allowed`)
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package validator // import "miniflux.app/validator"
+
+import (
+	"miniflux.app/model"
+	"miniflux.app/storage"
+)
+
+// ValidateAPIKeyCreation validates API key creation.
+func ValidateAPIKeyCreation(store *storage.Storage, userID int64, request *model.APIKeyCreationRequest) *ValidationError {
+	if request.Description == "" {
+		return NewValidationError("error.fields_mandatory")
+	}
+
+	if store.APIKeyExists(userID, request.Description) {
+		return NewValidationError("error.api_key_already_exists")
+	}
+
+	for _, scope := range request.Scopes {
+		if !model.IsValidAPIKeyScope(scope) {
+			return NewValidationError("error.invalid_api_key_scope")
+		}
+
+		// A read-only key cannot be granted write permissions.
+		if scope == model.APIKeyScopeReadOnly && len(request.Scopes) > 1 {
+			return NewValidationError("error.api_key_read_only_scope")
+		}
+	}
+
+	for _, categoryID := range request.CategoryIDs {
+		if !store.CategoryIDExists(userID, categoryID) {
+			return NewValidationError("error.feed_category_not_found")
+		}
+	}
+
+	return nil
+}
@@ -19,6 +19,11 @@ func ValidateCategoryCreation(store *storage.Storage, userID int64, request *mod
 		return NewValidationError("error.category_already_exists")
 	}
 
+	// Labels and categories share the same stream IDs in the Google Reader API.
+	if store.LabelTitleExists(userID, request.Title) {
+		return NewValidationError("error.category_label_conflict")
+	}
+
 	return nil
 }
 
@@ -32,5 +37,9 @@ func ValidateCategoryModification(store *storage.Storage, userID, categoryID int
 		return NewValidationError("error.category_already_exists")
 	}
 
+	if store.LabelTitleExists(userID, request.Title) {
+		return NewValidationError("error.category_label_conflict")
+	}
+
 	return nil
 }
@@ -0,0 +1,43 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package validator // import "miniflux.app/validator"
+
+import (
+	"miniflux.app/model"
+	"miniflux.app/storage"
+)
+
+// ValidateIntegration validates the integration settings of a user before saving them.
+func ValidateIntegration(store *storage.Storage, integration *model.Integration) *ValidationError {
+	if integration.FeverUsername != "" && store.HasDuplicateFeverUsername(integration.UserID, integration.FeverUsername) {
+		return NewValidationError("error.duplicate_fever_username")
+	}
+
+	if integration.GoogleReaderUsername != "" && store.HasDuplicateGoogleReaderUsername(integration.UserID, integration.GoogleReaderUsername) {
+		return NewValidationError("error.duplicate_googlereader_username")
+	}
+
+	if integration.NextcloudNewsUsername != "" && store.HasDuplicateNextcloudNewsUsername(integration.UserID, integration.NextcloudNewsUsername) {
+		return NewValidationError("error.duplicate_nextcloud_news_username")
+	}
+
+	if integration.TTRSSUsername != "" && store.HasDuplicateTTRSSUsername(integration.UserID, integration.TTRSSUsername) {
+		return NewValidationError("error.duplicate_ttrss_username")
+	}
+
+	for _, integrationURL := range []string{
+		integration.WallabagURL,
+		integration.NunuxKeeperURL,
+		integration.EspialURL,
+		integration.LinkdingURL,
+		integration.MatrixBotURL,
+	} {
+		if integrationURL != "" && !IsValidURL(integrationURL) {
+			return NewValidationError("error.invalid_integration_url")
+		}
+	}
+
+	return nil
+}
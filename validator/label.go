@@ -0,0 +1,59 @@
+// Copyright 2023 Frédéric Guillot. All rights reserved.
+// Use of this source code is governed by the Apache 2.0
+// license that can be found in the LICENSE file.
+
+package validator // import "miniflux.app/validator"
+
+import (
+	"strings"
+
+	"miniflux.app/model"
+	"miniflux.app/storage"
+)
+
+// ValidateLabelCreation validates label creation.
+func ValidateLabelCreation(store *storage.Storage, userID int64, request *model.LabelRequest) *ValidationError {
+	if request.Title == "" {
+		return NewValidationError("error.title_required")
+	}
+
+	if store.LabelTitleExists(userID, request.Title) {
+		return NewValidationError("error.label_already_exists")
+	}
+
+	// Labels and categories share the same stream IDs in the Google Reader API.
+	if store.CategoryTitleExists(userID, request.Title) {
+		return NewValidationError("error.label_category_conflict")
+	}
+
+	return nil
+}
+
+// ValidateLabelModification validates label modification.
+func ValidateLabelModification(store *storage.Storage, userID, labelID int64, request *model.LabelRequest) *ValidationError {
+	if request.Title == "" {
+		return NewValidationError("error.title_required")
+	}
+
+	if store.AnotherLabelExists(userID, labelID, request.Title) {
+		return NewValidationError("error.label_already_exists")
+	}
+
+	if store.CategoryTitleExists(userID, request.Title) {
+		return NewValidationError("error.label_category_conflict")
+	}
+
+	return nil
+}
+
+// ValidateEntryLabels validates the labels attached to an entry, the missing labels are created.
+func ValidateEntryLabels(store *storage.Storage, userID int64, titles []string) *ValidationError {
+	for _, title := range titles {
+		title = strings.TrimSpace(title)
+		if title != "" && !store.LabelTitleExists(userID, title) && store.CategoryTitleExists(userID, title) {
+			return NewValidationError("error.label_category_conflict")
+		}
+	}
+
+	return nil
+}
@@ -5,22 +5,48 @@
 package worker // import "miniflux.app/worker"
 
 import (
+	"sync"
+	"sync/atomic"
+
 	"miniflux.app/model"
 	"miniflux.app/storage"
 )
 
 // Pool handles a pool of workers.
 type Pool struct {
-	queue chan model.Job
+	queue     chan model.Job
+	pending   sync.WaitGroup
+	succeeded atomic.Int64
+	failed    atomic.Int64
 }
 
 // Push send a list of jobs to the queue.
 func (p *Pool) Push(jobs model.JobList) {
+	p.pending.Add(len(jobs))
 	for _, job := range jobs {
 		p.queue <- job
 	}
 }
 
+// Wait blocks until all the jobs pushed to the queue are processed.
+func (p *Pool) Wait() {
+	p.pending.Wait()
+}
+
+// Stats returns the number of jobs processed successfully and with an error since the creation of the pool.
+func (p *Pool) Stats() (succeeded, failed int64) {
+	return p.succeeded.Load(), p.failed.Load()
+}
+
+func (p *Pool) done(err error) {
+	if err != nil {
+		p.failed.Add(1)
+	} else {
+		p.succeeded.Add(1)
+	}
+	p.pending.Done()
+}
+
 // NewPool creates a pool of background workers.
 func NewPool(store *storage.Storage, nbWorkers int) *Pool {
 	workerPool := &Pool{
@@ -28,7 +54,7 @@ func NewPool(store *storage.Storage, nbWorkers int) *Pool {
 	}
 
 	for i := 0; i < nbWorkers; i++ {
-		worker := &Worker{id: i, store: store}
+		worker := &Worker{id: i, store: store, pool: workerPool}
 		go worker.Run(workerPool.queue)
 	}
 
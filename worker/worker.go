@@ -19,6 +19,7 @@ import (
 type Worker struct {
 	id    int
 	store *storage.Storage
+	pool  *Pool
 }
 
 // Run wait for a job and refresh the given feed.
@@ -43,5 +44,9 @@ func (w *Worker) Run(c chan model.Job) {
 		if refreshErr != nil {
 			logger.Error("[Worker] Refreshing the feed #%d returned this error: %v", job.FeedID, refreshErr)
 		}
+
+		if w.pool != nil {
+			w.pool.done(refreshErr)
+		}
 	}
 }